@@ -0,0 +1,201 @@
+// @ts-check
+import { describe, it, expect, beforeEach, vi } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+describe("batch events", () => {
+  let mockCore;
+  let mockGithub;
+
+  const encode = value => Buffer.from(JSON.stringify(value)).toString("base64");
+  const notFound = () => Object.assign(new Error("Not Found"), { status: 404 });
+  const mockEventsTree = names => {
+    mockGithub.rest.git.getTree.mockImplementation(async ({ tree_sha }) => {
+      if (tree_sha === "events-tree-sha") {
+        return { data: { truncated: false, tree: names.map(name => ({ path: name, type: "blob", sha: `${name}-sha` })) } };
+      }
+      return { data: { tree: [{ path: "README.md", type: "blob" }, { path: "events", type: "tree", sha: "events-tree-sha" }] } };
+    });
+  };
+
+  beforeEach(() => {
+    mockCore = {
+      info: vi.fn(),
+      warning: vi.fn(),
+      error: vi.fn(),
+      setOutput: vi.fn(),
+      setFailed: vi.fn(),
+    };
+
+    mockGithub = {
+      rest: {
+        repos: {
+          getContent: vi.fn(),
+          createOrUpdateFileContents: vi.fn().mockResolvedValue({ data: {} }),
+        },
+        git: {
+          getRef: vi.fn().mockResolvedValue({ data: { object: { sha: "head-sha" } } }),
+          getTree: vi.fn(),
+          getCommit: vi.fn().mockResolvedValue({ data: { sha: "head-sha", tree: { sha: "tree-sha" } } }),
+          createTree: vi.fn().mockResolvedValue({ data: { sha: "new-tree-sha" } }),
+          createCommit: vi.fn().mockResolvedValue({ data: { sha: "new-commit-sha" } }),
+          createRef: vi.fn().mockResolvedValue({ data: {} }),
+          updateRef: vi.fn().mockResolvedValue({ data: {} }),
+        },
+      },
+    };
+
+    global.core = mockCore;
+    global.github = mockGithub;
+    global.context = {
+      repo: { owner: "test-owner", repo: "test-repo" },
+      eventName: "issues",
+      runId: 42,
+      actor: "octocat",
+      payload: { action: "opened", issue: { number: 7, html_url: "https://github.com/test-owner/test-repo/issues/7", title: "secret title", body: "secret body" } },
+    };
+
+    process.env.GH_AW_BATCH_BRANCH = "memory/batch-triage";
+    vi.resetModules();
+  });
+
+  describe("buildEventRecord", () => {
+    it("should record metadata without titles or bodies", async () => {
+      const { buildEventRecord } = await import("./batch_events_helpers.cjs");
+
+      const record = buildEventRecord(global.context);
+
+      expect(record).toMatchObject({ event: "issues", action: "opened", number: 7, run_id: 42, actor: "octocat", url: "https://github.com/test-owner/test-repo/issues/7" });
+      expect(JSON.stringify(record)).not.toContain("secret");
+    });
+  });
+
+  describe("record_batch_event", () => {
+    it("should create the branch and record the event", async () => {
+      mockGithub.rest.git.getRef.mockRejectedValueOnce(notFound());
+      const { main } = await import("./record_batch_event.cjs");
+
+      await main();
+
+      expect(mockGithub.rest.git.createCommit).toHaveBeenCalledWith(expect.objectContaining({ parents: [] }));
+      expect(mockGithub.rest.git.createRef).toHaveBeenCalledWith(expect.objectContaining({ ref: "refs/heads/memory/batch-triage" }));
+      expect(mockGithub.rest.repos.createOrUpdateFileContents).toHaveBeenCalledWith(expect.objectContaining({ path: "events/42.json", branch: "memory/batch-triage" }));
+      expect(mockCore.setFailed).not.toHaveBeenCalled();
+    });
+
+    it("should retry when the branch was updated concurrently", async () => {
+      mockGithub.rest.repos.createOrUpdateFileContents.mockRejectedValueOnce(Object.assign(new Error("Conflict"), { status: 409 }));
+      const { main } = await import("./record_batch_event.cjs");
+
+      await main();
+
+      expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+      expect(mockGithub.rest.repos.createOrUpdateFileContents).toHaveBeenCalledTimes(2);
+      expect(mockCore.setFailed).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("check_batch_events", () => {
+    it("should not activate the agent for recorded events", async () => {
+      const { main } = await import("./check_batch_events.cjs");
+
+      await main();
+
+      expect(mockGithub.rest.git.getTree).not.toHaveBeenCalled();
+      expect(mockCore.setOutput).toHaveBeenCalledWith("batch_ok", "false");
+    });
+
+    it("should skip flush runs without pending events", async () => {
+      global.context.eventName = "schedule";
+      mockGithub.rest.git.getTree.mockRejectedValue(notFound());
+      const { main } = await import("./check_batch_events.cjs");
+
+      await main();
+
+      expect(mockCore.setOutput).toHaveBeenCalledWith("batch_ok", "false");
+    });
+
+    it("should activate flush runs with pending events", async () => {
+      global.context.eventName = "workflow_dispatch";
+      mockEventsTree(["42.json"]);
+      const { main } = await import("./check_batch_events.cjs");
+
+      await main();
+
+      expect(mockCore.setOutput).toHaveBeenCalledWith("batch_ok", "true");
+    });
+  });
+
+  describe("collect_batch_events", () => {
+    let promptPath;
+
+    beforeEach(() => {
+      promptPath = path.join(fs.mkdtempSync(path.join(os.tmpdir(), "batch-events-")), "prompt.txt");
+      fs.writeFileSync(promptPath, "# Triage\n");
+      process.env.GH_AW_PROMPT = promptPath;
+      process.env.GH_AW_BATCH_MAX_EVENTS = "2";
+    });
+
+    it("should add the oldest events to the prompt without modifying the branch", async () => {
+      mockEventsTree(["300.json", "100.json", "200.json"]);
+      mockGithub.rest.repos.getContent.mockImplementation(async ({ path: contentPath }) => {
+        const runId = parseInt(path.basename(contentPath), 10);
+        return { data: { content: encode({ event: "issues", run_id: runId }) } };
+      });
+      const { main } = await import("./collect_batch_events.cjs");
+
+      await main();
+
+      const prompt = fs.readFileSync(promptPath, "utf8");
+      expect(prompt).toContain("<batched-events>");
+      expect(prompt).toContain('{"event":"issues","run_id":100}');
+      expect(prompt).toContain('{"event":"issues","run_id":200}');
+      expect(prompt).not.toContain('"run_id":300');
+      expect(prompt).toContain("1 more event(s) are pending");
+      expect(mockCore.setOutput).toHaveBeenCalledWith("paths", JSON.stringify(["events/100.json", "events/200.json"]));
+      expect(mockGithub.rest.git.createTree).not.toHaveBeenCalled();
+      expect(mockGithub.rest.git.updateRef).not.toHaveBeenCalled();
+    });
+
+    it("should leave the prompt unchanged without pending events", async () => {
+      mockGithub.rest.git.getTree.mockRejectedValue(notFound());
+      const { main } = await import("./collect_batch_events.cjs");
+
+      await main();
+
+      expect(fs.readFileSync(promptPath, "utf8")).toBe("# Triage\n");
+      expect(mockCore.setOutput).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("flush_batch_events", () => {
+    it("should remove the processed events in a single commit", async () => {
+      process.env.GH_AW_BATCH_EVENTS = JSON.stringify(["events/100.json", "events/200.json"]);
+      const { main } = await import("./flush_batch_events.cjs");
+
+      await main();
+
+      expect(mockGithub.rest.git.createTree).toHaveBeenCalledWith(
+        expect.objectContaining({
+          base_tree: "tree-sha",
+          tree: [
+            { path: "events/100.json", mode: "100644", type: "blob", sha: null },
+            { path: "events/200.json", mode: "100644", type: "blob", sha: null },
+          ],
+        })
+      );
+      expect(mockGithub.rest.git.updateRef).toHaveBeenCalledWith(expect.objectContaining({ sha: "new-commit-sha", force: false }));
+    });
+
+    it("should do nothing without processed events", async () => {
+      process.env.GH_AW_BATCH_EVENTS = "";
+      const { main } = await import("./flush_batch_events.cjs");
+
+      await main();
+
+      expect(mockGithub.rest.git.updateRef).not.toHaveBeenCalled();
+      expect(mockCore.setFailed).not.toHaveBeenCalled();
+    });
+  });
+});
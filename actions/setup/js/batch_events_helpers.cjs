@@ -0,0 +1,198 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Helpers for on.batch. Events are stored as events/<run-id>.json files on a dedicated
+ * orphan branch (memory/batch-<workflow-id>) through the GitHub API, so that no checkout is
+ * needed and concurrent runs only conflict on the branch ref, which is retried.
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+const EVENTS_DIR = "events";
+const MAX_RETRIES = 3;
+
+/**
+ * @param {any} error
+ * @returns {number | undefined}
+ */
+function errorStatus(error) {
+  return error && typeof error === "object" ? error.status : undefined;
+}
+
+/**
+ * Build the metadata recorded for the triggering event. Titles and bodies are not recorded;
+ * the agent reads them with its GitHub tools.
+ * @param {any} ctx - github-script context
+ * @returns {Record<string, any>}
+ */
+function buildEventRecord(ctx) {
+  const payload = ctx.payload || {};
+  /** @type {Record<string, any>} */
+  const record = {
+    event: ctx.eventName,
+    run_id: ctx.runId,
+    recorded_at: new Date().toISOString(),
+    actor: ctx.actor,
+  };
+  if (payload.action) record.action = payload.action;
+  if (payload.issue) {
+    record.number = payload.issue.number;
+    record.url = payload.issue.html_url;
+    if (payload.issue.pull_request) record.is_pull_request = true;
+  }
+  if (payload.comment) {
+    record.comment_id = payload.comment.id;
+    record.url = payload.comment.html_url;
+  }
+  if (ctx.eventName === "push") {
+    record.ref = ctx.ref;
+    record.sha = ctx.sha;
+    record.url = payload.compare;
+    record.commits = Array.isArray(payload.commits) ? payload.commits.length : 0;
+  }
+  return record;
+}
+
+/**
+ * Create the batch branch as an orphan branch if it does not exist
+ * @param {string} branch
+ */
+async function ensureBatchBranch(branch) {
+  const { owner, repo } = context.repo;
+  try {
+    await github.rest.git.getRef({ owner, repo, ref: `heads/${branch}` });
+    return;
+  } catch (error) {
+    if (errorStatus(error) !== 404) throw error;
+  }
+
+  core.info(`Creating batch branch ${branch}`);
+  const { data: tree } = await github.rest.git.createTree({
+    owner,
+    repo,
+    tree: [{ path: "README.md", mode: "100644", type: "blob", content: "Events accumulated by an agentic workflow with on.batch. Managed by gh-aw.\n" }],
+  });
+  const { data: commit } = await github.rest.git.createCommit({ owner, repo, message: "Initialize batch queue", tree: tree.sha, parents: [] });
+  try {
+    await github.rest.git.createRef({ owner, repo, ref: `refs/heads/${branch}`, sha: commit.sha });
+  } catch (error) {
+    // Another run created the branch concurrently
+    if (errorStatus(error) !== 422) throw error;
+  }
+}
+
+/**
+ * Record an event on the batch branch
+ * @param {string} branch
+ * @param {Record<string, any>} record
+ */
+async function recordEvent(branch, record) {
+  const { owner, repo } = context.repo;
+  await ensureBatchBranch(branch);
+
+  const path = `${EVENTS_DIR}/${record.run_id}.json`;
+  const content = Buffer.from(JSON.stringify(record) + "\n").toString("base64");
+  for (let attempt = 1; ; attempt++) {
+    try {
+      await github.rest.repos.createOrUpdateFileContents({ owner, repo, path, branch, content, message: `Record ${record.event} event of run ${record.run_id}` });
+      return path;
+    } catch (error) {
+      // 409: the branch moved while the file was committed
+      if (errorStatus(error) !== 409 || attempt >= MAX_RETRIES) throw error;
+      core.info(`Branch ${branch} was updated concurrently, retrying (${attempt}/${MAX_RETRIES})`);
+    }
+  }
+}
+
+/**
+ * List the pending event files of the batch branch, oldest first. The git trees API is used
+ * because the contents API lists at most 1000 directory entries.
+ * @param {string} branch
+ * @returns {Promise<string[]>}
+ */
+async function listPendingEvents(branch) {
+  const { owner, repo } = context.repo;
+  let eventsTree;
+  try {
+    const { data: root } = await github.rest.git.getTree({ owner, repo, tree_sha: branch });
+    eventsTree = root.tree.find((/** @type {any} */ entry) => entry.path === EVENTS_DIR && entry.type === "tree");
+  } catch (error) {
+    // The branch does not exist yet
+    if (errorStatus(error) === 404) return [];
+    throw error;
+  }
+  if (!eventsTree) return [];
+
+  const { data } = await github.rest.git.getTree({ owner, repo, tree_sha: eventsTree.sha });
+  if (data.truncated) {
+    core.warning(`The events of ${branch} were truncated by the git trees API; the remaining events are processed by later flushes`);
+  }
+  const runId = (/** @type {string} */ name) => parseInt(name, 10) || 0;
+  return data.tree
+    .filter((/** @type {any} */ entry) => entry.type === "blob" && entry.path.endsWith(".json"))
+    .map((/** @type {any} */ entry) => entry.path)
+    .sort((/** @type {string} */ a, /** @type {string} */ b) => runId(a) - runId(b))
+    .map((/** @type {string} */ name) => `${EVENTS_DIR}/${name}`);
+}
+
+/**
+ * Read the events stored in the given files
+ * @param {string} branch
+ * @param {string[]} paths
+ * @returns {Promise<Array<Record<string, any>>>}
+ */
+async function readEvents(branch, paths) {
+  const { owner, repo } = context.repo;
+  const events = [];
+  for (const path of paths) {
+    try {
+      const { data } = await github.rest.repos.getContent({ owner, repo, path, ref: branch });
+      const content = Buffer.from(/** @type {any} */ (data).content || "", "base64").toString("utf8");
+      events.push(JSON.parse(content));
+    } catch (error) {
+      core.warning(`Skipping unreadable batched event ${path}: ${getErrorMessage(error)}`);
+    }
+  }
+  return events;
+}
+
+/**
+ * Remove event files from the batch branch in a single commit
+ * @param {string} branch
+ * @param {string[]} paths
+ */
+async function removeEvents(branch, paths) {
+  if (paths.length === 0) return;
+  const { owner, repo } = context.repo;
+  for (let attempt = 1; ; attempt++) {
+    const { data: ref } = await github.rest.git.getRef({ owner, repo, ref: `heads/${branch}` });
+    const { data: head } = await github.rest.git.getCommit({ owner, repo, commit_sha: ref.object.sha });
+    const { data: tree } = await github.rest.git.createTree({
+      owner,
+      repo,
+      base_tree: head.tree.sha,
+      tree: paths.map(path => ({ path, mode: "100644", type: "blob", sha: null })),
+    });
+    const { data: commit } = await github.rest.git.createCommit({ owner, repo, message: `Flush ${paths.length} batched event(s)`, tree: tree.sha, parents: [head.sha] });
+    try {
+      await github.rest.git.updateRef({ owner, repo, ref: `heads/${branch}`, sha: commit.sha, force: false });
+      return;
+    } catch (error) {
+      // 422: not a fast-forward because an event was recorded concurrently
+      if (errorStatus(error) !== 422 || attempt >= MAX_RETRIES) throw error;
+      core.info(`Branch ${branch} was updated concurrently, retrying (${attempt}/${MAX_RETRIES})`);
+    }
+  }
+}
+
+/**
+ * Whether the current run flushes the accumulated events
+ * @param {string} eventName
+ * @returns {boolean}
+ */
+function isFlushEvent(eventName) {
+  return eventName === "schedule" || eventName === "workflow_dispatch";
+}
+
+module.exports = { buildEventRecord, ensureBatchBranch, recordEvent, listPendingEvents, readEvents, removeEvents, isFlushEvent };
@@ -0,0 +1,41 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Batch check for on.batch
+ *
+ * Events of a batched workflow never activate the agent directly; they are recorded on the
+ * batch branch by the batch_accumulate job. Flush runs (schedule or workflow_dispatch) only
+ * activate the agent when events are pending.
+ */
+
+const { listPendingEvents, isFlushEvent } = require("./batch_events_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+async function main() {
+  const branch = process.env.GH_AW_BATCH_BRANCH || "";
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME || "workflow";
+
+  if (!isFlushEvent(context.eventName)) {
+    core.info(`📥 Recording ${context.eventName} event for the next batch of workflow '${workflowName}'`);
+    core.setOutput("batch_ok", "false");
+    return;
+  }
+
+  try {
+    const pending = await listPendingEvents(branch);
+    if (pending.length === 0) {
+      core.info(`✅ No batched events pending on ${branch}, skipping the run`);
+      core.setOutput("batch_ok", "false");
+      return;
+    }
+    core.info(`✅ ${pending.length} batched event(s) pending on ${branch}`);
+    core.setOutput("batch_ok", "true");
+  } catch (error) {
+    // On error, allow the workflow to proceed (fail-open) like the rate limit check
+    core.warning(`⚠️ Batch check failed, allowing the run to proceed: ${getErrorMessage(error)}`);
+    core.setOutput("batch_ok", "true");
+  }
+}
+
+module.exports = { main };
@@ -0,0 +1,163 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Concurrency policy check for concurrency.policy
+ *
+ * - queue: skips the run when more than max-depth runs of the workflow are waiting, otherwise
+ *   waits until the runs created before it have completed and fails after max-wait-minutes
+ * - debounce: waits for the quiet period. A newer event for the same entity cancels this job
+ *   through the concurrency group of the debounce job, so only the latest event proceeds.
+ */
+
+/** Statuses of workflow runs that have not completed yet */
+const IN_FLIGHT_STATUSES = ["requested", "queued", "pending", "waiting", "in_progress"];
+
+/**
+ * Statuses queried when listing the runs ahead. Each status is a separate query so that only
+ * in-flight runs are listed instead of the whole run history of the workflow.
+ */
+const QUEUE_STATUSES = ["queued", "in_progress", "waiting"];
+
+/**
+ * @param {number} ms
+ * @returns {Promise<void>}
+ */
+function sleep(ms) {
+  return new Promise(resolve => setTimeout(resolve, ms));
+}
+
+/**
+ * Resolve the workflow file from GITHUB_WORKFLOW_REF, falling back to the workflow name
+ * @returns {string}
+ */
+function resolveWorkflowId() {
+  const match = (process.env.GITHUB_WORKFLOW_REF || "").match(/\.github\/workflows\/([^@]+)/);
+  return match && match[1] ? match[1] : context.workflow;
+}
+
+/**
+ * Select the in-flight runs created before the current run
+ * @param {Array<{id: number, status: string | null, created_at: string}>} runs
+ * @param {{id: number, created_at: string}} current
+ * @returns {Array<{id: number, status: string | null, created_at: string}>}
+ */
+function runsAhead(runs, current) {
+  const currentCreatedAt = new Date(current.created_at).getTime();
+  return runs.filter(run => {
+    if (run.id === current.id || !IN_FLIGHT_STATUSES.includes(run.status || "")) {
+      return false;
+    }
+    const createdAt = new Date(run.created_at).getTime();
+    // Runs created in the same second are ordered by ID
+    return createdAt < currentCreatedAt || (createdAt === currentCreatedAt && run.id < current.id);
+  });
+}
+
+/**
+ * List the in-flight runs of the workflow created before the current run
+ * @param {string} workflowId
+ * @param {{id: number, created_at: string}} current
+ */
+async function listRunsAhead(workflowId, current) {
+  const { owner, repo } = context.repo;
+  const perPage = 100;
+  /** @type {Map<number, any>} */
+  const runs = new Map();
+  for (const status of QUEUE_STATUSES) {
+    for (let page = 1; ; page++) {
+      const response = await github.rest.actions.listWorkflowRuns({
+        owner,
+        repo,
+        workflow_id: workflowId,
+        status,
+        created: `<=${current.created_at}`,
+        per_page: perPage,
+        page,
+      });
+      // A run can change status between queries; keep one entry per run
+      for (const run of response.data.workflow_runs) {
+        runs.set(run.id, run);
+      }
+      if (response.data.workflow_runs.length < perPage) {
+        break;
+      }
+    }
+  }
+  return runsAhead([...runs.values()], current);
+}
+
+/**
+ * Enforce the queue policy
+ * @param {number} maxDepth
+ * @param {number} pollSeconds
+ * @param {number} maxWaitMinutes
+ */
+async function checkQueue(maxDepth, pollSeconds, maxWaitMinutes) {
+  const { owner, repo } = context.repo;
+  const workflowId = resolveWorkflowId();
+  const { data: current } = await github.rest.actions.getWorkflowRun({ owner, repo, run_id: context.runId });
+
+  let ahead = await listRunsAhead(workflowId, current);
+  // The first run ahead is running, the others are waiting like this one
+  if (ahead.length > maxDepth) {
+    core.warning(`⚠️ Queue of workflow '${workflowId}' is full: ${ahead.length} run(s) ahead, max-depth is ${maxDepth}. Skipping this run.`);
+    core.setOutput("concurrency_policy_ok", "false");
+    return;
+  }
+
+  const deadline = Date.now() + maxWaitMinutes * 60 * 1000;
+  while (ahead.length > 0) {
+    if (Date.now() >= deadline) {
+      core.setFailed(`Timed out after ${maxWaitMinutes} minute(s) waiting for ${ahead.length} run(s) ahead in the queue of workflow '${workflowId}': ${ahead.map(run => run.id).join(", ")}. Increase concurrency.max-wait-minutes or check for stuck runs.`);
+      core.setOutput("concurrency_policy_ok", "false");
+      return;
+    }
+    core.info(`⏳ Waiting for ${ahead.length} run(s) ahead in the queue: ${ahead.map(run => run.id).join(", ")}`);
+    await sleep(pollSeconds * 1000);
+    ahead = await listRunsAhead(workflowId, current);
+  }
+
+  core.info(`✅ No runs ahead in the queue, proceeding`);
+  core.setOutput("concurrency_policy_ok", "true");
+}
+
+/**
+ * Enforce the debounce policy
+ * @param {number} quietMinutes
+ */
+async function checkDebounce(quietMinutes) {
+  core.info(`⏳ Waiting ${quietMinutes} minute(s) for newer events; a newer event cancels this run`);
+  await sleep(quietMinutes * 60 * 1000);
+  core.info(`✅ No newer event within ${quietMinutes} minute(s), proceeding`);
+  core.setOutput("concurrency_policy_ok", "true");
+}
+
+async function main() {
+  const policy = process.env.GH_AW_CONCURRENCY_POLICY || "";
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME || "workflow";
+  const pollSeconds = parseInt(process.env.GH_AW_CONCURRENCY_POLL_SECONDS || "30", 10);
+
+  core.info(`🔍 Checking concurrency policy '${policy}' for workflow '${workflowName}'`);
+
+  try {
+    switch (policy) {
+      case "queue":
+        await checkQueue(parseInt(process.env.GH_AW_CONCURRENCY_MAX_DEPTH || "5", 10), pollSeconds, parseInt(process.env.GH_AW_CONCURRENCY_MAX_WAIT_MINUTES || "60", 10));
+        break;
+      case "debounce":
+        await checkDebounce(parseInt(process.env.GH_AW_CONCURRENCY_QUIET_MINUTES || "5", 10));
+        break;
+      default:
+        core.warning(`⚠️ Unknown concurrency policy '${policy}', allowing the run to proceed`);
+        core.setOutput("concurrency_policy_ok", "true");
+    }
+  } catch (error) {
+    // On error, allow the workflow to proceed (fail-open) like the rate limit check
+    const errorMsg = error instanceof Error ? error.message : String(error);
+    core.warning(`⚠️ Concurrency policy check failed, allowing the run to proceed: ${errorMsg}`);
+    core.setOutput("concurrency_policy_ok", "true");
+  }
+}
+
+module.exports = { main, runsAhead };
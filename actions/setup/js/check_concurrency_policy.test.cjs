@@ -0,0 +1,143 @@
+// @ts-check
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+describe("check_concurrency_policy", () => {
+  let mockCore;
+  let mockGithub;
+  let checkConcurrencyPolicy;
+
+  const currentRun = { id: 300, status: "in_progress", created_at: "2026-03-01T10:05:00Z" };
+
+  beforeEach(async () => {
+    mockCore = {
+      info: vi.fn(),
+      warning: vi.fn(),
+      error: vi.fn(),
+      setOutput: vi.fn(),
+      setFailed: vi.fn(),
+    };
+
+    mockGithub = {
+      rest: {
+        actions: {
+          getWorkflowRun: vi.fn().mockResolvedValue({ data: currentRun }),
+          listWorkflowRuns: vi.fn(),
+        },
+      },
+    };
+
+    global.core = mockCore;
+    global.github = mockGithub;
+    global.context = {
+      repo: { owner: "test-owner", repo: "test-repo" },
+      workflow: "triage",
+      runId: 300,
+    };
+
+    process.env.GITHUB_WORKFLOW_REF = "test-owner/test-repo/.github/workflows/triage.lock.yml@refs/heads/main";
+    process.env.GH_AW_CONCURRENCY_POLL_SECONDS = "0";
+    delete process.env.GH_AW_CONCURRENCY_POLICY;
+    delete process.env.GH_AW_CONCURRENCY_MAX_DEPTH;
+    delete process.env.GH_AW_CONCURRENCY_MAX_WAIT_MINUTES;
+    delete process.env.GH_AW_CONCURRENCY_QUIET_MINUTES;
+
+    vi.resetModules();
+    checkConcurrencyPolicy = await import("./check_concurrency_policy.cjs");
+  });
+
+  describe("runsAhead", () => {
+    it("should select in-flight runs created before the current run", () => {
+      const runs = [
+        { id: 100, status: "in_progress", created_at: "2026-03-01T10:00:00Z" },
+        { id: 150, status: "completed", created_at: "2026-03-01T10:01:00Z" },
+        { id: 200, status: "queued", created_at: "2026-03-01T10:05:00Z" },
+        currentRun,
+        { id: 400, status: "queued", created_at: "2026-03-01T10:05:00Z" },
+        { id: 500, status: "queued", created_at: "2026-03-01T10:06:00Z" },
+      ];
+
+      const ahead = checkConcurrencyPolicy.runsAhead(runs, currentRun);
+
+      expect(ahead.map(run => run.id)).toEqual([100, 200]);
+    });
+  });
+
+  describe("queue", () => {
+    beforeEach(() => {
+      process.env.GH_AW_CONCURRENCY_POLICY = "queue";
+      process.env.GH_AW_CONCURRENCY_MAX_DEPTH = "1";
+    });
+
+    it("should proceed immediately when no runs are ahead", async () => {
+      mockGithub.rest.actions.listWorkflowRuns.mockResolvedValue({ data: { workflow_runs: [currentRun] } });
+
+      await checkConcurrencyPolicy.main();
+
+      for (const status of ["queued", "in_progress", "waiting"]) {
+        expect(mockGithub.rest.actions.listWorkflowRuns).toHaveBeenCalledWith(expect.objectContaining({ workflow_id: "triage.lock.yml", status, created: "<=2026-03-01T10:05:00Z" }));
+      }
+      expect(mockGithub.rest.actions.listWorkflowRuns).toHaveBeenCalledTimes(3);
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "true");
+    });
+
+    it("should wait until the runs ahead have completed", async () => {
+      const running = { id: 100, status: "in_progress", created_at: "2026-03-01T10:00:00Z" };
+      let polls = 0;
+      mockGithub.rest.actions.listWorkflowRuns.mockImplementation(async ({ status }) => {
+        polls++;
+        // The run ahead completes after the first listing (3 queries)
+        return { data: { workflow_runs: status === "in_progress" && polls <= 3 ? [running, currentRun] : [] } };
+      });
+
+      await checkConcurrencyPolicy.main();
+
+      expect(mockGithub.rest.actions.listWorkflowRuns).toHaveBeenCalledTimes(6);
+      expect(mockCore.info).toHaveBeenCalledWith(expect.stringContaining("Waiting for 1 run(s) ahead"));
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "true");
+    });
+
+    it("should fail the run when the runs ahead exceed max-wait-minutes", async () => {
+      process.env.GH_AW_CONCURRENCY_MAX_WAIT_MINUTES = "0";
+      mockGithub.rest.actions.listWorkflowRuns.mockResolvedValue({ data: { workflow_runs: [{ id: 100, status: "in_progress", created_at: "2026-03-01T10:00:00Z" }, currentRun] } });
+
+      await checkConcurrencyPolicy.main();
+
+      expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("Timed out after 0 minute(s) waiting for 1 run(s) ahead"));
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "false");
+    });
+
+    it("should skip the run when the queue is full", async () => {
+      mockGithub.rest.actions.listWorkflowRuns.mockResolvedValue({
+        data: {
+          workflow_runs: [{ id: 100, status: "in_progress", created_at: "2026-03-01T10:00:00Z" }, { id: 200, status: "pending", created_at: "2026-03-01T10:01:00Z" }, currentRun],
+        },
+      });
+
+      await checkConcurrencyPolicy.main();
+
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Queue of workflow 'triage.lock.yml' is full"));
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "false");
+    });
+
+    it("should allow the run when the API fails", async () => {
+      mockGithub.rest.actions.getWorkflowRun.mockRejectedValue(new Error("API error"));
+
+      await checkConcurrencyPolicy.main();
+
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("API error"));
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "true");
+    });
+  });
+
+  describe("debounce", () => {
+    it("should proceed after the quiet period", async () => {
+      process.env.GH_AW_CONCURRENCY_POLICY = "debounce";
+      process.env.GH_AW_CONCURRENCY_QUIET_MINUTES = "0";
+
+      await checkConcurrencyPolicy.main();
+
+      expect(mockGithub.rest.actions.listWorkflowRuns).not.toHaveBeenCalled();
+      expect(mockCore.setOutput).toHaveBeenCalledWith("concurrency_policy_ok", "true");
+    });
+  });
+});
@@ -0,0 +1,91 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { ERR_CONFIG, ERR_PARSE } = require("./error_codes.cjs");
+
+/**
+ * @typedef {Object} ScheduleCalendar
+ * @property {string} [timezone] - IANA timezone in which dates are evaluated (default UTC)
+ * @property {Array<{date: string, reason?: string}>} [skip_dates] - Dates (YYYY-MM-DD) on which scheduled runs are skipped
+ * @property {Array<{start: string, end: string, reason?: string}>} [blackouts] - Windows in which scheduled runs are skipped
+ */
+
+const DATE_PATTERN = /^\d{4}-\d{2}-\d{2}$/;
+
+/**
+ * Format a time as a YYYY-MM-DD date in a timezone
+ * @param {Date} time
+ * @param {string} timeZone
+ * @returns {string}
+ */
+function formatLocalDate(time, timeZone) {
+  // The en-CA locale formats dates as YYYY-MM-DD
+  return new Intl.DateTimeFormat("en-CA", { timeZone, year: "numeric", month: "2-digit", day: "2-digit" }).format(time);
+}
+
+/**
+ * Find the exclusion that applies at the given time
+ * @param {ScheduleCalendar} calendar
+ * @param {Date} now
+ * @returns {string | null} The reason the run is skipped, or null when the run may proceed
+ */
+function findScheduleExclusion(calendar, now) {
+  const timeZone = calendar.timezone || "UTC";
+  const today = formatLocalDate(now, timeZone);
+
+  for (const skipDate of calendar.skip_dates || []) {
+    if (skipDate.date === today) {
+      return `${today} is an excluded date${skipDate.reason ? ` (${skipDate.reason})` : ""}`;
+    }
+  }
+
+  for (const blackout of calendar.blackouts || []) {
+    const inWindow = DATE_PATTERN.test(blackout.start) ? today >= blackout.start && today <= blackout.end : now >= new Date(blackout.start) && now <= new Date(blackout.end);
+    if (inWindow) {
+      return `blackout window ${blackout.start} to ${blackout.end}${blackout.reason ? ` (${blackout.reason})` : ""}`;
+    }
+  }
+  return null;
+}
+
+/**
+ * Skip scheduled runs on excluded dates and inside blackout windows.
+ * Runs triggered by other events (e.g. workflow_dispatch) always proceed.
+ */
+async function main() {
+  const calendarJSON = process.env.GH_AW_SCHEDULE_CALENDAR;
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME || "workflow";
+
+  if (context.eventName !== "schedule") {
+    core.info(`✅ Event '${context.eventName}' is not a scheduled run, schedule calendar does not apply`);
+    core.setOutput("schedule_calendar_ok", "true");
+    return;
+  }
+
+  if (!calendarJSON) {
+    core.setFailed(`${ERR_CONFIG}: Configuration error: GH_AW_SCHEDULE_CALENDAR not specified.`);
+    return;
+  }
+
+  /** @type {ScheduleCalendar} */
+  let calendar;
+  try {
+    calendar = JSON.parse(calendarJSON);
+  } catch (error) {
+    core.setFailed(`${ERR_PARSE}: Invalid GH_AW_SCHEDULE_CALENDAR: ${error instanceof Error ? error.message : String(error)}`);
+    return;
+  }
+
+  const exclusion = findScheduleExclusion(calendar, new Date());
+  if (!exclusion) {
+    core.info(`✅ ${formatLocalDate(new Date(), calendar.timezone || "UTC")} is not excluded by the schedule calendar, workflow will proceed`);
+    core.setOutput("schedule_calendar_ok", "true");
+    return;
+  }
+
+  core.warning(`⏸️ Scheduled run of ${workflowName} skipped: ${exclusion}`);
+  core.setOutput("schedule_calendar_ok", "false");
+  await core.summary.addRaw("### ⏸️ Scheduled run skipped\n\n").addRaw(`The scheduled run of **${workflowName}** was skipped: ${exclusion}.\n`).write();
+}
+
+module.exports = { main, findScheduleExclusion, formatLocalDate };
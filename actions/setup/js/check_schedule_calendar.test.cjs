@@ -0,0 +1,111 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+
+describe("check_schedule_calendar.cjs", () => {
+  let mockCore;
+  let mockContext;
+
+  const calendar = {
+    timezone: "Europe/Berlin",
+    skip_dates: [{ date: "2026-12-25", reason: "Christmas Day" }],
+    blackouts: [
+      { start: "2026-12-28", end: "2027-01-03", reason: "Release freeze" },
+      { start: "2026-11-10T18:00:00Z", end: "2026-11-11T06:00:00Z" },
+    ],
+  };
+
+  beforeEach(() => {
+    mockCore = {
+      info: vi.fn(),
+      warning: vi.fn(),
+      setFailed: vi.fn(),
+      setOutput: vi.fn(),
+      summary: {
+        addRaw: vi.fn().mockReturnThis(),
+        write: vi.fn().mockResolvedValue(undefined),
+      },
+    };
+    mockContext = { eventName: "schedule" };
+
+    global.core = mockCore;
+    global.context = mockContext;
+    process.env.GH_AW_SCHEDULE_CALENDAR = JSON.stringify(calendar);
+    process.env.GH_AW_WORKFLOW_NAME = "Daily Report";
+
+    vi.resetModules();
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+    vi.clearAllMocks();
+    delete global.core;
+    delete global.context;
+    delete process.env.GH_AW_SCHEDULE_CALENDAR;
+    delete process.env.GH_AW_WORKFLOW_NAME;
+  });
+
+  describe("findScheduleExclusion", () => {
+    it("should match skip dates in the calendar timezone", async () => {
+      const { findScheduleExclusion } = await import("./check_schedule_calendar.cjs");
+
+      // 23:30 UTC on Dec 24 is already Dec 25 in Berlin
+      expect(findScheduleExclusion(calendar, new Date("2026-12-24T23:30:00Z"))).toBe("2026-12-25 is an excluded date (Christmas Day)");
+      expect(findScheduleExclusion(calendar, new Date("2026-12-24T12:00:00Z"))).toBeNull();
+    });
+
+    it("should match date blackout windows inclusively", async () => {
+      const { findScheduleExclusion } = await import("./check_schedule_calendar.cjs");
+
+      expect(findScheduleExclusion(calendar, new Date("2027-01-03T12:00:00Z"))).toBe("blackout window 2026-12-28 to 2027-01-03 (Release freeze)");
+      expect(findScheduleExclusion(calendar, new Date("2027-01-04T12:00:00Z"))).toBeNull();
+    });
+
+    it("should match timestamp blackout windows", async () => {
+      const { findScheduleExclusion } = await import("./check_schedule_calendar.cjs");
+
+      expect(findScheduleExclusion(calendar, new Date("2026-11-11T01:00:00Z"))).toContain("blackout window 2026-11-10T18:00:00Z");
+      expect(findScheduleExclusion(calendar, new Date("2026-11-11T07:00:00Z"))).toBeNull();
+    });
+  });
+
+  it("should skip scheduled runs on excluded dates", async () => {
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-12-25T09:00:00Z"));
+
+    const { main } = await import("./check_schedule_calendar.cjs");
+    await main();
+
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_calendar_ok", "false");
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Scheduled run of Daily Report skipped"));
+    expect(mockCore.summary.write).toHaveBeenCalled();
+  });
+
+  it("should allow scheduled runs on other dates", async () => {
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-12-22T09:00:00Z"));
+
+    const { main } = await import("./check_schedule_calendar.cjs");
+    await main();
+
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_calendar_ok", "true");
+  });
+
+  it("should allow runs that are not scheduled", async () => {
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-12-25T09:00:00Z"));
+    mockContext.eventName = "workflow_dispatch";
+
+    const { main } = await import("./check_schedule_calendar.cjs");
+    await main();
+
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_calendar_ok", "true");
+  });
+
+  it("should fail on invalid configuration", async () => {
+    process.env.GH_AW_SCHEDULE_CALENDAR = "{not json";
+
+    const { main } = await import("./check_schedule_calendar.cjs");
+    await main();
+
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("ERR_PARSE"));
+  });
+});
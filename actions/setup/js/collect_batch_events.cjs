@@ -0,0 +1,58 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Adds the pending events of a batched workflow to the prompt. At most GH_AW_BATCH_MAX_EVENTS
+ * events are collected, oldest first; the others stay pending for the next flush. The paths of
+ * the collected events are output for the batch_flush job, which removes them from the batch
+ * branch once the agent job succeeded, so this step only reads the branch.
+ */
+
+const fs = require("fs");
+const { listPendingEvents, readEvents } = require("./batch_events_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+/**
+ * Render the batched events section of the prompt
+ * @param {Array<Record<string, any>>} events
+ * @param {number} remaining - Number of events left for the next flush
+ * @returns {string}
+ */
+function renderBatchedEvents(events, remaining) {
+  const lines = ["", "<batched-events>", `This run processes ${events.length} event(s) accumulated since the previous run, oldest first. Each line is a JSON object describing one event.`];
+  if (remaining > 0) {
+    lines.push(`${remaining} more event(s) are pending and will be processed by the next run.`);
+  }
+  lines.push("");
+  for (const event of events) {
+    lines.push(JSON.stringify(event));
+  }
+  lines.push("</batched-events>", "");
+  return lines.join("\n");
+}
+
+async function main() {
+  const branch = process.env.GH_AW_BATCH_BRANCH || "";
+  const promptPath = process.env.GH_AW_PROMPT || "";
+  const maxEvents = parseInt(process.env.GH_AW_BATCH_MAX_EVENTS || "100", 10);
+
+  let paths;
+  try {
+    paths = await listPendingEvents(branch);
+  } catch (error) {
+    core.warning(`⚠️ Failed to list batched events on ${branch}: ${getErrorMessage(error)}`);
+    return;
+  }
+  if (paths.length === 0) {
+    core.info(`No batched events pending on ${branch}`);
+    return;
+  }
+
+  const collected = paths.slice(0, maxEvents);
+  const events = await readEvents(branch, collected);
+  fs.appendFileSync(promptPath, renderBatchedEvents(events, paths.length - collected.length));
+  core.info(`✅ Added ${events.length} batched event(s) to the prompt`);
+  core.setOutput("paths", JSON.stringify(collected));
+}
+
+module.exports = { main, renderBatchedEvents };
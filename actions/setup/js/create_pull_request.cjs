@@ -19,6 +19,7 @@ const { generateFooterWithMessages } = require("./messages_footer.cjs");
 const { normalizeBranchName } = require("./normalize_branch_name.cjs");
 const { pushExtraEmptyCommit } = require("./extra_empty_commit.cjs");
 const { getBaseBranch } = require("./get_base_branch.cjs");
+const { getPathPolicy, findPathViolations, dropPathViolations, formatPathViolations } = require("./patch_path_policy.cjs");
 
 /**
  * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
@@ -85,6 +86,66 @@ function generatePatchPreview(patchContent) {
   return `\n\n<details><summary>${summary}</summary>\n\n\`\`\`diff\n${preview}${truncated ? "\n... (truncated)" : ""}\n\`\`\`\n\n</details>`;
 }
 
+/**
+ * Creates an issue instead of a pull request when the patch changes files outside the
+ * allowed-paths / protected-paths policy and if-path-violation is "issue"
+ * @param {Object} params
+ * @param {{owner: string, repo: string}} params.repoParts - Target repository
+ * @param {string} params.title - Pull request title
+ * @param {string} params.body - Pull request body
+ * @param {string[]} params.labels - Labels
+ * @param {import('./patch_path_policy.cjs').PathViolation[]} params.violations - Offending files
+ * @param {string} params.patchContent - Patch content
+ * @param {string} params.runUrl - Workflow run URL
+ * @returns {Promise<Object>} Handler result
+ */
+async function createPathPolicyFallbackIssue({ repoParts, title, body, labels, violations, patchContent, runUrl }) {
+  const fallbackBody = `${body}
+
+---
+
+> [!WARNING]
+> This was originally intended as a pull request, but the patch changes files that the workflow is not allowed to change:
+>
+${formatPathViolations(violations)
+  .split("\n")
+  .map(line => `> ${line}`)
+  .join("\n")}
+>
+> **Workflow Run:** [View run details and download patch artifact](${runUrl})
+${generatePatchPreview(patchContent)}`;
+
+  const { data: issue } = await github.rest.issues.create({
+    owner: repoParts.owner,
+    repo: repoParts.repo,
+    title,
+    body: fallbackBody,
+    labels,
+  });
+  core.info(`Created fallback issue #${issue.number} for protected paths: ${issue.html_url}`);
+
+  await updateActivationComment(github, context, core, issue.html_url, issue.number, "issue");
+  await core.summary
+    .addRaw(
+      `
+
+## Protected Paths Fallback
+- **Offending Files:** ${violations.map(v => `\`${v.path}\``).join(", ")}
+- **Fallback Issue:** [#${issue.number}](${issue.html_url})
+`
+    )
+    .write();
+
+  return {
+    success: true,
+    fallback_used: true,
+    path_violation: true,
+    issue_number: issue.number,
+    issue_url: issue.html_url,
+    repo: `${repoParts.owner}/${repoParts.repo}`,
+  };
+}
+
 /**
  * Main handler factory for create_pull_request
  * Returns a message handler function that processes individual create_pull_request messages
@@ -120,6 +181,7 @@ async function main(config = {}) {
 
   const includeFooter = parseBoolTemplatable(config.footer, true);
   const fallbackAsIssue = config.fallback_as_issue !== false; // Default to true (fallback enabled)
+  const pathPolicy = getPathPolicy(config);
 
   // Environment validation - fail early if required variables are missing
   const workflowId = process.env.GH_AW_WORKFLOW_ID;
@@ -153,6 +215,11 @@ async function main(config = {}) {
   }
   core.info(`Max count: ${maxCount}`);
   core.info(`Max patch size: ${maxSizeKb} KB`);
+  if (pathPolicy) {
+    core.info(`Allowed paths: ${pathPolicy.allowedPaths.join(", ") || "(all)"}`);
+    core.info(`Protected paths: ${pathPolicy.protectedPaths.join(", ") || "(none)"}`);
+    core.info(`If path violation: ${pathPolicy.action}`);
+  }
 
   // Track how many items we've processed for max limit
   let processedCount = 0;
@@ -337,6 +404,26 @@ async function main(config = {}) {
       }
     }
 
+    // Enforce the allowed-paths / protected-paths policy on the files changed by the patch
+    /** @type {import('./patch_path_policy.cjs').PathViolation[]} */
+    let issuePathViolations = [];
+    if (pathPolicy && !isEmpty) {
+      const violations = findPathViolations(patchContent, pathPolicy);
+      if (violations.length > 0) {
+        core.warning(`Patch changes ${violations.length} file(s) outside the path policy:\n${formatPathViolations(violations)}`);
+        if (pathPolicy.action === "drop") {
+          patchContent = dropPathViolations(patchContent, pathPolicy);
+          isEmpty = !patchContent.trim();
+          fs.writeFileSync(patchFilePath, patchContent, "utf8");
+          core.info(`Dropped ${violations.length} file(s) from the patch`);
+        } else if (pathPolicy.action === "issue") {
+          issuePathViolations = violations;
+        } else {
+          return { success: false, error: `Patch changes files outside the path policy: ${violations.map(v => v.path).join(", ")}` };
+        }
+      }
+    }
+
     // Validate patch size (unless empty)
     if (!isEmpty) {
       // maxSizeKb is already extracted from config at the top
@@ -402,6 +489,10 @@ async function main(config = {}) {
       summaryContent += `**Branch:** ${pullRequestItem.branch || "auto-generated"}\n\n`;
       summaryContent += `**Base:** ${baseBranch}\n\n`;
 
+      if (issuePathViolations.length > 0) {
+        summaryContent += `**Path policy:** ⚠️ An issue would be created instead because the patch changes protected files:\n${formatPathViolations(issuePathViolations)}\n\n`;
+      }
+
       if (pullRequestItem.body) {
         summaryContent += `**Body:**\n${pullRequestItem.body}\n\n`;
       }
@@ -537,6 +628,17 @@ async function main(config = {}) {
     core.info(`Draft: ${draft}`);
     core.info(`Body length: ${body.length}`);
 
+    if (issuePathViolations.length > 0) {
+      core.warning("Patch changes files outside the path policy - creating fallback issue instead of pull request");
+      try {
+        return await createPathPolicyFallbackIssue({ repoParts, title, body, labels, violations: issuePathViolations, patchContent, runUrl });
+      } catch (issueError) {
+        const error = `Patch changes files outside the path policy and the fallback issue could not be created: ${getErrorMessage(issueError)}`;
+        core.error(error);
+        return { success: false, error };
+      }
+    }
+
     const randomHex = crypto.randomBytes(8).toString("hex");
     // Use branch name from JSONL if provided, otherwise generate unique branch name
     if (!branchName) {
@@ -0,0 +1,145 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const fs = require("fs");
+const path = require("path");
+const { loadAgentOutput } = require("./load_agent_output.cjs");
+const { validateRequiredFields, validateInputValues } = require("./safe_inputs_validation.cjs");
+const { parseAllowedRepos, validateTargetRepo } = require("./repo_helpers.cjs");
+const { generateStagedPreview } = require("./staged_preview.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_CONFIG, ERR_VALIDATION } = require("./error_codes.cjs");
+
+/**
+ * Directory where the accepted items of a custom safe-output type are written
+ * so that step handlers can read them
+ */
+const CUSTOM_OUTPUT_ITEMS_DIR = "/tmp/gh-aw/safeoutputs";
+
+/**
+ * Filter and validate the agent output items of a custom safe-output type.
+ * Items are checked against the type's input schema and, when they carry a repo
+ * field, against the current repository and the configured allowed-repos. When
+ * allowed-repos is configured, items without a repo target the current repository.
+ *
+ * @param {any[]} items - All agent output items
+ * @param {string} type - Normalized custom type name
+ * @param {Object|null} schema - The tool input schema, or null to skip schema validation
+ * @param {string} defaultRepo - The current repository slug (always allowed)
+ * @param {Set<string>} allowedRepos - Additional allowed repository patterns
+ * @returns {{accepted: any[], rejected: string[]}}
+ */
+function prepareCustomOutputItems(items, type, schema, defaultRepo, allowedRepos) {
+  /** @type {any[]} */
+  const accepted = [];
+  /** @type {string[]} */
+  const rejected = [];
+
+  items
+    .filter(item => item && item.type === type)
+    .forEach((item, index) => {
+      const label = `${type} item ${index + 1}`;
+
+      if (schema) {
+        const missing = validateRequiredFields(item, schema);
+        const errors = [...missing.map(field => `missing required field '${field}'`), ...validateInputValues(item, schema)];
+        if (errors.length > 0) {
+          rejected.push(`${label}: ${errors.join("; ")}`);
+          return;
+        }
+      }
+
+      if (item.repo !== undefined && item.repo !== null && item.repo !== "") {
+        const repoValidation = validateTargetRepo(String(item.repo).trim(), defaultRepo, allowedRepos);
+        if (!repoValidation.valid) {
+          rejected.push(`${label}: ${repoValidation.error}`);
+          return;
+        }
+        item = { ...item, repo: repoValidation.qualifiedRepo };
+      } else if (allowedRepos.size > 0) {
+        // Handlers of cross-repo types can always rely on item.repo
+        item = { ...item, repo: defaultRepo };
+      }
+
+      accepted.push(item);
+    });
+
+  return { accepted, rejected };
+}
+
+/**
+ * Process the agent output items of a custom safe-output type.
+ *
+ * Accepted items are written to a JSON file exposed as the `items_file` output, and
+ * the number of items is exposed as `count` so that step handlers can be skipped when
+ * there is nothing to do. When a script handler is provided it is called once per item.
+ *
+ * @param {(item: any) => Promise<void>} [handler] - Optional per-item handler (script mode)
+ * @returns {Promise<void>}
+ */
+async function main(handler) {
+  const type = process.env.GH_AW_CUSTOM_OUTPUT_TYPE || "";
+  if (!type) {
+    core.setFailed(`${ERR_CONFIG}: GH_AW_CUSTOM_OUTPUT_TYPE is not set`);
+    return;
+  }
+
+  let schema = null;
+  if (process.env.GH_AW_CUSTOM_OUTPUT_SCHEMA) {
+    try {
+      schema = JSON.parse(process.env.GH_AW_CUSTOM_OUTPUT_SCHEMA);
+    } catch (error) {
+      core.setFailed(`${ERR_CONFIG}: Invalid GH_AW_CUSTOM_OUTPUT_SCHEMA: ${getErrorMessage(error)}`);
+      return;
+    }
+  }
+
+  const defaultRepo = `${context.repo.owner}/${context.repo.repo}`;
+  const allowedRepos = parseAllowedRepos(process.env.GH_AW_CUSTOM_OUTPUT_ALLOWED_REPOS || "");
+
+  const result = loadAgentOutput();
+  const { accepted, rejected } = prepareCustomOutputItems(result.success ? result.items : [], type, schema, defaultRepo, allowedRepos);
+
+  for (const message of rejected) {
+    core.warning(`${ERR_VALIDATION}: Skipping ${message}`);
+  }
+  core.info(`Found ${accepted.length} ${type} item(s) to process`);
+
+  fs.mkdirSync(CUSTOM_OUTPUT_ITEMS_DIR, { recursive: true });
+  const itemsFile = path.join(CUSTOM_OUTPUT_ITEMS_DIR, `custom_${type}_items.json`);
+  fs.writeFileSync(itemsFile, JSON.stringify(accepted, null, 2));
+  core.setOutput("items_file", itemsFile);
+
+  if (process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true" && accepted.length > 0) {
+    await generateStagedPreview({
+      title: type,
+      description: `The following ${type} items would be processed if staged mode was disabled:`,
+      items: accepted,
+      renderItem: (item, index) => `#### Item ${index + 1}\n\n\`\`\`json\n${JSON.stringify(item, null, 2)}\n\`\`\`\n\n`,
+    });
+    core.setOutput("count", "0");
+    return;
+  }
+
+  core.setOutput("count", String(accepted.length));
+  if (!handler) {
+    return;
+  }
+
+  /** @type {string[]} */
+  const failures = [];
+  for (let i = 0; i < accepted.length; i++) {
+    try {
+      await handler(accepted[i]);
+      core.info(`✓ Processed ${type} item ${i + 1}/${accepted.length}`);
+    } catch (error) {
+      failures.push(`${type} item ${i + 1}: ${getErrorMessage(error)}`);
+    }
+  }
+
+  if (failures.length > 0) {
+    core.setFailed(`Failed to process ${failures.length} ${type} item(s):\n${failures.join("\n")}`);
+  }
+}
+
+module.exports = { main, prepareCustomOutputItems };
@@ -0,0 +1,125 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+  summary: {
+    addRaw: vi.fn().mockReturnThis(),
+    write: vi.fn().mockResolvedValue(),
+  },
+};
+global.core = mockCore;
+global.context = { repo: { owner: "octo", repo: "app" } };
+
+const { main, prepareCustomOutputItems } = await import("./custom_safe_output.cjs");
+
+const schema = {
+  type: "object",
+  properties: {
+    environment: { type: "string", enum: ["staging", "preview"] },
+    replicas: { type: "integer", minimum: 1 },
+    repo: { type: "string" },
+  },
+  required: ["environment"],
+};
+
+describe("custom_safe_output", () => {
+  describe("prepareCustomOutputItems", () => {
+    it("keeps valid items of the requested type only", () => {
+      const items = [
+        { type: "deploy", environment: "staging" },
+        { type: "add_comment", body: "hi" },
+      ];
+      const { accepted, rejected } = prepareCustomOutputItems(items, "deploy", schema, "octo/app", new Set());
+      expect(accepted).toEqual([{ type: "deploy", environment: "staging" }]);
+      expect(rejected).toEqual([]);
+    });
+
+    it("rejects items that violate the schema", () => {
+      const items = [{ type: "deploy", replicas: 0 }, { type: "deploy", environment: "prod" }];
+      const { accepted, rejected } = prepareCustomOutputItems(items, "deploy", schema, "octo/app", new Set());
+      expect(accepted).toEqual([]);
+      expect(rejected).toHaveLength(2);
+      expect(rejected[0]).toContain("missing required field 'environment'");
+      expect(rejected[0]).toContain("replicas");
+      expect(rejected[1]).toContain("deploy item 2");
+    });
+
+    it("validates target repositories against allowed-repos", () => {
+      const items = [
+        { type: "deploy", environment: "staging", repo: "site" },
+        { type: "deploy", environment: "staging", repo: "evil/repo" },
+        { type: "deploy", environment: "staging", repo: "octo/app" },
+      ];
+      const { accepted, rejected } = prepareCustomOutputItems(items, "deploy", schema, "octo/app", new Set(["octo/site"]));
+      expect(accepted.map(item => item.repo)).toEqual(["octo/site", "octo/app"]);
+      expect(rejected).toHaveLength(1);
+      expect(rejected[0]).toContain("not in the allowed-repos list");
+    });
+
+    it("defaults the repo of cross-repo types to the current repository", () => {
+      const { accepted } = prepareCustomOutputItems([{ type: "deploy", environment: "staging" }], "deploy", schema, "octo/app", new Set(["octo/site"]));
+      expect(accepted[0].repo).toBe("octo/app");
+    });
+  });
+
+  describe("main", () => {
+    let tmpDir;
+
+    beforeEach(() => {
+      vi.clearAllMocks();
+      tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "custom-safe-output-"));
+      const outputFile = path.join(tmpDir, "agent_output.json");
+      fs.writeFileSync(
+        outputFile,
+        JSON.stringify({
+          items: [
+            { type: "deploy", environment: "staging" },
+            { type: "deploy", environment: "preview" },
+          ],
+        })
+      );
+      process.env.GH_AW_AGENT_OUTPUT = outputFile;
+      process.env.GH_AW_CUSTOM_OUTPUT_TYPE = "deploy";
+      process.env.GH_AW_CUSTOM_OUTPUT_SCHEMA = JSON.stringify(schema);
+    });
+
+    afterEach(() => {
+      fs.rmSync(tmpDir, { recursive: true, force: true });
+      delete process.env.GH_AW_AGENT_OUTPUT;
+      delete process.env.GH_AW_CUSTOM_OUTPUT_TYPE;
+      delete process.env.GH_AW_CUSTOM_OUTPUT_SCHEMA;
+      delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    });
+
+    it("calls the handler once per item", async () => {
+      const handler = vi.fn().mockResolvedValue(undefined);
+      await main(handler);
+      expect(handler).toHaveBeenCalledTimes(2);
+      expect(handler.mock.calls[1][0].environment).toBe("preview");
+      expect(mockCore.setOutput).toHaveBeenCalledWith("count", "2");
+      expect(mockCore.setFailed).not.toHaveBeenCalled();
+    });
+
+    it("fails when the handler throws", async () => {
+      await main(async () => {
+        throw new Error("boom");
+      });
+      expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("Failed to process 2 deploy item(s)"));
+    });
+
+    it("previews items in staged mode without calling the handler", async () => {
+      process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+      const handler = vi.fn();
+      await main(handler);
+      expect(handler).not.toHaveBeenCalled();
+      expect(mockCore.setOutput).toHaveBeenCalledWith("count", "0");
+      expect(mockCore.summary.addRaw).toHaveBeenCalled();
+    });
+  });
+});
@@ -0,0 +1,37 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Removes the events processed by a successful flush run from the batch branch.
+ * GH_AW_BATCH_EVENTS holds the JSON array of event paths collected by the activation job.
+ */
+
+const { removeEvents } = require("./batch_events_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+async function main() {
+  const branch = process.env.GH_AW_BATCH_BRANCH || "";
+
+  /** @type {string[]} */
+  let paths;
+  try {
+    paths = JSON.parse(process.env.GH_AW_BATCH_EVENTS || "[]");
+  } catch (error) {
+    core.setFailed(`Failed to parse GH_AW_BATCH_EVENTS: ${getErrorMessage(error)}`);
+    return;
+  }
+  if (!Array.isArray(paths) || paths.length === 0) {
+    core.info("No batched events to remove");
+    return;
+  }
+
+  try {
+    await removeEvents(branch, paths);
+    core.info(`✅ Removed ${paths.length} processed event(s) from ${branch}`);
+  } catch (error) {
+    // The events stay pending and are processed again by the next flush
+    core.warning(`⚠️ Failed to remove batched events from ${branch}: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main };
@@ -31,7 +31,7 @@ const fs = require("fs");
 const path = require("path");
 
 const { ReadBuffer } = require("./read_buffer.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateInputValues, formatInvalidInputsMessage } = require("./safe_inputs_validation.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 
@@ -66,6 +66,7 @@ const encoder = new TextEncoder();
  * @property {string} [logDir] - Optional log directory
  * @property {string} [logFilePath] - Optional log file path
  * @property {boolean} logFileInitialized - Whether log file has been initialized
+ * @property {boolean} [validateInputs] - Whether tool arguments are checked against the typed constraints of the input schema
  */
 
 /**
@@ -187,6 +188,7 @@ function createReplyErrorFunction(server) {
  * @param {ServerInfo} serverInfo - Server information (name and version)
  * @param {Object} [options] - Optional server configuration
  * @param {string} [options.logDir] - Directory for log file (optional)
+ * @param {boolean} [options.validateInputs] - Check tool arguments against enum, type, pattern and range constraints (optional)
  * @returns {MCPServer} The MCP server instance
  */
 function createServer(serverInfo, options = {}) {
@@ -206,6 +208,7 @@ function createServer(serverInfo, options = {}) {
     logDir,
     logFilePath,
     logFileInitialized: false,
+    validateInputs: options.validateInputs === true,
   };
 
   // Initialize functions with references to server
@@ -629,6 +632,14 @@ async function handleRequest(server, request, defaultHandler) {
         };
       }
 
+      const invalid = server.validateInputs ? validateInputValues(args, tool.inputSchema) : [];
+      if (invalid.length) {
+        throw {
+          code: -32602,
+          message: formatInvalidInputsMessage(name, invalid),
+        };
+      }
+
       // Call handler and await the result (supports both sync and async handlers)
       const handlerResult = await Promise.resolve(handler(args));
       const content = handlerResult && handlerResult.content ? handlerResult.content : [];
@@ -751,6 +762,12 @@ async function handleMessage(server, req, defaultHandler) {
         return;
       }
 
+      const invalid = server.validateInputs ? validateInputValues(args, tool.inputSchema) : [];
+      if (invalid.length) {
+        server.replyError(id, -32602, formatInvalidInputsMessage(name, invalid));
+        return;
+      }
+
       // Call handler and await the result (supports both sync and async handlers)
       server.debug(`Calling handler for tool: ${name}`);
       const result = await Promise.resolve(handler(args));
@@ -225,6 +225,45 @@ describe("mcp_server_core.cjs", () => {
       expect(results[0].error.message).toContain("Example:");
     });
 
+    it("should reject arguments that violate typed constraints when input validation is enabled", async () => {
+      const { handleMessage, handleRequest, registerTool } = await import("./mcp_server_core.cjs");
+      server.validateInputs = true;
+      registerTool(server, {
+        name: "typed_tool",
+        description: "A typed tool",
+        inputSchema: {
+          type: "object",
+          properties: { level: { type: "string", enum: ["low", "high"] } },
+          required: ["level"],
+        },
+        handler: () => ({ content: [] }),
+      });
+      const call = { jsonrpc: "2.0", id: 1, method: "tools/call", params: { name: "typed_tool", arguments: { level: "medium" } } };
+
+      await handleMessage(server, call);
+      expect(results).toHaveLength(1);
+      expect(results[0].error.code).toBe(-32602);
+      expect(results[0].error.message).toContain("Invalid arguments for tool 'typed_tool'");
+      expect(results[0].error.message).toContain(`input 'level' must be one of ["low","high"]`);
+
+      const response = await handleRequest(server, call);
+      expect(response.error).toEqual(results[0].error);
+    });
+
+    it("should not check typed constraints when input validation is disabled", async () => {
+      const { handleMessage, registerTool } = await import("./mcp_server_core.cjs");
+      registerTool(server, {
+        name: "typed_tool",
+        description: "A typed tool",
+        inputSchema: { type: "object", properties: { level: { type: "string", enum: ["low", "high"] } } },
+        handler: () => ({ content: [] }),
+      });
+
+      await handleMessage(server, { jsonrpc: "2.0", id: 1, method: "tools/call", params: { name: "typed_tool", arguments: { level: "medium" } } });
+      expect(results).toHaveLength(1);
+      expect(results[0].result).toEqual({ content: [], isError: false });
+    });
+
     it("should return error for unknown method", async () => {
       const { handleMessage } = await import("./mcp_server_core.cjs");
 
@@ -6,7 +6,8 @@
  *
  * This module parses the threat detection results from the agent output file
  * and determines whether any security threats were detected (prompt injection,
- * secret leak, malicious patch). It sets the appropriate output and fails the
+ * secret leak, malicious patch), merges the findings of the deterministic scanners
+ * (see threat_detection_scanners.cjs), sets the appropriate output and fails the
  * workflow if threats are detected.
  */
 
@@ -16,6 +17,71 @@ const { getErrorMessage } = require("./error_helpers.cjs");
 const { listFilesRecursively } = require("./file_helpers.cjs");
 const { AGENT_OUTPUT_FILENAME } = require("./constants.cjs");
 const { ERR_SYSTEM, ERR_VALIDATION } = require("./error_codes.cjs");
+const { SCANNER_FINDINGS_FILENAME } = require("./threat_detection_scanners.cjs");
+
+/**
+ * @typedef {Object} ThreatVerdict
+ * @property {boolean} prompt_injection - Prompt injection detected
+ * @property {boolean} secret_leak - Secret leak detected
+ * @property {boolean} malicious_patch - Malicious patch detected
+ * @property {string[]} reasons - Reasons for the detected threats
+ * @property {any[]} [findings] - Findings of the deterministic scanners
+ * @property {string[]} [warnings] - Scanner findings that do not block safe outputs
+ */
+
+/**
+ * Scanners whose findings are reported as warnings. Editing workflows or adding dependencies
+ * is legitimate in many pull requests, so these findings never block safe outputs on their own.
+ */
+const WARNING_SCANNERS = new Set(["workflow-files", "dependencies"]);
+
+/**
+ * Merges the findings of the deterministic scanners into a verdict. Credential findings
+ * are reported as a secret leak, workflow file and dependency findings as warnings, and all
+ * other findings as a malicious patch.
+ * @param {ThreatVerdict} verdict - Verdict of the AI analysis
+ * @param {Array<{scanner: string, message: string}>} findings - Scanner findings
+ * @returns {ThreatVerdict} Merged verdict
+ */
+function mergeScannerFindings(verdict, findings) {
+  if (findings.length === 0) {
+    return verdict;
+  }
+  const merged = { ...verdict, reasons: [...(verdict.reasons || [])], warnings: [...(verdict.warnings || [])], findings };
+  for (const finding of findings) {
+    const reason = `[${finding.scanner}] ${finding.message}`;
+    if (WARNING_SCANNERS.has(finding.scanner)) {
+      merged.warnings.push(reason);
+      continue;
+    }
+    if (finding.scanner === "secrets") {
+      merged.secret_leak = true;
+    } else {
+      merged.malicious_patch = true;
+    }
+    merged.reasons.push(reason);
+  }
+  return merged;
+}
+
+/**
+ * Reads the findings written by the threat detection scanners, if they ran
+ * @param {string} dir - Threat detection directory
+ * @returns {Array<{scanner: string, message: string}>} Scanner findings
+ */
+function readScannerFindings(dir) {
+  const findingsPath = path.join(dir, SCANNER_FINDINGS_FILENAME);
+  if (!fs.existsSync(findingsPath)) {
+    return [];
+  }
+  try {
+    const parsed = JSON.parse(fs.readFileSync(findingsPath, "utf8"));
+    return Array.isArray(parsed.findings) ? parsed.findings : [];
+  } catch (error) {
+    core.warning("Failed to read threat detection scanner findings: " + getErrorMessage(error));
+    return [];
+  }
+}
 
 /**
  * Main entry point for parsing threat detection results
@@ -23,6 +89,7 @@ const { ERR_SYSTEM, ERR_VALIDATION } = require("./error_codes.cjs");
  */
 async function main() {
   // Parse threat detection results
+  /** @type {ThreatVerdict} */
   let verdict = { prompt_injection: false, secret_leak: false, malicious_patch: false, reasons: [] };
 
   try {
@@ -59,7 +126,12 @@ async function main() {
     core.warning("Failed to parse threat detection results: " + getErrorMessage(error));
   }
 
+  verdict = mergeScannerFindings(verdict, readScannerFindings("/tmp/gh-aw/threat-detection"));
+
   core.info("Threat detection verdict: " + JSON.stringify(verdict));
+  for (const warning of verdict.warnings || []) {
+    core.warning(`⚠️ Threat detection scanner: ${warning}`);
+  }
 
   // Fail if threats detected
   if (verdict.prompt_injection || verdict.secret_leak || verdict.malicious_patch) {
@@ -80,4 +152,4 @@ async function main() {
   }
 }
 
-module.exports = { main };
+module.exports = { main, mergeScannerFindings, readScannerFindings };
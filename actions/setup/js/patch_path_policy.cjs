@@ -0,0 +1,302 @@
+// @ts-check
+
+/**
+ * Patch Path Policy
+ *
+ * Enforces the allowed-paths and protected-paths policy of create-pull-request and
+ * push-to-pull-request-branch against the files changed by a patch. A file violates
+ * the policy when allowed-paths is set and the file matches none of its globs, or when
+ * the file matches one of the protected-paths globs. Renamed files are checked with
+ * both their old and new paths, and files whose paths cannot be parsed are violations.
+ */
+
+const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
+
+/**
+ * @typedef {Object} PathPolicy
+ * @property {string[]} allowedPaths - Globs of the files the patch may change (empty allows all)
+ * @property {string[]} protectedPaths - Globs of the files the patch must not change
+ * @property {string} action - What to do with violations: "reject", "drop" or "issue"
+ */
+
+/**
+ * @typedef {Object} PathViolation
+ * @property {string} path - Path of the offending file
+ * @property {string} reason - Why the file violates the policy
+ */
+
+/**
+ * Reads the path policy from a handler configuration
+ * @param {Object} config - Handler configuration
+ * @param {string[]} [config.allowed_paths] - Globs of the files the patch may change
+ * @param {string[]} [config.protected_paths] - Globs of the files the patch must not change
+ * @param {string} [config.if_path_violation] - Action on violations (default: "reject")
+ * @returns {PathPolicy | null} The policy, or null when no paths are configured
+ */
+function getPathPolicy(config) {
+  const allowedPaths = Array.isArray(config.allowed_paths) ? config.allowed_paths.map(String) : [];
+  const protectedPaths = Array.isArray(config.protected_paths) ? config.protected_paths.map(String) : [];
+  if (allowedPaths.length === 0 && protectedPaths.length === 0) {
+    return null;
+  }
+  return { allowedPaths, protectedPaths, action: config.if_path_violation || "reject" };
+}
+
+/**
+ * Checks whether a path matches a glob. Globs starting with "**\/" also match at the root.
+ * @param {string} filePath - Repository-relative file path
+ * @param {string} pattern - Glob pattern (e.g. ".github/**", "**\/CODEOWNERS")
+ * @returns {boolean} True if the path matches
+ */
+function matchesPathGlob(filePath, pattern) {
+  if (globPatternToRegex(pattern).test(filePath)) {
+    return true;
+  }
+  return pattern.startsWith("**/") && globPatternToRegex(pattern.substring(3)).test(filePath);
+}
+
+/** Single-character escapes git uses in C-quoted paths */
+const GIT_PATH_ESCAPES = { a: 7, b: 8, t: 9, n: 10, v: 11, f: 12, r: 13, '"': 34, "\\": 92 };
+
+/**
+ * Decodes a path as git writes it in patches. Paths with special or non-ASCII characters
+ * are C-quoted, e.g. "a/\303\251.yml"; other paths are written as is.
+ * @param {string} text - Path as written in the patch
+ * @returns {string | null} The decoded path, or null when the quoting is malformed
+ */
+function unquoteGitPath(text) {
+  if (!text.startsWith('"')) {
+    return text;
+  }
+  if (text.length < 2 || !text.endsWith('"')) {
+    return null;
+  }
+  const body = text.slice(1, -1);
+  /** @type {number[]} */
+  const bytes = [];
+  for (let i = 0; i < body.length; i++) {
+    const ch = body[i];
+    if (ch === '"') {
+      return null;
+    }
+    if (ch !== "\\") {
+      bytes.push(...Buffer.from(ch, "utf8"));
+      continue;
+    }
+    const next = body[++i];
+    if (next !== undefined && next in GIT_PATH_ESCAPES) {
+      bytes.push(GIT_PATH_ESCAPES[/** @type {keyof typeof GIT_PATH_ESCAPES} */ (next)]);
+    } else if (/^[0-3][0-7]{2}$/.test(body.slice(i, i + 3))) {
+      bytes.push(parseInt(body.slice(i, i + 3), 8));
+      i += 2;
+    } else {
+      return null;
+    }
+  }
+  return Buffer.from(bytes).toString("utf8");
+}
+
+/**
+ * Decodes a path and removes its a/ or b/ prefix
+ * @param {string} text - Path as written in the patch
+ * @param {string} prefix - Expected prefix ("a/" or "b/")
+ * @returns {string | null} The repository-relative path, or null when it cannot be parsed
+ */
+function stripGitPathPrefix(text, prefix) {
+  const path = unquoteGitPath(text);
+  return path !== null && path.startsWith(prefix) && path.length > prefix.length ? path.substring(prefix.length) : null;
+}
+
+/**
+ * Parses the two paths of a "diff --git" line
+ * @param {string} line - The diff --git line
+ * @returns {string[] | null} The old and new path, or null when they cannot be parsed
+ */
+function parseDiffGitHeader(line) {
+  const rest = line.substring("diff --git ".length);
+  let oldText;
+  let newText;
+  if (rest.startsWith('"')) {
+    // The old path is quoted: it ends at the first unescaped quote
+    let close = 1;
+    while (close < rest.length && rest[close] !== '"') {
+      close += rest[close] === "\\" ? 2 : 1;
+    }
+    if (rest[close + 1] !== " ") {
+      return null;
+    }
+    oldText = rest.substring(0, close + 1);
+    newText = rest.substring(close + 2);
+  } else {
+    const match = rest.match(/^(.+) ("b\/.*"|b\/.+)$/);
+    if (!match) {
+      return null;
+    }
+    oldText = match[1];
+    newText = match[2];
+  }
+  const oldPath = stripGitPathPrefix(oldText, "a/");
+  const newPath = stripGitPathPrefix(newText, "b/");
+  return oldPath !== null && newPath !== null ? [oldPath, newPath] : null;
+}
+
+/**
+ * Returns the files changed by each diff section of a patch, including both sides of renames.
+ * Paths are read from the diff --git line and the extended header (---/+++, rename and copy
+ * lines), decoding git's C-quoting.
+ * @param {string} section - A diff section starting with "diff --git"
+ * @returns {string[] | null} Paths changed by the section, or null when a path cannot be parsed
+ */
+function getSectionPaths(section) {
+  const lines = section.split("\n");
+  const header = parseDiffGitHeader(lines[0]);
+  if (!header) {
+    return null;
+  }
+  const paths = new Set(header);
+  // Only the extended header holds paths; hunk lines may look like "--- a/..." too
+  for (const line of lines.slice(1)) {
+    if (line.startsWith("@@") || line.startsWith("GIT binary patch") || line.startsWith("Binary files ")) {
+      break;
+    }
+    const match = line.match(/^(---|\+\+\+|rename from|rename to|copy to) (.+)$/);
+    if (!match || match[2] === "/dev/null") {
+      continue;
+    }
+    const [, kind, text] = match;
+    let path;
+    if (kind === "---" || kind === "+++") {
+      // git appends a tab to unquoted paths containing spaces
+      path = stripGitPathPrefix(text.startsWith('"') ? text : text.replace(/\t$/, ""), kind === "---" ? "a/" : "b/");
+    } else {
+      path = unquoteGitPath(text);
+    }
+    if (path === null) {
+      return null;
+    }
+    paths.add(path);
+  }
+  return [...paths];
+}
+
+/**
+ * Splits a patch into messages (one per commit for git format-patch output) and each
+ * message into its header and diff sections
+ * @param {string} patchContent - Patch content
+ * @returns {Array<{header: string, sections: string[]}>} Parsed messages
+ */
+function splitPatch(patchContent) {
+  const messages = patchContent.split(/^(?=From [0-9a-f]{40} )/m).filter(message => message.length > 0);
+  return messages.map(message => {
+    const parts = message.split(/^(?=diff --git )/m);
+    // A plain diff without a mail header starts directly with its first section
+    const header = parts[0].startsWith("diff --git ") ? "" : parts.shift() || "";
+    return { header, sections: parts };
+  });
+}
+
+/**
+ * Returns all files changed by a patch
+ * @param {string} patchContent - Patch content
+ * @returns {string[]} Unique paths in patch order
+ */
+function getPatchFilePaths(patchContent) {
+  const paths = new Set();
+  for (const { sections } of splitPatch(patchContent)) {
+    for (const section of sections) {
+      (getSectionPaths(section) || []).forEach(p => paths.add(p));
+    }
+  }
+  return [...paths];
+}
+
+/**
+ * Checks a path against the policy
+ * @param {string} filePath - Repository-relative file path
+ * @param {PathPolicy} policy - Path policy
+ * @returns {string | null} The reason the path violates the policy, or null
+ */
+function checkPath(filePath, policy) {
+  const protectedBy = policy.protectedPaths.find(pattern => matchesPathGlob(filePath, pattern));
+  if (protectedBy) {
+    return `matches protected path '${protectedBy}'`;
+  }
+  if (policy.allowedPaths.length > 0 && !policy.allowedPaths.some(pattern => matchesPathGlob(filePath, pattern))) {
+    return "is not in allowed-paths";
+  }
+  return null;
+}
+
+/**
+ * Finds the files of a patch that violate the policy. Files whose paths cannot be parsed
+ * are reported as violations.
+ * @param {string} patchContent - Patch content
+ * @param {PathPolicy} policy - Path policy
+ * @returns {PathViolation[]} Violations in patch order
+ */
+function findPathViolations(patchContent, policy) {
+  /** @type {PathViolation[]} */
+  const violations = [];
+  const seen = new Set();
+  for (const { sections } of splitPatch(patchContent)) {
+    for (const section of sections) {
+      const paths = getSectionPaths(section);
+      if (!paths) {
+        // Fail closed: a file whose path cannot be parsed cannot be checked
+        violations.push({ path: section.split("\n")[0].substring("diff --git ".length), reason: "has a path that could not be parsed" });
+        continue;
+      }
+      for (const filePath of paths) {
+        if (seen.has(filePath)) {
+          continue;
+        }
+        seen.add(filePath);
+        const reason = checkPath(filePath, policy);
+        if (reason) {
+          violations.push({ path: filePath, reason });
+        }
+      }
+    }
+  }
+  return violations;
+}
+
+/**
+ * Removes the diff sections that change offending files, or files whose paths cannot be
+ * parsed, from a patch. Commits left without changes are removed, so the result can still
+ * be applied with git am.
+ * @param {string} patchContent - Patch content
+ * @param {PathPolicy} policy - Path policy
+ * @returns {string} The filtered patch (empty when no changes remain)
+ */
+function dropPathViolations(patchContent, policy) {
+  const kept = [];
+  for (const { header, sections } of splitPatch(patchContent)) {
+    const remaining = sections.filter(section => {
+      const paths = getSectionPaths(section);
+      return paths !== null && paths.every(p => !checkPath(p, policy));
+    });
+    if (remaining.length > 0) {
+      kept.push(header + remaining.join(""));
+    }
+  }
+  return kept.join("");
+}
+
+/**
+ * Formats violations as a markdown list
+ * @param {PathViolation[]} violations - Violations
+ * @returns {string} Markdown list
+ */
+function formatPathViolations(violations) {
+  return violations.map(v => `- \`${v.path}\` ${v.reason}`).join("\n");
+}
+
+module.exports = {
+  getPathPolicy,
+  matchesPathGlob,
+  getPatchFilePaths,
+  findPathViolations,
+  dropPathViolations,
+  formatPathViolations,
+};
@@ -0,0 +1,144 @@
+import { describe, it, expect } from "vitest";
+
+const { getPathPolicy, matchesPathGlob, getPatchFilePaths, findPathViolations, dropPathViolations, formatPathViolations } = require("./patch_path_policy.cjs");
+
+/**
+ * Builds a git format-patch message changing the given files
+ * @param {string} sha
+ * @param {string} subject
+ * @param {string[]} files
+ */
+function formatPatchMessage(sha, subject, files) {
+  const sections = files.map(file => [`diff --git a/${file} b/${file}`, `--- a/${file}`, `+++ b/${file}`, "@@ -1 +1,2 @@", " x", "+y", ""].join("\n"));
+  return [`From ${sha} Mon Sep 17 00:00:00 2001`, `Subject: [PATCH] ${subject}`, "", "---", ...sections, "-- ", "2.43.0", "", ""].join("\n");
+}
+
+const PATCH = formatPatchMessage("a".repeat(40), "one", ["CODEOWNERS", "src/app.js"]) + formatPatchMessage("b".repeat(40), "two", [".github/workflows/ci.yml"]);
+
+// Sections as written by git format-patch for non-ASCII paths, paths with spaces and quotes, and renames
+const QUOTED_PATCH = [
+  'diff --git "a/.github/workflows/\\303\\251vil.yml" "b/.github/workflows/\\303\\251vil.yml"',
+  "new file mode 100644",
+  "--- /dev/null",
+  '+++ "b/.github/workflows/\\303\\251vil.yml"',
+  "@@ -0,0 +1 @@",
+  "+x",
+  "diff --git a/my file.txt b/my file.txt",
+  "new file mode 100644",
+  "--- /dev/null",
+  "+++ b/my file.txt\t",
+  "@@ -0,0 +1 @@",
+  "+y",
+  'diff --git "a/q\\"uote.txt" "b/q\\"uote.txt"',
+  "new file mode 100644",
+  "--- /dev/null",
+  '+++ "b/q\\"uote.txt"',
+  "@@ -0,0 +1 @@",
+  "+z",
+  "diff --git a/my file.txt b/new name.txt",
+  "similarity index 100%",
+  "rename from my file.txt",
+  "rename to new name.txt",
+  'diff --git "a/.github/workflows/\\303\\251vil.yml" "b/ren \\303\\251.yml"',
+  "similarity index 100%",
+  'rename from ".github/workflows/\\303\\251vil.yml"',
+  'rename to "ren \\303\\251.yml"',
+  "",
+].join("\n");
+
+describe("patch_path_policy.cjs", () => {
+  describe("getPathPolicy", () => {
+    it("should return null without configured paths", () => {
+      expect(getPathPolicy({})).toBeNull();
+      expect(getPathPolicy({ allowed_paths: [], protected_paths: [] })).toBeNull();
+    });
+
+    it("should default the action to reject", () => {
+      expect(getPathPolicy({ protected_paths: ["CODEOWNERS"] })).toEqual({ allowedPaths: [], protectedPaths: ["CODEOWNERS"], action: "reject" });
+      expect(getPathPolicy({ allowed_paths: ["src/**"], if_path_violation: "drop" })?.action).toBe("drop");
+    });
+  });
+
+  describe("matchesPathGlob", () => {
+    it("should match path globs", () => {
+      expect(matchesPathGlob(".github/workflows/ci.yml", ".github/**")).toBe(true);
+      expect(matchesPathGlob("src/app.js", "src/*.js")).toBe(true);
+      expect(matchesPathGlob("src/lib/app.js", "src/*.js")).toBe(false);
+    });
+
+    it("should match leading **/ at the repository root", () => {
+      expect(matchesPathGlob("CODEOWNERS", "**/CODEOWNERS")).toBe(true);
+      expect(matchesPathGlob("docs/CODEOWNERS", "**/CODEOWNERS")).toBe(true);
+      expect(matchesPathGlob("CODEOWNERS.md", "**/CODEOWNERS")).toBe(false);
+    });
+  });
+
+  describe("getPatchFilePaths", () => {
+    it("should list the files of all commits", () => {
+      expect(getPatchFilePaths(PATCH)).toEqual(["CODEOWNERS", "src/app.js", ".github/workflows/ci.yml"]);
+    });
+
+    it("should decode C-quoted paths", () => {
+      expect(getPatchFilePaths(QUOTED_PATCH)).toEqual([".github/workflows/évil.yml", "my file.txt", 'q"uote.txt', "new name.txt", "ren é.yml"]);
+    });
+
+    it("should include both sides of renames", () => {
+      const patch = ["diff --git a/docs/old.md b/.github/CODEOWNERS", "similarity index 100%", "rename from docs/old.md", "rename to .github/CODEOWNERS", ""].join("\n");
+      expect(getPatchFilePaths(patch)).toEqual(["docs/old.md", ".github/CODEOWNERS"]);
+    });
+  });
+
+  describe("findPathViolations", () => {
+    it("should report protected files", () => {
+      const policy = getPathPolicy({ protected_paths: ["**/CODEOWNERS", ".github/workflows/**"] });
+      expect(findPathViolations(PATCH, policy)).toEqual([
+        { path: "CODEOWNERS", reason: "matches protected path '**/CODEOWNERS'" },
+        { path: ".github/workflows/ci.yml", reason: "matches protected path '.github/workflows/**'" },
+      ]);
+    });
+
+    it("should report files outside allowed-paths", () => {
+      const policy = getPathPolicy({ allowed_paths: ["src/**", ".github/**"] });
+      expect(findPathViolations(PATCH, policy)).toEqual([{ path: "CODEOWNERS", reason: "is not in allowed-paths" }]);
+    });
+
+    it("should report protected files with quoted paths", () => {
+      const policy = getPathPolicy({ protected_paths: [".github/workflows/**"] });
+      expect(findPathViolations(QUOTED_PATCH, policy)).toEqual([{ path: ".github/workflows/évil.yml", reason: "matches protected path '.github/workflows/**'" }]);
+    });
+
+    it("should report sections whose paths cannot be parsed", () => {
+      const patch = ['diff --git "a/.github/workflows/\\q.yml" "b/.github/workflows/\\q.yml', "new file mode 100644", "--- /dev/null", "@@ -0,0 +1 @@", "+x", ""].join("\n");
+      const policy = getPathPolicy({ allowed_paths: ["src/**"], if_path_violation: "drop" });
+      expect(findPathViolations(patch, policy)).toEqual([{ path: '"a/.github/workflows/\\q.yml" "b/.github/workflows/\\q.yml', reason: "has a path that could not be parsed" }]);
+      expect(dropPathViolations(patch, policy)).toBe("");
+    });
+
+    it("should let protected-paths take precedence over allowed-paths", () => {
+      const policy = getPathPolicy({ allowed_paths: [".github/**"], protected_paths: [".github/workflows/**"] });
+      expect(findPathViolations(PATCH, policy).map(v => v.path)).toEqual(["CODEOWNERS", "src/app.js", ".github/workflows/ci.yml"]);
+    });
+  });
+
+  describe("dropPathViolations", () => {
+    it("should remove offending files and commits left without changes", () => {
+      const policy = getPathPolicy({ protected_paths: ["CODEOWNERS", ".github/workflows/**"], if_path_violation: "drop" });
+      const filtered = dropPathViolations(PATCH, policy);
+
+      expect(getPatchFilePaths(filtered)).toEqual(["src/app.js"]);
+      expect(filtered).toContain("Subject: [PATCH] one");
+      expect(filtered).not.toContain("Subject: [PATCH] two");
+    });
+
+    it("should return an empty patch when every file is dropped", () => {
+      const policy = getPathPolicy({ allowed_paths: ["docs/**"], if_path_violation: "drop" });
+      expect(dropPathViolations(PATCH, policy)).toBe("");
+    });
+  });
+
+  describe("formatPathViolations", () => {
+    it("should format violations as a markdown list", () => {
+      expect(formatPathViolations([{ path: "CODEOWNERS", reason: "is not in allowed-paths" }])).toBe("- `CODEOWNERS` is not in allowed-paths");
+    });
+  });
+});
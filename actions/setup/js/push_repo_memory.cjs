@@ -8,6 +8,7 @@ const { getErrorMessage } = require("./error_helpers.cjs");
 const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
 const { execGitSync } = require("./git_helpers.cjs");
 const { parseAllowedRepos, validateRepo } = require("./repo_helpers.cjs");
+const { MEMORY_MODES, writeMemoryFile, applyJsonlRetention, findExpiredFiles, enforceTotalSize, compactHistory } = require("./repo_memory_governance.cjs");
 
 /**
  * Push repo-memory changes to git branch
@@ -34,6 +35,10 @@ const { parseAllowedRepos, validateRepo } = require("./repo_helpers.cjs");
  *                       INCORRECT pattern: "memory/code-metrics/*.jsonl"  (includes branch name)
  *
  *                     The branch name is used for git operations (checkout, push) but not for pattern matching.
+ *   MEMORY_MODE: Optional storage mode: "files" (default), "jsonl" (append-only .jsonl logs) or "kv" (merged .json objects)
+ *   MAX_COMMITS: Optional number of commits after which the branch history is squashed into one commit
+ *   MAX_TOTAL_SIZE: Optional maximum total size of the memory branch in bytes
+ *   RETENTION_DAYS: Optional number of days after which jsonl entries or files are removed
  *   GH_TOKEN: GitHub token for authentication
  *   GITHUB_RUN_ID: Workflow run ID for commit messages
  */
@@ -47,6 +52,15 @@ async function main() {
   const maxFileCount = parseInt(process.env.MAX_FILE_COUNT || "100", 10);
   const maxPatchSize = parseInt(process.env.MAX_PATCH_SIZE || "10240", 10);
   const fileGlobFilter = process.env.FILE_GLOB_FILTER || "";
+  const memoryMode = process.env.MEMORY_MODE || "files";
+  const maxCommits = parseInt(process.env.MAX_COMMITS || "0", 10);
+  const maxTotalSize = parseInt(process.env.MAX_TOTAL_SIZE || "0", 10);
+  const retentionDays = parseInt(process.env.RETENTION_DAYS || "0", 10);
+
+  if (!MEMORY_MODES.includes(memoryMode)) {
+    core.setFailed(`Invalid MEMORY_MODE: ${memoryMode}. Expected one of: ${MEMORY_MODES.join(", ")}`);
+    return;
+  }
 
   // Parse allowed extensions with error handling
   let allowedExtensions = [".json", ".jsonl", ".txt", ".md", ".csv"];
@@ -73,6 +87,10 @@ async function main() {
   core.info(`  ALLOWED_EXTENSIONS: ${JSON.stringify(allowedExtensions)}`);
   core.info(`  FILE_GLOB_FILTER: ${fileGlobFilter ? `"${fileGlobFilter}"` : "(empty - all files accepted)"}`);
   core.info(`  FILE_GLOB_FILTER length: ${fileGlobFilter.length}`);
+  core.info(`  MEMORY_MODE: ${memoryMode}`);
+  core.info(`  MAX_COMMITS: ${maxCommits || "(unlimited)"}`);
+  core.info(`  MAX_TOTAL_SIZE: ${maxTotalSize || "(unlimited)"}`);
+  core.info(`  RETENTION_DAYS: ${retentionDays || "(keep everything)"}`);
 
   /** @param {unknown} value */
   function isPlainObject(value) {
@@ -306,11 +324,16 @@ async function main() {
       // Ensure destination directory exists
       fs.mkdirSync(destDir, { recursive: true });
 
-      // Copy file
-      fs.copyFileSync(file.source, destFilePath);
-      core.info(`Copied: ${file.relativePath} (${file.size} bytes)`);
+      // Copy file, merging structured files into the stored copy
+      const result = writeMemoryFile(memoryMode, file.source, destFilePath);
+      core.info(`Wrote: ${file.relativePath} (${file.size} bytes, ${result})`);
     } catch (error) {
-      core.setFailed(`Failed to copy file ${file.relativePath}: ${getErrorMessage(error)}`);
+      const errorMessage = `Failed to copy file ${file.relativePath}: ${getErrorMessage(error)}`;
+      if (memoryMode !== "files") {
+        core.setOutput("validation_failed", "true");
+        core.setOutput("validation_error", errorMessage);
+      }
+      core.setFailed(errorMessage);
       return;
     }
   }
@@ -361,6 +384,43 @@ async function main() {
     return;
   }
 
+  // Apply retention and size limits to the whole memory branch. This runs after the patch size
+  // check so that removals made here do not count against the agent's patch.
+  if (retentionDays > 0 || maxTotalSize > 0) {
+    try {
+      const now = new Date();
+      if (retentionDays > 0 && memoryMode === "jsonl") {
+        const removed = applyJsonlRetention(destMemoryPath, retentionDays, now);
+        core.info(`Retention: removed ${removed} entr${removed === 1 ? "y" : "ies"} older than ${retentionDays} days`);
+      } else if (retentionDays > 0) {
+        const expired = findExpiredFiles(retentionDays, now);
+        for (const file of expired) {
+          execGitSync(["rm", "-q", "--", file], { stdio: "pipe" });
+        }
+        core.info(`Retention: removed ${expired.length} file(s) not updated within ${retentionDays} days`);
+      }
+
+      if (maxTotalSize > 0) {
+        const { totalSize, removed } = enforceTotalSize(destMemoryPath, maxTotalSize, memoryMode);
+        if (removed > 0) {
+          core.info(`Size limit: dropped the ${removed} oldest entr${removed === 1 ? "y" : "ies"} to fit in ${maxTotalSize} bytes`);
+        }
+        if (totalSize > maxTotalSize) {
+          const errorMessage = `Memory size (${totalSize} bytes) exceeds max-total-size (${maxTotalSize} bytes). Remove files from the memory or increase max-total-size.`;
+          core.setOutput("validation_failed", "true");
+          core.setOutput("validation_error", errorMessage);
+          core.setFailed(errorMessage);
+          return;
+        }
+      }
+
+      execGitSync(["add", "-A"], { stdio: "pipe" });
+    } catch (error) {
+      core.setFailed(`Failed to apply memory retention and size limits: ${getErrorMessage(error)}`);
+      return;
+    }
+  }
+
   // Commit changes
   try {
     execGitSync(["commit", "-m", `Update repo memory from workflow run ${githubRunId}`], { stdio: "inherit" });
@@ -379,11 +439,26 @@ async function main() {
     core.warning(`Pull failed (this may be expected): ${getErrorMessage(error)}`);
   }
 
+  // Squash the branch history once it grows past max-commits. The rewritten branch is pushed
+  // with a lease on the fetched tip so that concurrent updates are never overwritten.
+  const pushArgs = [];
+  if (maxCommits > 0) {
+    try {
+      const remoteTip = execGitSync(["rev-parse", "FETCH_HEAD"], { stdio: "pipe" }).trim();
+      if (compactHistory(maxCommits, `Compact repo memory history from workflow run ${githubRunId}`)) {
+        core.info(`Compacted the history of ${branchName} into a single commit (max-commits: ${maxCommits})`);
+        pushArgs.push(`--force-with-lease=${branchName}:${remoteTip}`);
+      }
+    } catch (error) {
+      core.warning(`Skipping history compaction: ${getErrorMessage(error)}`);
+    }
+  }
+
   // Push changes
   core.info(`Pushing changes to ${branchName}...`);
   try {
     const repoUrl = `https://x-access-token:${ghToken}@${serverHost}/${targetRepo}.git`;
-    execGitSync(["push", repoUrl, `HEAD:${branchName}`], { stdio: "inherit" });
+    execGitSync(["push", ...pushArgs, repoUrl, `HEAD:${branchName}`], { stdio: "inherit" });
     core.info(`Successfully pushed changes to ${branchName} branch`);
   } catch (error) {
     core.setFailed(`Failed to push changes: ${getErrorMessage(error)}`);
@@ -10,6 +10,7 @@ const { normalizeBranchName } = require("./normalize_branch_name.cjs");
 const { pushExtraEmptyCommit } = require("./extra_empty_commit.cjs");
 const { detectForkPR } = require("./pr_helpers.cjs");
 const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
+const { getPathPolicy, findPathViolations, dropPathViolations, formatPathViolations } = require("./patch_path_policy.cjs");
 
 /**
  * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
@@ -32,6 +33,7 @@ async function main(config = {}) {
   const commitTitleSuffix = config.commit_title_suffix || "";
   const maxSizeKb = config.max_patch_size ? parseInt(String(config.max_patch_size), 10) : 1024;
   const maxCount = config.max || 0; // 0 means no limit
+  const pathPolicy = getPathPolicy(config);
 
   // Cross-repo support: resolve target repository from config
   // This allows pushing to PRs in a different repository than the workflow
@@ -60,6 +62,11 @@ async function main(config = {}) {
   }
   core.info(`Max patch size: ${maxSizeKb} KB`);
   core.info(`Max count: ${maxCount || "unlimited"}`);
+  if (pathPolicy) {
+    core.info(`Allowed paths: ${pathPolicy.allowedPaths.join(", ") || "(all)"}`);
+    core.info(`Protected paths: ${pathPolicy.protectedPaths.join(", ") || "(none)"}`);
+    core.info(`If path violation: ${pathPolicy.action}`);
+  }
   core.info(`Default target repo: ${defaultTargetRepo}`);
   if (allowedRepos.size > 0) {
     core.info(`Allowed repos: ${[...allowedRepos].join(", ")}`);
@@ -103,7 +110,7 @@ async function main(config = {}) {
       }
     }
 
-    const patchContent = fs.readFileSync(patchFilePath, "utf8");
+    let patchContent = fs.readFileSync(patchFilePath, "utf8");
 
     // Check for actual error conditions
     if (patchContent.includes("Failed to generate patch")) {
@@ -117,6 +124,20 @@ async function main(config = {}) {
       return { success: false, error: msg };
     }
 
+    // Enforce the allowed-paths / protected-paths policy on the files changed by the patch
+    if (pathPolicy && patchContent.trim()) {
+      const violations = findPathViolations(patchContent, pathPolicy);
+      if (violations.length > 0) {
+        core.warning(`Patch changes ${violations.length} file(s) outside the path policy:\n${formatPathViolations(violations)}`);
+        if (pathPolicy.action !== "drop") {
+          return { success: false, error: `Patch changes files outside the path policy: ${violations.map(v => v.path).join(", ")}` };
+        }
+        patchContent = dropPathViolations(patchContent, pathPolicy);
+        fs.writeFileSync(patchFilePath, patchContent, "utf8");
+        core.info(`Dropped ${violations.length} file(s) from the patch`);
+      }
+    }
+
     // Validate patch size (unless empty)
     const isEmpty = !patchContent || !patchContent.trim();
     if (!isEmpty) {
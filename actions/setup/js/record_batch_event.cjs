@@ -0,0 +1,27 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Records the triggering event of a batched workflow on the batch branch
+ */
+
+const { buildEventRecord, recordEvent } = require("./batch_events_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+async function main() {
+  const branch = process.env.GH_AW_BATCH_BRANCH || "";
+  if (!branch) {
+    core.setFailed("GH_AW_BATCH_BRANCH is not set");
+    return;
+  }
+
+  const record = buildEventRecord(context);
+  try {
+    const path = await recordEvent(branch, record);
+    core.info(`✅ Recorded ${record.event} event in ${branch}:${path}`);
+  } catch (error) {
+    core.setFailed(`Failed to record ${record.event} event in ${branch}: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main };
@@ -43,48 +43,135 @@ function findFiles(dir, extensions) {
 }
 
 /**
- * Built-in regex patterns for common credential types
- * Each pattern is designed to match legitimate credential formats
+ * Built-in detectors for common credential types, keyed by the names accepted by
+ * secret-masking.detectors. Each pattern is designed to match legitimate credential formats.
+ * Keep in sync with pkg/redact/detectors.go; TestDetectorsMatchJavaScript fails when they differ.
  */
-const BUILT_IN_PATTERNS = [
-  // GitHub tokens
-  { name: "GitHub Personal Access Token (classic)", pattern: /ghp_[0-9a-zA-Z]{36}/g },
-  { name: "GitHub Server-to-Server Token", pattern: /ghs_[0-9a-zA-Z]{36}/g },
-  { name: "GitHub OAuth Access Token", pattern: /gho_[0-9a-zA-Z]{36}/g },
-  { name: "GitHub User Access Token", pattern: /ghu_[0-9a-zA-Z]{36}/g },
-  { name: "GitHub Fine-grained PAT", pattern: /github_pat_[0-9a-zA-Z_]{82}/g },
-  { name: "GitHub Refresh Token", pattern: /ghr_[0-9a-zA-Z]{36}/g },
-
-  // Azure tokens
-  { name: "Azure Storage Account Key", pattern: /[a-zA-Z0-9+/]{88}==/g },
-  { name: "Azure SAS Token", pattern: /\?sv=[0-9-]{1,20}&s[rts]=[\w\-]{1,20}&sig=[A-Za-z0-9%+/=]{1,200}/g },
-
-  // Google/GCP tokens
-  { name: "Google API Key", pattern: /AIzaSy[0-9A-Za-z_-]{33}/g },
-  { name: "Google OAuth Access Token", pattern: /ya29\.[0-9A-Za-z_-]{1,800}/g },
-
-  // AWS tokens
-  { name: "AWS Access Key ID", pattern: /AKIA[0-9A-Z]{16}/g },
-
-  // OpenAI tokens
-  { name: "OpenAI API Key", pattern: /sk-[a-zA-Z0-9]{48}/g },
-  { name: "OpenAI Project API Key", pattern: /sk-proj-[a-zA-Z0-9]{48,64}/g },
-
-  // Anthropic tokens
-  { name: "Anthropic API Key", pattern: /sk-ant-api03-[a-zA-Z0-9_-]{95}/g },
-];
+const DETECTORS = {
+  "github-tokens": [
+    { name: "GitHub Personal Access Token (classic)", pattern: /ghp_[0-9a-zA-Z]{36}/g },
+    { name: "GitHub Server-to-Server Token", pattern: /ghs_[0-9a-zA-Z]{36}/g },
+    { name: "GitHub OAuth Access Token", pattern: /gho_[0-9a-zA-Z]{36}/g },
+    { name: "GitHub User Access Token", pattern: /ghu_[0-9a-zA-Z]{36}/g },
+    { name: "GitHub Fine-grained PAT", pattern: /github_pat_[0-9a-zA-Z_]{82}/g },
+    { name: "GitHub Refresh Token", pattern: /ghr_[0-9a-zA-Z]{36}/g },
+  ],
+  "cloud-keys": [
+    // Azure tokens
+    { name: "Azure Storage Account Key", pattern: /[a-zA-Z0-9+/]{88}==/g },
+    { name: "Azure SAS Token", pattern: /\?sv=[0-9-]{1,20}&s[rts]=[\w\-]{1,20}&sig=[A-Za-z0-9%+/=]{1,200}/g },
+    // Google/GCP tokens
+    { name: "Google API Key", pattern: /AIzaSy[0-9A-Za-z_-]{33}/g },
+    { name: "Google OAuth Access Token", pattern: /ya29\.[0-9A-Za-z_-]{1,800}/g },
+    // AWS tokens
+    { name: "AWS Access Key ID", pattern: /AKIA[0-9A-Z]{16}/g },
+    { name: "AWS Temporary Access Key ID", pattern: /ASIA[0-9A-Z]{16}/g },
+  ],
+  "api-keys": [
+    { name: "OpenAI API Key", pattern: /sk-[a-zA-Z0-9]{48}/g },
+    { name: "OpenAI Project API Key", pattern: /sk-proj-[a-zA-Z0-9]{48,64}/g },
+    { name: "Anthropic API Key", pattern: /sk-ant-api03-[a-zA-Z0-9_-]{95}/g },
+    { name: "Slack Token", pattern: /xox[abposr]-[0-9A-Za-z-]{10,}/g },
+    { name: "Stripe Live Secret Key", pattern: /sk_live_[0-9a-zA-Z]{24,}/g },
+    { name: "npm Access Token", pattern: /npm_[0-9a-zA-Z]{36}/g },
+  ],
+  jwt: [{ name: "JSON Web Token", pattern: /eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}/g }],
+  "private-keys": [{ name: "Private Key", pattern: /-----BEGIN [A-Z ]{0,20}PRIVATE KEY(?: BLOCK)?-----[\s\S]*?-----END [A-Z ]{0,20}PRIVATE KEY(?: BLOCK)?-----/g }],
+};
+
+/**
+ * Name of the heuristic detector reporting random-looking strings. It is opt-in because it
+ * can match random-looking identifiers.
+ */
+const ENTROPY_DETECTOR = "entropy";
+
+/**
+ * Detectors used when secret-masking.detectors is not configured
+ */
+const DEFAULT_DETECTORS = Object.keys(DETECTORS);
+
+/**
+ * Patterns of the default detectors
+ */
+const BUILT_IN_PATTERNS = DEFAULT_DETECTORS.flatMap(name => DETECTORS[name]);
+
+/**
+ * Minimum length and Shannon entropy (bits per character) of strings reported by the entropy detector
+ */
+const ENTROPY_MIN_LENGTH = 32;
+const ENTROPY_THRESHOLD = 4.0;
+
+/**
+ * Computes the Shannon entropy of a string in bits per character
+ * @param {string} value - String to measure
+ * @returns {number} Entropy in bits per character
+ */
+function shannonEntropy(value) {
+  if (!value) {
+    return 0;
+  }
+  /** @type {Record<string, number>} */
+  const counts = {};
+  for (const char of value) {
+    counts[char] = (counts[char] || 0) + 1;
+  }
+  let entropy = 0;
+  for (const count of Object.values(counts)) {
+    const p = count / value.length;
+    entropy -= p * Math.log2(p);
+  }
+  return entropy;
+}
+
+/**
+ * Checks whether a string mixes upper case letters, lower case letters and at least three digits,
+ * which rules out identifiers, words and hexadecimal hashes
+ * @param {string} value - String to check
+ * @returns {boolean} True when the string looks like a random token
+ */
+function hasMixedCharacters(value) {
+  const digits = value.match(/[0-9]/g) || [];
+  return /[A-Z]/.test(value) && /[a-z]/.test(value) && digits.length >= 3;
+}
+
+/**
+ * Resolves the detectors configured through GH_AW_SECRET_DETECTORS
+ * @param {string | undefined} value - Comma-separated detector names, "none", or undefined for the defaults
+ * @returns {{patterns: Array<{name: string, pattern: RegExp}>, entropy: boolean}} Patterns to apply and whether the entropy detector is enabled
+ */
+function resolveDetectors(value) {
+  if (value === undefined || value.trim() === "") {
+    return { patterns: BUILT_IN_PATTERNS, entropy: false };
+  }
+  const patterns = [];
+  let entropy = false;
+  for (const name of value.split(",").map(n => n.trim())) {
+    if (!name || name === "none") {
+      continue;
+    }
+    if (name === ENTROPY_DETECTOR) {
+      entropy = true;
+    } else if (Object.prototype.hasOwnProperty.call(DETECTORS, name)) {
+      patterns.push(...DETECTORS[name]);
+    } else {
+      core.warning(`Unknown secret detector: ${name}`);
+    }
+  }
+  return { patterns, entropy };
+}
 
 /**
  * Detects and redacts secrets matching built-in patterns
  * @param {string} content - File content to process
+ * @param {Array<{name: string, pattern: RegExp}>} [patterns] - Patterns to apply (defaults to the patterns of the default detectors)
  * @returns {{content: string, redactionCount: number, detectedPatterns: string[]}} Redacted content, count, and detected pattern types
  */
-function redactBuiltInPatterns(content) {
+function redactBuiltInPatterns(content, patterns = BUILT_IN_PATTERNS) {
   let redactionCount = 0;
   let redacted = content;
   const detectedPatterns = [];
 
-  for (const { name, pattern } of BUILT_IN_PATTERNS) {
+  for (const { name, pattern } of patterns) {
     const matches = redacted.match(pattern);
     if (matches && matches.length > 0) {
       // Redact each match with fixed-length string
@@ -101,6 +188,26 @@ function redactBuiltInPatterns(content) {
   return { content: redacted, redactionCount, detectedPatterns };
 }
 
+/**
+ * Redacts long random-looking strings that no token format matches
+ * @param {string} content - File content to process
+ * @returns {{content: string, redactionCount: number}} Redacted content and count of redactions
+ */
+function redactHighEntropyStrings(content) {
+  let redactionCount = 0;
+  const redacted = content.replace(/[A-Za-z0-9+/=_-]+/g, candidate => {
+    if (candidate.length < ENTROPY_MIN_LENGTH || !hasMixedCharacters(candidate) || shannonEntropy(candidate) < ENTROPY_THRESHOLD) {
+      return candidate;
+    }
+    redactionCount++;
+    return "***REDACTED***";
+  });
+  if (redactionCount > 0) {
+    core.info(`Redacted ${redactionCount} high entropy string(s)`);
+  }
+  return { content: redacted, redactionCount };
+}
+
 /**
  * Redacts secrets from file content using exact string matching
  * @param {string} content - File content to process
@@ -137,14 +244,15 @@ function redactSecrets(content, secretValues) {
  * Process a single file for secret redaction
  * @param {string} filePath - Path to the file
  * @param {string[]} secretValues - Array of secret values to redact
+ * @param {{patterns: Array<{name: string, pattern: RegExp}>, entropy: boolean}} [detectors] - Detectors to apply
  * @returns {number} Number of redactions made
  */
-function processFile(filePath, secretValues) {
+function processFile(filePath, secretValues, detectors = { patterns: BUILT_IN_PATTERNS, entropy: false }) {
   try {
     const content = fs.readFileSync(filePath, "utf8");
 
     // First, redact built-in patterns
-    const builtInResult = redactBuiltInPatterns(content);
+    const builtInResult = redactBuiltInPatterns(content, detectors.patterns);
     let redacted = builtInResult.content;
     let totalRedactions = builtInResult.redactionCount;
 
@@ -153,6 +261,13 @@ function processFile(filePath, secretValues) {
     redacted = customResult.content;
     totalRedactions += customResult.redactionCount;
 
+    // Finally, redact remaining random-looking strings when the entropy detector is enabled
+    if (detectors.entropy) {
+      const entropyResult = redactHighEntropyStrings(redacted);
+      redacted = entropyResult.content;
+      totalRedactions += entropyResult.redactionCount;
+    }
+
     if (totalRedactions > 0) {
       fs.writeFileSync(filePath, redacted, "utf8");
       core.info(`Processed ${filePath}: ${totalRedactions} redaction(s)`);
@@ -194,6 +309,7 @@ async function main() {
     }
 
     // Always scan for built-in patterns, even if there are no custom secrets
+    const detectors = resolveDetectors(process.env.GH_AW_SECRET_DETECTORS);
     core.info("Scanning for built-in credential patterns and custom secrets");
 
     // Find all target files in /tmp/gh-aw and /opt/gh-aw directories
@@ -206,7 +322,7 @@ async function main() {
     let filesWithRedactions = 0;
     // Process each file
     for (const file of files) {
-      const redactionCount = processFile(file, secretValues);
+      const redactionCount = processFile(file, secretValues, detectors);
       if (redactionCount > 0) {
         filesWithRedactions++;
         totalRedactions += redactionCount;
@@ -222,4 +338,4 @@ async function main() {
   }
 }
 
-module.exports = { main, redactSecrets, redactBuiltInPatterns, BUILT_IN_PATTERNS };
+module.exports = { main, redactSecrets, redactBuiltInPatterns, redactHighEntropyStrings, resolveDetectors, shannonEntropy, BUILT_IN_PATTERNS, DETECTORS, DEFAULT_DETECTORS };
@@ -0,0 +1,324 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Repo Memory Governance
+ *
+ * Storage modes, retention, size limits and history compaction for repo-memory branches.
+ * push_repo_memory.cjs applies them to the checked out memory branch before pushing:
+ *   - "jsonl" mode appends the new lines of each .jsonl file to the stored copy
+ *   - "kv" mode merges the keys of each .json object into the stored copy (null deletes a key)
+ *   - retention-days removes jsonl entries by their "timestamp" field, or files by their last commit
+ *   - max-total-size drops the oldest jsonl entries to fit, and fails in the other modes
+ *   - max-commits squashes the branch history into a single commit
+ */
+
+const fs = require("fs");
+const path = require("path");
+
+const { execGitSync } = require("./git_helpers.cjs");
+
+const MEMORY_MODES = ["files", "jsonl", "kv"];
+
+/**
+ * Splits JSON Lines content into its non-empty lines
+ * @param {string} content - JSON Lines content
+ * @returns {string[]} Lines without trailing whitespace
+ */
+function splitJsonlLines(content) {
+  return content
+    .split("\n")
+    .map(line => line.trimEnd())
+    .filter(line => line.trim().length > 0);
+}
+
+/**
+ * Joins lines into JSON Lines content
+ * @param {string[]} lines - Lines
+ * @returns {string} Content ending with a newline (empty when there are no lines)
+ */
+function joinJsonlLines(lines) {
+  return lines.length > 0 ? lines.join("\n") + "\n" : "";
+}
+
+/**
+ * Appends the lines of the agent's copy of a .jsonl file that the stored copy does not contain.
+ * Stored lines are never changed or removed, so the file behaves as an append-only log.
+ * @param {string} existing - Stored content (empty for new files)
+ * @param {string} incoming - Content written by the agent
+ * @returns {{content: string, appended: number}} Merged content and number of appended lines
+ * @throws {Error} If an appended line is not a JSON value
+ */
+function mergeJsonlContent(existing, incoming) {
+  const lines = splitJsonlLines(existing);
+  const seen = new Set(lines);
+  let appended = 0;
+  for (const line of splitJsonlLines(incoming)) {
+    if (seen.has(line)) {
+      continue;
+    }
+    try {
+      JSON.parse(line);
+    } catch (e) {
+      throw new Error(`Invalid JSON Lines entry: ${line.substring(0, 100)}`);
+    }
+    lines.push(line);
+    seen.add(line);
+    appended++;
+  }
+  return { content: joinJsonlLines(lines), appended };
+}
+
+/**
+ * @param {unknown} value
+ * @returns {value is Record<string, unknown>}
+ */
+function isPlainObject(value) {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+
+/**
+ * Merges the keys of the agent's copy of a .json file into the stored copy. Keys set to null
+ * are deleted; keys missing from the agent's copy are kept.
+ * @param {string} existing - Stored content (empty for new files)
+ * @param {string} incoming - Content written by the agent
+ * @returns {{content: string, changed: string[]}} Merged content and the keys that were set or deleted
+ * @throws {Error} If the agent's copy is not a JSON object
+ */
+function mergeKvContent(existing, incoming) {
+  let update;
+  try {
+    update = JSON.parse(incoming);
+  } catch (e) {
+    throw new Error(`Invalid JSON: ${e instanceof Error ? e.message : String(e)}`);
+  }
+  if (!isPlainObject(update)) {
+    throw new Error("Key/value memory files must contain a JSON object");
+  }
+
+  /** @type {Record<string, unknown>} */
+  let merged = {};
+  if (existing.trim()) {
+    try {
+      const stored = JSON.parse(existing);
+      if (isPlainObject(stored)) {
+        merged = stored;
+      }
+    } catch {
+      // A corrupt stored copy is replaced by the agent's copy
+    }
+  }
+
+  const changed = [];
+  for (const [key, value] of Object.entries(update)) {
+    if (value === null) {
+      if (key in merged) {
+        delete merged[key];
+        changed.push(key);
+      }
+    } else if (JSON.stringify(merged[key]) !== JSON.stringify(value)) {
+      merged[key] = value;
+      changed.push(key);
+    }
+  }
+  return { content: JSON.stringify(merged, null, 2) + "\n", changed };
+}
+
+/**
+ * Writes a file of the agent's memory to the memory branch according to the storage mode
+ * @param {string} mode - Storage mode ("files", "jsonl" or "kv")
+ * @param {string} sourcePath - File written by the agent
+ * @param {string} destPath - File on the memory branch
+ * @returns {string} Description of the write for the log
+ */
+function writeMemoryFile(mode, sourcePath, destPath) {
+  const ext = path.extname(destPath).toLowerCase();
+  const structured = (mode === "jsonl" && ext === ".jsonl") || (mode === "kv" && ext === ".json");
+  if (!structured) {
+    fs.copyFileSync(sourcePath, destPath);
+    return "copied";
+  }
+
+  const incoming = fs.readFileSync(sourcePath, "utf8");
+  const existing = fs.existsSync(destPath) ? fs.readFileSync(destPath, "utf8") : "";
+  if (mode === "jsonl") {
+    const { content, appended } = mergeJsonlContent(existing, incoming);
+    fs.writeFileSync(destPath, content);
+    return `appended ${appended} entr${appended === 1 ? "y" : "ies"}`;
+  }
+  const { content, changed } = mergeKvContent(existing, incoming);
+  fs.writeFileSync(destPath, content);
+  return `merged ${changed.length} key(s)`;
+}
+
+/**
+ * Removes the entries of JSON Lines content whose "timestamp" is older than the retention period.
+ * Entries without a valid timestamp are kept.
+ * @param {string} content - JSON Lines content
+ * @param {number} retentionDays - Retention period in days
+ * @param {Date} now - Current time
+ * @returns {{content: string, removed: number}} Remaining content and number of removed entries
+ */
+function pruneJsonlByAge(content, retentionDays, now) {
+  const cutoff = now.getTime() - retentionDays * 24 * 60 * 60 * 1000;
+  const lines = splitJsonlLines(content);
+  const kept = lines.filter(line => {
+    let entry;
+    try {
+      entry = JSON.parse(line);
+    } catch {
+      return true;
+    }
+    const time = isPlainObject(entry) && entry.timestamp !== undefined ? new Date(/** @type {any} */ (entry.timestamp)).getTime() : NaN;
+    return isNaN(time) || time >= cutoff;
+  });
+  return { content: joinJsonlLines(kept), removed: lines.length - kept.length };
+}
+
+/**
+ * Drops the oldest entries of JSON Lines content until it fits in maxBytes
+ * @param {string} content - JSON Lines content
+ * @param {number} maxBytes - Maximum size in bytes
+ * @returns {{content: string, removed: number}} Remaining content and number of removed entries
+ */
+function trimJsonlToSize(content, maxBytes) {
+  const lines = splitJsonlLines(content);
+  let size = Buffer.byteLength(joinJsonlLines(lines), "utf8");
+  let removed = 0;
+  while (lines.length > 0 && size > maxBytes) {
+    const line = /** @type {string} */ (lines.shift());
+    size -= Buffer.byteLength(line, "utf8") + 1;
+    removed++;
+  }
+  return { content: joinJsonlLines(lines), removed };
+}
+
+/**
+ * Lists the files of a memory directory, skipping the .git directory
+ * @param {string} dir - Memory directory
+ * @returns {Array<{relativePath: string, size: number}>} Files with their sizes
+ */
+function listMemoryFiles(dir) {
+  /** @type {Array<{relativePath: string, size: number}>} */
+  const files = [];
+  /** @param {string} relativeDir */
+  function walk(relativeDir) {
+    for (const entry of fs.readdirSync(path.join(dir, relativeDir), { withFileTypes: true })) {
+      const relativePath = relativeDir ? path.posix.join(relativeDir, entry.name) : entry.name;
+      if (entry.isDirectory()) {
+        if (relativePath !== ".git") {
+          walk(relativePath);
+        }
+      } else if (entry.isFile()) {
+        files.push({ relativePath, size: fs.statSync(path.join(dir, relativePath)).size });
+      }
+    }
+  }
+  walk("");
+  return files;
+}
+
+/**
+ * Removes the jsonl entries that are older than the retention period
+ * @param {string} dir - Memory directory
+ * @param {number} retentionDays - Retention period in days
+ * @param {Date} now - Current time
+ * @returns {number} Number of removed entries
+ */
+function applyJsonlRetention(dir, retentionDays, now) {
+  let removed = 0;
+  for (const file of listMemoryFiles(dir).filter(f => f.relativePath.endsWith(".jsonl"))) {
+    const filePath = path.join(dir, file.relativePath);
+    const result = pruneJsonlByAge(fs.readFileSync(filePath, "utf8"), retentionDays, now);
+    if (result.removed > 0) {
+      fs.writeFileSync(filePath, result.content);
+      removed += result.removed;
+    }
+  }
+  return removed;
+}
+
+/**
+ * Finds the committed files of the memory branch that were not updated within the retention
+ * period. Files with staged changes count as updated.
+ * @param {number} retentionDays - Retention period in days
+ * @param {Date} now - Current time
+ * @returns {string[]} Paths of the expired files
+ */
+function findExpiredFiles(retentionDays, now) {
+  const cutoffSeconds = Math.floor(now.getTime() / 1000) - retentionDays * 24 * 60 * 60;
+  const staged = new Set(splitJsonlLines(execGitSync(["diff", "--cached", "--name-only"], { stdio: "pipe" })));
+  const expired = [];
+  for (const file of splitJsonlLines(execGitSync(["ls-files"], { stdio: "pipe" }))) {
+    if (staged.has(file)) {
+      continue;
+    }
+    const lastCommit = parseInt(execGitSync(["log", "-1", "--format=%ct", "--", file], { stdio: "pipe" }).trim(), 10);
+    if (!isNaN(lastCommit) && lastCommit < cutoffSeconds) {
+      expired.push(file);
+    }
+  }
+  return expired;
+}
+
+/**
+ * Keeps the memory directory within maxTotalSize. In jsonl mode the oldest entries of the
+ * largest .jsonl files are dropped first; in the other modes nothing is removed.
+ * @param {string} dir - Memory directory
+ * @param {number} maxTotalSize - Maximum total size in bytes
+ * @param {string} mode - Storage mode
+ * @returns {{totalSize: number, removed: number}} Total size afterwards and number of removed entries
+ */
+function enforceTotalSize(dir, maxTotalSize, mode) {
+  const files = listMemoryFiles(dir);
+  let totalSize = files.reduce((sum, f) => sum + f.size, 0);
+  let removed = 0;
+  if (mode !== "jsonl" || totalSize <= maxTotalSize) {
+    return { totalSize, removed };
+  }
+
+  const logs = files.filter(f => f.relativePath.endsWith(".jsonl")).sort((a, b) => b.size - a.size);
+  for (const file of logs) {
+    const excess = totalSize - maxTotalSize;
+    if (excess <= 0) {
+      break;
+    }
+    const filePath = path.join(dir, file.relativePath);
+    const result = trimJsonlToSize(fs.readFileSync(filePath, "utf8"), Math.max(0, file.size - excess));
+    fs.writeFileSync(filePath, result.content);
+    totalSize -= file.size - Buffer.byteLength(result.content, "utf8");
+    removed += result.removed;
+  }
+  return { totalSize, removed };
+}
+
+/**
+ * Squashes the history of the current branch into a single commit when it has more than
+ * maxCommits commits. The working tree is left unchanged.
+ * @param {number} maxCommits - Maximum number of commits to keep
+ * @param {string} message - Message of the squashed commit
+ * @returns {boolean} True if the history was compacted
+ */
+function compactHistory(maxCommits, message) {
+  const count = parseInt(execGitSync(["rev-list", "--count", "HEAD"], { stdio: "pipe" }).trim(), 10);
+  if (isNaN(count) || count <= maxCommits) {
+    return false;
+  }
+  const squashed = execGitSync(["commit-tree", "HEAD^{tree}", "-m", message], { stdio: "pipe" }).trim();
+  execGitSync(["reset", "--soft", squashed], { stdio: "pipe" });
+  return true;
+}
+
+module.exports = {
+  MEMORY_MODES,
+  mergeJsonlContent,
+  mergeKvContent,
+  writeMemoryFile,
+  pruneJsonlByAge,
+  trimJsonlToSize,
+  listMemoryFiles,
+  applyJsonlRetention,
+  findExpiredFiles,
+  enforceTotalSize,
+  compactHistory,
+};
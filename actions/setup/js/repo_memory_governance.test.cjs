@@ -0,0 +1,139 @@
+import { describe, it, expect, beforeEach, afterEach } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const { mergeJsonlContent, mergeKvContent, writeMemoryFile, pruneJsonlByAge, trimJsonlToSize, listMemoryFiles, applyJsonlRetention, enforceTotalSize } = require("./repo_memory_governance.cjs");
+
+describe("repo_memory_governance.cjs", () => {
+  let tmpDir;
+
+  beforeEach(() => {
+    tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "repo-memory-governance-"));
+  });
+
+  afterEach(() => {
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  });
+
+  describe("mergeJsonlContent", () => {
+    it("should append only new lines to the stored log", () => {
+      const result = mergeJsonlContent('{"a":1}\n{"b":2}\n', '{"a":1}\n{"c":3}\n\n');
+      expect(result).toEqual({ content: '{"a":1}\n{"b":2}\n{"c":3}\n', appended: 1 });
+    });
+
+    it("should keep stored lines the agent removed", () => {
+      expect(mergeJsonlContent('{"a":1}\n', "").content).toBe('{"a":1}\n');
+    });
+
+    it("should reject lines that are not JSON", () => {
+      expect(() => mergeJsonlContent("", "not json\n")).toThrow("Invalid JSON Lines entry");
+    });
+  });
+
+  describe("mergeKvContent", () => {
+    it("should merge keys and delete null keys", () => {
+      const result = mergeKvContent('{"a":1,"b":2,"c":3}', '{"b":20,"c":null,"d":4}');
+      expect(JSON.parse(result.content)).toEqual({ a: 1, b: 20, d: 4 });
+      expect(result.changed).toEqual(["b", "c", "d"]);
+    });
+
+    it("should replace a stored copy that is not an object", () => {
+      expect(JSON.parse(mergeKvContent("[1,2]", '{"a":1}').content)).toEqual({ a: 1 });
+    });
+
+    it("should reject values that are not objects", () => {
+      expect(() => mergeKvContent("", "[1]")).toThrow("must contain a JSON object");
+      expect(() => mergeKvContent("", "{")).toThrow("Invalid JSON");
+    });
+  });
+
+  describe("writeMemoryFile", () => {
+    it("should merge structured files and copy the others", () => {
+      const source = path.join(tmpDir, "source");
+      const dest = path.join(tmpDir, "dest");
+      fs.mkdirSync(source);
+      fs.mkdirSync(dest);
+      fs.writeFileSync(path.join(source, "log.jsonl"), '{"n":2}\n');
+      fs.writeFileSync(path.join(dest, "log.jsonl"), '{"n":1}\n');
+      fs.writeFileSync(path.join(source, "notes.md"), "new");
+      fs.writeFileSync(path.join(dest, "notes.md"), "old");
+
+      expect(writeMemoryFile("jsonl", path.join(source, "log.jsonl"), path.join(dest, "log.jsonl"))).toBe("appended 1 entry");
+      expect(writeMemoryFile("jsonl", path.join(source, "notes.md"), path.join(dest, "notes.md"))).toBe("copied");
+      expect(fs.readFileSync(path.join(dest, "log.jsonl"), "utf8")).toBe('{"n":1}\n{"n":2}\n');
+      expect(fs.readFileSync(path.join(dest, "notes.md"), "utf8")).toBe("new");
+    });
+
+    it("should overwrite .jsonl files in files mode", () => {
+      const source = path.join(tmpDir, "log-source.jsonl");
+      const dest = path.join(tmpDir, "log.jsonl");
+      fs.writeFileSync(source, '{"n":2}\n');
+      fs.writeFileSync(dest, '{"n":1}\n');
+
+      expect(writeMemoryFile("files", source, dest)).toBe("copied");
+      expect(fs.readFileSync(dest, "utf8")).toBe('{"n":2}\n');
+    });
+  });
+
+  describe("pruneJsonlByAge", () => {
+    it("should remove entries older than the retention period", () => {
+      const now = new Date("2026-03-10T00:00:00Z");
+      const content = ['{"timestamp":"2026-01-01T00:00:00Z","n":1}', '{"timestamp":"2026-03-09T00:00:00Z","n":2}', '{"n":3}', "[4]"].join("\n");
+
+      const result = pruneJsonlByAge(content, 30, now);
+      expect(result.removed).toBe(1);
+      expect(result.content).toBe('{"timestamp":"2026-03-09T00:00:00Z","n":2}\n{"n":3}\n[4]\n');
+    });
+  });
+
+  describe("trimJsonlToSize", () => {
+    it("should drop the oldest entries until the content fits", () => {
+      const content = '{"n":1}\n{"n":2}\n{"n":3}\n';
+      expect(trimJsonlToSize(content, 16)).toEqual({ content: '{"n":2}\n{"n":3}\n', removed: 1 });
+      expect(trimJsonlToSize(content, 100).removed).toBe(0);
+      expect(trimJsonlToSize(content, 0)).toEqual({ content: "", removed: 3 });
+    });
+  });
+
+  describe("listMemoryFiles", () => {
+    it("should list nested files and skip the .git directory", () => {
+      fs.mkdirSync(path.join(tmpDir, ".git"));
+      fs.writeFileSync(path.join(tmpDir, ".git", "HEAD"), "ref");
+      fs.mkdirSync(path.join(tmpDir, "history"));
+      fs.writeFileSync(path.join(tmpDir, "history", "a.jsonl"), "{}\n");
+
+      expect(listMemoryFiles(tmpDir)).toEqual([{ relativePath: "history/a.jsonl", size: 3 }]);
+    });
+  });
+
+  describe("applyJsonlRetention", () => {
+    it("should prune every log of the memory", () => {
+      fs.writeFileSync(path.join(tmpDir, "a.jsonl"), '{"timestamp":"2020-01-01T00:00:00Z"}\n{"timestamp":"2026-03-01T00:00:00Z"}\n');
+      fs.writeFileSync(path.join(tmpDir, "b.jsonl"), '{"timestamp":"2020-01-01T00:00:00Z"}\n');
+
+      expect(applyJsonlRetention(tmpDir, 90, new Date("2026-03-10T00:00:00Z"))).toBe(2);
+      expect(fs.readFileSync(path.join(tmpDir, "a.jsonl"), "utf8")).toBe('{"timestamp":"2026-03-01T00:00:00Z"}\n');
+      expect(fs.readFileSync(path.join(tmpDir, "b.jsonl"), "utf8")).toBe("");
+    });
+  });
+
+  describe("enforceTotalSize", () => {
+    it("should drop the oldest entries of the largest logs in jsonl mode", () => {
+      fs.writeFileSync(path.join(tmpDir, "big.jsonl"), '{"n":1}\n{"n":2}\n{"n":3}\n{"n":4}\n');
+      fs.writeFileSync(path.join(tmpDir, "small.jsonl"), '{"n":5}\n');
+
+      const result = enforceTotalSize(tmpDir, 24, "jsonl");
+      expect(result).toEqual({ totalSize: 24, removed: 2 });
+      expect(fs.readFileSync(path.join(tmpDir, "big.jsonl"), "utf8")).toBe('{"n":3}\n{"n":4}\n');
+      expect(fs.readFileSync(path.join(tmpDir, "small.jsonl"), "utf8")).toBe('{"n":5}\n');
+    });
+
+    it("should report the size without removing anything in other modes", () => {
+      fs.writeFileSync(path.join(tmpDir, "state.json"), '{"a":1}');
+
+      expect(enforceTotalSize(tmpDir, 2, "kv")).toEqual({ totalSize: 7, removed: 0 });
+      expect(fs.readFileSync(path.join(tmpDir, "state.json"), "utf8")).toBe('{"a":1}');
+    });
+  });
+});
@@ -50,7 +50,7 @@ const { getErrorMessage } = require("./error_helpers.cjs");
 function startSafeInputsServer(configPath, options = {}) {
   // Create server first to have logger available
   const logDir = options.logDir || undefined;
-  const server = createServer({ name: "safeinputs", version: "1.0.0" }, { logDir });
+  const server = createServer({ name: "safeinputs", version: "1.0.0" }, { logDir, validateInputs: true });
 
   // Bootstrap: load configuration and tools using shared logic
   const { config, tools } = bootstrapSafeInputsServer(configPath, server);
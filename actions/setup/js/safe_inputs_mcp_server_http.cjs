@@ -25,7 +25,7 @@ require("./shim.cjs");
 const http = require("http");
 const { randomUUID } = require("crypto");
 const { MCPServer, MCPHTTPTransport } = require("./mcp_http_transport.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateInputValues, formatInvalidInputsMessage } = require("./safe_inputs_validation.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 const { createLogger } = require("./mcp_logger.cjs");
 const { bootstrapSafeInputsServer, cleanupConfigFile } = require("./safe_inputs_bootstrap.cjs");
@@ -94,6 +94,12 @@ function createMCPServer(configPath, options = {}) {
         throw new Error(generateEnhancedErrorMessage(missing, tool.name, tool.inputSchema));
       }
 
+      // Validate typed constraints (enum, pattern, ranges, nested schemas) before execution
+      const invalid = validateInputValues(args, tool.inputSchema);
+      if (invalid.length) {
+        throw new Error(formatInvalidInputsMessage(tool.name, invalid));
+      }
+
       // Call the handler
       const result = await Promise.resolve(tool.handler(args));
       logger.debug(`Handler returned for tool: ${tool.name}`);
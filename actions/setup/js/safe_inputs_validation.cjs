@@ -6,6 +6,8 @@
  * This module provides validation utilities for safe-inputs MCP server.
  */
 
+const { getErrorMessage } = require("./error_helpers.cjs");
+
 /**
  * Validate required fields in tool arguments
  * @param {Object} args - The arguments object to validate
@@ -27,6 +29,149 @@ function validateRequiredFields(args, inputSchema) {
   return missing;
 }
 
+/**
+ * Check whether a value matches a JSON schema type
+ * @param {any} value - The value to check
+ * @param {string} type - The JSON schema type
+ * @returns {boolean} True if the value has the given type
+ */
+function matchesType(value, type) {
+  switch (type) {
+    case "string":
+      return typeof value === "string";
+    case "number":
+      return typeof value === "number" && Number.isFinite(value);
+    case "integer":
+      return Number.isInteger(value);
+    case "boolean":
+      return typeof value === "boolean";
+    case "array":
+      return Array.isArray(value);
+    case "object":
+      return typeof value === "object" && value !== null && !Array.isArray(value);
+    default:
+      return true;
+  }
+}
+
+/**
+ * Validate a single value against a property schema
+ * @param {string} path - Path of the value used in error messages
+ * @param {any} value - The value to validate
+ * @param {Object} schema - The property schema (type, enum, pattern, minimum, ...)
+ * @param {string[]} errors - Array collecting validation errors
+ */
+function validateValue(path, value, schema, errors) {
+  if (!schema || typeof schema !== "object") {
+    return;
+  }
+
+  if (schema.type && !matchesType(value, schema.type)) {
+    errors.push(`input '${path}' must be of type ${schema.type}`);
+    return;
+  }
+
+  if (Array.isArray(schema.enum) && schema.enum.length > 0) {
+    const allowed = schema.enum.some(/** @param {any} v */ v => JSON.stringify(v) === JSON.stringify(value));
+    if (!allowed) {
+      errors.push(`input '${path}' must be one of ${JSON.stringify(schema.enum)}`);
+    }
+  }
+
+  if (typeof value === "string") {
+    const length = Array.from(value).length;
+    if (typeof schema.minLength === "number" && length < schema.minLength) {
+      errors.push(`input '${path}' must be at least ${schema.minLength} characters`);
+    }
+    if (typeof schema.maxLength === "number" && length > schema.maxLength) {
+      errors.push(`input '${path}' must be at most ${schema.maxLength} characters`);
+    }
+    if (typeof schema.pattern === "string") {
+      let pattern;
+      try {
+        pattern = new RegExp(schema.pattern);
+      } catch (error) {
+        errors.push(`input '${path}' has a pattern that is not a valid JavaScript regular expression: ${getErrorMessage(error)}`);
+      }
+      if (pattern && !pattern.test(value)) {
+        errors.push(`input '${path}' must match pattern ${schema.pattern}`);
+      }
+    }
+  } else if (typeof value === "number") {
+    if (typeof schema.minimum === "number" && value < schema.minimum) {
+      errors.push(`input '${path}' must be >= ${schema.minimum}`);
+    }
+    if (typeof schema.maximum === "number" && value > schema.maximum) {
+      errors.push(`input '${path}' must be <= ${schema.maximum}`);
+    }
+  } else if (Array.isArray(value)) {
+    if (typeof schema.minItems === "number" && value.length < schema.minItems) {
+      errors.push(`input '${path}' must have at least ${schema.minItems} items`);
+    }
+    if (typeof schema.maxItems === "number" && value.length > schema.maxItems) {
+      errors.push(`input '${path}' must have at most ${schema.maxItems} items`);
+    }
+    if (schema.items) {
+      value.forEach((item, i) => validateValue(`${path}[${i}]`, item, schema.items, errors));
+    }
+  } else if (value && typeof value === "object" && schema.properties) {
+    validateProperties(path + ".", value, schema, errors);
+  }
+}
+
+/**
+ * Validate the properties of an object against an object schema
+ * @param {string} prefix - Prefix for property paths in error messages
+ * @param {Object} obj - The object to validate
+ * @param {Object} schema - The object schema with properties and required
+ * @param {string[]} errors - Array collecting validation errors
+ */
+function validateProperties(prefix, obj, schema, errors) {
+  const properties = schema.properties || {};
+  const required = Array.isArray(schema.required) ? schema.required : [];
+
+  for (const name of Object.keys(properties).sort()) {
+    const value = obj[name];
+    if (value === undefined || value === null) {
+      if (required.includes(name)) {
+        errors.push(`missing required input '${prefix}${name}'`);
+      }
+      continue;
+    }
+    validateValue(`${prefix}${name}`, value, properties[name], errors);
+  }
+}
+
+/**
+ * Validate tool arguments against the typed constraints of an input schema.
+ * Top-level required fields are reported separately by validateRequiredFields.
+ * @param {Object} args - The arguments object to validate
+ * @param {Object} inputSchema - The tool input schema
+ * @returns {string[]} Array of validation errors (empty if the arguments are valid)
+ */
+function validateInputValues(args, inputSchema) {
+  if (!inputSchema || !inputSchema.properties || !args) {
+    return [];
+  }
+
+  /** @type {string[]} */
+  const errors = [];
+  validateProperties("", args, { properties: inputSchema.properties }, errors);
+  return errors;
+}
+
+/**
+ * Format validation errors returned by validateInputValues as a tool error message
+ * @param {string} toolName - Name of the tool that was called
+ * @param {string[]} errors - Validation errors
+ * @returns {string} The error message
+ */
+function formatInvalidInputsMessage(toolName, errors) {
+  return `Invalid arguments for tool '${toolName}':\n  - ${errors.join("\n  - ")}`;
+}
+
 module.exports = {
   validateRequiredFields,
+  validateInputValues,
+  formatInvalidInputsMessage,
 };
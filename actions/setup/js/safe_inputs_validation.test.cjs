@@ -135,4 +135,66 @@ describe("safe_inputs_validation.cjs", () => {
       expect(missing).toEqual([]);
     });
   });
+
+  describe("validateInputValues", () => {
+    const schema = {
+      type: "object",
+      properties: {
+        level: { type: "string", enum: ["low", "high"] },
+        ref: { type: "string", pattern: "^v\\d+$", maxLength: 5 },
+        count: { type: "integer", minimum: 1, maximum: 10 },
+        labels: { type: "array", maxItems: 2, items: { type: "string", minLength: 2 } },
+        target: {
+          type: "object",
+          properties: { repo: { type: "string" }, number: { type: "integer" } },
+          required: ["repo"],
+        },
+      },
+    };
+
+    it("should accept valid arguments", async () => {
+      const { validateInputValues } = await import("./safe_inputs_validation.cjs");
+
+      const args = { level: "low", ref: "v12", count: 3, labels: ["ab"], target: { repo: "a/b", number: 2 } };
+
+      expect(validateInputValues(args, schema)).toEqual([]);
+    });
+
+    it("should ignore absent optional arguments", async () => {
+      const { validateInputValues } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateInputValues({}, schema)).toEqual([]);
+    });
+
+    it("should report every violated constraint", async () => {
+      const { validateInputValues } = await import("./safe_inputs_validation.cjs");
+
+      const args = { level: "medium", ref: "main", count: 11, labels: ["a", "bc", "de"], target: { number: 1.5 } };
+
+      expect(validateInputValues(args, schema)).toEqual([
+        "input 'count' must be <= 10",
+        "input 'labels' must have at most 2 items",
+        "input 'labels[0]' must be at least 2 characters",
+        "input 'level' must be one of [\"low\",\"high\"]",
+        "input 'ref' must match pattern ^v\\d+$",
+        "input 'target.number' must be of type integer",
+        "missing required input 'target.repo'",
+      ]);
+    });
+
+    it("should report patterns that are not valid JavaScript regular expressions", async () => {
+      const { validateInputValues } = await import("./safe_inputs_validation.cjs");
+
+      const errors = validateInputValues({ ref: "main" }, { type: "object", properties: { ref: { type: "string", pattern: "(?i)main" } } });
+
+      expect(errors).toHaveLength(1);
+      expect(errors[0]).toContain("input 'ref' has a pattern that is not a valid JavaScript regular expression");
+    });
+
+    it("should report type mismatches", async () => {
+      const { validateInputValues } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateInputValues({ count: "3" }, schema)).toEqual(["input 'count' must be of type integer"]);
+    });
+  });
 });
@@ -0,0 +1,89 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const fs = require("fs");
+const { APPROVAL_MARKER, parseApprovalTypes, requiresApproval, parseApprovedIndices, summarizeItem } = require("./safe_output_approval_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_API } = require("./error_codes.cjs");
+
+/**
+ * Filter agent output items according to the approval checklist
+ * @param {any[]} items - Agent output items
+ * @param {Set<string>} approvalTypes - Types requiring approval (empty = all)
+ * @param {Set<number>} approvedIndices - 1-based indices of approved items
+ * @returns {{kept: any[], discarded: Array<{index: number, item: any}>}}
+ */
+function filterApprovedItems(items, approvalTypes, approvedIndices) {
+  /** @type {any[]} */
+  const kept = [];
+  /** @type {Array<{index: number, item: any}>} */
+  const discarded = [];
+  items.forEach((item, i) => {
+    const index = i + 1;
+    if (!requiresApproval(item.type, approvalTypes) || approvedIndices.has(index)) {
+      kept.push(item);
+    } else {
+      discarded.push({ index, item });
+    }
+  });
+  return { kept, discarded };
+}
+
+/**
+ * Remove unapproved items from the agent output before safe outputs are processed.
+ *
+ * The job running this step is gated by an environment, so it only starts after a
+ * reviewer approved the deployment. Items that require approval are kept only when
+ * they are checked in the approval comment; if the comment cannot be read, all of
+ * them are discarded.
+ *
+ * @returns {Promise<void>}
+ */
+async function main() {
+  const agentOutputFile = process.env.GH_AW_AGENT_OUTPUT;
+  if (!agentOutputFile || !fs.existsSync(agentOutputFile)) {
+    core.info("No agent output to filter");
+    return;
+  }
+
+  const output = JSON.parse(fs.readFileSync(agentOutputFile, "utf8"));
+  const items = Array.isArray(output.items) ? output.items : [];
+  const approvalTypes = parseApprovalTypes(process.env.GH_AW_APPROVAL_TYPES);
+
+  /** @type {Set<number>} */
+  let approvedIndices = new Set();
+  const commentId = parseInt(process.env.GH_AW_APPROVAL_COMMENT_ID || "", 10);
+  if (commentId > 0) {
+    try {
+      const { data: comment } = await github.rest.issues.getComment({ owner: context.repo.owner, repo: context.repo.repo, comment_id: commentId });
+      if (comment.body && comment.body.includes(APPROVAL_MARKER)) {
+        approvedIndices = parseApprovedIndices(comment.body);
+      } else {
+        core.warning("Approval comment does not contain an approval checklist; discarding actions that require approval");
+      }
+    } catch (error) {
+      core.warning(`${ERR_API}: Failed to read approval comment ${commentId}: ${getErrorMessage(error)}`);
+    }
+  }
+
+  const { kept, discarded } = filterApprovedItems(items, approvalTypes, approvedIndices);
+  core.info(`Keeping ${kept.length} of ${items.length} safe output(s); ${discarded.length} not approved`);
+  for (const { index, item } of discarded) {
+    core.info(`Discarding unapproved action ${index} (${item.type}): ${summarizeItem(item)}`);
+  }
+
+  output.items = kept;
+  fs.writeFileSync(agentOutputFile, JSON.stringify(output));
+  core.setOutput("approved_count", String(kept.length));
+  core.setOutput("discarded_count", String(discarded.length));
+
+  if (discarded.length > 0) {
+    let summary = `### 🛂 Approval results\n\n${discarded.length} action(s) were not approved and will not be executed:\n\n`;
+    for (const { index, item } of discarded) {
+      summary += `- Action ${index}: **${item.type}** \`${summarizeItem(item)}\`\n`;
+    }
+    await core.summary.addRaw(summary).write();
+  }
+}
+
+module.exports = { main, filterApprovedItems };
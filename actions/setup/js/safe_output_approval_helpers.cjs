@@ -0,0 +1,126 @@
+// @ts-check
+
+/**
+ * Safe Output Approval Helpers
+ *
+ * Shared by the approval request step (which posts the review checklist) and the
+ * approval filter step (which keeps only the items a reviewer checked).
+ */
+
+/**
+ * Marker identifying approval review comments
+ */
+const APPROVAL_MARKER = "<!-- gh-aw-safe-output-approval -->";
+
+/**
+ * Maximum length of the one-line summary rendered for each item
+ */
+const MAX_SUMMARY_LENGTH = 120;
+
+/**
+ * Maximum length of the JSON details rendered for each item
+ */
+const MAX_DETAILS_LENGTH = 2000;
+
+/**
+ * Parse the comma-separated list of safe output types that require approval.
+ * An empty list means every type requires approval.
+ * @param {string|undefined} value - Comma-separated types (dashes or underscores)
+ * @returns {Set<string>} Normalized type names
+ */
+function parseApprovalTypes(value) {
+  return new Set(
+    (value || "")
+      .split(",")
+      .map(type => type.trim().replace(/-/g, "_"))
+      .filter(Boolean)
+  );
+}
+
+/**
+ * Check whether an item of the given type requires approval
+ * @param {string} type - Item type
+ * @param {Set<string>} approvalTypes - Types requiring approval (empty = all)
+ * @returns {boolean}
+ */
+function requiresApproval(type, approvalTypes) {
+  if (type === "noop" || type === "missing_tool" || type === "missing_data") {
+    return false;
+  }
+  return approvalTypes.size === 0 || approvalTypes.has(String(type).replace(/-/g, "_"));
+}
+
+/**
+ * Build a single-line, markdown-inert summary of an item
+ * @param {any} item - Safe output item
+ * @returns {string}
+ */
+function summarizeItem(item) {
+  const candidate = item.title || item.body || item.message || item.name || "";
+  let summary = String(candidate).replace(/\s+/g, " ").replace(/`/g, "'").trim();
+  if (!summary) {
+    const { type: _type, ...rest } = item;
+    summary = JSON.stringify(rest).replace(/`/g, "'");
+  }
+  if (summary.length > MAX_SUMMARY_LENGTH) {
+    summary = summary.substring(0, MAX_SUMMARY_LENGTH - 1) + "…";
+  }
+  return summary;
+}
+
+/**
+ * Render the approval checklist for the items requiring approval
+ * @param {Array<{index: number, item: any}>} pending - Items requiring approval with their index in the agent output
+ * @param {string} workflowName - Workflow name
+ * @param {string} runUrl - Workflow run URL
+ * @param {string} environment - Environment gating execution
+ * @returns {string} Markdown body
+ */
+function renderApprovalRequest(pending, workflowName, runUrl, environment) {
+  let body = `${APPROVAL_MARKER}\n`;
+  body += `### 🛂 Approval required: ${workflowName}\n\n`;
+  body += `The agent proposed ${pending.length} action(s) that require human approval. `;
+  body += `Check the actions to approve, then approve the \`${environment}\` deployment of [the workflow run](${runUrl}). `;
+  body += "Unchecked actions are discarded.\n\n";
+
+  for (const { index, item } of pending) {
+    body += `- [ ] **${item.type}** \`${summarizeItem(item)}\` <!-- gh-aw-approval-item:${index} -->\n`;
+  }
+
+  body += "\n<details><summary>Proposed action details</summary>\n\n";
+  for (const { index, item } of pending) {
+    let details = JSON.stringify(item, null, 2);
+    if (details.length > MAX_DETAILS_LENGTH) {
+      details = details.substring(0, MAX_DETAILS_LENGTH) + "\n…";
+    }
+    body += `**Action ${index}** (${item.type})\n\n\`\`\`\`json\n${details.replace(/````+/g, "'''")}\n\`\`\`\`\n\n`;
+  }
+  body += "</details>\n";
+  return body;
+}
+
+/**
+ * Parse the indices of checked items from an approval comment
+ * @param {string} body - Comment body
+ * @returns {Set<number>} Indices of approved items
+ */
+function parseApprovedIndices(body) {
+  const approved = new Set();
+  const pattern = /^\s*[-*]\s+\[([ xX])\].*<!-- gh-aw-approval-item:(\d+) -->\s*$/gm;
+  let match;
+  while ((match = pattern.exec(body || "")) !== null) {
+    if (match[1].toLowerCase() === "x") {
+      approved.add(parseInt(match[2], 10));
+    }
+  }
+  return approved;
+}
+
+module.exports = {
+  APPROVAL_MARKER,
+  parseApprovalTypes,
+  requiresApproval,
+  summarizeItem,
+  renderApprovalRequest,
+  parseApprovedIndices,
+};
@@ -0,0 +1,135 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const { APPROVAL_MARKER, parseApprovalTypes, requiresApproval, summarizeItem, renderApprovalRequest, parseApprovedIndices } = await import("./safe_output_approval_helpers.cjs");
+const { main, filterApprovedItems } = await import("./safe_output_approval_filter.cjs");
+
+describe("safe_output_approval_helpers", () => {
+  describe("parseApprovalTypes", () => {
+    it("normalizes dashes to underscores", () => {
+      expect(parseApprovalTypes("create-issue, add_comment")).toEqual(new Set(["create_issue", "add_comment"]));
+    });
+
+    it("returns an empty set when no types are given", () => {
+      expect(parseApprovalTypes("")).toEqual(new Set());
+      expect(parseApprovalTypes(undefined)).toEqual(new Set());
+    });
+  });
+
+  describe("requiresApproval", () => {
+    it("gates every type when no types are configured", () => {
+      expect(requiresApproval("create_issue", new Set())).toBe(true);
+      expect(requiresApproval("create_pull_request", new Set())).toBe(true);
+    });
+
+    it("gates only the configured types", () => {
+      const types = new Set(["create_pull_request"]);
+      expect(requiresApproval("create_pull_request", types)).toBe(true);
+      expect(requiresApproval("add_comment", types)).toBe(false);
+    });
+
+    it("never gates reporting types", () => {
+      expect(requiresApproval("noop", new Set())).toBe(false);
+      expect(requiresApproval("missing_tool", new Set())).toBe(false);
+      expect(requiresApproval("missing_data", new Set())).toBe(false);
+    });
+  });
+
+  describe("summarizeItem", () => {
+    it("uses the title on a single line without backticks", () => {
+      expect(summarizeItem({ type: "create_issue", title: "Fix `foo`\nnow" })).toBe("Fix 'foo' now");
+    });
+
+    it("truncates long summaries", () => {
+      const summary = summarizeItem({ type: "add_comment", body: "x".repeat(500) });
+      expect(summary.length).toBe(120);
+      expect(summary.endsWith("…")).toBe(true);
+    });
+  });
+
+  describe("renderApprovalRequest and parseApprovedIndices", () => {
+    const pending = [
+      { index: 1, item: { type: "create_issue", title: "First" } },
+      { index: 3, item: { type: "create_issue", title: "Second" } },
+    ];
+
+    it("renders an unchecked checklist item per pending action", () => {
+      const body = renderApprovalRequest(pending, "Triage", "https://github.com/octo/app/actions/runs/1", "agent-review");
+      expect(body.startsWith(APPROVAL_MARKER)).toBe(true);
+      expect(body).toContain("- [ ] **create_issue** `First` <!-- gh-aw-approval-item:1 -->");
+      expect(body).toContain("- [ ] **create_issue** `Second` <!-- gh-aw-approval-item:3 -->");
+      expect(body).toContain("`agent-review`");
+      expect(parseApprovedIndices(body)).toEqual(new Set());
+    });
+
+    it("returns the indices of checked items", () => {
+      const body = renderApprovalRequest(pending, "Triage", "https://github.com/octo/app/actions/runs/1", "agent-review").replace("- [ ] **create_issue** `Second`", "- [x] **create_issue** `Second`");
+      expect(parseApprovedIndices(body)).toEqual(new Set([3]));
+    });
+  });
+});
+
+describe("filterApprovedItems", () => {
+  const items = [
+    { type: "create_issue", title: "A" },
+    { type: "add_comment", body: "B" },
+    { type: "create_issue", title: "C" },
+    { type: "noop", message: "done" },
+  ];
+
+  it("keeps ungated and approved items", () => {
+    const { kept, discarded } = filterApprovedItems(items, new Set(["create_issue"]), new Set([3]));
+    expect(kept.map(item => item.title || item.body || item.message)).toEqual(["B", "C", "done"]);
+    expect(discarded).toEqual([{ index: 1, item: items[0] }]);
+  });
+
+  it("discards every gated item when nothing is approved", () => {
+    const { kept, discarded } = filterApprovedItems(items, new Set(), new Set());
+    expect(kept).toEqual([items[3]]);
+    expect(discarded.map(d => d.index)).toEqual([1, 2, 3]);
+  });
+});
+
+describe("main", () => {
+  let tmpDir;
+  let outputFile;
+
+  beforeEach(() => {
+    tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "approval-filter-"));
+    outputFile = path.join(tmpDir, "agent_output.json");
+    process.env.GH_AW_AGENT_OUTPUT = outputFile;
+    process.env.GH_AW_APPROVAL_COMMENT_ID = "42";
+    process.env.GH_AW_APPROVAL_TYPES = "";
+    global.core = { info: vi.fn(), warning: vi.fn(), setOutput: vi.fn(), summary: { addRaw: vi.fn().mockReturnValue({ write: vi.fn() }) } };
+    global.context = { repo: { owner: "octo", repo: "app" } };
+  });
+
+  afterEach(() => {
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+    delete process.env.GH_AW_AGENT_OUTPUT;
+    delete process.env.GH_AW_APPROVAL_COMMENT_ID;
+    delete process.env.GH_AW_APPROVAL_TYPES;
+    delete global.core;
+    delete global.github;
+    delete global.context;
+  });
+
+  it("removes rejected custom job items from the agent output the job reads", async () => {
+    const items = [
+      { type: "deploy", environment: "production" },
+      { type: "deploy", environment: "staging" },
+    ];
+    fs.writeFileSync(outputFile, JSON.stringify({ items, errors: [] }));
+    const pending = items.map((item, i) => ({ index: i + 1, item }));
+    const body = renderApprovalRequest(pending, "Deploy", "https://github.com/octo/app/actions/runs/1", "agent-review").replace(/- \[ \](.*<!-- gh-aw-approval-item:2 -->)/, "- [x]$1");
+    global.github = { rest: { issues: { getComment: vi.fn().mockResolvedValue({ data: { body } }) } } };
+
+    await main();
+
+    const output = JSON.parse(fs.readFileSync(outputFile, "utf8"));
+    expect(output.items).toEqual([items[1]]);
+    expect(global.core.setOutput).toHaveBeenCalledWith("discarded_count", "1");
+  });
+});
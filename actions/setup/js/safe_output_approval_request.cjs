@@ -0,0 +1,61 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { loadAgentOutput } = require("./load_agent_output.cjs");
+const { parseApprovalTypes, requiresApproval, renderApprovalRequest } = require("./safe_output_approval_helpers.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_API } = require("./error_codes.cjs");
+
+/**
+ * Post the approval checklist for the safe outputs that require human review.
+ *
+ * The checklist is posted on the triggering issue or pull request, or in a new issue
+ * when the workflow was not triggered by one. The comment is referenced by the
+ * safe_outputs job, which only executes the items checked by a reviewer.
+ *
+ * @returns {Promise<void>}
+ */
+async function main() {
+  const approvalTypes = parseApprovalTypes(process.env.GH_AW_APPROVAL_TYPES);
+  const environment = process.env.GH_AW_APPROVAL_ENVIRONMENT || "";
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME || "Workflow";
+  const runUrl = `${context.serverUrl}/${context.repo.owner}/${context.repo.repo}/actions/runs/${context.runId}`;
+
+  const result = loadAgentOutput();
+  const items = result.success ? result.items : [];
+  // Indices are 1-based positions in the agent output so that the filter can match them
+  const pending = items.map((item, i) => ({ index: i + 1, item })).filter(({ item }) => requiresApproval(item.type, approvalTypes));
+
+  core.setOutput("pending_count", String(pending.length));
+  if (pending.length === 0) {
+    core.info("No safe outputs require approval");
+    return;
+  }
+  core.info(`${pending.length} safe output(s) require approval`);
+
+  const body = renderApprovalRequest(pending, workflowName, runUrl, environment);
+  const { owner, repo } = context.repo;
+  const triggeringNumber = context.payload?.issue?.number || context.payload?.pull_request?.number;
+
+  try {
+    let comment;
+    if (triggeringNumber) {
+      const response = await github.rest.issues.createComment({ owner, repo, issue_number: triggeringNumber, body });
+      comment = response.data;
+    } else {
+      // Without a triggering issue or pull request, the checklist lives in its own issue
+      const issue = await github.rest.issues.create({ owner, repo, title: `[approval] ${workflowName} run ${context.runId}`, body: `Approval request for [${workflowName}](${runUrl}).` });
+      const response = await github.rest.issues.createComment({ owner, repo, issue_number: issue.data.number, body });
+      comment = response.data;
+    }
+
+    core.setOutput("comment_id", String(comment.id));
+    core.setOutput("comment_url", comment.html_url);
+    core.info(`Approval request posted: ${comment.html_url}`);
+    await core.summary.addRaw(`### 🛂 Approval required\n\n${pending.length} action(s) are waiting for review: ${comment.html_url}\n`).write();
+  } catch (error) {
+    core.setFailed(`${ERR_API}: Failed to post approval request: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main };
@@ -20,6 +20,7 @@ const { getIssuesToAssignCopilot } = require("./create_issue.cjs");
 const { createReviewBuffer } = require("./pr_review_buffer.cjs");
 const { sanitizeContent } = require("./sanitize_content.cjs");
 const { createManifestLogger, ensureManifestExists, extractCreatedItemFromResult } = require("./safe_output_manifest.cjs");
+const { loadTargets, createTargetRouter } = require("./safe_output_target_router.cjs");
 
 /**
  * Handler map configuration
@@ -73,6 +74,20 @@ const HANDLER_MAP = {
  */
 const STANDALONE_STEP_TYPES = new Set(["assign_to_agent", "create_agent_session", "upload_asset", "noop"]);
 
+/**
+ * Get the custom safe-output types declared in safe-outputs.types.
+ * These are processed by their own jobs, like the standalone types above.
+ * @returns {Set<string>} Set of normalized custom type names
+ */
+function getCustomSafeOutputTypes() {
+  return new Set(
+    (process.env.GH_AW_CUSTOM_SAFE_OUTPUT_TYPES || "")
+      .split(",")
+      .map(type => type.trim())
+      .filter(Boolean)
+  );
+}
+
 /**
  * Code-push safe output types that must succeed before remaining outputs are processed.
  * If any of these fail, the remaining non-code-push messages are cancelled with a clear reason.
@@ -214,11 +229,26 @@ function collectMissingMessages(messages) {
  * @param {Map<string, Function>} messageHandlers - Map of message handler functions
  * @param {Array<Object>} messages - Array of safe output messages
  * @param {((item: {type: string, url?: string, number?: number, repo?: string, temporaryId?: string}) => void)|null} [onItemCreated] - Optional callback invoked after each successful create operation (for manifest logging)
+ * @param {{run: (message: any, processMessage: () => Promise<any>) => Promise<any>}|null} [targetRouter] - Optional router selecting the credentials of the repository each message targets (safe-outputs.targets)
  * @returns {Promise<{success: boolean, results: Array<any>, temporaryIdMap: Object, outputsWithUnresolvedIds: Array<any>, missings: Object, codePushFailures: Array<{type: string, error: string}>}>}
  */
-async function processMessages(messageHandlers, messages, onItemCreated = null) {
+async function processMessages(messageHandlers, messages, onItemCreated = null, targetRouter = null) {
   const results = [];
 
+  /**
+   * Call a message handler, with the credentials of the targeted repository when targets are declared
+   * @param {Function} handler
+   * @param {any} message
+   * @param {Object} resolvedTemporaryIds
+   * @param {Map<string, {repo: string, number: number}>} temporaryIdMap
+   * @returns {Promise<any>}
+   */
+  const callHandler = (handler, message, resolvedTemporaryIds, temporaryIdMap) => {
+    const processMessage = () => handler(message, resolvedTemporaryIds, temporaryIdMap);
+    return targetRouter ? targetRouter.run(message, processMessage) : processMessage();
+  };
+  const customTypes = getCustomSafeOutputTypes();
+
   // Collect missing_tool and missing_data messages first
   const missings = collectMissingMessages(messages);
 
@@ -273,7 +303,7 @@ async function processMessages(messageHandlers, messages, onItemCreated = null)
 
     if (!messageHandler) {
       // Check if this message type is handled by a standalone step
-      if (STANDALONE_STEP_TYPES.has(messageType)) {
+      if (STANDALONE_STEP_TYPES.has(messageType) || customTypes.has(messageType)) {
         // Silently skip - this is handled by a dedicated step
         core.debug(`Message ${i + 1} (${messageType}) will be handled by standalone step`);
         results.push({
@@ -309,7 +339,7 @@ async function processMessages(messageHandlers, messages, onItemCreated = null)
       const tempIdMapSizeBefore = temporaryIdMap.size;
 
       // Call the message handler with the individual message and resolved temp IDs
-      const result = await messageHandler(message, resolvedTemporaryIds, temporaryIdMap);
+      const result = await callHandler(messageHandler, message, resolvedTemporaryIds, temporaryIdMap);
 
       // Check if the handler explicitly returned a failure
       if (result && result.success === false && !result.deferred) {
@@ -460,7 +490,7 @@ async function processMessages(messageHandlers, messages, onItemCreated = null)
         const tempIdMapSizeBefore = temporaryIdMap.size;
 
         // Call the handler again with updated temp ID map
-        const result = await deferred.handler(deferred.message, resolvedTemporaryIds, temporaryIdMap);
+        const result = await callHandler(deferred.handler, deferred.message, resolvedTemporaryIds, temporaryIdMap);
 
         // Check if the handler explicitly returned a failure
         if (result && result.success === false && !result.deferred) {
@@ -847,8 +877,15 @@ async function main() {
     // In staged mode, pass null so no items are logged (nothing is actually created).
     const logCreatedItem = isStaged ? null : createManifestLogger();
 
+    // Route messages to the credentials of the repository they target when safe-outputs.targets is declared
+    const targets = loadTargets();
+    const targetRouter = targets.length > 0 ? createTargetRouter(config, targets) : null;
+    if (targetRouter) {
+      core.info(`Routing messages to ${targets.length} declared target(s): ${targets.map(target => target.repo).join(", ")}`);
+    }
+
     // Process all messages in order of appearance
-    const processingResult = await processMessages(messageHandlers, agentOutput.items, logCreatedItem);
+    const processingResult = await processMessages(messageHandlers, agentOutput.items, logCreatedItem, targetRouter);
 
     // Finalize buffered PR review — submit when comments or metadata exist
     if (prReviewBuffer.hasBufferedComments() || prReviewBuffer.hasReviewMetadata()) {
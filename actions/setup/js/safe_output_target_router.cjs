@@ -0,0 +1,156 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Safe Output Target Router
+ *
+ * Routes safe output messages to the credentials declared in safe-outputs.targets.
+ * GH_AW_SAFE_OUTPUTS_TARGETS lists the declared targets ("owner/repo" or "owner/*") and the
+ * environment variable holding the token of each target. Before a message is processed, the
+ * repository it targets is resolved and the global github client is replaced by a client
+ * authenticated for that target. Messages for repositories that are not declared are rejected.
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_CONFIG, ERR_PARSE } = require("./error_codes.cjs");
+const { getDefaultTargetRepo, parseRepoSlug } = require("./repo_helpers.cjs");
+
+/**
+ * @typedef {Object} SafeOutputTarget
+ * @property {string} repo - Target repository ("owner/repo") or organization wildcard ("owner/*")
+ * @property {string} [token_env] - Environment variable holding the token of the target
+ */
+
+/**
+ * Load the declared targets from GH_AW_SAFE_OUTPUTS_TARGETS
+ * @returns {SafeOutputTarget[]} Declared targets, empty when targets are not configured
+ */
+function loadTargets() {
+  const value = process.env.GH_AW_SAFE_OUTPUTS_TARGETS;
+  if (!value) {
+    return [];
+  }
+  try {
+    const targets = JSON.parse(value);
+    if (!Array.isArray(targets)) {
+      throw new Error("expected an array of targets");
+    }
+    return targets;
+  } catch (error) {
+    throw new Error(`${ERR_PARSE}: Failed to parse GH_AW_SAFE_OUTPUTS_TARGETS: ${getErrorMessage(error)}`);
+  }
+}
+
+/**
+ * Find the target declared for a repository, preferring an exact match over an organization wildcard
+ * @param {SafeOutputTarget[]} targets - Declared targets
+ * @param {string} repo - Repository slug ("owner/repo")
+ * @returns {SafeOutputTarget|null}
+ */
+function matchTarget(targets, repo) {
+  const exact = targets.find(target => target.repo === repo);
+  if (exact) {
+    return exact;
+  }
+  const parts = parseRepoSlug(repo);
+  if (!parts) {
+    return null;
+  }
+  return targets.find(target => target.repo === `${parts.owner}/*`) || null;
+}
+
+/**
+ * Resolve the repository targeted by a message: the repo field of the message, qualified with
+ * the owner of the default repository when it is a bare name, or the target-repo of the handler
+ * @param {any} message - Safe output message
+ * @param {Object} handlerConfig - Configuration of the handler processing the message
+ * @returns {string} Repository slug
+ */
+function resolveMessageRepo(message, handlerConfig) {
+  const defaultRepo = getDefaultTargetRepo(handlerConfig);
+  const repo = typeof message.repo === "string" ? message.repo.trim() : "";
+  if (!repo) {
+    return defaultRepo;
+  }
+  if (repo.includes("/")) {
+    return repo;
+  }
+  const defaultParts = parseRepoSlug(defaultRepo);
+  return defaultParts ? `${defaultParts.owner}/${repo}` : repo;
+}
+
+/**
+ * Create a GitHub client authenticated with a token
+ * @param {string} token - GitHub token
+ * @returns {Promise<any>} Octokit instance
+ */
+async function createGitHubClient(token) {
+  // Lazy-load @actions/github, which is only installed when targets have their own credentials
+  const { getOctokit } = await import("@actions/github");
+  return getOctokit(token);
+}
+
+/**
+ * Create a router for the declared targets
+ * @param {Object} config - Handler manager configuration keyed by message type
+ * @param {SafeOutputTarget[]} targets - Declared targets
+ * @param {(token: string) => Promise<any>} [createClient] - Factory for authenticated clients
+ * @returns {{run: (message: any, processMessage: () => Promise<any>) => Promise<any>}}
+ */
+function createTargetRouter(config, targets, createClient = createGitHubClient) {
+  const currentRepo = `${context.repo.owner}/${context.repo.repo}`;
+  /** @type {Map<string, any>} */
+  const clients = new Map();
+
+  /**
+   * @param {SafeOutputTarget} target
+   * @returns {Promise<any>}
+   */
+  async function clientFor(target) {
+    if (!clients.has(target.repo)) {
+      const token = target.token_env ? process.env[target.token_env] : "";
+      if (!token) {
+        throw new Error(`${ERR_CONFIG}: No token is available for safe-outputs target '${target.repo}' (${target.token_env})`);
+      }
+      core.info(`Setting up GitHub client for safe-outputs target ${target.repo}`);
+      clients.set(target.repo, await createClient(token));
+    }
+    return clients.get(target.repo);
+  }
+
+  return {
+    /**
+     * Process a message with the client of the repository it targets
+     * @param {any} message - Safe output message
+     * @param {() => Promise<any>} processMessage - Calls the handler of the message
+     * @returns {Promise<any>} Result of the handler
+     */
+    async run(message, processMessage) {
+      const repo = resolveMessageRepo(message, config[message.type] || {});
+      const target = matchTarget(targets, repo);
+      if (!target) {
+        if (repo === currentRepo) {
+          return processMessage();
+        }
+        return {
+          success: false,
+          error: `Repository '${repo}' is not declared in safe-outputs.targets. Declared targets: ${targets.map(t => t.repo).join(", ")}`,
+        };
+      }
+      if (!target.token_env) {
+        return processMessage();
+      }
+
+      core.info(`Using credentials of safe-outputs target ${target.repo} for ${repo}`);
+      const defaultClient = global.github;
+      global.github = await clientFor(target);
+      try {
+        return await processMessage();
+      } finally {
+        global.github = defaultClient;
+      }
+    },
+  };
+}
+
+module.exports = { loadTargets, matchTarget, resolveMessageRepo, createTargetRouter };
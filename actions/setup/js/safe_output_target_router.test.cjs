@@ -0,0 +1,108 @@
+// @ts-check
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+describe("safe_output_target_router", () => {
+  let router;
+  let defaultGithub;
+
+  const targets = [{ repo: "acme/docs", token_env: "GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_0" }, { repo: "acme/*", token_env: "GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_1" }, { repo: "other/public" }];
+
+  beforeEach(async () => {
+    defaultGithub = { name: "default" };
+    global.core = { info: vi.fn(), warning: vi.fn(), debug: vi.fn() };
+    global.github = defaultGithub;
+    global.context = { repo: { owner: "acme", repo: "app" } };
+
+    process.env.GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_0 = "docs-token";
+    process.env.GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_1 = "org-token";
+    delete process.env.GH_AW_TARGET_REPO_SLUG;
+
+    vi.resetModules();
+    router = await import("./safe_output_target_router.cjs");
+  });
+
+  describe("loadTargets", () => {
+    it("should return no targets when not configured", () => {
+      delete process.env.GH_AW_SAFE_OUTPUTS_TARGETS;
+
+      expect(router.loadTargets()).toEqual([]);
+    });
+
+    it("should parse the declared targets", () => {
+      process.env.GH_AW_SAFE_OUTPUTS_TARGETS = JSON.stringify(targets);
+
+      expect(router.loadTargets()).toEqual(targets);
+    });
+  });
+
+  describe("matchTarget", () => {
+    it("should prefer an exact match over an organization wildcard", () => {
+      expect(router.matchTarget(targets, "acme/docs").repo).toBe("acme/docs");
+      expect(router.matchTarget(targets, "acme/tracker").repo).toBe("acme/*");
+      expect(router.matchTarget(targets, "evil/repo")).toBeNull();
+    });
+  });
+
+  describe("resolveMessageRepo", () => {
+    it("should use the repo field of the message, qualified with the default owner", () => {
+      expect(router.resolveMessageRepo({ repo: "docs" }, {})).toBe("acme/docs");
+      expect(router.resolveMessageRepo({ repo: "other/public" }, {})).toBe("other/public");
+    });
+
+    it("should fall back to the target-repo of the handler", () => {
+      expect(router.resolveMessageRepo({}, { "target-repo": "acme/tracker" })).toBe("acme/tracker");
+      expect(router.resolveMessageRepo({}, {})).toBe("acme/app");
+    });
+  });
+
+  describe("createTargetRouter", () => {
+    it("should process the message with the client of the target and restore the default client", async () => {
+      const createClient = vi.fn().mockImplementation(async token => ({ token }));
+      const targetRouter = router.createTargetRouter({ create_issue: { "target-repo": "acme/tracker" } }, targets, createClient);
+      let usedClient;
+
+      const result = await targetRouter.run({ type: "create_issue" }, async () => {
+        usedClient = global.github;
+        return { success: true };
+      });
+
+      expect(result).toEqual({ success: true });
+      expect(usedClient).toEqual({ token: "org-token" });
+      expect(global.github).toBe(defaultGithub);
+    });
+
+    it("should reuse the client of a target", async () => {
+      const createClient = vi.fn().mockImplementation(async token => ({ token }));
+      const targetRouter = router.createTargetRouter({}, targets, createClient);
+
+      await targetRouter.run({ type: "add_comment", repo: "acme/docs" }, async () => ({ success: true }));
+      await targetRouter.run({ type: "add_comment", repo: "docs" }, async () => ({ success: true }));
+
+      expect(createClient).toHaveBeenCalledTimes(1);
+      expect(createClient).toHaveBeenCalledWith("docs-token");
+    });
+
+    it("should use the default client for the current repository and targets without credentials", async () => {
+      const createClient = vi.fn();
+      const targetRouter = router.createTargetRouter({}, [{ repo: "other/public" }], createClient);
+      const processMessage = vi.fn().mockResolvedValue({ success: true });
+
+      await targetRouter.run({ type: "add_comment" }, processMessage);
+      await targetRouter.run({ type: "add_comment", repo: "other/public" }, processMessage);
+
+      expect(processMessage).toHaveBeenCalledTimes(2);
+      expect(createClient).not.toHaveBeenCalled();
+    });
+
+    it("should reject messages for repositories that are not declared", async () => {
+      const targetRouter = router.createTargetRouter({}, targets, vi.fn());
+      const processMessage = vi.fn();
+
+      const result = await targetRouter.run({ type: "create_issue", repo: "evil/repo" }, processMessage);
+
+      expect(result.success).toBe(false);
+      expect(result.error).toContain("Repository 'evil/repo' is not declared in safe-outputs.targets");
+      expect(processMessage).not.toHaveBeenCalled();
+    });
+  });
+});
@@ -0,0 +1,312 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Threat Detection Scanners
+ *
+ * This module runs deterministic scanners over the agent output and patches before
+ * the AI threat detection analysis. Each scanner applies fixed rules and produces
+ * structured findings, which are written to scanner_findings.json and merged into
+ * the detection verdict by parse_threat_detection_results.cjs.
+ *
+ * Scanners:
+ * - secrets: credential patterns in added patch lines and in agent output text
+ * - workflow-files: modifications of .github/workflows/** in patches
+ * - binary-files: binary files in patches
+ * - large-files: files whose added content exceeds the configured size
+ * - dependencies: suspicious dependency additions in package manifests
+ */
+
+const fs = require("fs");
+const path = require("path");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { AGENT_OUTPUT_FILENAME } = require("./constants.cjs");
+const { BUILT_IN_PATTERNS } = require("./redact_secrets.cjs");
+
+const THREAT_DETECTION_DIR = "/tmp/gh-aw/threat-detection";
+const SCANNER_FINDINGS_FILENAME = "scanner_findings.json";
+
+/**
+ * Credential patterns checked by the secrets scanner, taken from the redaction detectors
+ */
+const SECRET_PATTERNS = [
+  ...BUILT_IN_PATTERNS.filter(({ name }) => name !== "Private Key"),
+  // Patches often contain only part of a key, so the header alone is reported
+  { name: "Private Key", pattern: /-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY( BLOCK)?-----/g },
+];
+
+/**
+ * Rules of the dependencies scanner. Each rule applies to added lines of matching manifest files.
+ * @type {Array<{rule: string, files: RegExp, pattern: RegExp, message: string}>}
+ */
+const DEPENDENCY_RULES = [
+  {
+    rule: "npm-remote-source",
+    files: /(^|\/)package\.json$/,
+    pattern: /"[^"]+"\s*:\s*"(?:git\+|git:|https?:|file:|github:|gitlab:|bitbucket:)/,
+    message: "dependency installed from a URL, git repository or local path instead of the registry",
+  },
+  {
+    rule: "npm-install-script",
+    files: /(^|\/)package\.json$/,
+    pattern: /"(?:preinstall|install|postinstall|prepare)"\s*:/,
+    message: "install lifecycle script added",
+  },
+  {
+    rule: "registry-override",
+    files: /(^|\/)(?:\.npmrc|\.yarnrc(?:\.yml)?|pip\.conf|\.pypirc)$/,
+    pattern: /(?:registry|index-url|npmRegistryServer)\s*[=:]/,
+    message: "package registry overridden",
+  },
+  {
+    rule: "pip-remote-source",
+    files: /(^|\/)(?:requirements[^/]*\.txt|constraints[^/]*\.txt)$/,
+    pattern: /(?:^\s*(?:--(?:extra-)?index-url|--trusted-host|-i)\b|git\+|https?:\/\/)/,
+    message: "Python package installed from a custom index or URL",
+  },
+  {
+    rule: "go-replace",
+    files: /(^|\/)go\.mod$/,
+    pattern: /(?:^\s*replace\b|=>)/,
+    message: "Go module replaced with another module or a local path",
+  },
+  {
+    rule: "gem-remote-source",
+    files: /(^|\/)Gemfile$/,
+    pattern: /(?:\bgit:|\bgithub:|^\s*source\s+['"](?!https:\/\/rubygems\.org))/,
+    message: "gem installed from a git repository or custom source",
+  },
+  {
+    rule: "cargo-remote-source",
+    files: /(^|\/)Cargo\.toml$/,
+    pattern: /\b(?:git|path)\s*=\s*"/,
+    message: "crate installed from a git repository or local path",
+  },
+];
+
+/**
+ * @typedef {Object} ScannerConfig
+ * @property {boolean} [secrets] - Scan for credential patterns
+ * @property {boolean} [workflow_files] - Flag modifications of workflow files
+ * @property {boolean} [binary_files] - Flag binary files
+ * @property {number} [max_file_size] - Flag files with more added content than this size in KB (0 disables)
+ * @property {boolean} [dependencies] - Flag suspicious dependency additions
+ */
+
+/**
+ * @typedef {Object} ScannerFinding
+ * @property {string} scanner - Scanner that produced the finding
+ * @property {string} rule - Rule that matched
+ * @property {string} message - Human readable description
+ * @property {string} [file] - Patch or output file the finding was found in
+ * @property {string} [path] - Path of the changed file in the patch
+ * @property {number} [line] - Line in the changed file
+ */
+
+/**
+ * @typedef {Object} PatchedFile
+ * @property {string} path - Path of the file after the change
+ * @property {Array<{line: number, text: string}>} added - Added lines with their line numbers
+ * @property {number} addedBytes - Size of the added content
+ * @property {boolean} binary - Whether the change is a binary diff
+ */
+
+/**
+ * Parses a git patch into the files it changes and their added lines
+ * @param {string} patch - Patch content (git diff or git format-patch output)
+ * @returns {PatchedFile[]} Changed files
+ */
+function parsePatch(patch) {
+  /** @type {PatchedFile[]} */
+  const files = [];
+  /** @type {PatchedFile | null} */
+  let current = null;
+  let newLine = 0;
+  let inHunk = false;
+
+  for (const line of patch.split("\n")) {
+    const diffHeader = line.match(/^diff --git a\/.+ b\/(.+)$/);
+    if (diffHeader) {
+      current = { path: diffHeader[1], added: [], addedBytes: 0, binary: false };
+      files.push(current);
+      inHunk = false;
+      continue;
+    }
+    if (!current) {
+      continue;
+    }
+    const hunkHeader = line.match(/^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@/);
+    if (hunkHeader) {
+      newLine = parseInt(hunkHeader[1], 10);
+      inHunk = true;
+      continue;
+    }
+    if (!inHunk) {
+      if (line === "GIT binary patch" || /^Binary files .* differ$/.test(line)) {
+        current.binary = true;
+      }
+      continue;
+    }
+    if (line.startsWith("+")) {
+      const text = line.substring(1);
+      current.added.push({ line: newLine, text });
+      current.addedBytes += Buffer.byteLength(text, "utf8") + 1;
+      newLine++;
+    } else if (line.startsWith(" ")) {
+      newLine++;
+    } else if (!line.startsWith("-") && !line.startsWith("\\")) {
+      inHunk = false;
+    }
+  }
+  return files;
+}
+
+/**
+ * Finds the credential patterns in a text
+ * @param {string} text - Text to scan
+ * @returns {string[]} Names of the matched patterns
+ */
+function findSecretPatterns(text) {
+  const matched = [];
+  for (const { name, pattern } of SECRET_PATTERNS) {
+    if (text.match(pattern)) {
+      matched.push(name);
+    }
+  }
+  return matched;
+}
+
+/**
+ * Scans the files of a patch
+ * @param {string} patchFile - Name of the patch file
+ * @param {PatchedFile[]} files - Files changed by the patch
+ * @param {ScannerConfig} config - Enabled scanners
+ * @returns {ScannerFinding[]} Findings
+ */
+function scanPatch(patchFile, files, config) {
+  /** @type {ScannerFinding[]} */
+  const findings = [];
+  const maxBytes = (config.max_file_size || 0) * 1024;
+
+  for (const file of files) {
+    if (config.workflow_files && file.path.startsWith(".github/workflows/")) {
+      findings.push({ scanner: "workflow-files", rule: "workflow-modification", file: patchFile, path: file.path, message: `workflow file ${file.path} is modified` });
+    }
+    if (config.binary_files && file.binary) {
+      findings.push({ scanner: "binary-files", rule: "binary-file", file: patchFile, path: file.path, message: `binary file ${file.path} is added or modified` });
+    }
+    if (maxBytes > 0 && file.addedBytes > maxBytes) {
+      findings.push({ scanner: "large-files", rule: "large-file", file: patchFile, path: file.path, message: `${file.path} adds ${Math.ceil(file.addedBytes / 1024)} KB (limit: ${config.max_file_size} KB)` });
+    }
+    for (const added of file.added) {
+      if (config.secrets) {
+        for (const name of findSecretPatterns(added.text)) {
+          findings.push({ scanner: "secrets", rule: name, file: patchFile, path: file.path, line: added.line, message: `${name} in ${file.path}:${added.line}` });
+        }
+      }
+      if (config.dependencies) {
+        for (const rule of DEPENDENCY_RULES) {
+          if (rule.files.test(file.path) && rule.pattern.test(added.text)) {
+            findings.push({ scanner: "dependencies", rule: rule.rule, file: patchFile, path: file.path, line: added.line, message: `${rule.message} in ${file.path}:${added.line}` });
+          }
+        }
+      }
+    }
+  }
+  return findings;
+}
+
+/**
+ * Scans the text fields of the agent output items (titles, bodies, comments) for credentials
+ * @param {{items?: Array<Record<string, any>>}} agentOutput - Parsed agent output
+ * @returns {ScannerFinding[]} Findings
+ */
+function scanAgentOutput(agentOutput) {
+  /** @type {ScannerFinding[]} */
+  const findings = [];
+
+  /**
+   * @param {any} value
+   * @param {string} location
+   */
+  const visit = (value, location) => {
+    if (typeof value === "string") {
+      for (const name of findSecretPatterns(value)) {
+        findings.push({ scanner: "secrets", rule: name, file: AGENT_OUTPUT_FILENAME, path: location, message: `${name} in ${location}` });
+      }
+    } else if (Array.isArray(value)) {
+      value.forEach((item, index) => visit(item, `${location}[${index}]`));
+    } else if (value && typeof value === "object") {
+      for (const [key, item] of Object.entries(value)) {
+        visit(item, `${location}.${key}`);
+      }
+    }
+  };
+
+  (agentOutput.items || []).forEach((item, index) => visit(item, `items[${index}]`));
+  return findings;
+}
+
+/**
+ * Runs the configured scanners over the files in a threat detection directory
+ * @param {string} dir - Directory with agent_output.json and aw-*.patch files
+ * @param {ScannerConfig} config - Enabled scanners
+ * @returns {ScannerFinding[]} Findings
+ */
+function runScanners(dir, config) {
+  /** @type {ScannerFinding[]} */
+  const findings = [];
+
+  const agentOutputPath = path.join(dir, AGENT_OUTPUT_FILENAME);
+  if (config.secrets && fs.existsSync(agentOutputPath)) {
+    try {
+      findings.push(...scanAgentOutput(JSON.parse(fs.readFileSync(agentOutputPath, "utf8"))));
+    } catch (error) {
+      core.warning(`Failed to scan agent output: ${getErrorMessage(error)}`);
+    }
+  }
+
+  const patchFiles = fs.existsSync(dir) ? fs.readdirSync(dir).filter(entry => /^aw-.+\.patch$/.test(entry)) : [];
+  for (const patchFile of patchFiles.sort()) {
+    const files = parsePatch(fs.readFileSync(path.join(dir, patchFile), "utf8"));
+    core.info(`Scanning ${patchFile}: ${files.length} changed file(s)`);
+    findings.push(...scanPatch(patchFile, files, config));
+  }
+  return findings;
+}
+
+/**
+ * Main entry point for the threat detection scanners
+ * @returns {Promise<void>}
+ */
+async function main() {
+  /** @type {ScannerConfig} */
+  let config;
+  try {
+    config = JSON.parse(process.env.GH_AW_DETECTION_SCANNERS || "{}");
+  } catch (error) {
+    core.warning(`Invalid GH_AW_DETECTION_SCANNERS configuration, scanners skipped: ${getErrorMessage(error)}`);
+    return;
+  }
+
+  const findings = runScanners(THREAT_DETECTION_DIR, config);
+  fs.mkdirSync(THREAT_DETECTION_DIR, { recursive: true });
+  fs.writeFileSync(path.join(THREAT_DETECTION_DIR, SCANNER_FINDINGS_FILENAME), JSON.stringify({ findings }, null, 2));
+  core.setOutput("findings_count", String(findings.length));
+
+  if (findings.length === 0) {
+    core.info("✅ Threat detection scanners found no issues");
+    return;
+  }
+
+  for (const finding of findings) {
+    core.warning(`[${finding.scanner}] ${finding.message}`);
+  }
+  const rows = findings.map(finding => `| ${finding.scanner} | ${finding.rule} | ${finding.message.replace(/\|/g, "\\|")} |`);
+  await core.summary
+    .addRaw(`### 🔎 Threat detection scanners: ${findings.length} finding(s)\n\n`)
+    .addRaw("| Scanner | Rule | Finding |\n|---|---|---|\n" + rows.join("\n") + "\n")
+    .write();
+}
+
+module.exports = { main, parsePatch, scanPatch, scanAgentOutput, runScanners, SCANNER_FINDINGS_FILENAME };
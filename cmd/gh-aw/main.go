@@ -116,18 +116,41 @@ When called with a workflow name, creates a template file with comprehensive exa
 - Tools configuration (github, claude, MCPs)
 - All frontmatter options with explanations
 
+With --template, renders a parameterized workflow template instead. Templates are referenced
+by name (from .github/aw/templates/), by local path, or as owner/repo/path.md[@ref]. Parameter
+values are given with --param name=value; missing values are prompted for in a terminal and
+otherwise fall back to their defaults. The rendered workflow is compiled to validate it.
+
 ` + cli.WorkflowIDExplanation + `
 
 Examples:
   ` + string(constants.CLIExtensionPrefix) + ` new                      # Interactive mode
   ` + string(constants.CLIExtensionPrefix) + ` new my-workflow          # Create template file
   ` + string(constants.CLIExtensionPrefix) + ` new my-workflow.md       # Same as above (.md extension stripped)
-  ` + string(constants.CLIExtensionPrefix) + ` new my-workflow --force  # Overwrite if exists`,
+  ` + string(constants.CLIExtensionPrefix) + ` new my-workflow --force  # Overwrite if exists
+  ` + string(constants.CLIExtensionPrefix) + ` new triage --template triage-bot --param labels="bug, question"
+  ` + string(constants.CLIExtensionPrefix) + ` new deps --template dependency-updater --param ecosystem=go`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		forceFlag, _ := cmd.Flags().GetBool("force")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		interactiveFlag, _ := cmd.Flags().GetBool("interactive")
+		templateFlag, _ := cmd.Flags().GetString("template")
+		paramFlags, _ := cmd.Flags().GetStringArray("param")
+
+		if templateFlag != "" {
+			if interactiveFlag {
+				return errors.New("--template cannot be combined with --interactive")
+			}
+			workflowName := ""
+			if len(args) > 0 {
+				workflowName = args[0]
+			}
+			return cli.NewWorkflowFromTemplate(workflowName, templateFlag, paramFlags, verbose, forceFlag)
+		}
+		if len(paramFlags) > 0 {
+			return errors.New("--param requires --template")
+		}
 
 		// If no arguments provided or interactive flag is set, use interactive mode
 		if len(args) == 0 || interactiveFlag {
@@ -360,7 +383,10 @@ Examples:
   gh aw run daily-perf-improver --auto-merge-prs # Auto-merge any PRs created during execution
   gh aw run daily-perf-improver -F name=value -F env=prod  # Pass workflow inputs
   gh aw run daily-perf-improver --push  # Commit and push workflow files before running
-  gh aw run daily-perf-improver --dry-run  # Validate without actually running`,
+  gh aw run daily-perf-improver --dry-run  # Validate without actually running
+  gh aw run daily-perf-improver --follow   # Stream job progress and agent logs until completion
+  gh aw run triage --preset small-issue    # Use inputs from .github/aw/presets/triage.yml
+  gh aw run triage --matrix preset='*' --matrix engine=copilot,claude --push  # Compare presets and engines`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repeatCount, _ := cmd.Flags().GetInt("repeat")
@@ -372,6 +398,10 @@ Examples:
 		inputs, _ := cmd.Flags().GetStringArray("raw-field")
 		push, _ := cmd.Flags().GetBool("push")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		follow, _ := cmd.Flags().GetBool("follow")
+		modelOverride, _ := cmd.Flags().GetString("model")
+		preset, _ := cmd.Flags().GetString("preset")
+		matrix, _ := cmd.Flags().GetStringArray("matrix")
 
 		if err := validateEngine(engineOverride); err != nil {
 			return err
@@ -391,6 +421,12 @@ Examples:
 			if enable {
 				return errors.New("--enable-if-needed flag is not supported in interactive mode")
 			}
+			if follow {
+				return errors.New("--follow flag is not supported in interactive mode")
+			}
+			if preset != "" || len(matrix) > 0 {
+				return errors.New("--preset and --matrix flags are not supported in interactive mode")
+			}
 			if len(inputs) > 0 {
 				return errors.New("workflow inputs cannot be specified in interactive mode (they will be collected interactively)")
 			}
@@ -398,10 +434,12 @@ Examples:
 			return cli.RunWorkflowInteractively(cmd.Context(), verboseFlag, repoOverride, refOverride, autoMergePRs, push, engineOverride, dryRun)
 		}
 
-		return cli.RunWorkflowsOnGitHub(cmd.Context(), args, cli.RunOptions{
+		opts := cli.RunOptions{
 			RepeatCount:    repeatCount,
 			Enable:         enable,
 			EngineOverride: engineOverride,
+			ModelOverride:  modelOverride,
+			Preset:         preset,
 			RepoOverride:   repoOverride,
 			RefOverride:    refOverride,
 			AutoMergePRs:   autoMergePRs,
@@ -409,7 +447,15 @@ Examples:
 			Inputs:         inputs,
 			Verbose:        verboseFlag,
 			DryRun:         dryRun,
-		})
+			Follow:         follow,
+		}
+		if len(matrix) > 0 {
+			if len(args) != 1 {
+				return errors.New("--matrix runs a single workflow")
+			}
+			return cli.RunWorkflowMatrix(cmd.Context(), args[0], matrix, opts)
+		}
+		return cli.RunWorkflowsOnGitHub(cmd.Context(), args, opts)
 	},
 }
 
@@ -597,6 +643,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 
 	// Create and setup trial command
 	trialCmd := cli.NewTrialCommand(validateEngine)
+	evalCmd := cli.NewEvalCommand(validateEngine)
 
 	// Create and setup init command
 	initCmd := cli.NewInitCommand()
@@ -604,6 +651,8 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	// Add flags to new command
 	newCmd.Flags().BoolP("force", "f", false, "Overwrite existing files without confirmation")
 	newCmd.Flags().BoolP("interactive", "i", false, "Launch interactive workflow creation wizard")
+	newCmd.Flags().String("template", "", "Create the workflow from a template (name in .github/aw/templates, local path, or owner/repo/path.md[@ref])")
+	newCmd.Flags().StringArray("param", nil, "Template parameter value in name=value format (can be repeated)")
 
 	// Add AI flag to compile and add commands
 	compileCmd.Flags().StringP("engine", "e", "", "Override AI engine (claude, codex, copilot, custom)")
@@ -662,6 +711,10 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	runCmd.Flags().StringArrayP("raw-field", "F", []string{}, "Add a string parameter in key=value format (can be used multiple times)")
 	runCmd.Flags().Bool("push", false, "Commit and push workflow files (including transitive imports) before running")
 	runCmd.Flags().Bool("dry-run", false, "Validate workflow without actually triggering execution on GitHub Actions")
+	runCmd.Flags().String("model", "", "Override the AI model (recompiles the workflow; use with --push)")
+	runCmd.Flags().String("preset", "", "Apply a named input preset from .github/aw/presets/<workflow>.yml")
+	runCmd.Flags().StringArray("matrix", nil, "Dispatch once per combination of axis=value1,value2 (axes: engine, model, preset, or an input name; can be repeated)")
+	runCmd.Flags().Bool("follow", false, "Stream job progress and agent logs until the run completes, then exit with its conclusion")
 	// Register completions for run command
 	runCmd.ValidArgsFunction = cli.CompleteWorkflowNames
 	cli.RegisterEngineFlagCompletion(runCmd)
@@ -677,6 +730,9 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd := cli.NewLogsCommand()
 	auditCmd := cli.NewAuditCommand()
 	healthCmd := cli.NewHealthCommand()
+	scheduleCmd := cli.NewScheduleCommand()
+	memoryCmd := cli.NewMemoryCommand()
+	redactCmd := cli.NewRedactCommand()
 	mcpServerCmd := cli.NewMCPServerCommand()
 	prCmd := cli.NewPRCommand()
 	secretsCmd := cli.NewSecretsCommand()
@@ -685,6 +741,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
 	projectCmd := cli.NewProjectCommand()
+	fleetCmd := cli.NewFleetCommand()
 	checksCmd := cli.NewChecksCommand()
 	validateCmd := cli.NewValidateCommand(validateEngine)
 
@@ -711,11 +768,15 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	enableCmd.GroupID = "execution"
 	disableCmd.GroupID = "execution"
 	trialCmd.GroupID = "execution"
+	evalCmd.GroupID = "execution"
 
 	// Analysis Commands
 	logsCmd.GroupID = "analysis"
 	auditCmd.GroupID = "analysis"
 	healthCmd.GroupID = "analysis"
+	scheduleCmd.GroupID = "analysis"
+	memoryCmd.GroupID = "analysis"
+	redactCmd.GroupID = "analysis"
 	checksCmd.GroupID = "analysis"
 
 	// Utilities
@@ -724,6 +785,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	completionCmd.GroupID = "utilities"
 	hashCmd.GroupID = "utilities"
 	projectCmd.GroupID = "utilities"
+	fleetCmd.GroupID = "utilities"
 
 	// version command is intentionally left without a group (common practice)
 
@@ -732,6 +794,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(trialCmd)
+	rootCmd.AddCommand(evalCmd)
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(initCmd)
 
@@ -744,6 +807,9 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(memoryCmd)
+	rootCmd.AddCommand(redactCmd)
 	rootCmd.AddCommand(checksCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(mcpServerCmd)
@@ -755,6 +821,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
 	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(fleetCmd)
 }
 
 func main() {
@@ -10,6 +10,7 @@ import (
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/tty"
 	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/spf13/cobra"
@@ -69,6 +70,7 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` add https://github.com/githubnext/agentics/blob/main/workflows/ci-doctor.md
   ` + string(constants.CLIExtensionPrefix) + ` add githubnext/agentics/ci-doctor --create-pull-request --force
   ` + string(constants.CLIExtensionPrefix) + ` add githubnext/agentics/ci-doctor --push         # Add and push changes
+  ` + string(constants.CLIExtensionPrefix) + ` add octo/platform-pack@^1.2                     # Install a workflow package
   ` + string(constants.CLIExtensionPrefix) + ` add ./my-workflow.md                             # Add local workflow
   ` + string(constants.CLIExtensionPrefix) + ` add ./*.md                                       # Add all local workflows
   ` + string(constants.CLIExtensionPrefix) + ` add githubnext/agentics/ci-doctor --dir shared   # Add to .github/workflows/shared/
@@ -80,6 +82,10 @@ Workflow specifications:
   - Local file: "./path/to/workflow.md" (adds a workflow from local filesystem)
   - Local wildcard: "./*.md" or "./dir/*.md" (adds all .md files matching pattern)
   - Version can be tag, branch, or SHA (for remote workflows)
+  - Package: "owner/repo[@range]" installs every workflow listed in the repository's
+    aw-package.yml manifest, plus its shared imports and dependency packages. The range
+    (e.g. "^1.2", "~1.2.3", "1.x", ">=1.0.0 <2.0.0") is resolved against the repository's
+    tags and recorded in .github/aw/aw.lock.json, so 'update' stays within it
 
 The -n flag allows you to specify a custom name for the workflow file (only applies to the first workflow when adding multiple).
 The --dir flag allows you to specify a subdirectory under .github/workflows/ where the workflow will be added.
@@ -300,6 +306,11 @@ func addWorkflowsWithTracking(workflows []*ResolvedWorkflow, tracker *FileTracke
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Adding %d workflow(s)...", len(workflows))))
 	}
 
+	// Record installed packages in aw.lock.json first so that workflow pins land in the same lockfile
+	if err := recordWorkflowPackages(workflows, tracker); err != nil {
+		return err
+	}
+
 	// Add each workflow using pre-fetched content
 	for i, resolved := range workflows {
 		if !opts.Quiet && len(workflows) > 1 {
@@ -315,6 +326,11 @@ func addWorkflowsWithTracking(workflows []*ResolvedWorkflow, tracker *FileTracke
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Successfully added all %d workflows", len(workflows))))
 	}
 
+	// Install the shared imports of workflow packages and list the secrets they require
+	if err := installPackageImports(workflows, tracker, opts); err != nil {
+		return err
+	}
+
 	// If --push is enabled, commit and push changes
 	if opts.Push {
 		addLog.Print("Push enabled - preparing to commit and push changes")
@@ -374,6 +390,21 @@ func addWorkflowsWithTracking(workflows []*ResolvedWorkflow, tracker *FileTracke
 	return nil
 }
 
+// resolveAddTargetDir returns the .github/workflows directory (or the --dir subdirectory) workflows are added to
+func resolveAddTargetDir(gitRoot, workflowDir string) (string, error) {
+	if workflowDir == "" {
+		return filepath.Join(gitRoot, ".github/workflows"), nil
+	}
+	if filepath.IsAbs(workflowDir) {
+		return "", fmt.Errorf("workflow directory must be a relative path, got: %s", workflowDir)
+	}
+	workflowDir = filepath.Clean(workflowDir)
+	if !strings.HasPrefix(workflowDir, ".github/workflows") {
+		return filepath.Join(gitRoot, ".github/workflows", workflowDir), nil
+	}
+	return filepath.Join(gitRoot, workflowDir), nil
+}
+
 // addWorkflowWithTracking adds a workflow using pre-fetched content with file tracking
 func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, opts AddOptions) error {
 	workflowSpec := resolved.Spec
@@ -412,19 +443,9 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 	}
 
 	// Determine the target workflow directory
-	var githubWorkflowsDir string
-	if opts.WorkflowDir != "" {
-		if filepath.IsAbs(opts.WorkflowDir) {
-			return fmt.Errorf("workflow directory must be a relative path, got: %s", opts.WorkflowDir)
-		}
-		opts.WorkflowDir = filepath.Clean(opts.WorkflowDir)
-		if !strings.HasPrefix(opts.WorkflowDir, ".github/workflows") {
-			githubWorkflowsDir = filepath.Join(gitRoot, ".github/workflows", opts.WorkflowDir)
-		} else {
-			githubWorkflowsDir = filepath.Join(gitRoot, opts.WorkflowDir)
-		}
-	} else {
-		githubWorkflowsDir = filepath.Join(gitRoot, ".github/workflows")
+	githubWorkflowsDir, err := resolveAddTargetDir(gitRoot, opts.WorkflowDir)
+	if err != nil {
+		return err
 	}
 
 	// Ensure the target directory exists
@@ -593,6 +614,17 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 		return fmt.Errorf("failed to write destination file '%s': %w", destFile, err)
 	}
 
+	// Pin remote sources in aw.lock.json when the repository uses a lockfile
+	if sourceString != "" && !isLocalWorkflowPath(workflowSpec.WorkflowPath) {
+		pinRef := commitSHA
+		if pinRef == "" {
+			pinRef = workflowSpec.Version
+		}
+		if err := pinWorkflowInLock(destFile, sourceString, workflowSpec.RepoSlug, pinRef, sourceContent, false); err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to pin workflow in %s: %v", parser.AWLockFile, err)))
+		}
+	}
+
 	// Show output
 	if !opts.Quiet {
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Added workflow: "+destFile))
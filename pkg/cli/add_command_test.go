@@ -93,10 +93,10 @@ func TestAddWorkflows(t *testing.T) {
 			errorContains: "at least one workflow",
 		},
 		{
-			name:          "repo-only spec (requires workflow path)",
+			name:          "repo-only spec (installs a workflow package)",
 			workflows:     []string{"owner/repo"},
 			expectError:   true,
-			errorContains: "workflow specification must be in format",
+			errorContains: "failed to resolve package 'owner/repo'",
 		},
 	}
 
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var addPackageLog = logger.New("cli:add_package")
+
+// workflowPackages returns the distinct packages the workflows are installed from, in order
+func workflowPackages(workflows []*ResolvedWorkflow) []*ResolvedPackage {
+	var packages []*ResolvedPackage
+	seen := make(map[string]bool)
+	for _, wf := range workflows {
+		if wf.Package == nil || seen[wf.Package.Repo] {
+			continue
+		}
+		seen[wf.Package.Repo] = true
+		packages = append(packages, wf.Package)
+	}
+	return packages
+}
+
+// recordWorkflowPackages records installed packages and their version ranges in aw.lock.json,
+// creating the lockfile if needed so that update can follow the ranges
+func recordWorkflowPackages(workflows []*ResolvedWorkflow, tracker *FileTracker) error {
+	packages := workflowPackages(workflows)
+	if len(packages) == 0 {
+		return nil
+	}
+
+	root := awLockRoot()
+	lock, err := parser.LoadAWLock(root)
+	if err != nil {
+		return err
+	}
+
+	lockPath := filepath.Join(root, parser.AWLockFile)
+	if tracker != nil {
+		if lock == nil {
+			tracker.TrackCreated(lockPath)
+		} else {
+			tracker.TrackModified(lockPath)
+		}
+	}
+	if lock == nil {
+		lock = parser.NewAWLock()
+	}
+
+	for _, pkg := range packages {
+		addPackageLog.Printf("Recording package %s: range=%q, version=%s", pkg.Repo, pkg.Range, pkg.Version)
+		lock.SetPackage(pkg.Repo, &parser.AWLockPackage{Range: pkg.Range, Version: pkg.Version})
+	}
+	return lock.Save(root)
+}
+
+// installPackageImports downloads the shared imports listed in package manifests and
+// reports the secrets the packages require
+func installPackageImports(workflows []*ResolvedWorkflow, tracker *FileTracker, opts AddOptions) error {
+	packages := workflowPackages(workflows)
+	if len(packages) == 0 {
+		return nil
+	}
+
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return fmt.Errorf("add workflow requires being in a git repository: %w", err)
+	}
+	targetDir, err := resolveAddTargetDir(gitRoot, opts.WorkflowDir)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range packages {
+		if len(pkg.Manifest.Imports) > 0 {
+			addPackageLog.Printf("Installing %d shared imports from package %s", len(pkg.Manifest.Imports), pkg.Repo)
+			// Express the imports as root-relative frontmatter imports so they are saved
+			// relative to the package's workflow directory, like a workflow's own imports
+			var sb strings.Builder
+			sb.WriteString("---\nimports:\n")
+			for _, importPath := range pkg.Manifest.Imports {
+				fmt.Fprintf(&sb, "  - /%s\n", importPath)
+			}
+			sb.WriteString("---\n")
+
+			spec := &WorkflowSpec{
+				RepoSpec:     RepoSpec{RepoSlug: pkg.Repo, Version: pkg.Version},
+				WorkflowPath: pkg.Manifest.Workflows[0],
+			}
+			if err := fetchAndSaveRemoteFrontmatterImports(sb.String(), spec, targetDir, opts.Verbose, opts.Force, tracker); err != nil {
+				return fmt.Errorf("failed to install imports of package %s: %w", pkg.Repo, err)
+			}
+		}
+
+		if secrets := pkg.Manifest.requiredSecretNames(); len(secrets) > 0 && !opts.Quiet {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Package %s requires secrets: %s", pkg.Manifest.Name, strings.Join(secrets, ", "))))
+			for _, secret := range pkg.Manifest.Secrets {
+				if secret.Description != "" {
+					fmt.Fprintln(os.Stderr, console.FormatListItem(secret.Name+": "+secret.Description))
+				}
+				fmt.Fprintln(os.Stderr, console.FormatCommandMessage("gh aw secrets set "+secret.Name))
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/stringutil"
 	"github.com/github/gh-aw/pkg/workflow"
 )
@@ -16,12 +17,13 @@ var addWorkflowCompilationLog = logger.New("cli:add_workflow_compilation")
 // compileWorkflow compiles a workflow file without refreshing stop time.
 // This is a convenience wrapper around compileWorkflowWithRefresh.
 func compileWorkflow(filePath string, verbose bool, quiet bool, engineOverride string) error {
-	return compileWorkflowWithRefresh(filePath, verbose, quiet, engineOverride, false)
+	return compileWorkflowWithRefresh(filePath, verbose, quiet, engineOverride, false, false)
 }
 
-// compileWorkflowWithRefresh compiles a workflow file with optional stop time refresh.
+// compileWorkflowWithRefresh compiles a workflow file with optional stop time refresh and
+// optional re-pinning of remote imports in aw.lock.json.
 // This function handles the compilation process and ensures .gitattributes is updated.
-func compileWorkflowWithRefresh(filePath string, verbose bool, quiet bool, engineOverride string, refreshStopTime bool) error {
+func compileWorkflowWithRefresh(filePath string, verbose bool, quiet bool, engineOverride string, refreshStopTime bool, refreshImportLock bool) error {
 	addWorkflowCompilationLog.Printf("Compiling workflow: file=%s, refresh_stop_time=%v, engine=%s", filePath, refreshStopTime, engineOverride)
 
 	// Create compiler with auto-detected version and action mode
@@ -31,12 +33,19 @@ func compileWorkflowWithRefresh(filePath string, verbose bool, quiet bool, engin
 	)
 
 	compiler.SetRefreshStopTime(refreshStopTime)
+	compiler.SetRefreshImportLock(refreshImportLock)
+	compiler.SetRecordImportLock(true)
 	compiler.SetQuiet(quiet)
 	if err := CompileWorkflowWithValidation(compiler, filePath, verbose, false, false, false, false, false); err != nil {
 		addWorkflowCompilationLog.Printf("Compilation failed: %v", err)
 		return err
 	}
 
+	// Remote imports are pinned by add and update only; compile just verifies them
+	if err := compiler.SaveImportLock(); err != nil {
+		return fmt.Errorf("failed to pin imports in %s: %w", parser.AWLockFile, err)
+	}
+
 	addWorkflowCompilationLog.Print("Compilation completed successfully")
 
 	// Ensure .gitattributes marks .lock.yml files as generated
@@ -106,11 +115,16 @@ func compileWorkflowWithTrackingAndRefresh(filePath string, verbose bool, quiet
 	)
 	compiler.SetFileTracker(tracker)
 	compiler.SetRefreshStopTime(refreshStopTime)
+	compiler.SetRecordImportLock(true)
 	compiler.SetQuiet(quiet)
 	if err := CompileWorkflowWithValidation(compiler, filePath, verbose, false, false, false, false, false); err != nil {
 		return err
 	}
 
+	if err := compiler.SaveImportLock(); err != nil {
+		return fmt.Errorf("failed to pin imports in %s: %w", parser.AWLockFile, err)
+	}
+
 	// Ensure .gitattributes marks .lock.yml files as generated
 	if err := ensureGitAttributes(); err != nil {
 		if verbose {
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"math/rand"
 	"os"
 	"regexp"
 	"strings"
@@ -48,132 +47,68 @@ func sanitizeBranchName(name string) string {
 func addWorkflowsWithPR(workflows []*ResolvedWorkflow, opts AddOptions) (int, string, error) {
 	addWorkflowPRLog.Printf("Adding %d workflow(s) with PR creation (resolved)", len(workflows))
 
-	// Get current branch for restoration later
-	currentBranch, err := getCurrentBranch()
-	if err != nil {
-		addWorkflowPRLog.Printf("Failed to get current branch: %v", err)
-		return 0, "", fmt.Errorf("failed to get current branch: %w", err)
-	}
-
-	addWorkflowPRLog.Printf("Current branch: %s", currentBranch)
-
-	// Create temporary branch with random 4-digit number
-	// Use sanitized workflow name to avoid invalid git ref characters
-	randomNum := rand.Intn(9000) + 1000 // Generate number between 1000-9999
-	sanitizedName := sanitizeBranchName(workflows[0].Spec.WorkflowPath)
-	branchName := fmt.Sprintf("add-workflow-%s-%04d", sanitizedName, randomNum)
-
-	addWorkflowPRLog.Printf("Creating temporary branch: %s", branchName)
-
-	if err := createAndSwitchBranch(branchName, opts.Verbose); err != nil {
-		return 0, "", fmt.Errorf("failed to create branch %s: %w", branchName, err)
-	}
-
 	// Create file tracker for rollback capability
 	tracker, err := NewFileTracker()
 	if err != nil {
 		return 0, "", fmt.Errorf("failed to create file tracker: %w", err)
 	}
 
-	// Ensure we switch back to original branch on exit
-	defer func() {
-		if switchErr := switchBranch(currentBranch, opts.Verbose); switchErr != nil && opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to switch back to branch %s: %v", currentBranch, switchErr)))
-		}
-	}()
-
-	// Add workflows using the resolved workflow path
-	addWorkflowPRLog.Print("Adding workflows to repository")
-	prOpts := opts
-	prOpts.DisableSecurityScanner = false
-	if err := addWorkflowsWithTracking(workflows, tracker, prOpts); err != nil {
-		addWorkflowPRLog.Printf("Failed to add workflows: %v", err)
-		// Rollback on error
-		if rollbackErr := tracker.RollbackAllFiles(opts.Verbose); rollbackErr != nil && opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to rollback files: %v", rollbackErr)))
-		}
-		return 0, "", fmt.Errorf("failed to add workflows: %w", err)
-	}
-
-	// Stage all files before creating PR
-	addWorkflowPRLog.Print("Staging workflow files")
-	if err := tracker.StageAllFiles(opts.Verbose); err != nil {
-		if rollbackErr := tracker.RollbackAllFiles(opts.Verbose); rollbackErr != nil && opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to rollback files: %v", rollbackErr)))
-		}
-		return 0, "", fmt.Errorf("failed to stage workflow files: %w", err)
-	}
-
-	// Update .gitattributes and stage it if changed
-	if err := stageGitAttributesIfChanged(); err != nil && opts.Verbose {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to stage .gitattributes: %v", err)))
-	}
-
-	// Commit changes
-	var commitMessage, prTitle, prBody, joinedNames string
+	var title string
 	if len(workflows) == 1 {
-		joinedNames = workflows[0].Spec.WorkflowName
-		commitMessage = "Add agentic workflow " + joinedNames
-		prTitle = "Add agentic workflow " + joinedNames
-		prBody = "Add agentic workflow " + joinedNames
+		title = "Add agentic workflow " + workflows[0].Spec.WorkflowName
 	} else {
 		workflowNames := sliceutil.Map(workflows, func(wf *ResolvedWorkflow) string {
 			return wf.Spec.WorkflowName
 		})
-		joinedNames = strings.Join(workflowNames, ", ")
-		commitMessage = "Add agentic workflows: " + joinedNames
-		prTitle = "Add agentic workflows: " + joinedNames
-		prBody = "Add agentic workflows: " + joinedNames
-	}
-
-	if err := commitChanges(commitMessage, opts.Verbose); err != nil {
-		// Don't rollback - leave the workflow files on disk for manual recovery.
-		// Return a richly formatted error with clear instructions so the user can
-		// commit and push manually. The top-level error handler will print this.
-		return 0, "", fmt.Errorf(
-			"failed to commit workflow files: %w\n\n"+
-				"The workflow files have been written to disk and staged in git.\n"+
-				"Please commit the files manually, then either push them to the\n"+
-				"repository or create a pull request:\n\n"+
-				"  git commit -m %q\n"+
-				"  git push\n\n"+
-				"Or to create a pull request:\n\n"+
-				"  git checkout -b %s\n"+
-				"  git commit -m %q\n"+
-				"  git push -u origin %s\n"+
-				"  gh pr create --title %q",
-			err, commitMessage, branchName, commitMessage, branchName, prTitle,
-		)
+		title = "Add agentic workflows: " + strings.Join(workflowNames, ", ")
 	}
 
-	// Push branch
-	addWorkflowPRLog.Printf("Pushing branch %s to remote", branchName)
-	if err := pushBranch(branchName, opts.Verbose); err != nil {
-		addWorkflowPRLog.Printf("Failed to push branch: %v", err)
-		if rollbackErr := tracker.RollbackAllFiles(opts.Verbose); rollbackErr != nil && opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to rollback files: %v", rollbackErr)))
-		}
-		return 0, "", fmt.Errorf("failed to push branch %s: %w", branchName, err)
-	}
-
-	// Create PR
-	addWorkflowPRLog.Printf("Creating pull request: %s", prTitle)
-	prNumber, prURL, err := createPR(branchName, prTitle, prBody, opts.Verbose)
-	if err != nil {
-		addWorkflowPRLog.Printf("Failed to create PR: %v", err)
-		if rollbackErr := tracker.RollbackAllFiles(opts.Verbose); rollbackErr != nil && opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to rollback files: %v", rollbackErr)))
-		}
-		return 0, "", fmt.Errorf("failed to create PR: %w", err)
-	}
-
-	addWorkflowPRLog.Printf("Successfully created PR #%d: %s", prNumber, prURL)
-
-	// Switch back to original branch
-	if err := switchBranch(currentBranch, opts.Verbose); err != nil {
-		return prNumber, prURL, fmt.Errorf("failed to switch back to branch %s: %w", currentBranch, err)
-	}
-
-	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Created pull request "+prURL))
-	return prNumber, prURL, nil
+	// Use sanitized workflow name to avoid invalid git ref characters
+	return createWorkflowPR(workflowPROptions{
+		BranchPrefix: "add-workflow-" + sanitizeBranchName(workflows[0].Spec.WorkflowPath),
+		Title:        title,
+		Body:         func() string { return title },
+		Apply: func() error {
+			// Add workflows using the resolved workflow path
+			addWorkflowPRLog.Print("Adding workflows to repository")
+			prOpts := opts
+			prOpts.DisableSecurityScanner = false
+			if err := addWorkflowsWithTracking(workflows, tracker, prOpts); err != nil {
+				return fmt.Errorf("failed to add workflows: %w", err)
+			}
+			return nil
+		},
+		Stage: func() error {
+			addWorkflowPRLog.Print("Staging workflow files")
+			if err := tracker.StageAllFiles(opts.Verbose); err != nil {
+				return fmt.Errorf("failed to stage workflow files: %w", err)
+			}
+			return nil
+		},
+		Discard: func() {
+			if rollbackErr := tracker.RollbackAllFiles(opts.Verbose); rollbackErr != nil && opts.Verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to rollback files: %v", rollbackErr)))
+			}
+		},
+		CommitError: func(err error, branchName string) error {
+			// Don't rollback - leave the workflow files on disk for manual recovery.
+			// Return a richly formatted error with clear instructions so the user can
+			// commit and push manually. The top-level error handler will print this.
+			return fmt.Errorf(
+				"failed to commit workflow files: %w\n\n"+
+					"The workflow files have been written to disk and staged in git.\n"+
+					"Please commit the files manually, then either push them to the\n"+
+					"repository or create a pull request:\n\n"+
+					"  git commit -m %q\n"+
+					"  git push\n\n"+
+					"Or to create a pull request:\n\n"+
+					"  git checkout -b %s\n"+
+					"  git commit -m %q\n"+
+					"  git push -u origin %s\n"+
+					"  gh pr create --title %q",
+				err, title, branchName, title, branchName, title,
+			)
+		},
+		Verbose: opts.Verbose,
+	})
 }
@@ -30,6 +30,8 @@ type ResolvedWorkflow struct {
 	HasWorkflowDispatch bool
 	// IsPrivate indicates if the workflow has private: true in its frontmatter
 	IsPrivate bool
+	// Package is the workflow package the workflow is installed from (nil for individual workflows)
+	Package *ResolvedPackage
 }
 
 // ResolvedWorkflows contains all resolved workflows ready to be added
@@ -45,6 +47,7 @@ type ResolvedWorkflows struct {
 // ResolveWorkflows resolves workflow specifications by parsing specs and fetching workflow content.
 // For remote workflows, content is fetched directly from GitHub without cloning.
 // Wildcards are only supported for local workflows (not remote repositories).
+// Repository-only specs (owner/repo[@range]) install the workflow package in that repository.
 func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, error) {
 	resolutionLog.Printf("Resolving workflows: count=%d", len(workflows))
 
@@ -58,6 +61,12 @@ func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, err
 		}
 	}
 
+	// Expand workflow packages into the workflows they contain
+	workflows, packagesBySpec, err := expandPackageSpecs(workflows, verbose)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse workflow specifications
 	parsedSpecs := []*WorkflowSpec{}
 
@@ -148,6 +157,7 @@ func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, err
 			Engine:              engine,
 			HasWorkflowDispatch: workflowHasDispatch,
 			IsPrivate:           isPrivate,
+			Package:             packagesBySpec[spec.String()],
 		})
 	}
 
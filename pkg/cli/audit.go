@@ -62,9 +62,27 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` audit https://github.example.com/owner/repo/actions/runs/1234567890  # Audit from GitHub Enterprise
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 -o ./audit-reports  # Custom output directory
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 -v  # Verbose output
-  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --parse  # Parse agent logs and firewall logs, generating log.md and firewall.md`,
-		Args: cobra.ExactArgs(1),
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --parse  # Parse agent logs and firewall logs, generating log.md and firewall.md
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --bundle run.tar.gz  # Package the run into an audit bundle
+  ` + string(constants.CLIExtensionPrefix) + ` audit --from-bundle run.tar.gz  # Re-render the report from a bundle offline`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			outputDir, _ := cmd.Flags().GetString("output")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			parse, _ := cmd.Flags().GetBool("parse")
+			bundlePath, _ := cmd.Flags().GetString("bundle")
+			fromBundle, _ := cmd.Flags().GetString("from-bundle")
+
+			if fromBundle != "" {
+				if len(args) > 0 {
+					return errors.New("a run ID cannot be combined with --from-bundle")
+				}
+				return AuditFromBundle(fromBundle, outputDir, jsonOutput, verbose)
+			}
+			if len(args) == 0 {
+				return errors.New("a run ID or URL is required, or use --from-bundle to read an audit bundle")
+			}
 			runIDOrURL := args[0]
 
 			// Parse run information from input (either numeric ID or URL)
@@ -73,11 +91,9 @@ Examples:
 			if err != nil {
 				return err
 			}
-
-			outputDir, _ := cmd.Flags().GetString("output")
-			verbose, _ := cmd.Flags().GetBool("verbose")
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-			parse, _ := cmd.Flags().GetBool("parse")
+			if bundlePath != "" && components.JobID > 0 {
+				return errors.New("--bundle cannot be used with job URLs, audit the whole run instead")
+			}
 
 			return AuditWorkflowRun(
 				cmd.Context(),
@@ -91,6 +107,7 @@ Examples:
 				jsonOutput,
 				components.JobID,
 				components.StepNumber,
+				bundlePath,
 			)
 		},
 	}
@@ -99,6 +116,9 @@ Examples:
 	addOutputFlag(cmd, defaultLogsOutputDir)
 	addJSONFlag(cmd)
 	cmd.Flags().Bool("parse", false, "Run JavaScript parsers on agent logs and firewall logs, writing Markdown to log.md and firewall.md")
+	cmd.Flags().String("bundle", "", "Write a reproducible audit bundle (.tar.gz) with artifacts, audit data and the workflow source at the run's commit")
+	cmd.Flags().String("from-bundle", "", "Render the report from an audit bundle without accessing GitHub")
+	cmd.MarkFlagsMutuallyExclusive("bundle", "from-bundle")
 
 	// Register completions for audit command
 	RegisterDirFlagCompletion(cmd, "output")
@@ -131,7 +151,8 @@ func isPermissionError(err error) bool {
 // AuditWorkflowRun audits a single workflow run and generates a report
 // If jobID is provided (>0), focuses audit on that specific job
 // If stepNumber is provided (>0), extracts output for that specific step
-func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname string, outputDir string, verbose bool, parse bool, jsonOutput bool, jobID int64, stepNumber int) error {
+// If bundlePath is set, writes a reproducible audit bundle of the run to that path
+func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname string, outputDir string, verbose bool, parse bool, jsonOutput bool, jobID int64, stepNumber int, bundlePath string) error {
 	auditLog.Printf("Starting audit for workflow run: runID=%d, owner=%s, repo=%s, jobID=%d, stepNumber=%d", runID, owner, repo, jobID, stepNumber)
 
 	// Check context cancellation at the start
@@ -415,6 +436,19 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		}
 	}
 
+	// Package the run into an audit bundle that can be re-rendered offline
+	if bundlePath != "" {
+		repository := ""
+		if owner != "" && repo != "" {
+			repository = owner + "/" + repo
+		} else if slug, err := GetCurrentRepoSlug(); err == nil {
+			repository = slug
+		}
+		if err := writeAuditBundle(bundlePath, runOutputDir, auditData, run, repository, verbose); err != nil {
+			return fmt.Errorf("failed to write audit bundle: %w", err)
+		}
+	}
+
 	// Display logs location (only for console output)
 	if !jsonOutput {
 		absOutputDir, _ := filepath.Abs(runOutputDir)
@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var auditBundleLog = logger.New("cli:audit_bundle")
+
+// auditBundleVersion is the version of the bundle format written by this CLI. Bundles with a
+// higher version are rejected on import.
+const auditBundleVersion = 1
+
+// Layout of an audit bundle
+const (
+	auditBundleManifestFile = "manifest.json"
+	auditBundleDataFile     = "audit.json"
+	auditBundleArtifactsDir = "artifacts"
+	auditBundleSourceDir    = "source"
+)
+
+// fetchAuditBundleSource fetches a file of a repository at a commit. Tests replace it to run offline.
+var fetchAuditBundleSource = downloadWorkflowContent
+
+// AuditBundleManifest describes the content of an audit bundle
+type AuditBundleManifest struct {
+	Version      int               `json:"version"`
+	CLIVersion   string            `json:"cli_version"`
+	CreatedAt    time.Time         `json:"created_at"`
+	RunID        int64             `json:"run_id"`
+	Repository   string            `json:"repository,omitempty"`
+	HeadSHA      string            `json:"head_sha,omitempty"`
+	LockFile     string            `json:"lock_file,omitempty"`     // Path of the lock file in the bundle
+	MarkdownFile string            `json:"markdown_file,omitempty"` // Path of the workflow markdown in the bundle
+	AwInfo       string            `json:"aw_info,omitempty"`       // Path of aw_info.json in the bundle
+	Files        []AuditBundleFile `json:"files"`
+	Warnings     []string          `json:"warnings,omitempty"`
+}
+
+// AuditBundleFile is a file of an audit bundle with its checksum
+type AuditBundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// auditBundleEntry is a file to add to a bundle, read from disk or held in memory
+type auditBundleEntry struct {
+	name     string
+	diskPath string
+	data     []byte
+}
+
+func (e auditBundleEntry) content() ([]byte, error) {
+	if e.diskPath == "" {
+		return e.data, nil
+	}
+	return os.ReadFile(e.diskPath)
+}
+
+// writeAuditBundle packages the downloaded artifacts of a run, its audit data and the workflow
+// source at the run's commit into a tar.gz archive
+func writeAuditBundle(bundlePath, runOutputDir string, auditData AuditData, run WorkflowRun, repository string, verbose bool) error {
+	auditBundleLog.Printf("Writing audit bundle for run %d to %s", run.DatabaseID, bundlePath)
+
+	manifest := AuditBundleManifest{
+		Version:    auditBundleVersion,
+		CLIVersion: GetVersion(),
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		RunID:      run.DatabaseID,
+		Repository: repository,
+		HeadSHA:    run.HeadSha,
+	}
+
+	data, err := json.MarshalIndent(auditData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit data: %w", err)
+	}
+	entries := []auditBundleEntry{{name: auditBundleDataFile, data: data}}
+
+	// Workflow source at the commit the run executed
+	lockPath := run.WorkflowPath
+	switch {
+	case lockPath == "" || run.HeadSha == "" || repository == "":
+		manifest.Warnings = append(manifest.Warnings, "workflow source not included: run metadata unavailable")
+	default:
+		sources := []string{lockPath}
+		if strings.HasSuffix(lockPath, ".lock.yml") {
+			sources = append(sources, strings.TrimSuffix(lockPath, ".lock.yml")+".md")
+		}
+		for _, source := range sources {
+			content, err := fetchAuditBundleSource(repository, source, run.HeadSha, verbose)
+			if err != nil {
+				auditBundleLog.Printf("Failed to fetch %s at %s: %v", source, run.HeadSha, err)
+				manifest.Warnings = append(manifest.Warnings, fmt.Sprintf("%s not included: %v", source, err))
+				continue
+			}
+			name := path.Join(auditBundleSourceDir, source)
+			entries = append(entries, auditBundleEntry{name: name, data: content})
+			if source == lockPath {
+				manifest.LockFile = name
+			} else {
+				manifest.MarkdownFile = name
+			}
+		}
+	}
+
+	// Downloaded artifacts
+	err = filepath.WalkDir(runOutputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(runOutputDir, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(auditBundleArtifactsDir, filepath.ToSlash(rel))
+		if filepath.ToSlash(rel) == "aw_info.json" {
+			manifest.AwInfo = name
+		}
+		entries = append(entries, auditBundleEntry{name: name, diskPath: p})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to collect artifacts: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	// Checksums are computed before writing so the manifest can be the first entry
+	for _, entry := range entries {
+		content, err := entry.content()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.name, err)
+		}
+		sum := sha256.Sum256(content)
+		manifest.Files = append(manifest.Files, AuditBundleFile{Path: entry.name, Size: int64(len(content)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	entries = append([]auditBundleEntry{{name: auditBundleManifestFile, data: manifestData}}, entries...)
+
+	if dir := filepath.Dir(bundlePath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create bundle directory: %w", err)
+		}
+	}
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		content, err := entry.content()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.name, err)
+		}
+		// Entries get the same timestamp and mode so bundles only differ by their content
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(content)), ModTime: manifest.CreatedAt, Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	for _, warning := range manifest.Warnings {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Audit bundle: "+warning))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Audit bundle with %d file(s) written to %s", len(manifest.Files), bundlePath)))
+	return nil
+}
+
+// readAuditBundle extracts an audit bundle into destDir, verifies its checksums and returns its
+// manifest and audit data
+func readAuditBundle(bundlePath, destDir string) (*AuditBundleManifest, *AuditData, error) {
+	auditBundleLog.Printf("Reading audit bundle %s into %s", bundlePath, destDir)
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle %s: %w", bundlePath, err)
+	}
+	defer gzipReader.Close()
+
+	if err := os.RemoveAll(destDir); err != nil {
+		return nil, nil, fmt.Errorf("failed to clean %s: %w", destDir, err)
+	}
+
+	checksums := make(map[string]string)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read bundle %s: %w", bundlePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, nil, fmt.Errorf("invalid path in bundle: %s", header.Name)
+		}
+
+		target := filepath.Join(destDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, nil, fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+		hash := sha256.New()
+		_, copyErr := io.Copy(io.MultiWriter(out, hash), tarReader)
+		closeErr := out.Close()
+		if copyErr != nil || closeErr != nil {
+			return nil, nil, fmt.Errorf("failed to extract %s: %w", name, errors.Join(copyErr, closeErr))
+		}
+		checksums[name] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(destDir, auditBundleManifestFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not an audit bundle: missing %s", bundlePath, auditBundleManifestFile)
+	}
+	var manifest AuditBundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.Version > auditBundleVersion {
+		return nil, nil, fmt.Errorf("bundle format version %d is newer than the supported version %d, upgrade gh-aw to read it", manifest.Version, auditBundleVersion)
+	}
+	for _, f := range manifest.Files {
+		if checksums[f.Path] != f.SHA256 {
+			return nil, nil, fmt.Errorf("bundle is corrupted: checksum mismatch for %s", f.Path)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(destDir, auditBundleDataFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("bundle is missing %s: %w", auditBundleDataFile, err)
+	}
+	var auditData AuditData
+	if err := json.Unmarshal(data, &auditData); err != nil {
+		return nil, nil, fmt.Errorf("invalid audit data in bundle: %w", err)
+	}
+	return &manifest, &auditData, nil
+}
+
+// AuditFromBundle renders the audit report stored in a bundle without accessing GitHub
+func AuditFromBundle(bundlePath, outputDir string, jsonOutput, verbose bool) error {
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(bundlePath), ".gz"), ".tar")
+	destDir := filepath.Join(outputDir, "bundle-"+name)
+
+	manifest, auditData, err := readAuditBundle(bundlePath, destDir)
+	if err != nil {
+		return err
+	}
+	artifactsDir := filepath.Join(destDir, auditBundleArtifactsDir)
+	auditData.Overview.LogsPath = artifactsDir
+
+	if jsonOutput {
+		if err := renderJSON(*auditData); err != nil {
+			return fmt.Errorf("failed to render JSON output: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Audit bundle of run %d created %s by gh-aw %s",
+		manifest.RunID, manifest.CreatedAt.Format(time.RFC3339), manifest.CLIVersion)))
+	renderConsole(*auditData, artifactsDir)
+
+	if manifest.LockFile != "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow source at %s: %s", manifest.HeadSHA, filepath.Join(destDir, filepath.FromSlash(path.Dir(manifest.LockFile))))))
+	}
+	for _, warning := range manifest.Warnings {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Verified %d file checksum(s)", len(manifest.Files))))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Bundle extracted to "+destDir))
+	return nil
+}
@@ -0,0 +1,160 @@
+//go:build !integration
+
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAuditBundleTestRun(t *testing.T) (string, string) {
+	t.Helper()
+	dir := testutil.TempDir(t, "audit-bundle-test")
+	runDir := filepath.Join(dir, "run-42")
+	files := map[string]string{
+		"aw_info.json":           `{"engine_id":"copilot","workflow_name":"triage"}`,
+		"agent-stdio.log":        "agent output\n",
+		"agent_output/out.jsonl": `{"type":"noop"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(runDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir, runDir
+}
+
+func TestAuditBundleRoundTrip(t *testing.T) {
+	dir, runDir := writeAuditBundleTestRun(t)
+
+	original := fetchAuditBundleSource
+	defer func() { fetchAuditBundleSource = original }()
+	fetchAuditBundleSource = func(repo, path, ref string, verbose bool) ([]byte, error) {
+		assert.Equal(t, "octo/repo", repo)
+		assert.Equal(t, "abc123", ref)
+		if path == ".github/workflows/triage.md" {
+			return nil, errors.New("not found")
+		}
+		return []byte("name: triage\n"), nil
+	}
+
+	run := WorkflowRun{DatabaseID: 42, HeadSha: "abc123", WorkflowPath: ".github/workflows/triage.lock.yml"}
+	auditData := AuditData{Overview: OverviewData{RunID: 42, WorkflowName: "triage", LogsPath: runDir}}
+	bundlePath := filepath.Join(dir, "bundles", "run-42.tar.gz")
+
+	require.NoError(t, writeAuditBundle(bundlePath, runDir, auditData, run, "octo/repo", false))
+
+	manifest, data, err := readAuditBundle(bundlePath, filepath.Join(dir, "extracted"))
+	require.NoError(t, err)
+
+	assert.Equal(t, auditBundleVersion, manifest.Version)
+	assert.Equal(t, int64(42), manifest.RunID)
+	assert.Equal(t, "abc123", manifest.HeadSHA)
+	assert.Equal(t, "source/.github/workflows/triage.lock.yml", manifest.LockFile)
+	assert.Empty(t, manifest.MarkdownFile)
+	assert.Equal(t, "artifacts/aw_info.json", manifest.AwInfo)
+	require.Len(t, manifest.Warnings, 1)
+	assert.Contains(t, manifest.Warnings[0], ".github/workflows/triage.md not included")
+
+	paths := make([]string, 0, len(manifest.Files))
+	for _, f := range manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{
+		"artifacts/agent-stdio.log",
+		"artifacts/agent_output/out.jsonl",
+		"artifacts/aw_info.json",
+		"audit.json",
+		"source/.github/workflows/triage.lock.yml",
+	}, paths)
+
+	assert.Equal(t, "triage", data.Overview.WorkflowName)
+	lock, err := os.ReadFile(filepath.Join(dir, "extracted", "source", ".github", "workflows", "triage.lock.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: triage\n", string(lock))
+
+	require.NoError(t, AuditFromBundle(bundlePath, dir, true, false))
+	assert.DirExists(t, filepath.Join(dir, "bundle-run-42", "artifacts"))
+}
+
+func TestAuditBundleWithoutRunMetadata(t *testing.T) {
+	dir, runDir := writeAuditBundleTestRun(t)
+	bundlePath := filepath.Join(dir, "run.tar.gz")
+
+	require.NoError(t, writeAuditBundle(bundlePath, runDir, AuditData{}, WorkflowRun{DatabaseID: 42}, "", false))
+
+	manifest, _, err := readAuditBundle(bundlePath, filepath.Join(dir, "extracted"))
+	require.NoError(t, err)
+	assert.Empty(t, manifest.LockFile)
+	assert.Equal(t, []string{"workflow source not included: run metadata unavailable"}, manifest.Warnings)
+}
+
+// writeTestTarGz writes a tar.gz archive with the given entries
+func writeTestTarGz(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range entries {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+}
+
+func TestReadAuditBundleRejectsInvalidBundles(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantErr string
+	}{
+		{
+			name:    "path traversal",
+			entries: map[string]string{"../escape.txt": "x"},
+			wantErr: "invalid path in bundle",
+		},
+		{
+			name:    "missing manifest",
+			entries: map[string]string{"audit.json": "{}"},
+			wantErr: "is not an audit bundle",
+		},
+		{
+			name:    "newer version",
+			entries: map[string]string{"manifest.json": `{"version":99,"files":[]}`, "audit.json": "{}"},
+			wantErr: "bundle format version 99 is newer",
+		},
+		{
+			name: "checksum mismatch",
+			entries: map[string]string{
+				"manifest.json": `{"version":1,"files":[{"path":"audit.json","size":2,"sha256":"0000"}]}`,
+				"audit.json":    "{}",
+			},
+			wantErr: "checksum mismatch for audit.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testutil.TempDir(t, "audit-bundle-invalid")
+			bundlePath := filepath.Join(dir, "bundle.tar.gz")
+			writeTestTarGz(t, bundlePath, tt.entries)
+
+			_, _, err := readAuditBundle(bundlePath, filepath.Join(dir, "extracted"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.NoFileExists(t, filepath.Join(dir, "escape.txt"))
+		})
+	}
+}
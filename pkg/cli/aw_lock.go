@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var awLockLog = logger.New("cli:aw_lock")
+
+// Upstream lookups used when pinning, replaced in tests
+var (
+	awLockDefaultBranch = getRepoDefaultBranch
+	awLockResolveRef    = parser.ResolveRefToSHA
+)
+
+// awLockRoot returns the directory holding .github/aw/aw.lock.json. Like the
+// compiler's import cache, the lockfile is resolved from the working directory.
+func awLockRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return cwd
+}
+
+// awLockWorkflowKey returns the lockfile key of a workflow: its path relative to the lock root
+func awLockWorkflowKey(workflowPath string) string {
+	absPath, err := filepath.Abs(workflowPath)
+	if err != nil {
+		return workflowPath
+	}
+	rel, err := filepath.Rel(awLockRoot(), absPath)
+	if err != nil {
+		return workflowPath
+	}
+	return rel
+}
+
+// lockedWorkflowPin returns the lockfile pin of a workflow for the given source,
+// or nil when the workflow is not pinned or was pinned for another source
+func lockedWorkflowPin(workflowPath, source string) (*parser.AWLockEntry, error) {
+	lock, err := parser.LoadAWLock(awLockRoot())
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	entry := lock.Workflow(awLockWorkflowKey(workflowPath))
+	if entry == nil || entry.Source != source {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// pinWorkflowInLock records a workflow source in aw.lock.json, pinning ref (the ref the
+// upstream content was fetched at, or the default branch when empty) to a commit SHA and the upstream content to a content
+// hash. When create is false, the pin is only recorded if the repository already has a lockfile.
+func pinWorkflowInLock(workflowPath, source, repoSlug, ref string, upstreamContent []byte, create bool) error {
+	root := awLockRoot()
+	lock, err := parser.LoadAWLock(root)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		if !create {
+			return nil
+		}
+		lock = parser.NewAWLock()
+	}
+
+	sha := ref
+	if sha == "" {
+		// Content fetched without a ref comes from the upstream default branch
+		defaultBranch, err := awLockDefaultBranch(repoSlug)
+		if err != nil {
+			return fmt.Errorf("failed to get default branch for %s: %w", repoSlug, err)
+		}
+		sha = defaultBranch
+	}
+	if !IsCommitSHA(sha) {
+		owner, repo, _ := strings.Cut(repoSlug, "/")
+		resolved, err := awLockResolveRef(owner, repo, sha)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s@%s to a commit SHA: %w", repoSlug, sha, err)
+		}
+		sha = resolved
+	}
+
+	key := awLockWorkflowKey(workflowPath)
+	awLockLog.Printf("Pinning workflow %s: source=%s, sha=%s", key, source, sha)
+	lock.SetWorkflow(key, &parser.AWLockEntry{
+		Source: source,
+		SHA:    sha,
+		Hash:   parser.ComputeContentHash(upstreamContent),
+	})
+	return lock.Save(root)
+}
+
+// lockedPackage returns the installed package for a repository, or nil when the
+// repository has no lockfile or the repository is not installed as a package
+func lockedPackage(repo string) (*parser.AWLockPackage, error) {
+	lock, err := parser.LoadAWLock(awLockRoot())
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	return lock.Package(repo), nil
+}
+
+// recordPackageVersion updates the version an installed package was last updated to
+func recordPackageVersion(repo, version string) error {
+	root := awLockRoot()
+	lock, err := parser.LoadAWLock(root)
+	if err != nil || lock == nil {
+		return err
+	}
+	pkg := lock.Package(repo)
+	if pkg == nil || pkg.Version == version {
+		return nil
+	}
+	awLockLog.Printf("Recording package %s version %s", repo, version)
+	lock.SetPackage(repo, &parser.AWLockPackage{Range: pkg.Range, Version: version})
+	return lock.Save(root)
+}
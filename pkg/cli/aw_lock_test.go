@@ -0,0 +1,73 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinWorkflowInLock(t *testing.T) {
+	const (
+		sha    = "0123456789abcdef0123456789abcdef01234567"
+		source = "octo/aw/workflows/triage.md@" + sha
+	)
+	dir := t.TempDir()
+	t.Chdir(dir)
+	workflowPath := filepath.Join(dir, ".github", "workflows", "triage.md")
+	content := []byte("# Triage\n")
+
+	require.NoError(t, pinWorkflowInLock(workflowPath, source, "octo/aw", sha, content, false))
+	lock, err := parser.LoadAWLock(dir)
+	require.NoError(t, err)
+	assert.Nil(t, lock, "add should not create a lockfile")
+
+	require.NoError(t, pinWorkflowInLock(workflowPath, source, "octo/aw", sha, content, true))
+	lock, err = parser.LoadAWLock(dir)
+	require.NoError(t, err)
+	require.NotNil(t, lock, "update should create the lockfile")
+	entry := lock.Workflow(".github/workflows/triage.md")
+	require.NotNil(t, entry, "workflow should be keyed by its repository-relative path")
+	assert.Equal(t, source, entry.Source)
+	assert.Equal(t, sha, entry.SHA)
+	assert.Equal(t, parser.ComputeContentHash(content), entry.Hash)
+
+	pinned, err := lockedWorkflowPin(workflowPath, source)
+	require.NoError(t, err)
+	assert.Equal(t, entry.SHA, pinned.SHA)
+
+	pinned, err = lockedWorkflowPin(workflowPath, "octo/aw/workflows/triage.md@v2.0.0")
+	require.NoError(t, err)
+	assert.Nil(t, pinned, "a pin for another source should be ignored")
+}
+
+func TestPinWorkflowInLockResolvesDefaultBranch(t *testing.T) {
+	const sha = "89abcdef0123456789abcdef0123456789abcdef"
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	origDefaultBranch, origResolveRef := awLockDefaultBranch, awLockResolveRef
+	t.Cleanup(func() { awLockDefaultBranch, awLockResolveRef = origDefaultBranch, origResolveRef })
+	awLockDefaultBranch = func(repo string) (string, error) {
+		assert.Equal(t, "octo/aw", repo)
+		return "trunk", nil
+	}
+	var resolved []string
+	awLockResolveRef = func(owner, repo, ref string) (string, error) {
+		resolved = append(resolved, owner+"/"+repo+"@"+ref)
+		return sha, nil
+	}
+
+	workflowPath := filepath.Join(dir, ".github", "workflows", "triage.md")
+	require.NoError(t, pinWorkflowInLock(workflowPath, "octo/aw/workflows/triage.md", "octo/aw", "", []byte("# Triage\n"), true))
+	assert.Equal(t, []string{"octo/aw@trunk"}, resolved, "a source without a ref should be pinned from the upstream default branch")
+
+	lock, err := parser.LoadAWLock(dir)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, sha, lock.Workflow(".github/workflows/triage.md").SHA)
+}
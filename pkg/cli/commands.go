@@ -236,11 +236,33 @@ func NewWorkflow(workflowName string, verbose bool, force bool) error {
 
 	console.LogVerbose(verbose, "Creating new workflow: "+workflowName)
 
+	destFile, err := newWorkflowDestination(workflowName, force)
+	if err != nil {
+		return err
+	}
+
+	// Create the template content
+	template := createWorkflowTemplate(workflowName)
+
+	// Write the template to file with restrictive permissions (owner-only)
+	if err := os.WriteFile(destFile, []byte(template), 0600); err != nil {
+		return fmt.Errorf("failed to write workflow file '%s': %w", destFile, err)
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Created new workflow: "+destFile))
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Edit the file to customize your workflow, then run '%s compile' to generate the GitHub Actions workflow", string(constants.CLIExtensionPrefix))))
+
+	return nil
+}
+
+// newWorkflowDestination creates .github/workflows if needed and returns the validated path of
+// a new workflow file, failing if the file already exists and force is not set
+func newWorkflowDestination(workflowName string, force bool) (string, error) {
 	// Get current working directory for .github/workflows
 	workingDir, err := os.Getwd()
 	if err != nil {
 		commandsLog.Printf("Failed to get working directory: %v", err)
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
 	// Create .github/workflows directory if it doesn't exist
@@ -251,12 +273,12 @@ func NewWorkflow(workflowName string, verbose bool, force bool) error {
 	githubWorkflowsDir, err = fileutil.ValidateAbsolutePath(githubWorkflowsDir)
 	if err != nil {
 		commandsLog.Printf("Invalid workflows directory path: %v", err)
-		return fmt.Errorf("invalid workflows directory path: %w", err)
+		return "", fmt.Errorf("invalid workflows directory path: %w", err)
 	}
 
 	if err := os.MkdirAll(githubWorkflowsDir, 0755); err != nil {
 		commandsLog.Printf("Failed to create workflows directory: %v", err)
-		return fmt.Errorf("failed to create .github/workflows directory: %w", err)
+		return "", fmt.Errorf("failed to create .github/workflows directory: %w", err)
 	}
 
 	// Construct the destination file path
@@ -267,27 +289,16 @@ func NewWorkflow(workflowName string, verbose bool, force bool) error {
 	destFile, err = fileutil.ValidateAbsolutePath(destFile)
 	if err != nil {
 		commandsLog.Printf("Invalid destination file path: %v", err)
-		return fmt.Errorf("invalid destination file path: %w", err)
+		return "", fmt.Errorf("invalid destination file path: %w", err)
 	}
 
 	// Check if destination file already exists
 	if _, err := os.Stat(destFile); err == nil && !force {
 		commandsLog.Printf("Workflow file already exists and force=false: %s", destFile)
-		return fmt.Errorf("workflow file '%s' already exists. Use --force to overwrite", destFile)
-	}
-
-	// Create the template content
-	template := createWorkflowTemplate(workflowName)
-
-	// Write the template to file with restrictive permissions (owner-only)
-	if err := os.WriteFile(destFile, []byte(template), 0600); err != nil {
-		return fmt.Errorf("failed to write workflow file '%s': %w", destFile, err)
+		return "", fmt.Errorf("workflow file '%s' already exists. Use --force to overwrite", destFile)
 	}
 
-	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Created new workflow: "+destFile))
-	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Edit the file to customize your workflow, then run '%s compile' to generate the GitHub Actions workflow", string(constants.CLIExtensionPrefix))))
-
-	return nil
+	return destFile, nil
 }
 
 // createWorkflowTemplate generates a concise workflow template with essential options
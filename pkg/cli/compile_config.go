@@ -12,6 +12,7 @@ type CompileConfig struct {
 	MarkdownFiles          []string // Files to compile (empty for all files)
 	Verbose                bool     // Enable verbose output
 	EngineOverride         string   // Override AI engine setting
+	ModelOverride          string   // Override AI model setting
 	Validate               bool     // Enable schema validation
 	Watch                  bool     // Enable watch mode
 	WorkflowDir            string   // Custom workflow directory
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var evalCommandLog = logger.New("cli:eval_command")
+
+// Evaluation scenario statuses
+const (
+	evalStatusPassed = "passed"
+	evalStatusFailed = "failed"
+	evalStatusError  = "error" // The scenario could not be run
+)
+
+// EvalOptions holds the options of the eval command
+type EvalOptions struct {
+	Scenarios      []string // Scenario names to run (all when empty)
+	ResultsFile    string   // Evaluate a saved trial result instead of running a trial
+	HostRepo       string
+	EngineOverride string
+	TimeoutMinutes int
+	Yes            bool
+	JSONOutput     bool
+	Verbose        bool
+}
+
+// EvalScenarioResult is the outcome of one scenario
+type EvalScenarioResult struct {
+	Scenario     string                  `json:"scenario"`
+	Status       string                  `json:"status"`
+	RunID        string                  `json:"run_id,omitempty"`
+	Expectations []EvalExpectationResult `json:"expectations,omitempty"`
+	SafeOutputs  map[string]any          `json:"safe_outputs,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// EvalReport is the pass/fail report of an evaluation suite
+type EvalReport struct {
+	Workflow  string               `json:"workflow"`
+	Passed    int                  `json:"passed"`
+	Failed    int                  `json:"failed"`
+	Errors    int                  `json:"errors"`
+	Scenarios []EvalScenarioResult `json:"scenarios"`
+}
+
+// NewEvalCommand creates the eval command
+func NewEvalCommand(validateEngine func(string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval <workflow>",
+		Short: "Run a workflow's evaluation scenarios and check the safe outputs it produces",
+		Long: `Run the evaluation suite of a workflow and report which scenarios pass.
+
+The suite is stored in ` + EvalSuitesDir + `/<workflow>` + EvalSuiteSuffix + `. Each scenario describes
+how the workflow is triggered, which repository it runs against, and the safe outputs it is
+expected to produce:
+
+  scenarios:
+    - name: bug report is labeled
+      trigger-context: https://github.com/octo/repo/issues/12
+      logical-repo: octo/repo
+      expect:
+        - type: add_labels
+          labels: [bug]
+        - type: create_pull_request
+          count: 0
+
+Expectations match safe output items by type and optionally by title-contains,
+title-matches (regular expression), body-contains and labels. Without count at least one
+item must match; count sets the exact number of matching items (0 asserts none).
+
+Each scenario runs as a trial (see '` + string(constants.CLIExtensionPrefix) + ` trial') and is evaluated
+against the safe outputs of the run. With --results, a saved trial result is evaluated
+locally instead.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` eval issue-triage
+  ` + string(constants.CLIExtensionPrefix) + ` eval issue-triage --scenario "bug report is labeled" --yes
+  ` + string(constants.CLIExtensionPrefix) + ` eval issue-triage --scenario "bug report is labeled" --results trials/issue-triage-octo-repo.20260101-120000-1.json
+  ` + string(constants.CLIExtensionPrefix) + ` eval issue-triage --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scenarios, _ := cmd.Flags().GetStringArray("scenario")
+			resultsFile, _ := cmd.Flags().GetString("results")
+			hostRepo, _ := cmd.Flags().GetString("host-repo")
+			engineOverride, _ := cmd.Flags().GetString("engine")
+			timeout, _ := cmd.Flags().GetInt("timeout")
+			yes, _ := cmd.Flags().GetBool("yes")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			if err := validateEngine(engineOverride); err != nil {
+				return err
+			}
+			return RunWorkflowEvals(cmd.Context(), args[0], EvalOptions{
+				Scenarios:      scenarios,
+				ResultsFile:    resultsFile,
+				HostRepo:       hostRepo,
+				EngineOverride: engineOverride,
+				TimeoutMinutes: timeout,
+				Yes:            yes,
+				JSONOutput:     jsonOutput,
+				Verbose:        verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayP("scenario", "s", nil, "Run only the named scenario (can be repeated)")
+	cmd.Flags().String("results", "", "Evaluate a saved trial result file instead of running a trial")
+	cmd.Flags().String("host-repo", "", "Trial host repository (defaults to '<username>/gh-aw-trial')")
+	addEngineFlag(cmd)
+	cmd.Flags().Int("timeout", 30, "Execution timeout in minutes for each scenario")
+	cmd.Flags().BoolP("yes", "y", false, "Skip trial confirmation prompts")
+	cmd.Flags().BoolP("json", "j", false, "Output the report in JSON format")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunWorkflowEvals runs the selected scenarios of a workflow's evaluation suite and prints
+// the report. It returns an error when a scenario fails or cannot be run.
+func RunWorkflowEvals(ctx context.Context, workflowIdOrName string, opts EvalOptions) error {
+	evalCommandLog.Printf("Running evals: workflow=%s, scenarios=%v, results=%s", workflowIdOrName, opts.Scenarios, opts.ResultsFile)
+
+	workflowFile, err := resolveWorkflowFile(workflowIdOrName, opts.Verbose)
+	if err != nil {
+		return err
+	}
+	suite, err := loadEvalSuite(workflowFile)
+	if err != nil {
+		return err
+	}
+	scenarios, err := selectEvalScenarios(suite, opts.Scenarios)
+	if err != nil {
+		return err
+	}
+
+	var results []EvalScenarioResult
+	if opts.ResultsFile != "" {
+		if len(scenarios) != 1 {
+			return errors.New("--results evaluates a single scenario: use --scenario to select it")
+		}
+		trialResult, err := loadTrialResult(opts.ResultsFile)
+		if err != nil {
+			return err
+		}
+		results = append(results, evaluateEvalScenario(scenarios[0], trialResult))
+	} else {
+		for i, scenario := range scenarios {
+			if !opts.JSONOutput {
+				fmt.Fprintln(os.Stderr, console.FormatProgressMessage(fmt.Sprintf("[%d/%d] Scenario: %s", i+1, len(scenarios), scenario.Name)))
+			}
+			trialResult, err := runEvalScenarioTrial(ctx, workflowFile, scenario, opts)
+			if err != nil {
+				results = append(results, EvalScenarioResult{Scenario: scenario.Name, Status: evalStatusError, Error: err.Error()})
+				continue
+			}
+			results = append(results, evaluateEvalScenario(scenario, trialResult))
+		}
+	}
+
+	report := newEvalReport(normalizeWorkflowID(workflowFile), results)
+	if err := printEvalReport(report, opts.JSONOutput); err != nil {
+		return err
+	}
+	if report.Failed > 0 || report.Errors > 0 {
+		return fmt.Errorf("%d of %d scenarios did not pass", report.Failed+report.Errors, len(report.Scenarios))
+	}
+	return nil
+}
+
+// selectEvalScenarios returns the named scenarios, or all scenarios when no names are given
+func selectEvalScenarios(suite *EvalSuite, names []string) ([]EvalScenario, error) {
+	if len(names) == 0 {
+		return suite.Scenarios, nil
+	}
+	var selected []EvalScenario
+	for _, name := range names {
+		index := slices.IndexFunc(suite.Scenarios, func(s EvalScenario) bool { return s.Name == name })
+		if index < 0 {
+			available := make([]string, 0, len(suite.Scenarios))
+			for _, scenario := range suite.Scenarios {
+				available = append(available, scenario.Name)
+			}
+			return nil, fmt.Errorf("scenario %q not found (available: %s)", name, strings.Join(available, ", "))
+		}
+		selected = append(selected, suite.Scenarios[index])
+	}
+	return selected, nil
+}
+
+// runEvalScenarioTrial runs a scenario as a trial and returns the trial result
+func runEvalScenarioTrial(ctx context.Context, workflowFile string, scenario EvalScenario, opts EvalOptions) (*WorkflowTrialResult, error) {
+	spec := workflowFile
+	if !filepath.IsAbs(spec) {
+		spec = "./" + filepath.ToSlash(spec)
+	}
+
+	var result *WorkflowTrialResult
+	trialOpts := TrialOptions{
+		Repos: RepoConfig{
+			LogicalRepo: scenario.LogicalRepo,
+			CloneRepo:   scenario.CloneRepo,
+			HostRepo:    opts.HostRepo,
+		},
+		Quiet:          opts.Yes,
+		TimeoutMinutes: opts.TimeoutMinutes,
+		TriggerContext: scenario.TriggerContext,
+		EngineOverride: opts.EngineOverride,
+		Verbose:        opts.Verbose,
+		onResult:       func(r WorkflowTrialResult) { result = &r },
+	}
+	if err := RunWorkflowTrials(ctx, []string{spec}, trialOpts); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("trial produced no result")
+	}
+	return result, nil
+}
+
+// loadTrialResult reads a trial result saved by 'trial' in the trials/ directory
+func loadTrialResult(path string) (*WorkflowTrialResult, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trial result: %w", err)
+	}
+	var result WorkflowTrialResult
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("invalid trial result %s: %w", path, err)
+	}
+	return &result, nil
+}
+
+// evaluateEvalScenario checks a scenario's expectations against a trial result
+func evaluateEvalScenario(scenario EvalScenario, trialResult *WorkflowTrialResult) EvalScenarioResult {
+	expectations := evaluateExpectations(scenario.Expect, trialResult.SafeOutputs)
+	status := evalStatusPassed
+	for _, expectation := range expectations {
+		if !expectation.Passed {
+			status = evalStatusFailed
+			break
+		}
+	}
+	return EvalScenarioResult{
+		Scenario:     scenario.Name,
+		Status:       status,
+		RunID:        trialResult.RunID,
+		Expectations: expectations,
+		SafeOutputs:  trialResult.SafeOutputs,
+	}
+}
+
+// newEvalReport counts the scenario results by status
+func newEvalReport(workflowName string, results []EvalScenarioResult) EvalReport {
+	report := EvalReport{Workflow: workflowName, Scenarios: results}
+	for _, result := range results {
+		switch result.Status {
+		case evalStatusPassed:
+			report.Passed++
+		case evalStatusFailed:
+			report.Failed++
+		default:
+			report.Errors++
+		}
+	}
+	return report
+}
+
+// printEvalReport prints the report as JSON to stdout or as one table per scenario
+func printEvalReport(report EvalReport, jsonOutput bool) error {
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	for _, result := range report.Scenarios {
+		fmt.Fprintln(os.Stderr, "")
+		title := fmt.Sprintf("%s: %s", result.Scenario, result.Status)
+		if result.RunID != "" {
+			title += fmt.Sprintf(" (run %s)", result.RunID)
+		}
+		switch result.Status {
+		case evalStatusPassed:
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(title))
+		case evalStatusFailed:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(title))
+		default:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(title+": "+result.Error))
+		}
+		if len(result.Expectations) > 0 {
+			fmt.Print(console.RenderStruct(result.Expectations))
+		}
+	}
+
+	message := fmt.Sprintf("%s: %d passed, %d failed, %d errors", report.Workflow, report.Passed, report.Failed, report.Errors)
+	fmt.Fprintln(os.Stderr, "")
+	if report.Failed > 0 || report.Errors > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(message))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEvalWorkflow creates a workflow with an evaluation suite and a saved trial result
+func setupEvalWorkflow(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	workflowsDir := filepath.Join(dir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "triage.md"), []byte("---\non: workflow_dispatch\n---\n\n# Triage\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, EvalSuitesDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EvalSuitesDir, "triage"+EvalSuiteSuffix), []byte(testEvalSuite), 0644))
+
+	resultsFile := filepath.Join(dir, "trial.json")
+	require.NoError(t, os.WriteFile(resultsFile, []byte(`{"workflow_name":"triage","run_id":"42","safe_outputs":`+testSafeOutputs+`}`), 0644))
+	return resultsFile
+}
+
+func TestRunWorkflowEvalsWithResults(t *testing.T) {
+	resultsFile := setupEvalWorkflow(t)
+
+	err := RunWorkflowEvals(context.Background(), "triage", EvalOptions{
+		Scenarios:   []string{"bug report is labeled"},
+		ResultsFile: resultsFile,
+		JSONOutput:  true,
+	})
+	require.NoError(t, err, "passing scenario should not return an error")
+
+	err = RunWorkflowEvals(context.Background(), "triage", EvalOptions{
+		Scenarios:   []string{"weekly summary"},
+		ResultsFile: resultsFile,
+		JSONOutput:  true,
+	})
+	require.Error(t, err, "failing scenario should return an error")
+	assert.Contains(t, err.Error(), "1 of 1 scenarios did not pass")
+}
+
+func TestRunWorkflowEvalsResultsRequireOneScenario(t *testing.T) {
+	resultsFile := setupEvalWorkflow(t)
+
+	err := RunWorkflowEvals(context.Background(), "triage", EvalOptions{ResultsFile: resultsFile})
+	require.Error(t, err, "results for multiple scenarios should be rejected")
+	assert.Contains(t, err.Error(), "--scenario")
+
+	err = RunWorkflowEvals(context.Background(), "triage", EvalOptions{Scenarios: []string{"missing"}, ResultsFile: resultsFile})
+	require.Error(t, err, "unknown scenario should be rejected")
+	assert.Contains(t, err.Error(), "bug report is labeled, weekly summary", "error should list scenarios")
+}
+
+func TestRunWorkflowEvalsMissingSuite(t *testing.T) {
+	setupEvalWorkflow(t)
+	require.NoError(t, os.Remove(filepath.Join(EvalSuitesDir, "triage"+EvalSuiteSuffix)))
+
+	err := RunWorkflowEvals(context.Background(), "triage", EvalOptions{})
+	require.Error(t, err, "missing suite should be reported")
+	assert.Contains(t, err.Error(), "no evaluation suite found")
+}
+
+func TestNewEvalReport(t *testing.T) {
+	report := newEvalReport("triage", []EvalScenarioResult{
+		{Status: evalStatusPassed}, {Status: evalStatusFailed}, {Status: evalStatusError}, {Status: evalStatusPassed},
+	})
+	assert.Equal(t, 2, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Errors)
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var evalSuiteLog = logger.New("cli:eval_suite")
+
+// EvalSuitesDir holds the evaluation suite of each workflow (<workflow>.eval.yml). Suites
+// are kept out of .github/workflows, where GitHub Actions would parse them as workflows.
+const EvalSuitesDir = ".github/aw/evals"
+
+// EvalSuiteSuffix is the file name suffix of evaluation suites
+const EvalSuiteSuffix = ".eval.yml"
+
+// EvalSuite lists the scenarios a workflow is expected to handle
+type EvalSuite struct {
+	Scenarios []EvalScenario `yaml:"scenarios"`
+}
+
+// EvalScenario is one evaluation: how the workflow is triggered, which repository it runs
+// against and the safe outputs it is expected to produce
+type EvalScenario struct {
+	Name           string            `yaml:"name"`
+	TriggerContext string            `yaml:"trigger-context,omitempty"` // e.g. an issue URL, passed to trial
+	LogicalRepo    string            `yaml:"logical-repo,omitempty"`    // Repository the run is simulated against
+	CloneRepo      string            `yaml:"clone-repo,omitempty"`      // Fixture repository cloned into the trial repository
+	Expect         []EvalExpectation `yaml:"expect"`
+}
+
+// EvalExpectation matches safe output items of one type. Without count at least one
+// item must match; count: 0 asserts that no matching item was produced.
+type EvalExpectation struct {
+	Type          string   `yaml:"type"`
+	TitleContains string   `yaml:"title-contains,omitempty"`
+	TitleMatches  string   `yaml:"title-matches,omitempty"`
+	BodyContains  string   `yaml:"body-contains,omitempty"`
+	Labels        []string `yaml:"labels,omitempty"`
+	Count         *int     `yaml:"count,omitempty"`
+
+	titlePattern *regexp.Regexp
+}
+
+// EvalExpectationResult is the outcome of one expectation
+type EvalExpectationResult struct {
+	Expectation string `json:"expectation" console:"header:Expectation"`
+	Passed      bool   `json:"passed" console:"header:Passed"`
+	Actual      string `json:"actual" console:"header:Actual"`
+}
+
+// evalSuitePath returns the evaluation suite path of a workflow
+func evalSuitePath(workflowIdOrPath string) string {
+	return filepath.Join(EvalSuitesDir, normalizeWorkflowID(workflowIdOrPath)+EvalSuiteSuffix)
+}
+
+// loadEvalSuite reads and validates the evaluation suite of a workflow
+func loadEvalSuite(workflowFile string) (*EvalSuite, error) {
+	path := evalSuitePath(workflowFile)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no evaluation suite found: create %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evaluation suite: %w", err)
+	}
+	suite, err := parseEvalSuite(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evaluation suite %s: %w", path, err)
+	}
+	evalSuiteLog.Printf("Loaded %d scenarios from %s", len(suite.Scenarios), path)
+	return suite, nil
+}
+
+// parseEvalSuite parses an evaluation suite and validates its scenarios and matchers
+func parseEvalSuite(content []byte) (*EvalSuite, error) {
+	var suite EvalSuite
+	if err := yaml.Unmarshal(content, &suite); err != nil {
+		return nil, err
+	}
+	if len(suite.Scenarios) == 0 {
+		return nil, errors.New("at least one scenario is required")
+	}
+
+	seen := make(map[string]bool)
+	for i := range suite.Scenarios {
+		scenario := &suite.Scenarios[i]
+		if strings.TrimSpace(scenario.Name) == "" {
+			return nil, fmt.Errorf("scenario %d: name is required", i+1)
+		}
+		if seen[scenario.Name] {
+			return nil, fmt.Errorf("scenario %q is defined more than once", scenario.Name)
+		}
+		seen[scenario.Name] = true
+		if scenario.LogicalRepo != "" && scenario.CloneRepo != "" {
+			return nil, fmt.Errorf("scenario %q: logical-repo and clone-repo cannot be combined", scenario.Name)
+		}
+		if len(scenario.Expect) == 0 {
+			return nil, fmt.Errorf("scenario %q: at least one expectation is required", scenario.Name)
+		}
+		for j := range scenario.Expect {
+			if err := scenario.Expect[j].validate(); err != nil {
+				return nil, fmt.Errorf("scenario %q, expectation %d: %w", scenario.Name, j+1, err)
+			}
+		}
+	}
+	return &suite, nil
+}
+
+// validate normalizes the safe output type and compiles the title pattern
+func (e *EvalExpectation) validate() error {
+	e.Type = normalizeSafeOutputType(e.Type)
+	if e.Type == "" {
+		return errors.New("type is required")
+	}
+	if e.Count != nil && *e.Count < 0 {
+		return errors.New("count cannot be negative")
+	}
+	if e.TitleMatches != "" {
+		pattern, err := regexp.Compile(e.TitleMatches)
+		if err != nil {
+			return fmt.Errorf("invalid title-matches pattern: %w", err)
+		}
+		e.titlePattern = pattern
+	}
+	return nil
+}
+
+// String describes the expectation, e.g. `create_issue with title containing "flaky"`
+func (e EvalExpectation) String() string {
+	var conditions []string
+	if e.TitleContains != "" {
+		conditions = append(conditions, fmt.Sprintf("title containing %q", e.TitleContains))
+	}
+	if e.TitleMatches != "" {
+		conditions = append(conditions, fmt.Sprintf("title matching /%s/", e.TitleMatches))
+	}
+	if e.BodyContains != "" {
+		conditions = append(conditions, fmt.Sprintf("body containing %q", e.BodyContains))
+	}
+	if len(e.Labels) > 0 {
+		conditions = append(conditions, "labels "+strings.Join(e.Labels, ", "))
+	}
+
+	text := e.Type
+	if len(conditions) > 0 {
+		text += " with " + strings.Join(conditions, " and ")
+	}
+	switch {
+	case e.Count == nil:
+		return text
+	case *e.Count == 0:
+		return "no " + text
+	default:
+		return fmt.Sprintf("exactly %d × %s", *e.Count, text)
+	}
+}
+
+// matches reports whether a safe output item satisfies the expectation's matchers
+func (e EvalExpectation) matches(item map[string]any) bool {
+	if normalizeSafeOutputType(evalItemString(item, "type")) != e.Type {
+		return false
+	}
+	title := evalItemString(item, "title")
+	if e.TitleContains != "" && !strings.Contains(strings.ToLower(title), strings.ToLower(e.TitleContains)) {
+		return false
+	}
+	if e.titlePattern != nil && !e.titlePattern.MatchString(title) {
+		return false
+	}
+	if e.BodyContains != "" && !strings.Contains(strings.ToLower(evalItemString(item, "body")), strings.ToLower(e.BodyContains)) {
+		return false
+	}
+	if len(e.Labels) > 0 {
+		labels := evalItemStrings(item, "labels")
+		for _, label := range e.Labels {
+			if !slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// evaluateExpectations checks the expectations of a scenario against the safe outputs of
+// a run (the content of agent_output.json)
+func evaluateExpectations(expectations []EvalExpectation, safeOutputs map[string]any) []EvalExpectationResult {
+	items := evalSafeOutputItems(safeOutputs)
+	results := make([]EvalExpectationResult, 0, len(expectations))
+	for _, expectation := range expectations {
+		ofType, matching := 0, 0
+		for _, item := range items {
+			if normalizeSafeOutputType(evalItemString(item, "type")) != expectation.Type {
+				continue
+			}
+			ofType++
+			if expectation.matches(item) {
+				matching++
+			}
+		}
+
+		passed := matching > 0
+		if expectation.Count != nil {
+			passed = matching == *expectation.Count
+		}
+		results = append(results, EvalExpectationResult{
+			Expectation: expectation.String(),
+			Passed:      passed,
+			Actual:      fmt.Sprintf("%d matching of %d %s items", matching, ofType, expectation.Type),
+		})
+	}
+	return results
+}
+
+// evalSafeOutputItems returns the items of a safe outputs document
+func evalSafeOutputItems(safeOutputs map[string]any) []map[string]any {
+	rawItems, _ := safeOutputs["items"].([]any)
+	items := make([]map[string]any, 0, len(rawItems))
+	for _, raw := range rawItems {
+		if item, ok := raw.(map[string]any); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// evalItemString returns a string field of a safe output item
+func evalItemString(item map[string]any, key string) string {
+	value, _ := item[key].(string)
+	return value
+}
+
+// evalItemStrings returns a string list field of a safe output item
+func evalItemStrings(item map[string]any, key string) []string {
+	rawValues, _ := item[key].([]any)
+	values := make([]string, 0, len(rawValues))
+	for _, raw := range rawValues {
+		if value, ok := raw.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,118 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEvalSuite = `scenarios:
+  - name: bug report is labeled
+    trigger-context: https://github.com/octo/repo/issues/12
+    logical-repo: octo/repo
+    expect:
+      - type: add-labels
+        labels: [bug]
+      - type: create_issue
+        title-contains: flaky
+        body-contains: retry
+      - type: create_pull_request
+        count: 0
+  - name: weekly summary
+    expect:
+      - type: create_discussion
+        title-matches: '^Weekly report \d+'
+`
+
+const testSafeOutputs = `{
+  "items": [
+    {"type": "add_labels", "labels": ["Bug", "triage"]},
+    {"type": "create_issue", "title": "Fix flaky test", "body": "Add a retry around the network call"},
+    {"type": "create_issue", "title": "Unrelated", "body": "Something else"}
+  ],
+  "errors": []
+}`
+
+func testSafeOutputsMap(t *testing.T) map[string]any {
+	t.Helper()
+	var safeOutputs map[string]any
+	require.NoError(t, json.Unmarshal([]byte(testSafeOutputs), &safeOutputs))
+	return safeOutputs
+}
+
+func TestParseEvalSuite(t *testing.T) {
+	suite, err := parseEvalSuite([]byte(testEvalSuite))
+	require.NoError(t, err, "valid suite should parse")
+	require.Len(t, suite.Scenarios, 2)
+
+	scenario := suite.Scenarios[0]
+	assert.Equal(t, "octo/repo", scenario.LogicalRepo, "logical repo should be parsed")
+	assert.Equal(t, "add_labels", scenario.Expect[0].Type, "types should be normalized to underscores")
+	require.NotNil(t, scenario.Expect[2].Count)
+	assert.Equal(t, 0, *scenario.Expect[2].Count, "count should be parsed")
+}
+
+func TestParseEvalSuiteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no scenarios", "scenarios: []\n", "at least one scenario"},
+		{"missing name", "scenarios:\n  - expect:\n      - type: noop\n", "name is required"},
+		{"duplicate name", "scenarios:\n  - name: a\n    expect: [{type: noop}]\n  - name: a\n    expect: [{type: noop}]\n", "more than once"},
+		{"no expectations", "scenarios:\n  - name: a\n", "at least one expectation"},
+		{"missing type", "scenarios:\n  - name: a\n    expect: [{title-contains: x}]\n", "type is required"},
+		{"negative count", "scenarios:\n  - name: a\n    expect: [{type: noop, count: -1}]\n", "cannot be negative"},
+		{"invalid pattern", "scenarios:\n  - name: a\n    expect: [{type: noop, title-matches: '('}]\n", "invalid title-matches"},
+		{"both repos", "scenarios:\n  - name: a\n    logical-repo: o/r\n    clone-repo: o/r\n    expect: [{type: noop}]\n", "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEvalSuite([]byte(tt.content))
+			require.Error(t, err, "invalid suite should be rejected")
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
+
+func TestEvaluateExpectations(t *testing.T) {
+	suite, err := parseEvalSuite([]byte(testEvalSuite))
+	require.NoError(t, err)
+
+	results := evaluateExpectations(suite.Scenarios[0].Expect, testSafeOutputsMap(t))
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.True(t, result.Passed, "expectation %q should pass: %s", result.Expectation, result.Actual)
+	}
+	assert.Equal(t, "1 matching of 2 create_issue items", results[1].Actual, "actual should count matching items")
+	assert.Equal(t, "no create_pull_request", results[2].Expectation, "count 0 should read as 'no'")
+
+	results = evaluateExpectations(suite.Scenarios[1].Expect, testSafeOutputsMap(t))
+	assert.False(t, results[0].Passed, "missing discussion should fail")
+}
+
+func TestEvaluateExpectationsCount(t *testing.T) {
+	two, one := 2, 1
+	expectations := []EvalExpectation{
+		{Type: "create_issue", Count: &two},
+		{Type: "create_issue", Count: &one},
+		{Type: "add_labels", Labels: []string{"bug", "wontfix"}},
+	}
+	results := evaluateExpectations(expectations, testSafeOutputsMap(t))
+	assert.True(t, results[0].Passed, "exact count should pass")
+	assert.False(t, results[1].Passed, "different count should fail")
+	assert.False(t, results[2].Passed, "all labels must be present")
+	assert.Equal(t, "exactly 2 × create_issue", results[0].Expectation)
+}
+
+func TestEvaluateExpectationsWithoutSafeOutputs(t *testing.T) {
+	zero := 0
+	results := evaluateExpectations([]EvalExpectation{{Type: "create_pull_request", Count: &zero}, {Type: "noop"}}, nil)
+	assert.True(t, results[0].Passed, "count 0 should pass without safe outputs")
+	assert.False(t, results[1].Passed, "required output should fail without safe outputs")
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var fleetCommandLog = logger.New("cli:fleet_command")
+
+// defaultFleetConcurrency is the default number of repositories processed in parallel
+const defaultFleetConcurrency = 4
+
+// FleetOptions holds the options shared by the fleet subcommands
+type FleetOptions struct {
+	Org         string   // Organization or user whose repositories are targeted
+	ReposFile   string   // File listing target repositories, one owner/repo per line
+	Repos       []string // Target repositories from --repo flags
+	Concurrency int
+	JSONOutput  bool
+	Verbose     bool
+	CreatePR    bool     // update: open a pull request in each repository with changes
+	OutputDir   string   // logs: parent directory of the per-repository log directories
+	Args        []string // Arguments passed through to the underlying command
+}
+
+// NewFleetCommand creates the fleet command with subcommands
+func NewFleetCommand() *cobra.Command {
+	fleetCommandLog.Print("Creating fleet command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Run status, compile, update, logs, health and schedule across many repositories",
+		Long: `Run gh aw commands across many repositories and print a consolidated report.
+
+Target repositories are selected with --org (all non-archived source repositories of an
+organization or user), --repos-file (one owner/repo per line, '#' starts a comment) and
+--repo (repeatable). The operation runs on up to --concurrency repositories at a time.
+Repositories without agentic workflows are reported as skipped.
+
+status, compile, update and schedule work on a shallow clone of each repository in a
+temporary directory. logs and health query the repository through the GitHub API.
+
+Arguments after '--' are passed to the underlying command in every repository.
+
+Available subcommands:
+  • status   - List agentic workflows, engines and enabled state
+  • compile  - Compile workflows and check that lock files are up to date
+  • update   - Check for workflow updates, or open pull requests with them
+  • logs     - Download and summarize workflow run logs
+  • health   - Report workflow success rates
+  • schedule - Combine the scheduled runs of all repositories into one load report
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` fleet status --org my-org
+  ` + string(constants.CLIExtensionPrefix) + ` fleet compile --repos-file repos.txt --concurrency 8
+  ` + string(constants.CLIExtensionPrefix) + ` fleet update --org my-org --create-pull-request
+  ` + string(constants.CLIExtensionPrefix) + ` fleet logs --org my-org -- --count 5 --engine copilot
+  ` + string(constants.CLIExtensionPrefix) + ` fleet health --repo octo/a --repo octo/b --json -- --days 30
+  ` + string(constants.CLIExtensionPrefix) + ` fleet schedule --org my-org --timezone America/New_York`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.PersistentFlags().String("org", "", "Target all non-archived source repositories of an organization or user")
+	cmd.PersistentFlags().String("repos-file", "", "File listing target repositories, one owner/repo per line")
+	cmd.PersistentFlags().StringArrayP("repo", "r", nil, "Target repository in owner/repo format (can be repeated)")
+	cmd.PersistentFlags().Int("concurrency", defaultFleetConcurrency, "Number of repositories processed in parallel")
+	cmd.PersistentFlags().BoolP("json", "j", false, "Output the consolidated report in JSON format")
+
+	cmd.AddCommand(newFleetSubcommand("status", "List agentic workflows across repositories", fleetStatus))
+	cmd.AddCommand(newFleetSubcommand("compile", "Compile workflows and check that lock files are up to date across repositories", fleetCompile))
+
+	updateCmd := newFleetSubcommand("update", "Check for workflow updates across repositories, optionally opening pull requests", fleetUpdate)
+	updateCmd.Flags().Bool("create-pull-request", false, "Open a pull request with the updates in each repository")
+	cmd.AddCommand(updateCmd)
+
+	logsCmd := newFleetSubcommand("logs", "Download and summarize workflow run logs across repositories", fleetLogs)
+	logsCmd.Flags().StringP("output", "o", "fleet-logs", "Output directory; logs are stored in <output>/<owner>/<repo>")
+	cmd.AddCommand(logsCmd)
+
+	cmd.AddCommand(newFleetSubcommand("health", "Report workflow success rates across repositories", fleetHealth))
+	cmd.AddCommand(newFleetScheduleCommand())
+
+	return cmd
+}
+
+// newFleetSubcommand creates a fleet subcommand that runs op on every target repository
+func newFleetSubcommand(name, short string, op fleetOperation) *cobra.Command {
+	return &cobra.Command{
+		Use:   name + " [-- args...]",
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := fleetOptionsFromFlags(cmd, args)
+			fleetCommandLog.Printf("Running fleet %s: org=%s, reposFile=%s, repos=%d, concurrency=%d", name, opts.Org, opts.ReposFile, len(opts.Repos), opts.Concurrency)
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runFleet(ctx, "fleet "+name, opts, op)
+		},
+	}
+}
+
+// fleetOptionsFromFlags reads the fleet flags of a subcommand
+func fleetOptionsFromFlags(cmd *cobra.Command, args []string) FleetOptions {
+	org, _ := cmd.Flags().GetString("org")
+	reposFile, _ := cmd.Flags().GetString("repos-file")
+	repos, _ := cmd.Flags().GetStringArray("repo")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	createPR, _ := cmd.Flags().GetBool("create-pull-request")
+	outputDir, _ := cmd.Flags().GetString("output")
+
+	return FleetOptions{
+		Org:         org,
+		ReposFile:   reposFile,
+		Repos:       repos,
+		Concurrency: concurrency,
+		JSONOutput:  jsonOutput,
+		Verbose:     verbose,
+		CreatePR:    createPR,
+		OutputDir:   outputDir,
+		Args:        args,
+	}
+}
+
+// FleetScheduleReport is the fleet report of 'fleet schedule' with the combined load of all
+// repositories
+type FleetScheduleReport struct {
+	FleetReport
+	Load ScheduleLoadReport `json:"load"`
+}
+
+// newFleetScheduleCommand creates the fleet subcommand that reads the schedules of every
+// repository and simulates their combined load, since Actions concurrency limits are shared
+// by all repositories of an account
+func newFleetScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Combine the scheduled runs of all repositories into one load report",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := fleetOptionsFromFlags(cmd, args)
+			scheduleOpts := scheduleOptionsFromFlags(cmd)
+			fleetCommandLog.Printf("Running fleet schedule: org=%s, reposFile=%s, repos=%d", opts.Org, opts.ReposFile, len(opts.Repos))
+
+			loc, from, until, err := scheduleOpts.window()
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			report, err := collectFleetResults(ctx, "fleet schedule", opts, fleetSchedule)
+			if err != nil {
+				return err
+			}
+
+			var workflows []ScheduledWorkflow
+			for _, result := range report.Results {
+				if scheduled, ok := result.Details.([]ScheduledWorkflow); ok {
+					workflows = append(workflows, scheduled...)
+				}
+			}
+			load, warnings := buildScheduleLoad(workflows, from, until, loc)
+
+			if opts.JSONOutput {
+				jsonBytes, err := json.MarshalIndent(FleetScheduleReport{FleetReport: report, Load: load}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(jsonBytes))
+			} else {
+				if err := printFleetReport(report, false); err != nil {
+					return err
+				}
+				for _, warning := range warnings {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+				}
+				printScheduleLoad(load)
+			}
+			return fleetReportError(report)
+		},
+	}
+	addScheduleFlags(cmd)
+	return cmd
+}
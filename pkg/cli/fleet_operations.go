@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var fleetOperationsLog = logger.New("cli:fleet_operations")
+
+// fleetPRURLPattern finds the pull request URL printed by 'update --create-pull-request'
+var fleetPRURLPattern = regexp.MustCompile(`https://\S+/pull/\d+`)
+
+// fleetStatus reports the agentic workflows of a repository and their GitHub state
+func fleetStatus(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	dir, err := r.clone(ctx, repo)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	stdout, _, err := r.runSelf(ctx, dir, r.withPassthroughArgs("status", "--json", "--repo", repo)...)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetStatus(stdout)
+}
+
+// summarizeFleetStatus summarizes the JSON output of 'status'
+func summarizeFleetStatus(output []byte) FleetResult {
+	var statuses []WorkflowStatus
+	if err := json.Unmarshal(output, &statuses); err != nil {
+		return FleetResult{Status: fleetStatusFailed, Summary: "invalid status output", Error: err.Error()}
+	}
+	if len(statuses) == 0 {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no agentic workflows"}
+	}
+
+	disabled := 0
+	engines := make(map[string]int)
+	for _, status := range statuses {
+		if status.Status == "disabled" {
+			disabled++
+		}
+		if status.EngineID != "" {
+			engines[status.EngineID]++
+		}
+	}
+
+	var engineCounts []string
+	for _, engine := range slices.Sorted(maps.Keys(engines)) {
+		engineCounts = append(engineCounts, fmt.Sprintf("%s: %d", engine, engines[engine]))
+	}
+	summary := fmt.Sprintf("%d workflows, %d disabled", len(statuses), disabled)
+	if len(engineCounts) > 0 {
+		summary += " (" + strings.Join(engineCounts, ", ") + ")"
+	}
+	return FleetResult{Status: fleetStatusOK, Summary: summary, Details: statuses}
+}
+
+// fleetCompile compiles the workflows of a repository and checks that the committed lock
+// files are up to date, like 'compile' followed by 'git diff --exit-code'
+func fleetCompile(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	dir, err := r.clone(ctx, repo)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	if !hasFleetWorkflows(dir) {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no agentic workflows"}
+	}
+
+	stdout, _, compileErr := r.runSelf(ctx, dir, r.withPassthroughArgs("compile", "--json", "--no-check-update")...)
+	var results []ValidationResult
+	if err := json.Unmarshal(stdout, &results); err != nil {
+		if compileErr != nil {
+			return fleetFailure(repo, compileErr)
+		}
+		return FleetResult{Status: fleetStatusFailed, Summary: "invalid compile output", Error: err.Error()}
+	}
+
+	changed, err := fleetChangedFiles(ctx, dir)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetCompile(results, changed)
+}
+
+// summarizeFleetCompile summarizes compile results and the files changed by compilation
+func summarizeFleetCompile(results []ValidationResult, changed []string) FleetResult {
+	var invalid []string
+	for _, result := range results {
+		if !result.Valid {
+			invalid = append(invalid, result.Workflow)
+		}
+	}
+	var stale []string
+	for _, file := range changed {
+		if strings.HasSuffix(file, ".lock.yml") {
+			stale = append(stale, file)
+		}
+	}
+
+	details := map[string]any{"results": results}
+	switch {
+	case len(invalid) > 0:
+		return FleetResult{
+			Status:  fleetStatusFailed,
+			Summary: fmt.Sprintf("%d of %d workflows invalid", len(invalid), len(results)),
+			Error:   "invalid workflows: " + strings.Join(invalid, ", "),
+			Details: details,
+		}
+	case len(stale) > 0:
+		details["stale_lock_files"] = stale
+		return FleetResult{
+			Status:  fleetStatusFailed,
+			Summary: fmt.Sprintf("%d lock files out of date", len(stale)),
+			Error:   "lock files out of date: " + strings.Join(stale, ", "),
+			Details: details,
+		}
+	default:
+		return FleetResult{Status: fleetStatusOK, Summary: fmt.Sprintf("%d workflows, lock files up to date", len(results)), Details: details}
+	}
+}
+
+// fleetUpdate updates the workflows of a repository from their sources. Without
+// --create-pull-request it only reports the repositories with pending updates.
+func fleetUpdate(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	dir, err := r.clone(ctx, repo)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	if !hasFleetWorkflows(dir) {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no agentic workflows"}
+	}
+
+	args := []string{"update"}
+	if r.opts.CreatePR {
+		args = append(args, "--create-pull-request")
+	}
+	_, stderr, err := r.runSelf(ctx, dir, r.withPassthroughArgs(args...)...)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+
+	if r.opts.CreatePR {
+		return summarizeFleetUpdatePR(string(stderr))
+	}
+	changed, err := fleetChangedFiles(ctx, dir)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetUpdate(changed)
+}
+
+// summarizeFleetUpdate reports the files an update would change
+func summarizeFleetUpdate(changed []string) FleetResult {
+	if len(changed) == 0 {
+		return FleetResult{Status: fleetStatusOK, Summary: "up to date"}
+	}
+	return FleetResult{
+		Status:  fleetStatusOutdated,
+		Summary: fmt.Sprintf("%d files would change", len(changed)),
+		Details: map[string]any{"changed_files": changed},
+	}
+}
+
+// summarizeFleetUpdatePR reports the pull request opened by 'update --create-pull-request'
+func summarizeFleetUpdatePR(stderr string) FleetResult {
+	prURL := fleetPRURLPattern.FindString(stderr)
+	if prURL == "" {
+		return FleetResult{Status: fleetStatusOK, Summary: "up to date"}
+	}
+	return FleetResult{Status: fleetStatusUpdated, Summary: "pull request opened", PullRequest: prURL}
+}
+
+// fleetLogs downloads the logs of a repository's agentic workflow runs into a
+// per-repository directory under the fleet output directory
+func fleetLogs(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	outputDir, err := filepath.Abs(filepath.Join(r.opts.OutputDir, filepath.FromSlash(repo)))
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	stdout, _, err := r.runSelf(ctx, "", r.withPassthroughArgs("logs", "--repo", repo, "--json", "--output", outputDir)...)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetLogs(stdout)
+}
+
+// summarizeFleetLogs summarizes the JSON output of 'logs'
+func summarizeFleetLogs(output []byte) FleetResult {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no runs"}
+	}
+	var data LogsData
+	if err := json.Unmarshal(output, &data); err != nil {
+		return FleetResult{Status: fleetStatusFailed, Summary: "invalid logs output", Error: err.Error()}
+	}
+	if data.Summary.TotalRuns == 0 {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no runs"}
+	}
+	summary := fmt.Sprintf("%d runs, %d errors, %d tokens, $%.2f",
+		data.Summary.TotalRuns, data.Summary.TotalErrors, data.Summary.TotalTokens, data.Summary.TotalCost)
+	return FleetResult{
+		Status:  fleetStatusOK,
+		Summary: summary,
+		Details: map[string]any{"summary": data.Summary, "logs_location": data.LogsLocation},
+	}
+}
+
+// fleetHealth reports the success rates of a repository's agentic workflows
+func fleetHealth(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	stdout, _, err := r.runSelf(ctx, "", r.withPassthroughArgs("health", "--repo", repo, "--json")...)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetHealth(stdout)
+}
+
+// summarizeFleetHealth summarizes the JSON output of 'health'
+func summarizeFleetHealth(output []byte) FleetResult {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no runs"}
+	}
+	var summary HealthSummary
+	if err := json.Unmarshal(output, &summary); err != nil {
+		return FleetResult{Status: fleetStatusFailed, Summary: "invalid health output", Error: err.Error()}
+	}
+
+	text := fmt.Sprintf("%d workflows, %d healthy", summary.TotalWorkflows, summary.HealthyWorkflows)
+	if summary.BelowThreshold == 0 {
+		return FleetResult{Status: fleetStatusOK, Summary: text, Details: summary}
+	}
+
+	var degraded []string
+	for _, wf := range summary.Workflows {
+		if wf.BelowThresh {
+			degraded = append(degraded, wf.WorkflowName)
+		}
+	}
+	text += fmt.Sprintf(", below threshold: %s", strings.Join(degraded, ", "))
+	return FleetResult{Status: fleetStatusDegraded, Summary: text, Details: summary}
+}
+
+// fleetSchedule reads the cron schedules of a repository's compiled workflows. Workflow
+// names are prefixed with the repository so the fleet load can be combined.
+func fleetSchedule(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+	dir, err := r.clone(ctx, repo)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	if !hasFleetWorkflows(dir) {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no agentic workflows"}
+	}
+	workflows, err := loadScheduledWorkflows(filepath.Join(dir, ".github", "workflows"), r.opts.Verbose)
+	if err != nil {
+		return fleetFailure(repo, err)
+	}
+	return summarizeFleetSchedule(repo, workflows)
+}
+
+// summarizeFleetSchedule summarizes the scheduled workflows of a repository
+func summarizeFleetSchedule(repo string, workflows []ScheduledWorkflow) FleetResult {
+	if len(workflows) == 0 {
+		return FleetResult{Status: fleetStatusSkipped, Summary: "no scheduled workflows"}
+	}
+	crons := 0
+	for i := range workflows {
+		workflows[i].Workflow = repo + "/" + workflows[i].Workflow
+		crons += len(workflows[i].Crons)
+	}
+	return FleetResult{
+		Status:  fleetStatusOK,
+		Summary: fmt.Sprintf("%d scheduled workflows, %d cron schedules", len(workflows), crons),
+		Details: workflows,
+	}
+}
+
+// hasFleetWorkflows reports whether a clone contains markdown workflows
+func hasFleetWorkflows(dir string) bool {
+	matches, err := filepath.Glob(filepath.Join(dir, ".github", "workflows", "*.md"))
+	return err == nil && len(matches) > 0
+}
+
+// fleetChangedFiles lists the files changed or added in a clone
+func fleetChangedFiles(ctx context.Context, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=all")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	var files []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		if len(line) > 3 {
+			files = append(files, strings.TrimSpace(line[3:]))
+		}
+	}
+	fleetOperationsLog.Printf("Changed files in %s: %d", dir, len(files))
+	return files, nil
+}
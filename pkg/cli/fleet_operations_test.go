@@ -0,0 +1,90 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeFleetStatus(t *testing.T) {
+	result := summarizeFleetStatus([]byte(`[
+  {"workflow": "triage", "engine_id": "copilot", "status": "active"},
+  {"workflow": "report", "engine_id": "claude", "status": "disabled"},
+  {"workflow": "doctor", "engine_id": "copilot", "status": "active"}
+]`))
+	assert.Equal(t, fleetStatusOK, result.Status)
+	assert.Equal(t, "3 workflows, 1 disabled (claude: 1, copilot: 2)", result.Summary)
+
+	assert.Equal(t, fleetStatusSkipped, summarizeFleetStatus([]byte("[]")).Status)
+	assert.Equal(t, fleetStatusFailed, summarizeFleetStatus([]byte("not json")).Status)
+}
+
+func TestSummarizeFleetCompile(t *testing.T) {
+	valid := []ValidationResult{{Workflow: "a.md", Valid: true}, {Workflow: "b.md", Valid: true}}
+
+	result := summarizeFleetCompile(valid, nil)
+	assert.Equal(t, fleetStatusOK, result.Status)
+	assert.Equal(t, "2 workflows, lock files up to date", result.Summary)
+
+	result = summarizeFleetCompile(valid, []string{".github/workflows/a.lock.yml", ".gitattributes"})
+	assert.Equal(t, fleetStatusFailed, result.Status)
+	assert.Equal(t, "1 lock files out of date", result.Summary)
+	assert.Contains(t, result.Error, "a.lock.yml")
+
+	result = summarizeFleetCompile([]ValidationResult{{Workflow: "a.md", Valid: false}, {Workflow: "b.md", Valid: true}}, nil)
+	assert.Equal(t, fleetStatusFailed, result.Status)
+	assert.Equal(t, "1 of 2 workflows invalid", result.Summary)
+}
+
+func TestSummarizeFleetUpdate(t *testing.T) {
+	assert.Equal(t, fleetStatusOK, summarizeFleetUpdate(nil).Status)
+
+	result := summarizeFleetUpdate([]string{".github/workflows/a.md", ".github/workflows/a.lock.yml"})
+	assert.Equal(t, fleetStatusOutdated, result.Status)
+	assert.Equal(t, "2 files would change", result.Summary)
+
+	result = summarizeFleetUpdatePR("✓ Updated triage\n✓ Created pull request https://github.com/octo/api/pull/42\n")
+	assert.Equal(t, fleetStatusUpdated, result.Status)
+	assert.Equal(t, "https://github.com/octo/api/pull/42", result.PullRequest)
+
+	assert.Equal(t, fleetStatusOK, summarizeFleetUpdatePR("ℹ No workflow changes, pull request not created").Status)
+}
+
+func TestSummarizeFleetLogs(t *testing.T) {
+	result := summarizeFleetLogs([]byte(`{"summary": {"total_runs": 4, "total_errors": 1, "total_tokens": 12000, "total_cost": 0.5}, "runs": [], "logs_location": "/tmp/logs"}`))
+	assert.Equal(t, fleetStatusOK, result.Status)
+	assert.Equal(t, "4 runs, 1 errors, 12000 tokens, $0.50", result.Summary)
+
+	assert.Equal(t, fleetStatusSkipped, summarizeFleetLogs(nil).Status)
+	assert.Equal(t, fleetStatusSkipped, summarizeFleetLogs([]byte(`{"summary": {"total_runs": 0}}`)).Status)
+}
+
+func TestSummarizeFleetHealth(t *testing.T) {
+	result := summarizeFleetHealth([]byte(`{"total_workflows": 2, "healthy_workflows": 2, "below_threshold": 0, "workflows": []}`))
+	assert.Equal(t, fleetStatusOK, result.Status)
+	assert.Equal(t, "2 workflows, 2 healthy", result.Summary)
+
+	result = summarizeFleetHealth([]byte(`{"total_workflows": 2, "healthy_workflows": 1, "below_threshold": 1,
+  "workflows": [{"workflow_name": "triage", "below_threshold": true}, {"workflow_name": "report"}]}`))
+	assert.Equal(t, fleetStatusDegraded, result.Status)
+	assert.Equal(t, "2 workflows, 1 healthy, below threshold: triage", result.Summary)
+
+	assert.Equal(t, fleetStatusSkipped, summarizeFleetHealth([]byte("\n")).Status)
+}
+
+func TestSummarizeFleetSchedule(t *testing.T) {
+	result := summarizeFleetSchedule("octo/a", []ScheduledWorkflow{
+		{Workflow: "daily", Crons: []string{"17 9 * * *"}},
+		{Workflow: "sweeper", Crons: []string{"0 */6 * * *", "30 1 * * 0"}},
+	})
+	assert.Equal(t, fleetStatusOK, result.Status)
+	assert.Equal(t, "2 scheduled workflows, 3 cron schedules", result.Summary)
+	workflows, ok := result.Details.([]ScheduledWorkflow)
+	require.True(t, ok, "details should hold the scheduled workflows")
+	assert.Equal(t, "octo/a/daily", workflows[0].Workflow, "workflow names should be prefixed with the repository")
+
+	assert.Equal(t, fleetStatusSkipped, summarizeFleetSchedule("octo/a", nil).Status)
+}
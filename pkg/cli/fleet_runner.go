@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/sourcegraph/conc/pool"
+)
+
+var fleetRunnerLog = logger.New("cli:fleet_runner")
+
+// Fleet result statuses
+const (
+	fleetStatusOK       = "ok"
+	fleetStatusFailed   = "failed"
+	fleetStatusSkipped  = "skipped"
+	fleetStatusOutdated = "outdated" // update: upstream changes are available
+	fleetStatusUpdated  = "updated"  // update: a pull request was opened
+	fleetStatusDegraded = "degraded" // health: workflows below the success threshold
+)
+
+// FleetResult is the outcome of a fleet operation for one repository
+type FleetResult struct {
+	Repository  string `json:"repository" console:"header:Repository"`
+	Status      string `json:"status" console:"header:Status"`
+	Summary     string `json:"summary,omitempty" console:"header:Summary"`
+	PullRequest string `json:"pull_request,omitempty" console:"header:Pull Request,omitempty"`
+	Error       string `json:"error,omitempty" console:"-"`
+	Details     any    `json:"details,omitempty" console:"-"` // Parsed JSON output of the underlying command
+}
+
+// FleetReport is the consolidated report of a fleet operation
+type FleetReport struct {
+	Operation    string         `json:"operation"`
+	Repositories int            `json:"repositories"`
+	Statuses     map[string]int `json:"statuses"`
+	Results      []FleetResult  `json:"results"`
+}
+
+// fleetOperation runs an operation against one repository
+type fleetOperation func(ctx context.Context, r *fleetRunner, repo string) FleetResult
+
+// fleetRunner runs gh-aw commands against many repositories
+type fleetRunner struct {
+	opts       FleetOptions
+	binaryPath string
+	workDir    string // Parent directory for clones
+
+	// execSelf creates the command for a gh-aw invocation; replaced in tests
+	execSelf func(ctx context.Context, args ...string) *exec.Cmd
+}
+
+// runFleet resolves the target repositories, runs the operation on each of them with
+// bounded concurrency and prints the consolidated report
+func runFleet(ctx context.Context, operation string, opts FleetOptions, op fleetOperation) error {
+	report, err := collectFleetResults(ctx, operation, opts, op)
+	if err != nil {
+		return err
+	}
+	if err := printFleetReport(report, opts.JSONOutput); err != nil {
+		return err
+	}
+	return fleetReportError(report)
+}
+
+// collectFleetResults resolves the target repositories and runs the operation on each of
+// them with bounded concurrency
+func collectFleetResults(ctx context.Context, operation string, opts FleetOptions, op fleetOperation) (FleetReport, error) {
+	repos, err := resolveFleetRepos(opts, listFleetOrgRepos)
+	if err != nil {
+		return FleetReport{}, err
+	}
+
+	binaryPath, err := GetBinaryPath()
+	if err != nil {
+		return FleetReport{}, err
+	}
+	workDir, err := os.MkdirTemp("", "gh-aw-fleet-")
+	if err != nil {
+		return FleetReport{}, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	r := &fleetRunner{opts: opts, binaryPath: binaryPath, workDir: workDir}
+	r.execSelf = func(ctx context.Context, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, r.binaryPath, args...)
+	}
+
+	if !opts.JSONOutput {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Running %s on %d repositories (concurrency %d)", operation, len(repos), r.concurrency())))
+	}
+	return newFleetReport(operation, r.run(ctx, repos, op)), nil
+}
+
+// fleetReportError returns an error when the operation failed for any repository
+func fleetReportError(report FleetReport) error {
+	if failed := report.Statuses[fleetStatusFailed]; failed > 0 {
+		return fmt.Errorf("%s failed for %d of %d repositories", report.Operation, failed, report.Repositories)
+	}
+	return nil
+}
+
+// concurrency returns the number of repositories processed in parallel
+func (r *fleetRunner) concurrency() int {
+	if r.opts.Concurrency < 1 {
+		return 1
+	}
+	return r.opts.Concurrency
+}
+
+// run applies the operation to every repository and returns the results in input order
+func (r *fleetRunner) run(ctx context.Context, repos []string, op fleetOperation) []FleetResult {
+	results := make([]FleetResult, len(repos))
+	var completed int64
+
+	p := pool.New().WithContext(ctx).WithMaxGoroutines(r.concurrency())
+	for i, repo := range repos {
+		p.Go(func(ctx context.Context) error {
+			var result FleetResult
+			if err := ctx.Err(); err != nil {
+				result = fleetFailure(repo, err)
+			} else {
+				result = op(ctx, r, repo)
+			}
+			result.Repository = repo
+			results[i] = result
+
+			done := atomic.AddInt64(&completed, 1)
+			fleetRunnerLog.Printf("Completed %s: status=%s", repo, result.Status)
+			if !r.opts.JSONOutput {
+				fmt.Fprintln(os.Stderr, console.FormatProgressMessage(fmt.Sprintf("[%d/%d] %s: %s", done, len(repos), repo, result.Status)))
+			}
+			return nil
+		})
+	}
+	_ = p.Wait()
+	return results
+}
+
+// fleetFailure returns a failed result for the error
+func fleetFailure(repo string, err error) FleetResult {
+	return FleetResult{Repository: repo, Status: fleetStatusFailed, Summary: firstLine(err.Error()), Error: err.Error()}
+}
+
+// clone makes a shallow clone of the repository in the runner's working directory
+func (r *fleetRunner) clone(ctx context.Context, repo string) (string, error) {
+	dir := filepath.Join(r.workDir, filepath.FromSlash(repo))
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create clone directory: %w", err)
+	}
+
+	fleetRunnerLog.Printf("Cloning %s into %s", repo, dir)
+	cmd := workflow.ExecGHContext(ctx, "repo", "clone", repo, dir, "--", "--depth", "1", "--quiet")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to clone %s: %w: %s", repo, err, strings.TrimSpace(string(output)))
+	}
+	return dir, nil
+}
+
+// runSelf runs gh-aw with the given arguments in dir (or the current directory when empty)
+// and returns its stdout and stderr. The error includes the tail of stderr.
+func (r *fleetRunner) runSelf(ctx context.Context, dir string, args ...string) ([]byte, []byte, error) {
+	fleetRunnerLog.Printf("Running gh aw %v in %q", args, dir)
+	console.LogVerbose(r.opts.Verbose, fmt.Sprintf("Running gh aw %s", strings.Join(args, " ")))
+	cmd := r.execSelf(ctx, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if tail := lastLines(stderr.String(), 5); tail != "" {
+			err = fmt.Errorf("%s: %w\n%s", args[0], err, tail)
+		} else {
+			err = fmt.Errorf("%s: %w", args[0], err)
+		}
+		return stdout.Bytes(), stderr.Bytes(), err
+	}
+	return stdout.Bytes(), stderr.Bytes(), nil
+}
+
+// withPassthroughArgs appends the arguments given after '--' to a command line
+func (r *fleetRunner) withPassthroughArgs(args ...string) []string {
+	return append(args, r.opts.Args...)
+}
+
+// newFleetReport counts the results by status
+func newFleetReport(operation string, results []FleetResult) FleetReport {
+	statuses := make(map[string]int)
+	for _, result := range results {
+		statuses[result.Status]++
+	}
+	return FleetReport{Operation: operation, Repositories: len(results), Statuses: statuses, Results: results}
+}
+
+// printFleetReport prints the report as JSON to stdout or as a table
+func printFleetReport(report FleetReport, jsonOutput bool) error {
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Print(console.RenderStruct(report.Results))
+
+	var counts []string
+	for _, status := range []string{fleetStatusOK, fleetStatusUpdated, fleetStatusOutdated, fleetStatusDegraded, fleetStatusSkipped, fleetStatusFailed} {
+		if n := report.Statuses[status]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	message := fmt.Sprintf("%s: %d repositories (%s)", report.Operation, report.Repositories, strings.Join(counts, ", "))
+	if report.Statuses[fleetStatusFailed] > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
+		for _, result := range report.Results {
+			if result.Status == fleetStatusFailed {
+				fmt.Fprintln(os.Stderr, console.FormatListItem(result.Repository+": "+result.Error))
+			}
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(message))
+	}
+	return nil
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// lastLines returns the last n non-empty lines of s
+func lastLines(s string, n int) string {
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFleetRunnerRun(t *testing.T) {
+	r := &fleetRunner{opts: FleetOptions{Concurrency: 2, JSONOutput: true}}
+	repos := []string{"octo/a", "octo/b", "octo/c", "octo/d", "octo/e"}
+
+	var running, maxRunning int64
+	results := r.run(context.Background(), repos, func(ctx context.Context, r *fleetRunner, repo string) FleetResult {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			m := atomic.LoadInt64(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt64(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt64(&running, -1)
+		if repo == "octo/c" {
+			return fleetFailure(repo, errors.New("clone failed\ndetails"))
+		}
+		return FleetResult{Status: fleetStatusOK}
+	})
+
+	require.Len(t, results, len(repos))
+	for i, result := range results {
+		assert.Equal(t, repos[i], result.Repository, "results should keep input order")
+	}
+	assert.Equal(t, fleetStatusFailed, results[2].Status)
+	assert.Equal(t, "clone failed", results[2].Summary)
+	assert.LessOrEqual(t, maxRunning, int64(2), "concurrency should be bounded")
+
+	report := newFleetReport("fleet status", results)
+	assert.Equal(t, map[string]int{fleetStatusOK: 4, fleetStatusFailed: 1}, report.Statuses)
+}
+
+func TestFleetRunnerRunSelf(t *testing.T) {
+	var gotArgs []string
+	r := &fleetRunner{opts: FleetOptions{Args: []string{"--days", "30"}}}
+	r.execSelf = func(ctx context.Context, args ...string) *exec.Cmd {
+		gotArgs = args
+		if args[0] == "health" {
+			return exec.CommandContext(ctx, "sh", "-c", `echo '{"ok": true}'`)
+		}
+		return exec.CommandContext(ctx, "sh", "-c", "echo line1 >&2; echo boom >&2; exit 3")
+	}
+
+	stdout, _, err := r.runSelf(context.Background(), "", r.withPassthroughArgs("health", "--json")...)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok": true}`, string(stdout))
+	assert.Equal(t, []string{"health", "--json", "--days", "30"}, gotArgs)
+
+	_, _, err = r.runSelf(context.Background(), "", "compile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "compile: exit status 3")
+	assert.Contains(t, err.Error(), "boom")
+}
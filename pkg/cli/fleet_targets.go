@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var fleetTargetsLog = logger.New("cli:fleet_targets")
+
+// fleetRepoPattern matches owner/repo slugs. Repository names may contain dots.
+var fleetRepoPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?/[A-Za-z0-9._-]+$`)
+
+// maxFleetOrgRepos bounds the number of repositories listed from an organization
+const maxFleetOrgRepos = 1000
+
+// resolveFleetRepos collects the target repositories from --repo flags, a repo list file
+// and an organization, in that order, without duplicates
+func resolveFleetRepos(opts FleetOptions, listOrgRepos func(org string) ([]string, error)) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+	add := func(repo, source string) error {
+		if !fleetRepoPattern.MatchString(repo) {
+			return fmt.Errorf("invalid repository %q from %s: expected owner/repo", repo, source)
+		}
+		key := strings.ToLower(repo)
+		if !seen[key] {
+			seen[key] = true
+			repos = append(repos, repo)
+		}
+		return nil
+	}
+
+	for _, repo := range opts.Repos {
+		if err := add(strings.TrimSpace(repo), "--repo"); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ReposFile != "" {
+		content, err := os.ReadFile(opts.ReposFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read repository list: %w", err)
+		}
+		fileRepos, err := parseFleetReposFile(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository list %s: %w", opts.ReposFile, err)
+		}
+		for _, repo := range fileRepos {
+			if err := add(repo, opts.ReposFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.Org != "" {
+		orgRepos, err := listOrgRepos(opts.Org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", opts.Org, err)
+		}
+		for _, repo := range orgRepos {
+			if err := add(repo, "organization "+opts.Org); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(repos) == 0 {
+		return nil, errors.New("no target repositories: use --org, --repos-file or --repo")
+	}
+	fleetTargetsLog.Printf("Resolved %d fleet repositories", len(repos))
+	return repos, nil
+}
+
+// parseFleetReposFile parses a repository list with one owner/repo per line.
+// Blank lines and '#' comments are ignored.
+func parseFleetReposFile(content string) ([]string, error) {
+	var repos []string
+	for i, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !fleetRepoPattern.MatchString(line) {
+			return nil, fmt.Errorf("line %d: %q is not in owner/repo format", i+1, line)
+		}
+		repos = append(repos, line)
+	}
+	return repos, nil
+}
+
+// listFleetOrgRepos lists the non-archived repositories of an organization or user
+func listFleetOrgRepos(org string) ([]string, error) {
+	fleetTargetsLog.Printf("Listing repositories of %s", org)
+	output, err := workflow.RunGH("Listing repositories of "+org+"...", "repo", "list", org,
+		"--no-archived", "--source", "--limit", strconv.Itoa(maxFleetOrgRepos), "--json", "nameWithOwner")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	}
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse repository list: %w", err)
+	}
+	repos := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		repos = append(repos, entry.NameWithOwner)
+	}
+	return repos, nil
+}
@@ -0,0 +1,55 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFleetReposFile(t *testing.T) {
+	repos, err := parseFleetReposFile(`# platform repositories
+octo/api
+octo/web.app   # trailing comment
+
+octo/cli
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"octo/api", "octo/web.app", "octo/cli"}, repos)
+
+	_, err = parseFleetReposFile("octo/api\nnot-a-repo\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestResolveFleetRepos(t *testing.T) {
+	reposFile := filepath.Join(t.TempDir(), "repos.txt")
+	require.NoError(t, os.WriteFile(reposFile, []byte("octo/b\nocto/c\n"), 0600))
+
+	listOrg := func(org string) ([]string, error) {
+		assert.Equal(t, "octo", org)
+		return []string{"octo/c", "octo/d", "Octo/A"}, nil
+	}
+
+	t.Run("combines sources without duplicates", func(t *testing.T) {
+		repos, err := resolveFleetRepos(FleetOptions{Repos: []string{"octo/a"}, ReposFile: reposFile, Org: "octo"}, listOrg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"octo/a", "octo/b", "octo/c", "octo/d"}, repos)
+	})
+
+	t.Run("no targets", func(t *testing.T) {
+		_, err := resolveFleetRepos(FleetOptions{}, listOrg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no target repositories")
+	})
+
+	t.Run("invalid repo flag", func(t *testing.T) {
+		_, err := resolveFleetRepos(FleetOptions{Repos: []string{"octo"}}, listOrg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected owner/repo")
+	})
+}
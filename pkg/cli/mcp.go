@@ -24,11 +24,15 @@ Available subcommands:
   • list-tools - List available tools for a specific MCP server
   • inspect    - Inspect MCP servers and list available tools, resources, and roots
   • add        - Add an MCP tool to an agentic workflow
+  • registry   - Manage MCP registries and publish MCP servers
+  • check      - Check MCP server health and detect tool schema drift
 
 Examples:
   gh aw mcp list                              # List all workflows with MCP servers
   gh aw mcp inspect weekly-research           # Inspect MCP servers in workflow
   gh aw mcp add my-workflow tavily            # Add Tavily MCP server to workflow
+  gh aw mcp registry search notion            # Search the configured MCP registry
+  gh aw mcp check weekly-research             # Detect MCP tool schema drift
   gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
@@ -40,6 +44,8 @@ Examples:
 	cmd.AddCommand(NewMCPListSubcommand())
 	cmd.AddCommand(NewMCPListToolsSubcommand())
 	cmd.AddCommand(NewMCPInspectSubcommand())
+	cmd.AddCommand(NewMCPRegistrySubcommand())
+	cmd.AddCommand(NewMCPCheckSubcommand())
 
 	return cmd
 }
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
-	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/stringutil"
@@ -33,8 +32,11 @@ func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, tra
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Adding MCP tool '%s' to workflow: %s", mcpServerID, console.ToRelativePath(workflowPath))))
 	}
 
-	// Create registry client
-	registryClient := NewMCPRegistryClient(registryURL)
+	// Create registry client, resolving configured registry names and credentials
+	registryClient, err := resolveMCPRegistryClient(registryURL, false)
+	if err != nil {
+		return err
+	}
 
 	// Search for the MCP server in the registry
 	if verbose {
@@ -325,6 +327,7 @@ Examples:
   gh aw mcp add weekly-research makenotion/notion-mcp-server  # Add Notion MCP server to weekly-research.md
   gh aw mcp add weekly-research makenotion/notion-mcp-server --transport stdio  # Prefer stdio transport
   gh aw mcp add weekly-research makenotion/notion-mcp-server --registry https://custom.registry.com/v1  # Use custom registry
+  gh aw mcp add weekly-research acme/search-mcp --registry internal  # Use a registry configured with 'gh aw mcp registry add'
   gh aw mcp add weekly-research makenotion/notion-mcp-server --tool-id my-notion  # Use custom tool ID
 
 The command will:
@@ -333,17 +336,13 @@ The command will:
 - Add the MCP tool configuration to the workflow's frontmatter
 - Automatically compile the workflow to generate the .lock.yml file
 
-Registry URL defaults to: https://api.mcp.github.com/v0.1`,
+Registry URL defaults to the 'default' entry of .github/aw/mcp-registries.json, or https://api.mcp.github.com/v0.1`,
 		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			// If no arguments provided, show list of available servers
 			if len(args) == 0 {
-				// Use default registry URL if not provided
-				if registryURL == "" {
-					registryURL = string(constants.DefaultMCPRegistryURL)
-				}
 				return listAvailableServers(registryURL, verbose)
 			}
 
@@ -360,7 +359,7 @@ Registry URL defaults to: https://api.mcp.github.com/v0.1`,
 		},
 	}
 
-	cmd.Flags().StringVar(&registryURL, "registry", "", "MCP registry URL (default: https://api.mcp.github.com/v0.1)")
+	cmd.Flags().StringVar(&registryURL, "registry", "", "MCP registry name or URL (default: https://api.mcp.github.com/v0.1)")
 	cmd.Flags().StringVar(&transportType, "transport", "", "Preferred transport type (stdio, http, docker)")
 	cmd.Flags().StringVar(&customToolID, "tool-id", "", "Custom tool ID to use in the workflow (default: uses server ID)")
 
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+var mcpCheckLog = logger.New("cli:mcp_check")
+
+// mcpCheckConnect connects to an MCP server; it is a variable so tests can stub connections
+var mcpCheckConnect = connectToMCPServer
+
+// MCPCheckOptions holds the options for the mcp check command
+type MCPCheckOptions struct {
+	WorkflowIDs  []string
+	ServerFilter string
+	Update       bool
+	JSONOutput   bool
+	Verbose      bool
+}
+
+// MCPServerHealth records the connection result for one MCP server
+type MCPServerHealth struct {
+	Server    string `json:"server"`
+	Healthy   bool   `json:"healthy"`
+	ToolCount int    `json:"tool_count"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MCPCheckWorkflowResult is the check result for a single workflow
+type MCPCheckWorkflowResult struct {
+	Workflow        string            `json:"workflow"`
+	Snapshot        string            `json:"snapshot"`
+	SnapshotWritten bool              `json:"snapshot_written"`
+	Servers         []MCPServerHealth `json:"servers"`
+	Findings        []MCPDriftFinding `json:"findings,omitempty"`
+}
+
+// Failed reports whether the workflow has unhealthy servers or breaking drift
+func (r MCPCheckWorkflowResult) Failed() bool {
+	for _, s := range r.Servers {
+		if !s.Healthy {
+			return true
+		}
+	}
+	for _, f := range r.Findings {
+		if f.Severity == MCPDriftBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// RunMCPCheck connects to every MCP server of the selected workflows, compares the live
+// tool contracts with the committed snapshots, and returns an error on breaking drift
+func RunMCPCheck(opts MCPCheckOptions) error {
+	mcpCheckLog.Printf("Running MCP check: workflows=%v, server=%q, update=%t", opts.WorkflowIDs, opts.ServerFilter, opts.Update)
+
+	workflows, err := ScanWorkflowsForMCP(getWorkflowsDir(), opts.ServerFilter, opts.Verbose)
+	if err != nil {
+		return err
+	}
+	if len(opts.WorkflowIDs) > 0 {
+		workflows = selectMCPWorkflows(workflows, opts.WorkflowIDs)
+	}
+	if len(workflows) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No workflows with MCP servers found"))
+		return nil
+	}
+
+	var results []MCPCheckWorkflowResult
+	for _, wf := range workflows {
+		result, err := checkWorkflowMCPServers(wf, opts)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+
+	if opts.JSONOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		renderMCPCheckResults(results, opts.Verbose)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("MCP check failed for %d workflow(s). Review the findings and run 'gh aw mcp check --update' once workflows are adapted", failed)
+	}
+	return nil
+}
+
+// selectMCPWorkflows filters scanned workflows to the requested workflow IDs
+func selectMCPWorkflows(workflows []WorkflowMCPMetadata, ids []string) []WorkflowMCPMetadata {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[normalizeWorkflowID(id)] = true
+	}
+	var selected []WorkflowMCPMetadata
+	for _, wf := range workflows {
+		if wanted[wf.BaseName] {
+			selected = append(selected, wf)
+		}
+	}
+	return selected
+}
+
+// checkWorkflowMCPServers checks a single workflow against its snapshot
+func checkWorkflowMCPServers(wf WorkflowMCPMetadata, opts MCPCheckOptions) (MCPCheckWorkflowResult, error) {
+	snapshotPath := mcpSnapshotPath(wf.BaseName)
+	result := MCPCheckWorkflowResult{Workflow: wf.BaseName, Snapshot: snapshotPath}
+
+	previous, err := loadMCPWorkflowSnapshot(snapshotPath)
+	if err != nil {
+		return result, err
+	}
+
+	current := &MCPWorkflowSnapshot{Workflow: wf.BaseName, Servers: make(map[string]MCPServerSnapshot)}
+	if previous != nil && opts.ServerFilter != "" {
+		// Servers outside the filter keep their recorded state so --server --update is
+		// non-destructive, unless they were removed from the workflow
+		declared, err := workflowMCPServerNames(wf)
+		if err != nil {
+			return result, err
+		}
+		for name, server := range previous.Servers {
+			if declared[name] {
+				current.Servers[name] = server
+			}
+		}
+	}
+
+	for _, config := range filterOutSafeOutputs(wf.MCPConfigs) {
+		health, snapshot := probeMCPServer(config, opts.Verbose)
+		result.Servers = append(result.Servers, health)
+		if !health.Healthy {
+			continue
+		}
+
+		var recorded *MCPServerSnapshot
+		if previous != nil {
+			if s, ok := previous.Servers[config.Name]; ok {
+				recorded = &s
+			}
+		}
+		if opts.Update {
+			recorded = nil
+		}
+		result.Findings = append(result.Findings, compareMCPServerSnapshots(config.Name, recorded, snapshot, config.Allowed)...)
+		current.Servers[config.Name] = snapshot
+	}
+
+	// Only record a snapshot when every server was reachable, otherwise a flaky
+	// server would silently drop out of the committed contract
+	allHealthy := true
+	for _, s := range result.Servers {
+		allHealthy = allHealthy && s.Healthy
+	}
+	if allHealthy && (opts.Update || previous == nil) {
+		if err := saveMCPWorkflowSnapshot(snapshotPath, current); err != nil {
+			return result, err
+		}
+		result.SnapshotWritten = true
+	}
+
+	return result, nil
+}
+
+// workflowMCPServerNames returns the names of every MCP server declared by the workflow,
+// regardless of the --server filter used to scan it
+func workflowMCPServerNames(wf WorkflowMCPMetadata) (map[string]bool, error) {
+	configs, err := parser.ExtractMCPConfigurations(wf.Frontmatter, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP servers of %s: %w", wf.FileName, err)
+	}
+	names := make(map[string]bool, len(configs))
+	for _, config := range filterOutSafeOutputs(configs) {
+		names[config.Name] = true
+	}
+	return names, nil
+}
+
+// probeMCPServer connects to a server and converts its tool list into a snapshot
+func probeMCPServer(config parser.MCPServerConfig, verbose bool) (MCPServerHealth, MCPServerSnapshot) {
+	health := MCPServerHealth{Server: config.Name}
+
+	start := time.Now()
+	info, err := mcpCheckConnect(config, verbose)
+	health.LatencyMS = time.Since(start).Milliseconds()
+	if err == nil && info == nil {
+		err = errors.New("server returned no information")
+	}
+	if err != nil {
+		mcpCheckLog.Printf("MCP server %s unhealthy: %v", config.Name, err)
+		health.Error = err.Error()
+		return health, MCPServerSnapshot{}
+	}
+
+	snapshot, err := snapshotFromServerInfo(info)
+	if err != nil {
+		health.Error = err.Error()
+		return health, MCPServerSnapshot{}
+	}
+	if snapshot.Type == "" {
+		snapshot.Type = config.Type
+	}
+
+	health.Healthy = true
+	health.ToolCount = len(snapshot.Tools)
+	return health, snapshot
+}
+
+// renderMCPCheckResults prints a per-workflow health table followed by drift findings
+func renderMCPCheckResults(results []MCPCheckWorkflowResult, verbose bool) {
+	for _, result := range results {
+		rows := make([][]string, 0, len(result.Servers))
+		for _, s := range result.Servers {
+			status := "✓ Healthy"
+			detail := fmt.Sprintf("%d tools", s.ToolCount)
+			if !s.Healthy {
+				status = "✗ Unreachable"
+				detail = s.Error
+			}
+			rows = append(rows, []string{s.Server, status, detail, fmt.Sprintf("%dms", s.LatencyMS)})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "MCP servers: " + result.Workflow,
+			Headers: []string{"Server", "Status", "Details", "Latency"},
+			Rows:    rows,
+		}))
+
+		for _, f := range result.Findings {
+			location := f.Server
+			if f.Tool != "" {
+				location += "." + f.Tool
+			}
+			switch f.Severity {
+			case MCPDriftBreaking:
+				fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %s", location, f.Message)))
+			default:
+				if verbose {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s: %s", location, f.Message)))
+				}
+			}
+		}
+
+		if result.SnapshotWritten {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated snapshot "+result.Snapshot))
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// NewMCPCheckSubcommand creates the mcp check subcommand
+func NewMCPCheckSubcommand() *cobra.Command {
+	var serverFilter string
+	var update bool
+
+	cmd := &cobra.Command{
+		Use:   "check [workflow]...",
+		Short: "Check MCP server health and detect tool schema drift against committed snapshots",
+		Long: `Connect to every MCP server configured in the given workflows (or all workflows),
+record their tools and input schemas, and compare them against the snapshot committed in
+` + mcpSnapshotsDir + `/<workflow>.json.
+
+The check fails when:
+- A server cannot be reached
+- A tool listed in 'allowed' is not provided by the server
+- A tool the workflow may call was removed, or its input schema changed incompatibly
+  (parameter removed, type changed, enum value removed, new required parameter)
+
+Compatible changes such as new tools or new optional parameters are reported with --verbose.
+The first run (or --update) writes the snapshot; commit it alongside the workflow.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  gh aw mcp check                         # Check all workflows with MCP servers
+  gh aw mcp check weekly-research         # Check a specific workflow
+  gh aw mcp check --server notion         # Check only servers matching 'notion'
+  gh aw mcp check weekly-research --update  # Accept the current server contracts`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunMCPCheck(MCPCheckOptions{
+				WorkflowIDs:  args,
+				ServerFilter: strings.TrimSpace(serverFilter),
+				Update:       update,
+				JSONOutput:   jsonOutput,
+				Verbose:      verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&serverFilter, "server", "", "Only check MCP servers whose name contains this value")
+	cmd.Flags().BoolVar(&update, "update", false, "Rewrite snapshots with the current server contracts")
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
@@ -0,0 +1,160 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mcpCheckTestWorkflow = `---
+on: workflow_dispatch
+mcp-servers:
+  search:
+    command: "./search-server"
+    allowed: ["search"]
+---
+# Search
+`
+
+func stubMCPCheckConnect(t *testing.T, tools map[string]map[string]any, connectErr error) {
+	t.Helper()
+	original := mcpCheckConnect
+	t.Cleanup(func() { mcpCheckConnect = original })
+	mcpCheckConnect = func(config parser.MCPServerConfig, verbose bool) (*parser.MCPServerInfo, error) {
+		if connectErr != nil {
+			return nil, connectErr
+		}
+		info := &parser.MCPServerInfo{Config: config, Connected: true}
+		for name, schema := range tools {
+			info.Tools = append(info.Tools, &mcp.Tool{Name: name, InputSchema: schema})
+		}
+		return info, nil
+	}
+}
+
+func setupMCPCheckRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "workflows", "search.md"), []byte(mcpCheckTestWorkflow), 0644))
+	t.Chdir(dir)
+}
+
+func TestRunMCPCheck_WritesSnapshotThenDetectsDrift(t *testing.T) {
+	setupMCPCheckRepo(t)
+
+	original := map[string]map[string]any{
+		"search": schemaWith(map[string]any{"q": map[string]any{"type": "string"}}, "q"),
+	}
+	stubMCPCheckConnect(t, original, nil)
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{JSONOutput: true}))
+
+	snapshot, err := loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	require.NotNil(t, snapshot, "first run should record a snapshot")
+	assert.Contains(t, snapshot.Servers["search"].Tools, "search")
+
+	// Upstream renames the tool: the allowed tool disappears
+	stubMCPCheckConnect(t, map[string]map[string]any{"search_v2": original["search"]}, nil)
+	err = RunMCPCheck(MCPCheckOptions{JSONOutput: true})
+	require.Error(t, err, "renamed allowed tool should fail the check")
+
+	// The snapshot is not rewritten without --update
+	unchanged, err := loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, unchanged)
+}
+
+func TestRunMCPCheck_UnreachableServer(t *testing.T) {
+	setupMCPCheckRepo(t)
+	stubMCPCheckConnect(t, nil, errors.New("connection refused"))
+
+	err := RunMCPCheck(MCPCheckOptions{JSONOutput: true})
+	require.Error(t, err)
+
+	_, statErr := os.Stat(mcpSnapshotPath("search"))
+	assert.True(t, os.IsNotExist(statErr), "no snapshot should be written when a server is unreachable")
+}
+
+func TestRunMCPCheck_UpdateAcceptsCompatibleChanges(t *testing.T) {
+	setupMCPCheckRepo(t)
+
+	stubMCPCheckConnect(t, map[string]map[string]any{
+		"search": schemaWith(map[string]any{"q": map[string]any{"type": "string"}}, "q"),
+	}, nil)
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{JSONOutput: true}))
+
+	stubMCPCheckConnect(t, map[string]map[string]any{
+		"search": schemaWith(map[string]any{
+			"q":     map[string]any{"type": "string"},
+			"limit": map[string]any{"type": "integer"},
+		}, "q"),
+	}, nil)
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{JSONOutput: true}), "new optional parameter is compatible")
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{Update: true, JSONOutput: true}))
+
+	snapshot, err := loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	assert.Contains(t, snapshot.Servers["search"].Tools["search"].InputSchema["properties"], "limit")
+}
+
+func TestRunMCPCheck_UpdatePrunesRemovedServers(t *testing.T) {
+	setupMCPCheckRepo(t)
+	workflowPath := filepath.Join(".github", "workflows", "search.md")
+	twoServers := strings.Replace(mcpCheckTestWorkflow, "mcp-servers:\n", "mcp-servers:\n  docs:\n    command: \"./docs-server\"\n    allowed: [\"search\"]\n", 1)
+	require.NoError(t, os.WriteFile(workflowPath, []byte(twoServers), 0644))
+
+	stubMCPCheckConnect(t, map[string]map[string]any{
+		"search": schemaWith(map[string]any{"q": map[string]any{"type": "string"}}, "q"),
+	}, nil)
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{JSONOutput: true}))
+	snapshot, err := loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	require.Contains(t, snapshot.Servers, "docs")
+
+	// The docs server is removed from the workflow
+	require.NoError(t, os.WriteFile(workflowPath, []byte(mcpCheckTestWorkflow), 0644))
+
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{ServerFilter: "search", Update: true, JSONOutput: true}))
+	snapshot, err = loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	assert.NotContains(t, snapshot.Servers, "docs", "filtered update should prune servers removed from the workflow")
+	assert.Contains(t, snapshot.Servers, "search")
+
+	require.NoError(t, os.WriteFile(workflowPath, []byte(twoServers), 0644))
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{Update: true, JSONOutput: true}))
+	require.NoError(t, os.WriteFile(workflowPath, []byte(mcpCheckTestWorkflow), 0644))
+
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{Update: true, JSONOutput: true}))
+	snapshot, err = loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search"}, slices.Sorted(maps.Keys(snapshot.Servers)), "full update should only record declared servers")
+}
+
+func TestRunMCPCheck_FilteredUpdateKeepsOtherServers(t *testing.T) {
+	setupMCPCheckRepo(t)
+	workflowPath := filepath.Join(".github", "workflows", "search.md")
+	twoServers := strings.Replace(mcpCheckTestWorkflow, "mcp-servers:\n", "mcp-servers:\n  docs:\n    command: \"./docs-server\"\n    allowed: [\"search\"]\n", 1)
+	require.NoError(t, os.WriteFile(workflowPath, []byte(twoServers), 0644))
+
+	stubMCPCheckConnect(t, map[string]map[string]any{
+		"search": schemaWith(map[string]any{"q": map[string]any{"type": "string"}}, "q"),
+	}, nil)
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{JSONOutput: true}))
+
+	require.NoError(t, RunMCPCheck(MCPCheckOptions{ServerFilter: "search", Update: true, JSONOutput: true}))
+	snapshot, err := loadMCPWorkflowSnapshot(mcpSnapshotPath("search"))
+	require.NoError(t, err)
+	assert.Contains(t, snapshot.Servers, "docs", "servers outside the filter keep their recorded state")
+}
@@ -164,6 +164,8 @@ func NewMCPInspectSubcommand() *cobra.Command {
 	var toolFilter string
 	var spawnInspector bool
 	var checkSecrets bool
+	var toolInput string
+	var fakeInput bool
 
 	cmd := &cobra.Command{
 		Use:   "inspect [workflow]",
@@ -187,11 +189,14 @@ Examples:
   gh aw mcp inspect weekly-research -v # Verbose output with detailed connection info
   gh aw mcp inspect weekly-research --inspector  # Launch @modelcontextprotocol/inspector
   gh aw mcp inspect weekly-research --check-secrets  # Check GitHub Actions secrets
+  gh aw mcp inspect my-workflow --tool fetch-data --input '{"limit": 5}'  # Run a safe-inputs tool locally
+  gh aw mcp inspect my-workflow --tool fetch-data --fake-input  # Run a safe-inputs tool with generated arguments
 
 The command will:
 - Parse the workflow file to extract MCP server configurations
 - Start each MCP server (stdio, docker, http)
 - Automatically start and inspect safe-inputs server if present
+- With --input or --fake-input, validate the arguments and run the safe-inputs tool locally
 - Query available tools, resources, and roots
 - Validate required secrets are available  
 - Display results in formatted tables with error details`,
@@ -215,6 +220,17 @@ The command will:
 				}
 			}
 
+			// Running a safe-inputs tool validates the arguments and calls the tool locally
+			if toolInput != "" || fakeInput {
+				if toolFilter == "" {
+					return errors.New("--input and --fake-input require --tool to be specified")
+				}
+				if toolInput != "" && fakeInput {
+					return errors.New("--input and --fake-input cannot be used together")
+				}
+				return RunSafeInputsTool(workflowFile, toolFilter, toolInput, fakeInput, verbose)
+			}
+
 			// Validate that tool flag requires server flag
 			if toolFilter != "" && serverFilter == "" {
 				return errors.New("--tool flag requires --server flag to be specified")
@@ -233,6 +249,8 @@ The command will:
 	cmd.Flags().StringVar(&toolFilter, "tool", "", "Show detailed information about a specific tool (requires --server)")
 	cmd.Flags().BoolVar(&spawnInspector, "inspector", false, "Launch the official @modelcontextprotocol/inspector tool")
 	cmd.Flags().BoolVar(&checkSecrets, "check-secrets", false, "Check GitHub Actions repository secrets for missing secrets")
+	cmd.Flags().StringVar(&toolInput, "input", "", "Run the safe-inputs tool given by --tool with these JSON arguments (inline or @file.json)")
+	cmd.Flags().BoolVar(&fakeInput, "fake-input", false, "Run the safe-inputs tool given by --tool with arguments generated from its input definitions")
 
 	// Register completions for mcp inspect command
 	cmd.ValidArgsFunction = CompleteWorkflowNames
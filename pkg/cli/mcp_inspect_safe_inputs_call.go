@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var mcpInspectCallLog = logger.New("cli:mcp_inspect_safe_inputs_call")
+
+// fakeSafeInputValue is the placeholder used for string inputs when generating fake arguments
+const fakeSafeInputValue = "example"
+
+// parseSafeInputsToolInput parses the --input flag value: an inline JSON object or @path to a JSON file
+func parseSafeInputsToolInput(input string) (map[string]any, error) {
+	data := []byte(input)
+	if path, ok := strings.CutPrefix(input, "@"); ok {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input file %s: %w", path, err)
+		}
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("--input must be a JSON object: %w", err)
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	return args, nil
+}
+
+// fakeSafeInputArguments builds arguments that satisfy a tool's parameter definitions.
+// Defaults and enum values are preferred; otherwise the smallest value meeting the constraints is used.
+// Parameters with a pattern and no default cannot be synthesized and are set to a placeholder.
+func fakeSafeInputArguments(tool *workflow.SafeInputToolConfig) map[string]any {
+	args := make(map[string]any, len(tool.Inputs))
+	for _, name := range slices.Sorted(maps.Keys(tool.Inputs)) {
+		args[name] = fakeSafeInputValueFor(tool.Inputs[name])
+	}
+	return args
+}
+
+func fakeSafeInputValueFor(param *workflow.SafeInputParam) any {
+	if param.Default != nil {
+		return param.Default
+	}
+	if len(param.Enum) > 0 {
+		return param.Enum[0]
+	}
+
+	switch param.Type {
+	case "number", "integer":
+		switch {
+		case param.Minimum != nil && param.Type == "integer":
+			return math.Ceil(*param.Minimum)
+		case param.Minimum != nil:
+			return *param.Minimum
+		case param.Maximum != nil && *param.Maximum < 1:
+			return *param.Maximum
+		default:
+			return 1
+		}
+	case "boolean":
+		return true
+	case "array":
+		count := 1
+		if param.MinItems != nil && *param.MinItems > count {
+			count = *param.MinItems
+		}
+		if param.MaxItems != nil && *param.MaxItems < count {
+			count = *param.MaxItems
+		}
+		items := make([]any, 0, count)
+		itemParam := param.Items
+		if itemParam == nil {
+			itemParam = &workflow.SafeInputParam{Type: "string"}
+		}
+		for range count {
+			items = append(items, fakeSafeInputValueFor(itemParam))
+		}
+		return items
+	case "object":
+		obj := make(map[string]any, len(param.Properties))
+		for _, name := range slices.Sorted(maps.Keys(param.Properties)) {
+			obj[name] = fakeSafeInputValueFor(param.Properties[name])
+		}
+		return obj
+	default:
+		value := fakeSafeInputValue
+		if param.MinLength != nil && len(value) < *param.MinLength {
+			value += strings.Repeat("x", *param.MinLength-len(value))
+		}
+		if param.MaxLength != nil && len(value) > *param.MaxLength {
+			value = value[:*param.MaxLength]
+		}
+		return value
+	}
+}
+
+// RunSafeInputsTool exercises a single safe-inputs tool locally: the arguments are validated
+// against the tool's parameter definitions, then the tool is called through a locally started
+// safe-inputs MCP server and its output is written to stdout.
+func RunSafeInputsTool(workflowFile string, toolName string, input string, fakeInput bool, verbose bool) error {
+	mcpInspectCallLog.Printf("Running safe-inputs tool: workflow=%s, tool=%s, fake=%t", workflowFile, toolName, fakeInput)
+
+	if workflowFile == "" {
+		return errors.New("a workflow is required to run a safe-inputs tool")
+	}
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(workflowPath) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		workflowPath = filepath.Join(cwd, workflowPath)
+	}
+
+	compiler := workflow.NewCompiler(
+		workflow.WithVerbose(verbose),
+	)
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	if workflowData.SafeInputs == nil || len(workflowData.SafeInputs.Tools) == 0 {
+		return errors.New("no safe-inputs configuration found in workflow")
+	}
+
+	tool, ok := workflowData.SafeInputs.Tools[toolName]
+	if !ok {
+		available := slices.Sorted(maps.Keys(workflowData.SafeInputs.Tools))
+		return fmt.Errorf("safe-inputs tool '%s' not found. Available tools: %s", toolName, strings.Join(available, ", "))
+	}
+
+	var args map[string]any
+	if fakeInput {
+		args = fakeSafeInputArguments(tool)
+	} else {
+		args, err = parseSafeInputsToolInput(input)
+		if err != nil {
+			return err
+		}
+	}
+
+	argsJSON, _ := json.MarshalIndent(args, "", "  ")
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Calling %s with arguments:\n%s", toolName, argsJSON)))
+
+	// Validate locally first so that constraint errors are reported without starting the server
+	if err := workflow.ValidateSafeInputArguments(tool, args); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Arguments are valid"))
+
+	config, serverCmd, tmpDir, err := startSafeInputsServer(workflowData.SafeInputs, verbose)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serverCmd.Process != nil {
+			_ = serverCmd.Process.Kill()
+		}
+		if err := os.RemoveAll(tmpDir); err != nil && verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to cleanup temporary directory: %v", err)))
+		}
+	}()
+
+	// Allow the tool's own timeout plus time for the server round trip
+	timeout := time.Duration(tool.Timeout)*time.Second + MCPConnectTimeout
+	result, err := callMCPTool(*config, toolName, args, timeout)
+	if err != nil {
+		return err
+	}
+
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			fmt.Println(text.Text)
+		}
+	}
+	if result.IsError {
+		return fmt.Errorf("safe-inputs tool '%s' returned an error", toolName)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Tool '%s' completed successfully", toolName)))
+	return nil
+}
+
+// callMCPTool connects to an HTTP MCP server and calls a single tool
+func callMCPTool(config parser.MCPServerConfig, toolName string, args map[string]any, timeout time.Duration) (*mcp.CallToolResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-inspector", Version: "1.0.0"}, &mcp.ClientOptions{
+		Logger: logger.NewSlogLoggerWithHandler(mcpInspectCallLog),
+	})
+	transport := &mcp.StreamableClientTransport{
+		Endpoint:             config.URL,
+		DisableStandaloneSSE: true,
+	}
+
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to safe-inputs server: %w", err)
+	}
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: toolName, Arguments: args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool '%s': %w", toolName, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeInputsToolInput(t *testing.T) {
+	args, err := parseSafeInputsToolInput(`{"query": "bugs", "limit": 5}`)
+	require.NoError(t, err, "inline JSON should parse")
+	assert.Equal(t, map[string]any{"query": "bugs", "limit": float64(5)}, args)
+
+	path := filepath.Join(t.TempDir(), "input.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"query": "from-file"}`), 0644))
+	args, err = parseSafeInputsToolInput("@" + path)
+	require.NoError(t, err, "input file should parse")
+	assert.Equal(t, "from-file", args["query"])
+
+	_, err = parseSafeInputsToolInput(`["not", "an", "object"]`)
+	require.Error(t, err, "arrays are not valid arguments")
+	assert.Contains(t, err.Error(), "--input must be a JSON object")
+}
+
+func TestFakeSafeInputArgumentsSatisfyDefinitions(t *testing.T) {
+	minimum := 2.5
+	minLength := 10
+	minItems := 2
+	tool := &workflow.SafeInputToolConfig{
+		Name: "deploy",
+		Inputs: map[string]*workflow.SafeInputParam{
+			"env":      {Type: "string", Enum: []any{"staging", "production"}, Required: true},
+			"name":     {Type: "string", MinLength: &minLength},
+			"replicas": {Type: "integer", Minimum: &minimum},
+			"dry-run":  {Type: "boolean", Default: false},
+			"regions":  {Type: "array", MinItems: &minItems, Items: &workflow.SafeInputParam{Type: "string"}},
+			"owner": {Type: "object", Properties: map[string]*workflow.SafeInputParam{
+				"team": {Type: "string", Required: true},
+			}},
+		},
+	}
+
+	args := fakeSafeInputArguments(tool)
+
+	assert.Equal(t, "staging", args["env"], "first enum value should be used")
+	assert.Equal(t, false, args["dry-run"], "default should be used")
+	assert.InDelta(t, 3.0, args["replicas"], 0, "integer minimum should be rounded up")
+	assert.Len(t, args["regions"], 2, "min-items should be honored")
+	assert.Len(t, args["name"], 10, "min-length should be honored")
+	require.NoError(t, workflow.ValidateSafeInputArguments(tool, args), "fake arguments should pass validation")
+}
@@ -33,27 +33,60 @@ type MCPRegistryServerForProcessing struct {
 type MCPRegistryClient struct {
 	registryURL string
 	httpClient  *http.Client
+	authToken   string
+	cache       *mcpRegistryCache
+}
+
+// MCPRegistryClientOptions configures optional behaviour of an MCP registry client
+type MCPRegistryClientOptions struct {
+	// AuthToken is sent as a bearer token on every request (for private registries)
+	AuthToken string
+	// CacheDir enables on-disk caching of registry responses when non-empty
+	CacheDir string
+	// CacheTTL controls how long cached responses are considered fresh
+	CacheTTL time.Duration
 }
 
 // NewMCPRegistryClient creates a new MCP registry client
 func NewMCPRegistryClient(registryURL string) *MCPRegistryClient {
+	return NewMCPRegistryClientWithOptions(registryURL, MCPRegistryClientOptions{})
+}
+
+// NewMCPRegistryClientWithOptions creates a new MCP registry client with authentication and caching options
+func NewMCPRegistryClientWithOptions(registryURL string, opts MCPRegistryClientOptions) *MCPRegistryClient {
 	if registryURL == "" {
 		registryURL = string(constants.DefaultMCPRegistryURL)
 	}
+	registryURL = strings.TrimSuffix(registryURL, "/")
 
-	mcpRegistryLog.Printf("Creating MCP registry client: url=%s", registryURL)
+	mcpRegistryLog.Printf("Creating MCP registry client: url=%s, auth=%t, cache=%t", registryURL, opts.AuthToken != "", opts.CacheDir != "")
 
-	return &MCPRegistryClient{
+	client := &MCPRegistryClient{
 		registryURL: registryURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		authToken: opts.AuthToken,
+	}
+	if opts.CacheDir != "" {
+		client.cache = newMCPRegistryCache(opts.CacheDir, opts.CacheTTL)
 	}
+	return client
+}
+
+// serversURL returns the URL of the registry's server listing endpoint
+func (c *MCPRegistryClient) serversURL() string {
+	return c.registryURL + "/servers"
 }
 
 // createRegistryRequest creates an HTTP request with appropriate headers for the MCP registry
 func (c *MCPRegistryClient) createRegistryRequest(method, url string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+	return c.createRegistryRequestWithBody(method, url, nil)
+}
+
+// createRegistryRequestWithBody creates an HTTP request with a body and the standard registry headers
+func (c *MCPRegistryClient) createRegistryRequestWithBody(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -61,51 +94,68 @@ func (c *MCPRegistryClient) createRegistryRequest(method, url string) (*http.Req
 	// Set standard headers
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "gh-aw-cli")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
 
 	return req, nil
 }
 
-// SearchServers searches for MCP servers in the registry by fetching all servers and filtering locally
-func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForProcessing, error) {
-	mcpRegistryLog.Printf("Searching MCP servers: query=%q", query)
+// registryStatusError converts a non-200 registry response into a descriptive error
+func registryStatusError(statusCode int, body []byte) error {
+	// Provide more helpful error messages for common HTTP status codes
+	switch statusCode {
+	case http.StatusForbidden:
+		return fmt.Errorf("MCP registry access forbidden (403): %s\nThis may be due to network or firewall restrictions", string(body))
+	case http.StatusUnauthorized:
+		return fmt.Errorf("MCP registry access unauthorized (401): %s\nAuthentication may be required", string(body))
+	case http.StatusNotFound:
+		return fmt.Errorf("MCP registry endpoint not found (404): %s\nPlease verify the registry URL is correct", string(body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("MCP registry rate limit exceeded (429): %s\nPlease try again later", string(body))
+	default:
+		return fmt.Errorf("MCP registry returned status %d: %s", statusCode, string(body))
+	}
+}
+
+// fetchServerList retrieves the full server listing, serving it from the on-disk cache when fresh
+func (c *MCPRegistryClient) fetchServerList(spinnerMessage string, successMessage func(count int) string) (*ServerListResponse, error) {
+	url := c.serversURL()
 
-	// Always use servers endpoint for listing all servers
-	searchURL := c.registryURL + "/servers"
+	if c.cache != nil {
+		if body, ok := c.cache.Get(url); ok {
+			var response ServerListResponse
+			if err := json.Unmarshal(body, &response); err == nil {
+				mcpRegistryLog.Printf("Using cached registry response: url=%s, servers=%d", url, len(response.Servers))
+				return &response, nil
+			}
+			mcpRegistryLog.Printf("Ignoring unparseable cached registry response: url=%s", url)
+		}
+	}
 
 	// Create HTTP request with proper headers
-	req, err := c.createRegistryRequest("GET", searchURL)
+	req, err := c.createRegistryRequest("GET", url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create registry request: %w", err)
 	}
 
 	// Make HTTP request with spinner
-	spinnerMessage := fmt.Sprintf("Fetching servers from %s...", searchURL)
 	spinner := console.NewSpinner(spinnerMessage)
 	spinner.Start()
 	resp, err := c.httpClient.Do(req)
-
 	if err != nil {
 		spinner.Stop()
-		return nil, fmt.Errorf("failed to search MCP registry: %w", err)
+		return nil, fmt.Errorf("failed to query MCP registry: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		spinner.Stop()
 		body, _ := io.ReadAll(resp.Body)
-		// Provide more helpful error messages for common HTTP status codes
-		switch resp.StatusCode {
-		case http.StatusForbidden:
-			return nil, fmt.Errorf("MCP registry access forbidden (403): %s\nThis may be due to network or firewall restrictions", string(body))
-		case http.StatusUnauthorized:
-			return nil, fmt.Errorf("MCP registry access unauthorized (401): %s\nAuthentication may be required", string(body))
-		case http.StatusNotFound:
-			return nil, fmt.Errorf("MCP registry endpoint not found (404): %s\nPlease verify the registry URL is correct", string(body))
-		case http.StatusTooManyRequests:
-			return nil, fmt.Errorf("MCP registry rate limit exceeded (429): %s\nPlease try again later", string(body))
-		default:
-			return nil, fmt.Errorf("MCP registry returned status %d: %s", resp.StatusCode, string(body))
-		}
+		return nil, registryStatusError(resp.StatusCode, body)
 	}
 
 	// Parse response
@@ -122,7 +172,27 @@ func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForP
 	}
 
 	// Stop spinner with success message
-	spinner.StopWithMessage(fmt.Sprintf("✓ Fetched %d servers from registry", len(response.Servers)))
+	spinner.StopWithMessage(successMessage(len(response.Servers)))
+
+	if c.cache != nil {
+		if err := c.cache.Set(url, body); err != nil {
+			mcpRegistryLog.Printf("Failed to cache registry response: %v", err)
+		}
+	}
+
+	return &response, nil
+}
+
+// SearchServers searches for MCP servers in the registry by fetching all servers and filtering locally
+func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForProcessing, error) {
+	mcpRegistryLog.Printf("Searching MCP servers: query=%q", query)
+
+	response, err := c.fetchServerList(fmt.Sprintf("Fetching servers from %s...", c.serversURL()), func(count int) string {
+		return fmt.Sprintf("✓ Fetched %d servers from registry", count)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert servers to flattened format and filter by status
 	mcpRegistryLog.Printf("Processing %d servers from registry", len(response.Servers))
@@ -264,59 +334,13 @@ func (c *MCPRegistryClient) GetServer(serverName string) (*MCPRegistryServerForP
 	mcpRegistryLog.Printf("Getting MCP server: name=%s", serverName)
 
 	// Use the servers endpoint and filter locally, just like SearchServers
-	serversURL := c.registryURL + "/servers"
-
-	// Create HTTP request with proper headers
-	req, err := c.createRegistryRequest("GET", serversURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create registry request: %w", err)
-	}
-
-	// Make HTTP request with spinner
-	spinner := console.NewSpinner(fmt.Sprintf("Fetching MCP server '%s'...", serverName))
-	spinner.Start()
-	resp, err := c.httpClient.Do(req)
-
-	if err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to get MCP server: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		spinner.Stop()
-		body, _ := io.ReadAll(resp.Body)
-		// Provide more helpful error messages for common HTTP status codes
-		switch resp.StatusCode {
-		case http.StatusForbidden:
-			return nil, fmt.Errorf("MCP registry access forbidden (403): %s\nThis may be due to network or firewall restrictions", string(body))
-		case http.StatusUnauthorized:
-			return nil, fmt.Errorf("MCP registry access unauthorized (401): %s\nAuthentication may be required", string(body))
-		case http.StatusNotFound:
-			return nil, fmt.Errorf("MCP registry endpoint not found (404): %s\nPlease verify the registry URL is correct", string(body))
-		case http.StatusTooManyRequests:
-			return nil, fmt.Errorf("MCP registry rate limit exceeded (429): %s\nPlease try again later", string(body))
-		default:
-			return nil, fmt.Errorf("MCP registry returned status %d: %s", resp.StatusCode, string(body))
-		}
-	}
-
-	// Parse response
-	body, err := io.ReadAll(resp.Body)
+	response, err := c.fetchServerList(fmt.Sprintf("Fetching MCP server '%s'...", serverName), func(int) string {
+		return fmt.Sprintf("✓ Fetched MCP server '%s'", serverName)
+	})
 	if err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to read registry response: %w", err)
-	}
-
-	var response ServerListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to parse server response: %w", err)
+		return nil, err
 	}
 
-	// Stop spinner with success message
-	spinner.StopWithMessage(fmt.Sprintf("✓ Fetched MCP server '%s'", serverName))
-
 	// Find exact match by name, filtering locally
 	for _, serverResp := range response.Servers {
 		server := serverResp.Server
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpRegistryCacheLog = logger.New("cli:mcp_registry_cache")
+
+// defaultMCPRegistryCacheTTL is how long cached registry responses are reused before refetching
+const defaultMCPRegistryCacheTTL = 1 * time.Hour
+
+// mcpRegistryCacheEntry is the on-disk representation of a cached registry response
+type mcpRegistryCacheEntry struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// mcpRegistryCache stores raw registry responses on disk, one file per request URL.
+// Entries are keyed by a hash of the URL so that multiple registries can share a directory.
+type mcpRegistryCache struct {
+	dir string
+	ttl time.Duration
+}
+
+func newMCPRegistryCache(dir string, ttl time.Duration) *mcpRegistryCache {
+	if ttl <= 0 {
+		ttl = defaultMCPRegistryCacheTTL
+	}
+	return &mcpRegistryCache{dir: dir, ttl: ttl}
+}
+
+// defaultMCPRegistryCacheDir returns the user-level cache directory for registry responses
+func defaultMCPRegistryCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil || cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "gh-aw", "mcp-registry")
+}
+
+func (c *mcpRegistryCache) pathFor(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the cached body for url if present and not older than the TTL
+func (c *mcpRegistryCache) Get(url string) ([]byte, bool) {
+	data, err := os.ReadFile(c.pathFor(url))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			mcpRegistryCacheLog.Printf("Failed to read cache entry for %s: %v", url, err)
+		}
+		return nil, false
+	}
+
+	var entry mcpRegistryCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		mcpRegistryCacheLog.Printf("Corrupt cache entry for %s: %v", url, err)
+		return nil, false
+	}
+
+	// Guard against hash collisions and stale entries
+	if entry.URL != url {
+		return nil, false
+	}
+	if age := time.Since(entry.FetchedAt); age > c.ttl {
+		mcpRegistryCacheLog.Printf("Cache entry for %s expired (age %v)", url, age)
+		return nil, false
+	}
+
+	mcpRegistryCacheLog.Printf("Cache hit for %s", url)
+	return entry.Body, true
+}
+
+// Set stores body as the cached response for url
+func (c *mcpRegistryCache) Set(url string, body []byte) error {
+	if !json.Valid(body) {
+		return fmt.Errorf("refusing to cache non-JSON response for %s", url)
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create MCP registry cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(mcpRegistryCacheEntry{
+		URL:       url,
+		FetchedAt: time.Now(),
+		Body:      body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.WriteFile(c.pathFor(url), data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	mcpRegistryCacheLog.Printf("Cached response for %s (%d bytes)", url, len(body))
+	return nil
+}
+
+// Clear removes all cached registry responses
+func (c *mcpRegistryCache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear MCP registry cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build !integration
+
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPRegistryCache_GetSet(t *testing.T) {
+	cache := newMCPRegistryCache(t.TempDir(), time.Hour)
+
+	_, ok := cache.Get("https://example.com/servers")
+	assert.False(t, ok, "empty cache should miss")
+
+	require.NoError(t, cache.Set("https://example.com/servers", []byte(`{"servers":[]}`)))
+	body, ok := cache.Get("https://example.com/servers")
+	require.True(t, ok, "cache should hit after set")
+	assert.JSONEq(t, `{"servers":[]}`, string(body))
+
+	_, ok = cache.Get("https://other.example.com/servers")
+	assert.False(t, ok, "different URL should miss")
+
+	assert.Error(t, cache.Set("https://example.com/servers", []byte("not json")), "non-JSON bodies should not be cached")
+}
+
+func TestMCPRegistryCache_Expiry(t *testing.T) {
+	cache := newMCPRegistryCache(t.TempDir(), time.Nanosecond)
+	require.NoError(t, cache.Set("https://example.com/servers", []byte(`{}`)))
+	time.Sleep(time.Millisecond)
+
+	_, ok := cache.Get("https://example.com/servers")
+	assert.False(t, ok, "expired entries should miss")
+}
+
+func TestMCPRegistryClient_AuthAndCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"servers":[{"server":{"name":"acme/search","description":"Search","version":"1.0.0"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewMCPRegistryClientWithOptions(server.URL, MCPRegistryClientOptions{
+		AuthToken: "test-token",
+		CacheDir:  t.TempDir(),
+	})
+
+	servers, err := client.SearchServers("search")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "acme/search", servers[0].Name)
+
+	server2, err := client.GetServer("acme/search")
+	require.NoError(t, err)
+	assert.Equal(t, "acme/search", server2.Name)
+	assert.Equal(t, int32(1), requests.Load(), "second lookup should be served from the cache")
+
+	unauthenticated := NewMCPRegistryClient(server.URL)
+	_, err = unauthenticated.SearchServers("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var mcpRegistryCommandLog = logger.New("cli:mcp_registry_command")
+
+// NewMCPRegistrySubcommand creates the mcp registry subcommand with its children
+func NewMCPRegistrySubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Manage MCP registries and publish MCP servers",
+		Long: `Manage the MCP registries used by 'gh aw mcp add' and publish MCP servers to them.
+
+Registries are configured per repository in ` + mcpRegistriesConfigPath + `. The public
+GitHub MCP registry is always available under the name '` + defaultMCPRegistryName + `'. Private
+registries authenticate with a bearer token read from the environment variable named
+by --token-env; tokens are never written to the configuration file.
+
+Registry responses are cached on disk for one hour. Use --no-cache to bypass the cache.
+
+Examples:
+  gh aw mcp registry list                                           # List configured registries
+  gh aw mcp registry add internal https://mcp.example.com/v0.1 --token-env MCP_REGISTRY_TOKEN
+  gh aw mcp registry search notion --registry internal              # Search a specific registry
+  gh aw mcp registry publish my-workflow my-server --version 1.0.0  # Print server.json
+  gh aw mcp registry publish my-workflow my-server --version 1.0.0 --registry internal`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newMCPRegistryListSubcommand())
+	cmd.AddCommand(newMCPRegistryAddSubcommand())
+	cmd.AddCommand(newMCPRegistryRemoveSubcommand())
+	cmd.AddCommand(newMCPRegistrySearchSubcommand())
+	cmd.AddCommand(newMCPRegistryPublishSubcommand())
+
+	return cmd
+}
+
+func newMCPRegistryListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List configured MCP registries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			config, err := loadMCPRegistriesConfig(mcpRegistriesConfigPath)
+			if err != nil {
+				return err
+			}
+			return renderMCPRegistries(config, jsonOutput)
+		},
+	}
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// renderMCPRegistries prints the configured registries as a table or JSON
+func renderMCPRegistries(config *MCPRegistriesConfig, jsonOutput bool) error {
+	defaultName := config.Default
+	if defaultName == "" {
+		defaultName = defaultMCPRegistryName
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(map[string]any{
+			"default":    defaultName,
+			"registries": config.All(),
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(config.Registries)+1)
+	for _, entry := range config.All() {
+		auth := "-"
+		if entry.TokenEnv != "" {
+			auth = "$" + entry.TokenEnv
+			if entry.Token() == "" {
+				auth += " (unset)"
+			}
+		}
+		name := entry.Name
+		if name == defaultName {
+			name += " (default)"
+		}
+		rows = append(rows, []string{name, entry.URL, auth})
+	}
+
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "MCP registries",
+		Headers: []string{"Name", "URL", "Token"},
+		Rows:    rows,
+	}))
+	return nil
+}
+
+func newMCPRegistryAddSubcommand() *cobra.Command {
+	var tokenEnv string
+	var makeDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: "Add or update an MCP registry in the repository configuration",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadMCPRegistriesConfig(mcpRegistriesConfigPath)
+			if err != nil {
+				return err
+			}
+			config.Add(MCPRegistryEntry{Name: args[0], URL: args[1], TokenEnv: tokenEnv})
+			if makeDefault {
+				config.Default = args[0]
+			}
+			if err := saveMCPRegistriesConfig(mcpRegistriesConfigPath, config); err != nil {
+				return err
+			}
+			mcpRegistryCommandLog.Printf("Added MCP registry: name=%s, url=%s", args[0], args[1])
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Added MCP registry '%s' to %s", args[0], mcpRegistriesConfigPath)))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&tokenEnv, "token-env", "", "Environment variable holding the bearer token for this registry")
+	cmd.Flags().BoolVar(&makeDefault, "default", false, "Use this registry when --registry is not specified")
+	return cmd
+}
+
+func newMCPRegistryRemoveSubcommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an MCP registry from the repository configuration",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadMCPRegistriesConfig(mcpRegistriesConfigPath)
+			if err != nil {
+				return err
+			}
+			if err := config.Remove(args[0]); err != nil {
+				return err
+			}
+			if err := saveMCPRegistriesConfig(mcpRegistriesConfigPath, config); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Removed MCP registry '%s'", args[0])))
+			return nil
+		},
+	}
+}
+
+func newMCPRegistrySearchSubcommand() *cobra.Command {
+	var registry string
+	var noCache bool
+
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search for MCP servers in a registry",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var query string
+			if len(args) > 0 {
+				query = args[0]
+			}
+
+			client, err := resolveMCPRegistryClient(registry, !noCache)
+			if err != nil {
+				return err
+			}
+			servers, err := client.SearchServers(query)
+			if err != nil {
+				return err
+			}
+			if len(servers) == 0 {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No matching MCP servers found"))
+				return nil
+			}
+
+			rows := make([][]string, 0, len(servers))
+			for _, server := range servers {
+				description := server.Description
+				if len(description) > 80 {
+					description = description[:77] + "..."
+				}
+				rows = append(rows, []string{server.Name, server.Transport, description})
+			}
+			fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+				Title:     "MCP registry: " + client.registryURL,
+				Headers:   []string{"Name", "Transport", "Description"},
+				Rows:      rows,
+				ShowTotal: true,
+				TotalRow:  []string{fmt.Sprintf("Total: %d servers", len(servers)), "", ""},
+			}))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&registry, "registry", "", "Registry name or URL (default: configured default registry)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the on-disk registry response cache")
+	return cmd
+}
+
+func newMCPRegistryPublishSubcommand() *cobra.Command {
+	var opts MCPPublishOptions
+	var outputPath string
+	var registry string
+
+	cmd := &cobra.Command{
+		Use:   "publish <workflow> <server>",
+		Short: "Generate a registry server.json from a workflow's MCP server and optionally publish it",
+		Long: `Generate a registry server.json from an MCP server declared under mcp-servers in a workflow.
+
+Container servers are published as OCI packages, npx and uvx commands as npm and PyPI
+packages, and HTTP servers as streamable-http remotes. Environment variables and headers
+that reference secrets are published as required secret inputs, never as values.
+
+Without --output or --registry the server.json is printed to stdout.
+
+` + WorkflowIDExplanation,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return PublishMCPServer(args[0], args[1], outputPath, registry, opts, verbose)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Registry name in reverse-DNS form (default: io.github.<owner>/<server>)")
+	cmd.Flags().StringVar(&opts.Version, "version", "", "Version to publish (required)")
+	cmd.Flags().StringVar(&opts.Description, "description", "", "Server description")
+	cmd.Flags().StringVar(&opts.RepositoryURL, "repository-url", "", "Source repository URL recorded in server.json")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write server.json to this file")
+	cmd.Flags().StringVar(&registry, "registry", "", "Registry name or URL to publish to")
+	_ = cmd.MarkFlagRequired("version")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	return cmd
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpRegistryConfigLog = logger.New("cli:mcp_registry_config")
+
+const (
+	// mcpRegistriesConfigPath is the repository-relative path of the MCP registries configuration file
+	mcpRegistriesConfigPath = ".github/aw/mcp-registries.json"
+
+	// defaultMCPRegistryName is the name under which the public GitHub MCP registry is always available
+	defaultMCPRegistryName = "github"
+)
+
+// MCPRegistryEntry describes a single configured MCP registry.
+// Credentials are never stored in the file; TokenEnv names the environment
+// variable that holds the bearer token for private registries.
+type MCPRegistryEntry struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	TokenEnv string `json:"token-env,omitempty"`
+}
+
+// Token returns the bearer token for the registry, or an empty string if none is configured
+func (e MCPRegistryEntry) Token() string {
+	if e.TokenEnv == "" {
+		return ""
+	}
+	return os.Getenv(e.TokenEnv)
+}
+
+// MCPRegistriesConfig is the structure of .github/aw/mcp-registries.json
+type MCPRegistriesConfig struct {
+	// Default is the name of the registry used when no --registry flag is given
+	Default    string             `json:"default,omitempty"`
+	Registries []MCPRegistryEntry `json:"registries"`
+}
+
+// builtinMCPRegistry returns the entry for the public GitHub MCP registry
+func builtinMCPRegistry() MCPRegistryEntry {
+	return MCPRegistryEntry{
+		Name: defaultMCPRegistryName,
+		URL:  string(constants.DefaultMCPRegistryURL),
+	}
+}
+
+// loadMCPRegistriesConfig reads the registries configuration from path.
+// A missing file yields an empty configuration.
+func loadMCPRegistriesConfig(path string) (*MCPRegistriesConfig, error) {
+	mcpRegistryConfigLog.Printf("Loading MCP registries config: %s", path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			mcpRegistryConfigLog.Print("No MCP registries config found, using defaults")
+			return &MCPRegistriesConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var config MCPRegistriesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	mcpRegistryConfigLog.Printf("Loaded %d configured MCP registries", len(config.Registries))
+	return &config, nil
+}
+
+// saveMCPRegistriesConfig writes the registries configuration to path
+func saveMCPRegistriesConfig(path string, config *MCPRegistriesConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP registries config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	mcpRegistryConfigLog.Printf("Saved %d MCP registries to %s", len(config.Registries), path)
+	return nil
+}
+
+// validate checks names are unique, URLs are absolute and the default refers to a known registry
+func (c *MCPRegistriesConfig) validate() error {
+	seen := make(map[string]bool)
+	for _, entry := range c.Registries {
+		if entry.Name == "" {
+			return errors.New("registry entries must have a name")
+		}
+		if entry.Name == defaultMCPRegistryName {
+			return fmt.Errorf("registry name '%s' is reserved for the public GitHub MCP registry", defaultMCPRegistryName)
+		}
+		if seen[entry.Name] {
+			return fmt.Errorf("duplicate registry name '%s'", entry.Name)
+		}
+		seen[entry.Name] = true
+		if !isRegistryURL(entry.URL) {
+			return fmt.Errorf("registry '%s' has invalid url '%s': must start with https:// or http://", entry.Name, entry.URL)
+		}
+	}
+	if c.Default != "" && c.Default != defaultMCPRegistryName && !seen[c.Default] {
+		return fmt.Errorf("default registry '%s' is not configured", c.Default)
+	}
+	return nil
+}
+
+// All returns the built-in registry followed by all configured registries
+func (c *MCPRegistriesConfig) All() []MCPRegistryEntry {
+	return append([]MCPRegistryEntry{builtinMCPRegistry()}, c.Registries...)
+}
+
+// Resolve looks up a registry by name or URL. An empty value resolves to the
+// configured default, falling back to the public GitHub MCP registry. URLs that
+// match a configured registry inherit its credentials; other URLs are used as-is.
+func (c *MCPRegistriesConfig) Resolve(nameOrURL string) (MCPRegistryEntry, error) {
+	if nameOrURL == "" {
+		nameOrURL = c.Default
+		if nameOrURL == "" {
+			nameOrURL = defaultMCPRegistryName
+		}
+	}
+
+	if isRegistryURL(nameOrURL) {
+		normalized := strings.TrimSuffix(nameOrURL, "/")
+		for _, entry := range c.All() {
+			if strings.TrimSuffix(entry.URL, "/") == normalized {
+				return entry, nil
+			}
+		}
+		return MCPRegistryEntry{Name: normalized, URL: normalized}, nil
+	}
+
+	for _, entry := range c.All() {
+		if entry.Name == nameOrURL {
+			return entry, nil
+		}
+	}
+
+	names := make([]string, 0, len(c.Registries)+1)
+	for _, entry := range c.All() {
+		names = append(names, entry.Name)
+	}
+	return MCPRegistryEntry{}, fmt.Errorf("unknown MCP registry '%s'. Configured registries: %s", nameOrURL, strings.Join(names, ", "))
+}
+
+// Add registers a new registry, replacing an existing entry with the same name
+func (c *MCPRegistriesConfig) Add(entry MCPRegistryEntry) {
+	idx := slices.IndexFunc(c.Registries, func(e MCPRegistryEntry) bool { return e.Name == entry.Name })
+	if idx >= 0 {
+		c.Registries[idx] = entry
+		return
+	}
+	c.Registries = append(c.Registries, entry)
+}
+
+// Remove deletes the named registry, clearing the default if it pointed at it
+func (c *MCPRegistriesConfig) Remove(name string) error {
+	idx := slices.IndexFunc(c.Registries, func(e MCPRegistryEntry) bool { return e.Name == name })
+	if idx < 0 {
+		return fmt.Errorf("MCP registry '%s' is not configured", name)
+	}
+	c.Registries = slices.Delete(c.Registries, idx, idx+1)
+	if c.Default == name {
+		c.Default = ""
+	}
+	return nil
+}
+
+func isRegistryURL(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+// newMCPRegistryClientForEntry creates a registry client with the entry's credentials.
+// When useCache is set, responses are cached under the user cache directory.
+func newMCPRegistryClientForEntry(entry MCPRegistryEntry, useCache bool) (*MCPRegistryClient, error) {
+	opts := MCPRegistryClientOptions{AuthToken: entry.Token()}
+	if entry.TokenEnv != "" && opts.AuthToken == "" {
+		return nil, fmt.Errorf("MCP registry '%s' requires a token but environment variable %s is not set", entry.Name, entry.TokenEnv)
+	}
+	if useCache {
+		opts.CacheDir = defaultMCPRegistryCacheDir()
+	}
+	return NewMCPRegistryClientWithOptions(entry.URL, opts), nil
+}
+
+// resolveMCPRegistryClient resolves a registry name or URL against the repository
+// configuration and returns a client for it
+func resolveMCPRegistryClient(nameOrURL string, useCache bool) (*MCPRegistryClient, error) {
+	config, err := loadMCPRegistriesConfig(mcpRegistriesConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := config.Resolve(nameOrURL)
+	if err != nil {
+		return nil, err
+	}
+	return newMCPRegistryClientForEntry(entry, useCache)
+}
@@ -0,0 +1,150 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMCPRegistriesConfig_MissingFile(t *testing.T) {
+	config, err := loadMCPRegistriesConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err, "missing config should not be an error")
+	assert.Empty(t, config.Registries, "missing config should have no registries")
+
+	entry, err := config.Resolve("")
+	require.NoError(t, err)
+	assert.Equal(t, defaultMCPRegistryName, entry.Name, "empty config should resolve to the public registry")
+}
+
+func TestMCPRegistriesConfig_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".github", "aw", "mcp-registries.json")
+
+	config := &MCPRegistriesConfig{}
+	config.Add(MCPRegistryEntry{Name: "internal", URL: "https://mcp.example.com/v0.1", TokenEnv: "INTERNAL_TOKEN"})
+	config.Default = "internal"
+	require.NoError(t, saveMCPRegistriesConfig(path, config))
+
+	loaded, err := loadMCPRegistriesConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "internal", loaded.Default)
+	require.Len(t, loaded.Registries, 1)
+	assert.Equal(t, "INTERNAL_TOKEN", loaded.Registries[0].TokenEnv)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"token-env": "INTERNAL_TOKEN"`, "config should use kebab-case keys")
+}
+
+func TestMCPRegistriesConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  MCPRegistriesConfig
+		wantErr string
+	}{
+		{
+			name:    "reserved name",
+			config:  MCPRegistriesConfig{Registries: []MCPRegistryEntry{{Name: "github", URL: "https://x"}}},
+			wantErr: "reserved",
+		},
+		{
+			name: "duplicate name",
+			config: MCPRegistriesConfig{Registries: []MCPRegistryEntry{
+				{Name: "a", URL: "https://x"},
+				{Name: "a", URL: "https://y"},
+			}},
+			wantErr: "duplicate",
+		},
+		{
+			name:    "invalid url",
+			config:  MCPRegistriesConfig{Registries: []MCPRegistryEntry{{Name: "a", URL: "ftp://x"}}},
+			wantErr: "invalid url",
+		},
+		{
+			name:    "unknown default",
+			config:  MCPRegistriesConfig{Default: "nope"},
+			wantErr: "not configured",
+		},
+		{
+			name:   "valid",
+			config: MCPRegistriesConfig{Default: "a", Registries: []MCPRegistryEntry{{Name: "a", URL: "https://x"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMCPRegistriesConfig_Resolve(t *testing.T) {
+	config := &MCPRegistriesConfig{
+		Default: "internal",
+		Registries: []MCPRegistryEntry{
+			{Name: "internal", URL: "https://mcp.example.com/v0.1", TokenEnv: "INTERNAL_TOKEN"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		input        string
+		wantName     string
+		wantTokenEnv string
+		wantErr      bool
+	}{
+		{name: "empty uses default", input: "", wantName: "internal", wantTokenEnv: "INTERNAL_TOKEN"},
+		{name: "by name", input: "github", wantName: "github"},
+		{name: "url matching configured registry inherits token", input: "https://mcp.example.com/v0.1/", wantName: "internal", wantTokenEnv: "INTERNAL_TOKEN"},
+		{name: "ad-hoc url", input: "https://other.example.com", wantName: "https://other.example.com"},
+		{name: "unknown name", input: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := config.Resolve(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "internal", "error should list configured registries")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, entry.Name)
+			assert.Equal(t, tt.wantTokenEnv, entry.TokenEnv)
+		})
+	}
+}
+
+func TestMCPRegistriesConfig_Remove(t *testing.T) {
+	config := &MCPRegistriesConfig{
+		Default:    "internal",
+		Registries: []MCPRegistryEntry{{Name: "internal", URL: "https://mcp.example.com"}},
+	}
+
+	require.NoError(t, config.Remove("internal"))
+	assert.Empty(t, config.Registries)
+	assert.Empty(t, config.Default, "removing the default registry should clear the default")
+	assert.Error(t, config.Remove("internal"), "removing twice should fail")
+}
+
+func TestNewMCPRegistryClientForEntry_MissingToken(t *testing.T) {
+	t.Setenv("GH_AW_TEST_REGISTRY_TOKEN", "")
+	_, err := newMCPRegistryClientForEntry(MCPRegistryEntry{Name: "internal", URL: "https://x", TokenEnv: "GH_AW_TEST_REGISTRY_TOKEN"}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GH_AW_TEST_REGISTRY_TOKEN")
+
+	t.Setenv("GH_AW_TEST_REGISTRY_TOKEN", "secret")
+	client, err := newMCPRegistryClientForEntry(MCPRegistryEntry{Name: "internal", URL: "https://x", TokenEnv: "GH_AW_TEST_REGISTRY_TOKEN"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", client.authToken)
+}
@@ -13,8 +13,11 @@ var mcpRegistryListLog = logger.New("cli:mcp_registry_list")
 // listAvailableServers shows a list of available MCP servers from the registry
 func listAvailableServers(registryURL string, verbose bool) error {
 	mcpRegistryListLog.Printf("Listing available MCP servers: registry_url=%s", registryURL)
-	// Create registry client
-	registryClient := NewMCPRegistryClient(registryURL)
+	// Create registry client, resolving configured registry names and credentials
+	registryClient, err := resolveMCPRegistryClient(registryURL, false)
+	if err != nil {
+		return err
+	}
 
 	// Search for all servers (empty query)
 	if verbose {
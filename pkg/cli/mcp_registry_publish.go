@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var mcpRegistryPublishLog = logger.New("cli:mcp_registry_publish")
+
+// mcpServerJSONSchemaURL is the schema referenced by generated server.json files
+const mcpServerJSONSchemaURL = "https://static.modelcontextprotocol.io/schemas/2025-09-29/server.schema.json"
+
+// mcpRegistryNamePattern validates reverse-DNS registry names such as io.github.owner/server
+var mcpRegistryNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.-]+/[a-zA-Z0-9._-]+$`)
+
+// MCPPublishOptions holds the inputs for generating a registry server.json
+type MCPPublishOptions struct {
+	// Name is the reverse-DNS registry name (e.g. io.github.owner/server)
+	Name string
+	// Description overrides the generated description
+	Description string
+	// Version is the published server version
+	Version string
+	// RepositoryURL is recorded as the server's source repository
+	RepositoryURL string
+}
+
+// buildRegistryServerDetail converts a workflow's custom MCP server configuration into
+// a registry server.json entry. Secrets referenced through ${{ secrets.* }} are published
+// as required secret inputs rather than values so they are never leaked to the registry.
+func buildRegistryServerDetail(config parser.MCPServerConfig, opts MCPPublishOptions) (*ServerDetail, error) {
+	mcpRegistryPublishLog.Printf("Building server.json for MCP server: name=%s, type=%s", config.Name, config.Type)
+
+	if !mcpRegistryNamePattern.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid registry name '%s': must be in reverse-DNS form such as io.github.owner/%s", opts.Name, config.Name)
+	}
+	if opts.Version == "" {
+		return nil, errors.New("a version is required to publish an MCP server")
+	}
+
+	detail := &ServerDetail{
+		Schema:      mcpServerJSONSchemaURL,
+		Name:        opts.Name,
+		Description: opts.Description,
+		Version:     opts.Version,
+	}
+	if detail.Description == "" {
+		detail.Description = fmt.Sprintf("MCP server '%s' published from an agentic workflow", config.Name)
+	}
+	if opts.RepositoryURL != "" {
+		detail.Repository = &Repository{URL: opts.RepositoryURL, Source: "github"}
+	}
+
+	switch config.Type {
+	case "http":
+		detail.Remotes = []Remote{{
+			Type:    "streamable-http",
+			URL:     config.URL,
+			Headers: registryInputsFromMap(config.Headers),
+		}}
+	case "stdio", "local", "":
+		pkg, err := registryPackageFromConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		detail.Packages = []MCPPackage{*pkg}
+	default:
+		return nil, fmt.Errorf("cannot publish MCP server '%s' with unsupported type '%s'", config.Name, config.Type)
+	}
+
+	return detail, nil
+}
+
+// registryPackageFromConfig derives a registry package from a stdio MCP configuration.
+// Container-based servers map to OCI packages; npx and uvx commands map to npm and PyPI.
+func registryPackageFromConfig(config parser.MCPServerConfig) (*MCPPackage, error) {
+	pkg := &MCPPackage{
+		Transport:            &Transport{Type: "stdio"},
+		EnvironmentVariables: registryInputsFromMap(config.Env),
+	}
+
+	if config.Container != "" {
+		pkg.RegistryType = "oci"
+		pkg.Identifier = config.Container
+		if config.Version != "" {
+			pkg.Identifier += ":" + config.Version
+		}
+		pkg.PackageArguments = positionalArguments(config.EntrypointArgs)
+		return pkg, nil
+	}
+
+	switch config.Command {
+	case "npx", "uvx":
+		identifier, rest := splitPackageCommandArgs(config.Args)
+		if identifier == "" {
+			return nil, fmt.Errorf("cannot determine package for MCP server '%s': no package argument after '%s'", config.Name, config.Command)
+		}
+		pkg.RegistryType = "npm"
+		if config.Command == "uvx" {
+			pkg.RegistryType = "pypi"
+		}
+		pkg.RuntimeHint = config.Command
+		pkg.Identifier, pkg.Version = splitPackageVersion(identifier)
+		pkg.PackageArguments = positionalArguments(rest)
+		return pkg, nil
+	default:
+		return nil, fmt.Errorf("cannot publish MCP server '%s': command '%s' is not a known package runner. Use 'container' or an npx/uvx command", config.Name, config.Command)
+	}
+}
+
+// splitPackageCommandArgs returns the first non-flag argument as the package and the remaining arguments
+func splitPackageCommandArgs(args []string) (string, []string) {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg, args[i+1:]
+		}
+	}
+	return "", nil
+}
+
+// splitPackageVersion splits "pkg@1.2.3" (or "@scope/pkg@1.2.3") into name and version
+func splitPackageVersion(identifier string) (string, string) {
+	at := strings.LastIndex(identifier, "@")
+	if at <= 0 {
+		return identifier, ""
+	}
+	return identifier[:at], identifier[at+1:]
+}
+
+func positionalArguments(values []string) []Argument {
+	if len(values) == 0 {
+		return nil
+	}
+	args := make([]Argument, 0, len(values))
+	for _, value := range values {
+		args = append(args, Argument{Type: ArgumentTypePositional, Value: value})
+	}
+	return args
+}
+
+// registryInputsFromMap converts env vars or headers into registry inputs, sorted by name.
+// Values that reference secrets become required secret inputs, other expressions become
+// required inputs, and literal values become defaults.
+func registryInputsFromMap(values map[string]string) []EnvironmentVariable {
+	if len(values) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputs := make([]EnvironmentVariable, 0, len(names))
+	for _, name := range names {
+		value := values[name]
+		input := EnvironmentVariable{Name: name}
+		switch {
+		case len(workflow.ExtractSecretsFromValue(value)) > 0:
+			input.IsRequired = true
+			input.IsSecret = true
+		case strings.Contains(value, "${{"):
+			input.IsRequired = true
+		default:
+			input.Default = value
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+// GenerateMCPServerJSON loads a workflow and renders the registry server.json for one of its custom MCP servers
+func GenerateMCPServerJSON(workflowFile string, serverName string, opts MCPPublishOptions) ([]byte, error) {
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowData, configs, err := loadWorkflowMCPConfigs(workflowPath, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only servers declared under mcp-servers can be published; built-in servers are provided by gh-aw
+	customServers, _ := workflowData.Frontmatter["mcp-servers"].(map[string]any)
+	if _, ok := customServers[serverName]; !ok {
+		return nil, fmt.Errorf("MCP server '%s' is not defined under mcp-servers in %s", serverName, filepath.Base(workflowPath))
+	}
+
+	var target *parser.MCPServerConfig
+	for i := range configs {
+		if configs[i].Name == serverName {
+			target = &configs[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("MCP server '%s' not found in %s", serverName, filepath.Base(workflowPath))
+	}
+
+	if opts.Name == "" {
+		slug, err := GetCurrentRepoSlug()
+		if err != nil {
+			return nil, fmt.Errorf("could not derive registry name from repository, use --name: %w", err)
+		}
+		owner, _, _ := strings.Cut(slug, "/")
+		opts.Name = fmt.Sprintf("io.github.%s/%s", owner, serverName)
+	}
+
+	detail, err := buildRegistryServerDetail(*target, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(detail, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode server.json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// PublishServer submits a server.json document to the registry's publish endpoint
+func (c *MCPRegistryClient) PublishServer(serverJSON []byte) error {
+	publishURL := c.registryURL + "/publish"
+	mcpRegistryPublishLog.Printf("Publishing MCP server to %s", publishURL)
+
+	if c.authToken == "" {
+		return fmt.Errorf("publishing to %s requires a registry token (set token-env for this registry)", c.registryURL)
+	}
+
+	req, err := c.createRegistryRequestWithBody("POST", publishURL, bytes.NewReader(serverJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create publish request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish to MCP registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return registryStatusError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+// PublishMCPServer generates server.json for a workflow's MCP server and writes it to
+// outputPath (stdout when empty), optionally submitting it to a configured registry
+func PublishMCPServer(workflowFile, serverName, outputPath, registry string, opts MCPPublishOptions, verbose bool) error {
+	serverJSON, err := GenerateMCPServerJSON(workflowFile, serverName, opts)
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" && registry == "" {
+		fmt.Print(string(serverJSON))
+		return nil
+	}
+
+	if outputPath != "" {
+		if err := os.WriteFile(outputPath, serverJSON, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outputPath, err)
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Wrote "+outputPath))
+	}
+
+	if registry != "" {
+		client, err := resolveMCPRegistryClient(registry, false)
+		if err != nil {
+			return err
+		}
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Publishing to "+client.registryURL))
+		}
+		if err := client.PublishServer(serverJSON); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Published MCP server '%s' to %s", serverName, client.registryURL)))
+	}
+
+	return nil
+}
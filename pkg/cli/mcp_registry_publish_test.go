@@ -0,0 +1,167 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRegistryServerDetail(t *testing.T) {
+	opts := MCPPublishOptions{Name: "io.github.acme/search", Version: "1.0.0"}
+
+	t.Run("container server becomes oci package", func(t *testing.T) {
+		config := parser.MCPServerConfig{
+			Name: "search",
+			BaseMCPServerConfig: types.BaseMCPServerConfig{
+				Type:           "stdio",
+				Container:      "ghcr.io/acme/search-mcp",
+				Version:        "v2",
+				EntrypointArgs: []string{"--stdio"},
+				Env: map[string]string{
+					"API_KEY":  "${{ secrets.SEARCH_API_KEY }}",
+					"LOG":      "debug",
+					"BASE_URL": "${{ vars.SEARCH_URL }}",
+				},
+			},
+		}
+
+		detail, err := buildRegistryServerDetail(config, opts)
+		require.NoError(t, err)
+		require.Len(t, detail.Packages, 1)
+		pkg := detail.Packages[0]
+		assert.Equal(t, "oci", pkg.RegistryType)
+		assert.Equal(t, "ghcr.io/acme/search-mcp:v2", pkg.Identifier)
+		assert.Equal(t, "stdio", pkg.Transport.Type)
+		require.Len(t, pkg.PackageArguments, 1)
+		assert.Equal(t, "--stdio", pkg.PackageArguments[0].Value)
+
+		require.Len(t, pkg.EnvironmentVariables, 3)
+		assert.Equal(t, EnvironmentVariable{Name: "API_KEY", IsRequired: true, IsSecret: true}, pkg.EnvironmentVariables[0])
+		assert.Equal(t, EnvironmentVariable{Name: "BASE_URL", IsRequired: true}, pkg.EnvironmentVariables[1])
+		assert.Equal(t, EnvironmentVariable{Name: "LOG", Default: "debug"}, pkg.EnvironmentVariables[2])
+	})
+
+	t.Run("npx command becomes npm package", func(t *testing.T) {
+		config := parser.MCPServerConfig{
+			Name: "notion",
+			BaseMCPServerConfig: types.BaseMCPServerConfig{
+				Type:    "stdio",
+				Command: "npx",
+				Args:    []string{"-y", "@notionhq/notion-mcp-server@1.8.0", "--read-only"},
+			},
+		}
+
+		detail, err := buildRegistryServerDetail(config, opts)
+		require.NoError(t, err)
+		pkg := detail.Packages[0]
+		assert.Equal(t, "npm", pkg.RegistryType)
+		assert.Equal(t, "@notionhq/notion-mcp-server", pkg.Identifier)
+		assert.Equal(t, "1.8.0", pkg.Version)
+		assert.Equal(t, "npx", pkg.RuntimeHint)
+		require.Len(t, pkg.PackageArguments, 1)
+		assert.Equal(t, "--read-only", pkg.PackageArguments[0].Value)
+	})
+
+	t.Run("http server becomes remote with secret header", func(t *testing.T) {
+		config := parser.MCPServerConfig{
+			Name: "remote",
+			BaseMCPServerConfig: types.BaseMCPServerConfig{
+				Type:    "http",
+				URL:     "https://mcp.example.com",
+				Headers: map[string]string{"Authorization": "Bearer ${{ secrets.TOKEN }}"},
+			},
+		}
+
+		detail, err := buildRegistryServerDetail(config, opts)
+		require.NoError(t, err)
+		require.Len(t, detail.Remotes, 1)
+		assert.Equal(t, "streamable-http", detail.Remotes[0].Type)
+		assert.Equal(t, "https://mcp.example.com", detail.Remotes[0].URL)
+		require.Len(t, detail.Remotes[0].Headers, 1)
+		assert.True(t, detail.Remotes[0].Headers[0].IsSecret, "secret-backed header should be marked secret")
+
+		data, err := json.Marshal(detail)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secrets.TOKEN", "secret expressions must not be published")
+	})
+
+	t.Run("unknown command is rejected", func(t *testing.T) {
+		config := parser.MCPServerConfig{
+			Name:                "local",
+			BaseMCPServerConfig: types.BaseMCPServerConfig{Type: "stdio", Command: "./server.sh"},
+		}
+		_, err := buildRegistryServerDetail(config, opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a known package runner")
+	})
+
+	t.Run("invalid name and missing version", func(t *testing.T) {
+		config := parser.MCPServerConfig{Name: "x", BaseMCPServerConfig: types.BaseMCPServerConfig{Type: "http", URL: "https://x"}}
+		_, err := buildRegistryServerDetail(config, MCPPublishOptions{Name: "not valid", Version: "1.0.0"})
+		assert.Error(t, err)
+		_, err = buildRegistryServerDetail(config, MCPPublishOptions{Name: "io.github.acme/x"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerateMCPServerJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowPath := filepath.Join(tmpDir, "search.md")
+	content := `---
+on: workflow_dispatch
+mcp-servers:
+  search:
+    container: ghcr.io/acme/search-mcp
+    env:
+      API_KEY: "${{ secrets.SEARCH_API_KEY }}"
+    allowed: ["*"]
+---
+# Search
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	data, err := GenerateMCPServerJSON(workflowPath, "search", MCPPublishOptions{Name: "io.github.acme/search", Version: "0.1.0"})
+	require.NoError(t, err)
+
+	var detail ServerDetail
+	require.NoError(t, json.Unmarshal(data, &detail))
+	assert.Equal(t, "io.github.acme/search", detail.Name)
+	assert.Equal(t, mcpServerJSONSchemaURL, detail.Schema)
+	require.Len(t, detail.Packages, 1)
+	assert.Equal(t, "ghcr.io/acme/search-mcp", detail.Packages[0].Identifier)
+
+	_, err = GenerateMCPServerJSON(workflowPath, "github", MCPPublishOptions{Name: "io.github.acme/github", Version: "0.1.0"})
+	require.Error(t, err, "built-in servers cannot be published")
+	assert.Contains(t, err.Error(), "mcp-servers")
+}
+
+func TestMCPRegistryClient_PublishServer(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/publish", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer publish-token", r.Header.Get("Authorization"))
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewMCPRegistryClientWithOptions(server.URL, MCPRegistryClientOptions{AuthToken: "publish-token"})
+	require.NoError(t, client.PublishServer([]byte(`{"name":"io.github.acme/search"}`)))
+	assert.JSONEq(t, `{"name":"io.github.acme/search"}`, string(received))
+
+	err := NewMCPRegistryClient(server.URL).PublishServer([]byte(`{}`))
+	require.Error(t, err, "publishing without a token should fail before contacting the registry")
+	assert.Contains(t, err.Error(), "token")
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var mcpSchemaDriftLog = logger.New("cli:mcp_schema_drift")
+
+// mcpSnapshotsDir is the repository-relative directory holding committed MCP tool snapshots
+const mcpSnapshotsDir = ".github/aw/mcp-snapshots"
+
+// MCPToolSnapshot records the contract of a single MCP tool
+type MCPToolSnapshot struct {
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema,omitempty"`
+}
+
+// MCPServerSnapshot records the tools exposed by one MCP server
+type MCPServerSnapshot struct {
+	Type  string                     `json:"type"`
+	Tools map[string]MCPToolSnapshot `json:"tools"`
+}
+
+// MCPWorkflowSnapshot is the committed snapshot of every MCP server used by a workflow.
+// It intentionally contains no timestamps so that unchanged servers produce no diff.
+type MCPWorkflowSnapshot struct {
+	Workflow string                       `json:"workflow"`
+	Servers  map[string]MCPServerSnapshot `json:"servers"`
+}
+
+// MCPDriftSeverity classifies a schema drift finding
+type MCPDriftSeverity string
+
+const (
+	// MCPDriftBreaking marks changes that can break a workflow at runtime
+	MCPDriftBreaking MCPDriftSeverity = "breaking"
+	// MCPDriftCompatible marks changes that existing callers tolerate
+	MCPDriftCompatible MCPDriftSeverity = "compatible"
+)
+
+// MCPDriftFinding describes one difference between a snapshot and the live server
+type MCPDriftFinding struct {
+	Server   string           `json:"server"`
+	Tool     string           `json:"tool,omitempty"`
+	Severity MCPDriftSeverity `json:"severity"`
+	Message  string           `json:"message"`
+}
+
+// mcpSnapshotPath returns the snapshot file path for a workflow ID
+func mcpSnapshotPath(workflowID string) string {
+	return filepath.Join(mcpSnapshotsDir, workflowID+".json")
+}
+
+// snapshotFromServerInfo converts the inspection result of a server into a snapshot
+func snapshotFromServerInfo(info *parser.MCPServerInfo) (MCPServerSnapshot, error) {
+	snapshot := MCPServerSnapshot{
+		Type:  info.Config.Type,
+		Tools: make(map[string]MCPToolSnapshot, len(info.Tools)),
+	}
+	for _, tool := range info.Tools {
+		if tool == nil {
+			continue
+		}
+		schema, err := normalizeJSONSchema(tool.InputSchema)
+		if err != nil {
+			return MCPServerSnapshot{}, fmt.Errorf("tool '%s' has an invalid input schema: %w", tool.Name, err)
+		}
+		snapshot.Tools[tool.Name] = MCPToolSnapshot{
+			Description: tool.Description,
+			InputSchema: schema,
+		}
+	}
+	return snapshot, nil
+}
+
+// normalizeJSONSchema round-trips a schema through JSON so that it can be compared structurally
+func normalizeJSONSchema(schema any) (map[string]any, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// loadMCPWorkflowSnapshot reads a committed snapshot; it returns nil when none exists
+func loadMCPWorkflowSnapshot(path string) (*MCPWorkflowSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read MCP snapshot %s: %w", path, err)
+	}
+	var snapshot MCPWorkflowSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// saveMCPWorkflowSnapshot writes a snapshot with stable key ordering
+func saveMCPWorkflowSnapshot(path string, snapshot *MCPWorkflowSnapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write MCP snapshot %s: %w", path, err)
+	}
+	mcpSchemaDriftLog.Printf("Wrote MCP snapshot: %s (%d servers)", path, len(snapshot.Servers))
+	return nil
+}
+
+// isWildcardAllowed reports whether an allowed list permits every tool
+func isWildcardAllowed(allowed []string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, "*")
+}
+
+// compareMCPServerSnapshots reports drift between a recorded and a live server.
+// Only tools the workflow may call (the allowed list, or every recorded tool for
+// wildcards) can produce breaking findings; other differences are informational.
+func compareMCPServerSnapshots(server string, previous *MCPServerSnapshot, current MCPServerSnapshot, allowed []string) []MCPDriftFinding {
+	var findings []MCPDriftFinding
+	add := func(tool string, severity MCPDriftSeverity, format string, args ...any) {
+		findings = append(findings, MCPDriftFinding{Server: server, Tool: tool, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Allowed tools must always exist on the live server, snapshot or not
+	if !isWildcardAllowed(allowed) {
+		for _, tool := range allowed {
+			if _, ok := current.Tools[tool]; ok {
+				continue
+			}
+			if previous != nil {
+				if _, wasPresent := previous.Tools[tool]; wasPresent {
+					add(tool, MCPDriftBreaking, "allowed tool was removed from the server")
+					continue
+				}
+			}
+			add(tool, MCPDriftBreaking, "allowed tool is not provided by the server")
+		}
+	}
+
+	if previous == nil {
+		return findings
+	}
+
+	relevant := allowed
+	if isWildcardAllowed(allowed) {
+		relevant = slices.Sorted(maps.Keys(previous.Tools))
+		for _, tool := range relevant {
+			if _, ok := current.Tools[tool]; !ok {
+				add(tool, MCPDriftBreaking, "tool was removed from the server")
+			}
+		}
+	}
+
+	for _, tool := range relevant {
+		before, hadBefore := previous.Tools[tool]
+		after, hasAfter := current.Tools[tool]
+		if !hadBefore || !hasAfter {
+			continue
+		}
+		breaking, compatible := compareToolSchemas(before.InputSchema, after.InputSchema)
+		for _, msg := range breaking {
+			add(tool, MCPDriftBreaking, "%s", msg)
+		}
+		for _, msg := range compatible {
+			add(tool, MCPDriftCompatible, "%s", msg)
+		}
+		if before.Description != after.Description {
+			add(tool, MCPDriftCompatible, "description changed")
+		}
+	}
+
+	for _, tool := range slices.Sorted(maps.Keys(current.Tools)) {
+		if _, ok := previous.Tools[tool]; !ok {
+			add(tool, MCPDriftCompatible, "new tool available")
+		}
+	}
+
+	return findings
+}
+
+// compareToolSchemas compares two input schemas and splits the differences into
+// changes that break existing callers and changes that existing callers tolerate
+func compareToolSchemas(previous, current map[string]any) (breaking []string, compatible []string) {
+	prevProps := schemaProperties(previous)
+	curProps := schemaProperties(current)
+	prevRequired := schemaRequired(previous)
+	curRequired := schemaRequired(current)
+
+	for _, name := range slices.Sorted(maps.Keys(prevProps)) {
+		curProp, ok := curProps[name]
+		if !ok {
+			breaking = append(breaking, fmt.Sprintf("parameter '%s' was removed", name))
+			continue
+		}
+		prevProp, _ := prevProps[name].(map[string]any)
+		curPropMap, _ := curProp.(map[string]any)
+		if !reflect.DeepEqual(prevProp["type"], curPropMap["type"]) {
+			breaking = append(breaking, fmt.Sprintf("parameter '%s' changed type from %v to %v", name, prevProp["type"], curPropMap["type"]))
+		}
+		if removed := removedEnumValues(prevProp["enum"], curPropMap["enum"]); len(removed) > 0 {
+			breaking = append(breaking, fmt.Sprintf("parameter '%s' no longer accepts %v", name, removed))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(curProps)) {
+		if _, ok := prevProps[name]; ok {
+			continue
+		}
+		if curRequired[name] {
+			breaking = append(breaking, fmt.Sprintf("new required parameter '%s'", name))
+		} else {
+			compatible = append(compatible, fmt.Sprintf("new optional parameter '%s'", name))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(curRequired)) {
+		if _, existed := prevProps[name]; existed && !prevRequired[name] {
+			breaking = append(breaking, fmt.Sprintf("parameter '%s' is now required", name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(prevRequired)) {
+		if _, exists := curProps[name]; exists && !curRequired[name] {
+			compatible = append(compatible, fmt.Sprintf("parameter '%s' is now optional", name))
+		}
+	}
+
+	return breaking, compatible
+}
+
+func schemaProperties(schema map[string]any) map[string]any {
+	props, _ := schema["properties"].(map[string]any)
+	if props == nil {
+		return map[string]any{}
+	}
+	return props
+}
+
+func schemaRequired(schema map[string]any) map[string]bool {
+	required := make(map[string]bool)
+	list, _ := schema["required"].([]any)
+	for _, item := range list {
+		if name, ok := item.(string); ok {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// removedEnumValues returns values present in the previous enum but absent from the current one.
+// Dropping the enum entirely widens the accepted values and is not reported.
+func removedEnumValues(previous, current any) []any {
+	prevList, _ := previous.([]any)
+	curList, ok := current.([]any)
+	if len(prevList) == 0 || !ok {
+		return nil
+	}
+	var removed []any
+	for _, value := range prevList {
+		if !slices.ContainsFunc(curList, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+			removed = append(removed, value)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,164 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaWith(props map[string]any, required ...string) map[string]any {
+	req := make([]any, 0, len(required))
+	for _, r := range required {
+		req = append(req, r)
+	}
+	return map[string]any{"type": "object", "properties": props, "required": req}
+}
+
+func TestCompareToolSchemas(t *testing.T) {
+	base := schemaWith(map[string]any{
+		"query": map[string]any{"type": "string"},
+		"mode":  map[string]any{"type": "string", "enum": []any{"fast", "deep"}},
+	}, "query")
+
+	tests := []struct {
+		name           string
+		current        map[string]any
+		wantBreaking   []string
+		wantCompatible []string
+	}{
+		{
+			name:    "identical",
+			current: base,
+		},
+		{
+			name: "parameter removed",
+			current: schemaWith(map[string]any{
+				"query": map[string]any{"type": "string"},
+			}, "query"),
+			wantBreaking: []string{"parameter 'mode' was removed"},
+		},
+		{
+			name: "type changed and enum narrowed",
+			current: schemaWith(map[string]any{
+				"query": map[string]any{"type": "number"},
+				"mode":  map[string]any{"type": "string", "enum": []any{"fast"}},
+			}, "query"),
+			wantBreaking: []string{
+				"parameter 'query' changed type from string to number",
+				"parameter 'mode' no longer accepts [deep]",
+			},
+		},
+		{
+			name: "new required and new optional",
+			current: schemaWith(map[string]any{
+				"query": map[string]any{"type": "string"},
+				"mode":  map[string]any{"type": "string", "enum": []any{"fast", "deep"}},
+				"limit": map[string]any{"type": "integer"},
+				"repo":  map[string]any{"type": "string"},
+			}, "query", "repo"),
+			wantBreaking:   []string{"new required parameter 'repo'"},
+			wantCompatible: []string{"new optional parameter 'limit'"},
+		},
+		{
+			name: "existing parameter becomes required, required becomes optional",
+			current: schemaWith(map[string]any{
+				"query": map[string]any{"type": "string"},
+				"mode":  map[string]any{"type": "string", "enum": []any{"fast", "deep"}},
+			}, "mode"),
+			wantBreaking:   []string{"parameter 'mode' is now required"},
+			wantCompatible: []string{"parameter 'query' is now optional"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaking, compatible := compareToolSchemas(base, tt.current)
+			assert.ElementsMatch(t, tt.wantBreaking, breaking, "breaking changes")
+			assert.ElementsMatch(t, tt.wantCompatible, compatible, "compatible changes")
+		})
+	}
+}
+
+func TestCompareMCPServerSnapshots(t *testing.T) {
+	previous := MCPServerSnapshot{Type: "stdio", Tools: map[string]MCPToolSnapshot{
+		"search": {InputSchema: schemaWith(map[string]any{"q": map[string]any{"type": "string"}}, "q")},
+		"fetch":  {InputSchema: schemaWith(map[string]any{"url": map[string]any{"type": "string"}}, "url")},
+	}}
+	renamed := MCPServerSnapshot{Type: "stdio", Tools: map[string]MCPToolSnapshot{
+		"search_v2": previous.Tools["search"],
+		"fetch":     previous.Tools["fetch"],
+	}}
+
+	t.Run("renamed allowed tool is breaking", func(t *testing.T) {
+		findings := compareMCPServerSnapshots("srv", &previous, renamed, []string{"search"})
+		require.NotEmpty(t, findings)
+		assert.Equal(t, MCPDriftBreaking, findings[0].Severity)
+		assert.Equal(t, "search", findings[0].Tool)
+		assert.Contains(t, findings[0].Message, "removed")
+	})
+
+	t.Run("removal of a tool that is not allowed is not breaking", func(t *testing.T) {
+		findings := compareMCPServerSnapshots("srv", &previous, renamed, []string{"fetch"})
+		for _, f := range findings {
+			assert.NotEqual(t, MCPDriftBreaking, f.Severity, "unexpected breaking finding: %+v", f)
+		}
+	})
+
+	t.Run("wildcard treats every recorded tool as used", func(t *testing.T) {
+		findings := compareMCPServerSnapshots("srv", &previous, renamed, []string{"*"})
+		var breakingTools []string
+		for _, f := range findings {
+			if f.Severity == MCPDriftBreaking {
+				breakingTools = append(breakingTools, f.Tool)
+			}
+		}
+		assert.Equal(t, []string{"search"}, breakingTools)
+	})
+
+	t.Run("no snapshot still validates allowed list", func(t *testing.T) {
+		findings := compareMCPServerSnapshots("srv", nil, renamed, []string{"search", "fetch"})
+		require.Len(t, findings, 1)
+		assert.Equal(t, "allowed tool is not provided by the server", findings[0].Message)
+	})
+}
+
+func TestSnapshotFromServerInfo(t *testing.T) {
+	info := &parser.MCPServerInfo{
+		Config: parser.MCPServerConfig{Name: "srv"},
+		Tools: []*mcp.Tool{
+			{Name: "search", Description: "Search things", InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"q": map[string]any{"type": "string"}},
+			}},
+		},
+	}
+
+	snapshot, err := snapshotFromServerInfo(info)
+	require.NoError(t, err)
+	require.Contains(t, snapshot.Tools, "search")
+	assert.Equal(t, "Search things", snapshot.Tools["search"].Description)
+	assert.Equal(t, "object", snapshot.Tools["search"].InputSchema["type"])
+}
+
+func TestMCPWorkflowSnapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "wf.json")
+
+	missing, err := loadMCPWorkflowSnapshot(path)
+	require.NoError(t, err)
+	assert.Nil(t, missing, "missing snapshot should load as nil")
+
+	snapshot := &MCPWorkflowSnapshot{Workflow: "wf", Servers: map[string]MCPServerSnapshot{
+		"srv": {Type: "http", Tools: map[string]MCPToolSnapshot{"a": {Description: "A"}}},
+	}}
+	require.NoError(t, saveMCPWorkflowSnapshot(path, snapshot))
+
+	loaded, err := loadMCPWorkflowSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+}
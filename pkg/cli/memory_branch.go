@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var memoryBranchLog = logger.New("cli:memory_branch")
+
+// emptyTreeSHA is the hash of the empty git tree, used to diff the first commit of a branch
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// MemoryFile is a file stored on a memory branch
+type MemoryFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// MemoryBranchInfo describes the content and history of a memory branch
+type MemoryBranchInfo struct {
+	MemoryID    string       `json:"memory_id"`
+	Branch      string       `json:"branch"`
+	Remote      string       `json:"remote"`
+	Commit      string       `json:"commit"`
+	Commits     int          `json:"commits"`
+	LastUpdated string       `json:"last_updated"`
+	TotalSize   int64        `json:"total_size"`
+	Files       []MemoryFile `json:"files"`
+}
+
+// runMemoryGit runs a git command in dir (the current directory when empty) and returns its output
+func runMemoryGit(dir string, args ...string) (string, error) {
+	memoryBranchLog.Printf("Running git %s", strings.Join(args, " "))
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return string(output), nil
+}
+
+// memoryRemote returns the git remote that holds a memory branch: the target repository of the
+// memory when it has one, otherwise the given remote of the current repository
+func memoryRemote(memory *workflow.RepoMemoryEntry, remote string) string {
+	if memory.TargetRepo != "" && !strings.Contains(memory.TargetRepo, "${{") {
+		return fmt.Sprintf("%s/%s.git", getGitHubHostForRepo(memory.TargetRepo), memory.TargetRepo)
+	}
+	return remote
+}
+
+// fetchMemoryBranch fetches a memory branch without creating a local branch and returns its commit
+func fetchMemoryBranch(dir, remote, branch string) (string, error) {
+	output, err := runMemoryGit(dir, "ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(output) == "" {
+		return "", fmt.Errorf("memory branch '%s' not found on %s: the workflow has not stored any memory yet", branch, remote)
+	}
+	if _, err := runMemoryGit(dir, "fetch", "--quiet", "--no-tags", remote, "refs/heads/"+branch); err != nil {
+		return "", err
+	}
+	sha, err := runMemoryGit(dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// readMemoryBranch lists the files and counts the commits of a fetched memory branch
+func readMemoryBranch(dir, commit string) (*MemoryBranchInfo, error) {
+	info := &MemoryBranchInfo{Commit: commit, Files: []MemoryFile{}}
+
+	count, err := runMemoryGit(dir, "rev-list", "--count", commit)
+	if err != nil {
+		return nil, err
+	}
+	if info.Commits, err = strconv.Atoi(strings.TrimSpace(count)); err != nil {
+		return nil, fmt.Errorf("unexpected commit count %q: %w", strings.TrimSpace(count), err)
+	}
+
+	lastUpdated, err := runMemoryGit(dir, "log", "-1", "--format=%cI", commit)
+	if err != nil {
+		return nil, err
+	}
+	info.LastUpdated = strings.TrimSpace(lastUpdated)
+
+	// Each line of ls-tree -l is "<mode> <type> <object> <size>\t<path>"
+	tree, err := runMemoryGit(dir, "ls-tree", "-r", "-l", commit)
+	if err != nil {
+		return nil, err
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(tree), "\n") {
+		meta, filePath, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(meta)
+		size, _ := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		info.Files = append(info.Files, MemoryFile{Path: filePath, Size: size})
+		info.TotalSize += size
+	}
+	return info, nil
+}
+
+// readMemoryFile returns the content of a file on a fetched memory branch
+func readMemoryFile(dir, commit, filePath string) (string, error) {
+	return runMemoryGit(dir, "show", commit+":"+filePath)
+}
+
+// diffMemoryBranch returns the changes made by the last commits of a fetched memory branch.
+// The whole branch content is shown when commits covers the entire history.
+func diffMemoryBranch(dir, commit string, commits int, stat bool) (string, error) {
+	if commits < 1 {
+		return "", errors.New("--commits must be at least 1")
+	}
+	base := fmt.Sprintf("%s~%d", commit, commits)
+	if _, err := runMemoryGit(dir, "rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		base = emptyTreeSHA
+	}
+	args := []string{"diff"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	return runMemoryGit(dir, append(args, base, commit)...)
+}
+
+// deleteMemoryBranch deletes a memory branch from its remote
+func deleteMemoryBranch(dir, remote, branch string) error {
+	_, err := runMemoryGit(dir, "push", "--quiet", remote, "--delete", "refs/heads/"+branch)
+	return err
+}
@@ -0,0 +1,661 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/tty"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var memoryCacheLog = logger.New("cli:memory_cache")
+
+// CacheMemoryOptions holds the options shared by the memory cache subcommands
+type CacheMemoryOptions struct {
+	MemoryID string // Cache to operate on (the only or the "default" cache when empty)
+	Repo     string // Repository override ([HOST/]owner/repo)
+	Verbose  bool
+}
+
+// CacheMemorySnapshot is an Actions cache entry holding the cache-memory saved by a workflow run
+type CacheMemorySnapshot struct {
+	ID             int64  `json:"id"`
+	Key            string `json:"key"`
+	RunID          int64  `json:"run_id,omitempty"`
+	Ref            string `json:"ref"`
+	SizeInBytes    int64  `json:"size_in_bytes"`
+	CreatedAt      string `json:"created_at"`
+	LastAccessedAt string `json:"last_accessed_at"`
+}
+
+// workflowCacheMemory is a cache-memory of a workflow together with its resolved key prefix
+type workflowCacheMemory struct {
+	Cache      workflow.CacheMemoryEntry
+	WorkflowID string
+	KeyPrefix  string // Key without the run ID; shared by the snapshots of all runs
+	ExactKey   bool   // False when the key contains expressions that cannot be resolved locally
+}
+
+// memoryCacheCanPrompt reports whether deletions can be confirmed interactively. It is a
+// variable so that tests can simulate a terminal.
+var memoryCacheCanPrompt = func() bool {
+	return tty.IsStderrTerminal() && !IsRunningInCI()
+}
+
+// runMemoryCacheGH runs a gh command for the memory cache subcommands and returns its output.
+// It is a variable so that tests can replace the GitHub CLI.
+var runMemoryCacheGH = func(args ...string) ([]byte, error) {
+	memoryCacheLog.Printf("Running gh %s", strings.Join(args, " "))
+	cmd := workflow.ExecGH(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("gh %s failed: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("gh %s failed: %w", args[0], err)
+	}
+	return output, nil
+}
+
+func newMemoryCacheSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect, diff, purge and roll back the cache-memory of a workflow",
+		Long: `Inspect the cache-memory of a workflow across runs.
+
+Each run of a workflow that uses the cache-memory tool saves its memory in an Actions cache
+entry whose key ends with the run ID. These commands resolve the keys exactly as the compiled
+workflow does, list the entries, download the memory a run uploaded as an artifact and purge
+entries. The Actions cache API cannot create entries, so 'rollback' returns the memory to the
+snapshot of an earlier run by deleting the newer entries that the next run would restore.
+
+Available subcommands:
+  • list     - List the cache entries of a workflow's cache-memory
+  • show     - Download and display the cache-memory of a run
+  • diff     - Compare the cache-memory of two runs
+  • purge    - Delete cache entries so the next run starts from scratch
+  • rollback - Delete the entries newer than a run so the next run restores its cache-memory
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache list daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache show daily-report --run 1234567890
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache diff daily-report 1234567890 1234567999
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache purge daily-report --all
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache rollback daily-report --run 1234567890`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newMemoryCacheListSubcommand())
+	cmd.AddCommand(newMemoryCacheShowSubcommand())
+	cmd.AddCommand(newMemoryCacheDiffSubcommand())
+	cmd.AddCommand(newMemoryCachePurgeSubcommand())
+	cmd.AddCommand(newMemoryCacheRollbackSubcommand())
+
+	return cmd
+}
+
+// addCacheMemoryFlags adds the flags shared by the memory cache subcommands
+func addCacheMemoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("memory", "m", "", "Cache ID (default: the only cache of the workflow, or 'default')")
+	addRepoFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+}
+
+// cacheMemoryOptionsFromFlags reads the shared memory cache flags of a command
+func cacheMemoryOptionsFromFlags(cmd *cobra.Command) CacheMemoryOptions {
+	memoryID, _ := cmd.Flags().GetString("memory")
+	repo, _ := cmd.Flags().GetString("repo")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return CacheMemoryOptions{MemoryID: memoryID, Repo: repo, Verbose: verbose}
+}
+
+func newMemoryCacheListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <workflow>",
+		Short: "List the cache entries of a workflow's cache-memory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunMemoryCacheList(args[0], jsonOutput, cacheMemoryOptionsFromFlags(cmd))
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+func newMemoryCacheShowSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <workflow>",
+		Short: "Download and display the cache-memory of a run",
+		Long: `Download the cache-memory a run uploaded as an artifact and list its files, or print one
+of them with --file. Runs upload their cache-memory as an artifact when threat detection is
+enabled. Use --output to keep the downloaded files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runID, _ := cmd.Flags().GetInt64("run")
+			file, _ := cmd.Flags().GetString("file")
+			output, _ := cmd.Flags().GetString("output")
+			return RunMemoryCacheShow(args[0], runID, file, output, cacheMemoryOptionsFromFlags(cmd))
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().Int64("run", 0, "Workflow run ID")
+	cmd.Flags().String("file", "", "Print the content of this file of the memory")
+	addOutputFlag(cmd, "")
+	_ = cmd.MarkFlagRequired("run")
+	return cmd
+}
+
+func newMemoryCacheDiffSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <workflow> <run-id> <run-id>",
+		Short: "Compare the cache-memory of two runs",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid run ID '%s'", args[1])
+			}
+			to, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid run ID '%s'", args[2])
+			}
+			stat, _ := cmd.Flags().GetBool("stat")
+			return RunMemoryCacheDiff(args[0], from, to, stat, cacheMemoryOptionsFromFlags(cmd))
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().Bool("stat", false, "Show a summary of changed files instead of the full diff")
+	return cmd
+}
+
+func newMemoryCachePurgeSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge <workflow>",
+		Short: "Delete cache entries of a workflow's cache-memory",
+		Long: `Delete the cache entry saved by one run (--run), or all cache entries of the
+cache-memory (--all). The next run restores the newest remaining entry, or starts with an
+empty memory when none is left.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runID, _ := cmd.Flags().GetInt64("run")
+			all, _ := cmd.Flags().GetBool("all")
+			yes, _ := cmd.Flags().GetBool("yes")
+			return RunMemoryCachePurge(args[0], runID, all, yes, cacheMemoryOptionsFromFlags(cmd))
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().Int64("run", 0, "Delete the entry saved by this workflow run")
+	cmd.Flags().Bool("all", false, "Delete all entries of the cache-memory")
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	cmd.MarkFlagsMutuallyExclusive("run", "all")
+	cmd.MarkFlagsOneRequired("run", "all")
+	return cmd
+}
+
+func newMemoryCacheRollbackSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <workflow>",
+		Short: "Delete the cache entries newer than a run so the next run restores its cache-memory",
+		Long: `Roll the cache-memory of a workflow back to the snapshot saved by an earlier run.
+Runs restore the newest cache entry of their cache-memory, so every entry created after the
+given run is permanently deleted. The deletion is confirmed interactively; pass --yes when
+running without a terminal.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runID, _ := cmd.Flags().GetInt64("run")
+			yes, _ := cmd.Flags().GetBool("yes")
+			return RunMemoryCacheRollback(args[0], runID, yes, cacheMemoryOptionsFromFlags(cmd))
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().Int64("run", 0, "Workflow run whose snapshot the next run should restore")
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	_ = cmd.MarkFlagRequired("run")
+	return cmd
+}
+
+// RunMemoryCacheList lists the cache entries of a workflow's cache-memory
+func RunMemoryCacheList(workflowIdOrName string, jsonOutput bool, opts CacheMemoryOptions) error {
+	memory, err := resolveWorkflowCacheMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	snapshots, err := listCacheMemorySnapshots(opts.Repo, memory)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(snapshots, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal cache entries: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No cache entries found for cache-memory '%s' (key prefix: %s)", memory.Cache.ID, memory.KeyPrefix)))
+		return nil
+	}
+	rows := make([][]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		runID := "-"
+		if s.RunID != 0 {
+			runID = strconv.FormatInt(s.RunID, 10)
+		}
+		rows = append(rows, []string{runID, s.Key, s.Ref, console.FormatFileSize(s.SizeInBytes), s.CreatedAt, s.LastAccessedAt})
+	}
+	fmt.Print(console.RenderTable(console.TableConfig{
+		Title:   fmt.Sprintf("Cache-memory '%s' of %s", memory.Cache.ID, memory.WorkflowID),
+		Headers: []string{"Run", "Key", "Ref", "Size", "Created", "Last Accessed"},
+		Rows:    rows,
+	}))
+	return nil
+}
+
+// RunMemoryCacheShow downloads the cache-memory of a run and lists its files or prints one of them
+func RunMemoryCacheShow(workflowIdOrName string, runID int64, file, outputDir string, opts CacheMemoryOptions) error {
+	memory, err := resolveWorkflowCacheMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+
+	dir := outputDir
+	if dir == "" {
+		tmpDir, err := os.MkdirTemp("", "gh-aw-cache-memory-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		dir = tmpDir
+	}
+	if err := downloadCacheMemorySnapshot(opts.Repo, runID, memory.Cache, dir); err != nil {
+		return err
+	}
+
+	if file != "" {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if rel, err := filepath.Rel(dir, path); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("invalid file path '%s'", file)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("file '%s' not found in the cache-memory of run %d", file, runID)
+		}
+		defer f.Close()
+		_, err = io.Copy(os.Stdout, f)
+		return err
+	}
+
+	files, err := listSnapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+	var total int64
+	rows := make([][]string, 0, len(files))
+	for _, f := range files {
+		rows = append(rows, []string{f.Path, console.FormatFileSize(f.Size)})
+		total += f.Size
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Cache-memory '%s' of run %d: %d file(s), %s", memory.Cache.ID, runID, len(files), console.FormatFileSize(total))))
+	if outputDir != "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Downloaded to "+outputDir))
+	}
+	if len(rows) > 0 {
+		fmt.Print(console.RenderTable(console.TableConfig{Headers: []string{"File", "Size"}, Rows: rows}))
+	}
+	return nil
+}
+
+// RunMemoryCacheDiff prints the differences between the cache-memory of two runs
+func RunMemoryCacheDiff(workflowIdOrName string, fromRunID, toRunID int64, stat bool, opts CacheMemoryOptions) error {
+	memory, err := resolveWorkflowCacheMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-cache-memory-diff-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	from := strconv.FormatInt(fromRunID, 10)
+	to := strconv.FormatInt(toRunID, 10)
+	for _, runID := range []int64{fromRunID, toRunID} {
+		if err := downloadCacheMemorySnapshot(opts.Repo, runID, memory.Cache, filepath.Join(tmpDir, strconv.FormatInt(runID, 10))); err != nil {
+			return err
+		}
+	}
+
+	diff, err := diffSnapshotDirs(tmpDir, from, to, stat)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No changes in cache-memory '%s' between runs %s and %s", memory.Cache.ID, from, to)))
+		return nil
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+// RunMemoryCachePurge deletes the cache entry of a run, or all cache entries of a cache-memory
+func RunMemoryCachePurge(workflowIdOrName string, runID int64, all, yes bool, opts CacheMemoryOptions) error {
+	memory, err := resolveWorkflowCacheMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	snapshots, err := listCacheMemorySnapshots(opts.Repo, memory)
+	if err != nil {
+		return err
+	}
+
+	var targets []CacheMemorySnapshot
+	if all {
+		targets = snapshots
+	} else {
+		target, err := findRunSnapshot(snapshots, memory, runID)
+		if err != nil {
+			return err
+		}
+		targets = []CacheMemorySnapshot{*target}
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No cache entries found for cache-memory '%s'", memory.Cache.ID)))
+		return nil
+	}
+
+	return deleteCacheMemorySnapshots(opts.Repo, targets, yes,
+		fmt.Sprintf("Delete %d cache entr%s of cache-memory '%s'?", len(targets), pluralSuffix(len(targets), "y", "ies"), memory.Cache.ID))
+}
+
+// RunMemoryCacheRollback rolls a cache-memory back to the snapshot of a run by deleting the newer entries
+func RunMemoryCacheRollback(workflowIdOrName string, runID int64, yes bool, opts CacheMemoryOptions) error {
+	memory, err := resolveWorkflowCacheMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	snapshots, err := listCacheMemorySnapshots(opts.Repo, memory)
+	if err != nil {
+		return err
+	}
+	target, err := findRunSnapshot(snapshots, memory, runID)
+	if err != nil {
+		return err
+	}
+
+	newer := newerCacheMemorySnapshots(snapshots, *target)
+	if len(newer) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("The snapshot of run %d is already the newest entry of cache-memory '%s'", runID, memory.Cache.ID)))
+		return nil
+	}
+	return deleteCacheMemorySnapshots(opts.Repo, newer, yes,
+		fmt.Sprintf("Delete the %d cache entr%s created after run %d so the next run restores its snapshot?", len(newer), pluralSuffix(len(newer), "y", "ies"), runID))
+}
+
+// pluralSuffix returns singular when n is 1 and plural otherwise
+func pluralSuffix(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// resolveWorkflowCacheMemory reads the cache-memory configuration of a workflow, selects a cache
+// and resolves its key prefix the same way the compiled workflow does
+func resolveWorkflowCacheMemory(workflowIdOrName string, opts CacheMemoryOptions) (*workflowCacheMemory, error) {
+	workflowFile, err := resolveWorkflowFile(workflowIdOrName, opts.Verbose)
+	if err != nil {
+		return nil, err
+	}
+	memoryCacheLog.Printf("Reading cache-memory configuration from %s", workflowFile)
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(opts.Verbose))
+	data, err := compiler.ParseWorkflowFile(workflowFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	cache, err := selectCacheMemory(data.CacheMemoryConfig, opts.MemoryID, workflowIdOrName)
+	if err != nil {
+		return nil, err
+	}
+	prefix, exact := workflow.ResolveCacheMemoryKeyPrefix(*cache, data.WorkflowID)
+	memoryCacheLog.Printf("Resolved cache-memory key prefix: %s (exact=%v)", prefix, exact)
+	return &workflowCacheMemory{Cache: *cache, WorkflowID: data.WorkflowID, KeyPrefix: prefix, ExactKey: exact}, nil
+}
+
+// selectCacheMemory returns the cache with the given ID. Without an ID, the only cache of the
+// workflow is returned, or the one named "default".
+func selectCacheMemory(config *workflow.CacheMemoryConfig, memoryID, workflowName string) (*workflow.CacheMemoryEntry, error) {
+	if config == nil || len(config.Caches) == 0 {
+		return nil, fmt.Errorf("workflow '%s' does not use cache-memory", workflowName)
+	}
+	if memoryID == "" && len(config.Caches) == 1 {
+		return &config.Caches[0], nil
+	}
+
+	wanted := memoryID
+	if wanted == "" {
+		wanted = "default"
+	}
+	ids := make([]string, 0, len(config.Caches))
+	for i := range config.Caches {
+		if config.Caches[i].ID == wanted {
+			return &config.Caches[i], nil
+		}
+		ids = append(ids, config.Caches[i].ID)
+	}
+	if memoryID == "" {
+		return nil, fmt.Errorf("workflow '%s' has several caches, select one with --memory: %s", workflowName, strings.Join(ids, ", "))
+	}
+	return nil, fmt.Errorf("workflow '%s' has no cache '%s', available caches: %s", workflowName, memoryID, strings.Join(ids, ", "))
+}
+
+// splitRepoOverride splits a [HOST/]owner/repo override into its host and owner/repo parts
+func splitRepoOverride(repo string) (host, slug string) {
+	if parts := strings.Split(repo, "/"); len(parts) == 3 {
+		return parts[0], parts[1] + "/" + parts[2]
+	}
+	return "", repo
+}
+
+// cacheAPIArgs returns gh api arguments for an Actions cache endpoint of the repository
+func cacheAPIArgs(repo, endpoint string) []string {
+	host, slug := splitRepoOverride(repo)
+	if slug == "" {
+		slug = "{owner}/{repo}"
+	}
+	args := []string{"api", "repos/" + slug + "/actions/caches" + endpoint}
+	if host != "" {
+		args = append(args, "--hostname", host)
+	}
+	return args
+}
+
+// listCacheMemorySnapshots lists the cache entries of a cache-memory, newest first
+func listCacheMemorySnapshots(repo string, memory *workflowCacheMemory) ([]CacheMemorySnapshot, error) {
+	query := "?per_page=100&sort=created_at&direction=desc&key=" + url.QueryEscape(memory.KeyPrefix)
+	args := append(cacheAPIArgs(repo, query), "--paginate", "--jq", ".actions_caches[]")
+	output, err := runMemoryCacheGH(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+	return parseCacheMemorySnapshots(output, memory)
+}
+
+// parseCacheMemorySnapshots parses the cache entries printed one per line by gh api --jq and
+// extracts the run ID from the keys of the cache-memory
+func parseCacheMemorySnapshots(output []byte, memory *workflowCacheMemory) ([]CacheMemorySnapshot, error) {
+	snapshots := []CacheMemorySnapshot{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var snapshot CacheMemorySnapshot
+		if err := decoder.Decode(&snapshot); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse cache entries: %w", err)
+		}
+		if !strings.HasPrefix(snapshot.Key, memory.KeyPrefix) {
+			continue
+		}
+		if memory.ExactKey {
+			if runID, err := strconv.ParseInt(strings.TrimPrefix(snapshot.Key, memory.KeyPrefix), 10, 64); err == nil {
+				snapshot.RunID = runID
+			} else {
+				// Keys of other caches can share the prefix (the keys of a cache with ID "daily"
+				// start with "memory-daily-"), but the run ID does not follow it directly
+				continue
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt > snapshots[j].CreatedAt })
+	return snapshots, nil
+}
+
+// findRunSnapshot returns the cache entry saved by a run
+func findRunSnapshot(snapshots []CacheMemorySnapshot, memory *workflowCacheMemory, runID int64) (*CacheMemorySnapshot, error) {
+	if !memory.ExactKey {
+		return nil, fmt.Errorf("cannot match cache entries of cache-memory '%s' to runs: its key uses expressions that are only resolved in GitHub Actions (%s)", memory.Cache.ID, memory.Cache.Key)
+	}
+	for i := range snapshots {
+		if snapshots[i].RunID == runID {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no cache entry of cache-memory '%s' found for run %d (key: %s%d)", memory.Cache.ID, runID, memory.KeyPrefix, runID)
+}
+
+// newerCacheMemorySnapshots returns the cache entries created after the given entry
+func newerCacheMemorySnapshots(snapshots []CacheMemorySnapshot, target CacheMemorySnapshot) []CacheMemorySnapshot {
+	var newer []CacheMemorySnapshot
+	for _, s := range snapshots {
+		if s.ID != target.ID && s.CreatedAt > target.CreatedAt {
+			newer = append(newer, s)
+		}
+	}
+	return newer
+}
+
+// deleteCacheMemorySnapshots deletes cache entries after confirmation. Without a terminal
+// to confirm in, the deletion requires --yes.
+func deleteCacheMemorySnapshots(repo string, snapshots []CacheMemorySnapshot, yes bool, question string) error {
+	for _, s := range snapshots {
+		fmt.Fprintf(os.Stderr, "  %s (%s, created %s)\n", s.Key, console.FormatFileSize(s.SizeInBytes), s.CreatedAt)
+	}
+	if !yes && !memoryCacheCanPrompt() {
+		return fmt.Errorf("refusing to delete %d cache entr%s without confirmation; re-run with --yes", len(snapshots), pluralSuffix(len(snapshots), "y", "ies"))
+	}
+	if !yes {
+		confirmed, err := console.ConfirmAction(question, "Yes, delete", "No, cancel")
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Operation cancelled."))
+			return nil
+		}
+	}
+
+	for _, s := range snapshots {
+		args := append(cacheAPIArgs(repo, "/"+strconv.FormatInt(s.ID, 10)), "--method", "DELETE")
+		if _, err := runMemoryCacheGH(args...); err != nil {
+			return fmt.Errorf("failed to delete cache entry %s: %w", s.Key, err)
+		}
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Deleted %d cache entr%s", len(snapshots), pluralSuffix(len(snapshots), "y", "ies"))))
+	return nil
+}
+
+// cacheMemoryArtifactNames returns the names of the artifact a run uploads its cache-memory to.
+// A single default cache uses "cache-memory"; other caches are suffixed with their ID.
+func cacheMemoryArtifactNames(cache workflow.CacheMemoryEntry) []string {
+	if cache.ID == "default" {
+		return []string{"cache-memory", "cache-memory-default"}
+	}
+	return []string{"cache-memory-" + cache.ID}
+}
+
+// downloadCacheMemorySnapshot downloads the cache-memory artifact of a run into dir
+func downloadCacheMemorySnapshot(repo string, runID int64, cache workflow.CacheMemoryEntry, dir string) error {
+	var lastErr error
+	for _, name := range cacheMemoryArtifactNames(cache) {
+		args := []string{"run", "download", strconv.FormatInt(runID, 10), "--name", name, "--dir", dir}
+		if repo != "" {
+			args = append(args, "--repo", repo)
+		}
+		if _, lastErr = runMemoryCacheGH(args...); lastErr == nil {
+			return nil
+		}
+	}
+	return errors.New(console.FormatErrorWithSuggestions(
+		fmt.Sprintf("failed to download the cache-memory of run %d: %v", runID, lastErr),
+		[]string{
+			"Runs upload their cache-memory as an artifact only when threat detection is enabled",
+			"Artifacts expire after their retention period; set retention-days on the cache-memory to keep them longer",
+		},
+	))
+}
+
+// listSnapshotFiles lists the files of a downloaded cache-memory snapshot
+func listSnapshotFiles(dir string) ([]MemoryFile, error) {
+	files := []MemoryFile{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, MemoryFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+// diffSnapshotDirs compares two snapshot directories of baseDir with git diff --no-index
+func diffSnapshotDirs(baseDir, from, to string, stat bool) (string, error) {
+	args := []string{"diff", "--no-index", "--no-color"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	cmd := exec.Command("git", append(args, from, to)...)
+	cmd.Dir = baseDir
+	output, err := cmd.Output()
+	// git diff --no-index exits with 1 when the directories differ
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return string(output), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to compare snapshots: %w", err)
+	}
+	return string(output), nil
+}
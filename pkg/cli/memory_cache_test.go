@@ -0,0 +1,140 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cacheEntriesOutput = `{"id":3,"key":"memory-dailyreport-300","ref":"refs/heads/main","size_in_bytes":2048,"created_at":"2026-03-03T00:00:00Z","last_accessed_at":"2026-03-03T01:00:00Z"}
+{"id":1,"key":"memory-dailyreport-100","ref":"refs/heads/main","size_in_bytes":1024,"created_at":"2026-03-01T00:00:00Z","last_accessed_at":"2026-03-01T01:00:00Z"}
+{"id":9,"key":"memory-dailyreport-notes-5","ref":"refs/heads/main","size_in_bytes":10,"created_at":"2026-03-04T00:00:00Z","last_accessed_at":"2026-03-04T00:00:00Z"}
+{"id":2,"key":"memory-dailyreport-200","ref":"refs/heads/main","size_in_bytes":1536,"created_at":"2026-03-02T00:00:00Z","last_accessed_at":"2026-03-02T01:00:00Z"}
+`
+
+func TestParseCacheMemorySnapshots(t *testing.T) {
+	memory := &workflowCacheMemory{Cache: workflow.CacheMemoryEntry{ID: "default"}, KeyPrefix: "memory-dailyreport-", ExactKey: true}
+
+	snapshots, err := parseCacheMemorySnapshots([]byte(cacheEntriesOutput), memory)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3, "keys without a run ID after the prefix should be skipped")
+	assert.Equal(t, []int64{300, 200, 100}, []int64{snapshots[0].RunID, snapshots[1].RunID, snapshots[2].RunID}, "snapshots should be sorted newest first")
+
+	target, err := findRunSnapshot(snapshots, memory, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), target.ID)
+
+	newer := newerCacheMemorySnapshots(snapshots, *target)
+	assert.Len(t, newer, 2)
+
+	_, err = findRunSnapshot(snapshots, memory, 999)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory-dailyreport-999")
+
+	inexact := &workflowCacheMemory{Cache: workflow.CacheMemoryEntry{ID: "default", Key: "state-${{ github.ref_name }}-${{ github.run_id }}"}, KeyPrefix: "memory-dailyreport-"}
+	_, err = findRunSnapshot(snapshots, inexact, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only resolved in GitHub Actions")
+}
+
+func TestCacheAPIArgs(t *testing.T) {
+	assert.Equal(t, []string{"api", "repos/{owner}/{repo}/actions/caches/7"}, cacheAPIArgs("", "/7"))
+	assert.Equal(t, []string{"api", "repos/octo/app/actions/caches"}, cacheAPIArgs("octo/app", ""))
+	assert.Equal(t, []string{"api", "repos/octo/app/actions/caches", "--hostname", "ghe.example.com"}, cacheAPIArgs("ghe.example.com/octo/app", ""))
+}
+
+func TestSelectCacheMemory(t *testing.T) {
+	config := &workflow.CacheMemoryConfig{Caches: []workflow.CacheMemoryEntry{{ID: "default"}, {ID: "notes"}}}
+
+	cache, err := selectCacheMemory(config, "", "my-workflow")
+	require.NoError(t, err)
+	assert.Equal(t, "default", cache.ID)
+
+	cache, err = selectCacheMemory(config, "notes", "my-workflow")
+	require.NoError(t, err)
+	assert.Equal(t, "notes", cache.ID)
+
+	_, err = selectCacheMemory(nil, "", "my-workflow")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not use cache-memory")
+}
+
+func TestRunMemoryCacheRollback(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "memory-cache-rollback-test")
+	workflowFile := filepath.Join(tmpDir, "daily-report.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(`---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+tools:
+  cache-memory: true
+---
+
+# Daily Report
+
+Write the report.
+`), 0644))
+
+	var calls []string
+	original := runMemoryCacheGH
+	runMemoryCacheGH = func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if strings.Contains(strings.Join(args, " "), "--paginate") {
+			return []byte(cacheEntriesOutput), nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { runMemoryCacheGH = original })
+
+	originalCanPrompt := memoryCacheCanPrompt
+	memoryCacheCanPrompt = func() bool { return false }
+	t.Cleanup(func() { memoryCacheCanPrompt = originalCanPrompt })
+
+	err := RunMemoryCacheRollback(workflowFile, 100, false, CacheMemoryOptions{})
+	require.Error(t, err, "rollback without a terminal should require --yes")
+	assert.Contains(t, err.Error(), "re-run with --yes")
+	require.Len(t, calls, 1, "nothing should be deleted without confirmation")
+
+	calls = nil
+	require.NoError(t, RunMemoryCacheRollback(workflowFile, 100, true, CacheMemoryOptions{}))
+
+	require.Len(t, calls, 3, "one list call and one delete call per newer entry")
+	assert.Contains(t, calls[0], "repos/{owner}/{repo}/actions/caches?per_page=100&sort=created_at&direction=desc&key=memory-dailyreport-")
+	assert.Equal(t, "api repos/{owner}/{repo}/actions/caches/3 --method DELETE", calls[1])
+	assert.Equal(t, "api repos/{owner}/{repo}/actions/caches/2 --method DELETE", calls[2])
+}
+
+func TestDiffSnapshotDirs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := testutil.TempDir(t, "memory-cache-diff-test")
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "100"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "200"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "100", "notes.md"), []byte("old\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "200", "notes.md"), []byte("new\n"), 0644))
+
+	diff, err := diffSnapshotDirs(tmpDir, "100", "200", false)
+	require.NoError(t, err, "differences should not be reported as an error")
+	assert.Contains(t, diff, "-old")
+	assert.Contains(t, diff, "+new")
+
+	files, err := listSnapshotFiles(filepath.Join(tmpDir, "200"))
+	require.NoError(t, err)
+	assert.Equal(t, []MemoryFile{{Path: "notes.md", Size: 4}}, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "200", "notes.md"), []byte("old\n"), 0644))
+	diff, err = diffSnapshotDirs(tmpDir, "100", "200", false)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
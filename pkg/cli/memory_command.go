@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var memoryCommandLog = logger.New("cli:memory_command")
+
+// MemoryOptions holds the options shared by the memory subcommands
+type MemoryOptions struct {
+	MemoryID string // Memory to operate on (the only or the "default" memory when empty)
+	Remote   string // Git remote of the current repository
+	Verbose  bool
+}
+
+// NewMemoryCommand creates the memory command with its subcommands
+func NewMemoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "Inspect, diff and reset the repo-memory and cache-memory of a workflow",
+		Long: `Inspect, diff and reset the repo-memory and cache-memory of a workflow.
+
+Workflows that use the repo-memory tool store what the agent remembers on a git branch
+per memory (memory/<workflow-id> by default). These commands read the memory configuration
+of a workflow, fetch its memory branch without checking it out and show what it contains.
+
+Available subcommands:
+  • show  - List the files of a memory, or print one of them
+  • diff  - Show what the latest runs changed in a memory
+  • reset - Delete a memory branch so the next run starts from scratch
+  • cache - Inspect, diff, purge and roll back the cache-memory of a workflow
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report --file history.jsonl
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report --commits 3
+  ` + string(constants.CLIExtensionPrefix) + ` memory reset daily-report --memory notes
+  ` + string(constants.CLIExtensionPrefix) + ` memory cache list daily-report`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newMemoryShowSubcommand())
+	cmd.AddCommand(newMemoryDiffSubcommand())
+	cmd.AddCommand(newMemoryResetSubcommand())
+	cmd.AddCommand(newMemoryCacheSubcommand())
+
+	return cmd
+}
+
+// addMemoryFlags adds the flags shared by the memory subcommands
+func addMemoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("memory", "m", "", "Memory ID (default: the only memory of the workflow, or 'default')")
+	cmd.Flags().String("remote", "origin", "Git remote holding memory branches of the current repository")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+}
+
+// memoryOptionsFromFlags reads the shared memory flags of a command
+func memoryOptionsFromFlags(cmd *cobra.Command) MemoryOptions {
+	memoryID, _ := cmd.Flags().GetString("memory")
+	remote, _ := cmd.Flags().GetString("remote")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return MemoryOptions{MemoryID: memoryID, Remote: remote, Verbose: verbose}
+}
+
+func newMemoryShowSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <workflow>",
+		Short: "List the files of a workflow's memory, or print one of them",
+		Long: `List the files stored on the memory branch of a workflow together with their sizes,
+the number of commits on the branch and when it was last updated. With --file, the content
+of that file is printed instead.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report --file state.json
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunMemoryShow(args[0], file, jsonOutput, memoryOptionsFromFlags(cmd))
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().String("file", "", "Print the content of this file of the memory")
+	cmd.Flags().BoolP("json", "j", false, "Output in JSON format")
+	return cmd
+}
+
+func newMemoryDiffSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <workflow>",
+		Short: "Show what the latest runs changed in a workflow's memory",
+		Long: `Show the changes made by the last commits of the memory branch of a workflow. Each
+workflow run that updates the memory adds one commit, so --commits is roughly the number of
+runs to look back. When --commits covers the whole history, the entire memory is shown.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report --commits 5 --stat`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			commits, _ := cmd.Flags().GetInt("commits")
+			stat, _ := cmd.Flags().GetBool("stat")
+			return RunMemoryDiff(args[0], commits, stat, memoryOptionsFromFlags(cmd))
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().IntP("commits", "n", 1, "Number of commits to look back")
+	cmd.Flags().Bool("stat", false, "Show a summary of changed files instead of the full diff")
+	return cmd
+}
+
+func newMemoryResetSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset <workflow>",
+		Short: "Delete a workflow's memory branch so the next run starts from scratch",
+		Long: `Delete the memory branch of a workflow from its repository. The next run of the
+workflow starts with an empty memory and creates the branch again when it stores something.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory reset daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory reset daily-report --memory notes --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			yes, _ := cmd.Flags().GetBool("yes")
+			return RunMemoryReset(args[0], yes, memoryOptionsFromFlags(cmd))
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
+
+// RunMemoryShow lists the files of a workflow's memory, or prints one of them
+func RunMemoryShow(workflowIdOrName, file string, jsonOutput bool, opts MemoryOptions) error {
+	memory, err := resolveWorkflowMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	remote := memoryRemote(memory, opts.Remote)
+	commit, err := fetchMemoryBranch("", remote, memory.BranchName)
+	if err != nil {
+		return err
+	}
+
+	if file != "" {
+		content, err := readMemoryFile("", commit, file)
+		if err != nil {
+			return fmt.Errorf("file '%s' not found in memory '%s': %w", file, memory.ID, err)
+		}
+		fmt.Print(content)
+		return nil
+	}
+
+	info, err := readMemoryBranch("", commit)
+	if err != nil {
+		return err
+	}
+	info.MemoryID = memory.ID
+	info.Branch = memory.BranchName
+	info.Remote = remote
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal memory: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Memory '%s' on branch %s: %d file(s), %s, %d commit(s), last updated %s",
+		info.MemoryID, info.Branch, len(info.Files), console.FormatFileSize(info.TotalSize), info.Commits, info.LastUpdated)))
+	if len(info.Files) == 0 {
+		return nil
+	}
+	rows := make([][]string, 0, len(info.Files))
+	for _, f := range info.Files {
+		rows = append(rows, []string{f.Path, console.FormatFileSize(f.Size)})
+	}
+	fmt.Print(console.RenderTable(console.TableConfig{Headers: []string{"File", "Size"}, Rows: rows}))
+	return nil
+}
+
+// RunMemoryDiff prints the changes made by the last commits of a workflow's memory
+func RunMemoryDiff(workflowIdOrName string, commits int, stat bool, opts MemoryOptions) error {
+	memory, err := resolveWorkflowMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	commit, err := fetchMemoryBranch("", memoryRemote(memory, opts.Remote), memory.BranchName)
+	if err != nil {
+		return err
+	}
+	diff, err := diffMemoryBranch("", commit, commits, stat)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No changes in memory '"+memory.ID+"'"))
+		return nil
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+// RunMemoryReset deletes the memory branch of a workflow
+func RunMemoryReset(workflowIdOrName string, yes bool, opts MemoryOptions) error {
+	memory, err := resolveWorkflowMemory(workflowIdOrName, opts)
+	if err != nil {
+		return err
+	}
+	remote := memoryRemote(memory, opts.Remote)
+	if _, err := fetchMemoryBranch("", remote, memory.BranchName); err != nil {
+		return err
+	}
+
+	if !yes {
+		confirmed, err := console.ConfirmAction(
+			fmt.Sprintf("Delete memory branch %s? Everything the workflow remembers in memory '%s' will be lost.", memory.BranchName, memory.ID),
+			"Yes, delete",
+			"No, cancel",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Operation cancelled."))
+			return nil
+		}
+	}
+
+	if err := deleteMemoryBranch("", remote, memory.BranchName); err != nil {
+		return fmt.Errorf("failed to delete memory branch %s: %w", memory.BranchName, err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Deleted memory branch %s", memory.BranchName)))
+	return nil
+}
+
+// resolveWorkflowMemory reads the repo-memory configuration of a workflow and selects a memory
+func resolveWorkflowMemory(workflowIdOrName string, opts MemoryOptions) (*workflow.RepoMemoryEntry, error) {
+	workflowFile, err := resolveWorkflowFile(workflowIdOrName, opts.Verbose)
+	if err != nil {
+		return nil, err
+	}
+	memoryCommandLog.Printf("Reading repo-memory configuration from %s", workflowFile)
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(opts.Verbose))
+	data, err := compiler.ParseWorkflowFile(workflowFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	return selectRepoMemory(data.RepoMemoryConfig, opts.MemoryID, workflowIdOrName)
+}
+
+// selectRepoMemory returns the memory with the given ID. Without an ID, the only memory of the
+// workflow is returned, or the one named "default".
+func selectRepoMemory(config *workflow.RepoMemoryConfig, memoryID, workflowName string) (*workflow.RepoMemoryEntry, error) {
+	if config == nil || len(config.Memories) == 0 {
+		return nil, fmt.Errorf("workflow '%s' does not use repo-memory", workflowName)
+	}
+	if memoryID == "" && len(config.Memories) == 1 {
+		return &config.Memories[0], nil
+	}
+
+	wanted := memoryID
+	if wanted == "" {
+		wanted = "default"
+	}
+	ids := make([]string, 0, len(config.Memories))
+	for i := range config.Memories {
+		if config.Memories[i].ID == wanted {
+			return &config.Memories[i], nil
+		}
+		ids = append(ids, config.Memories[i].ID)
+	}
+	if memoryID == "" {
+		return nil, fmt.Errorf("workflow '%s' has several memories, select one with --memory: %s", workflowName, strings.Join(ids, ", "))
+	}
+	return nil, fmt.Errorf("workflow '%s' has no memory '%s', available memories: %s", workflowName, memoryID, strings.Join(ids, ", "))
+}
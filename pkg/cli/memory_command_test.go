@@ -0,0 +1,124 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRepoMemory(t *testing.T) {
+	single := &workflow.RepoMemoryConfig{Memories: []workflow.RepoMemoryEntry{{ID: "notes", BranchName: "memory/notes"}}}
+	multi := &workflow.RepoMemoryConfig{Memories: []workflow.RepoMemoryEntry{{ID: "default"}, {ID: "notes"}}}
+	noDefault := &workflow.RepoMemoryConfig{Memories: []workflow.RepoMemoryEntry{{ID: "state"}, {ID: "notes"}}}
+
+	tests := []struct {
+		name       string
+		config     *workflow.RepoMemoryConfig
+		memoryID   string
+		expectedID string
+		errorMsg   string
+	}{
+		{name: "only memory", config: single, expectedID: "notes"},
+		{name: "default memory", config: multi, expectedID: "default"},
+		{name: "explicit memory", config: multi, memoryID: "notes", expectedID: "notes"},
+		{name: "no repo-memory", config: nil, errorMsg: "does not use repo-memory"},
+		{name: "ambiguous memory", config: noDefault, errorMsg: "select one with --memory: state, notes"},
+		{name: "unknown memory", config: multi, memoryID: "state", errorMsg: "has no memory 'state'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory, err := selectRepoMemory(tt.config, tt.memoryID, "my-workflow")
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, memory.ID)
+		})
+	}
+}
+
+func TestMemoryRemote(t *testing.T) {
+	assert.Equal(t, "origin", memoryRemote(&workflow.RepoMemoryEntry{}, "origin"))
+	assert.Equal(t, "origin", memoryRemote(&workflow.RepoMemoryEntry{TargetRepo: "${{ github.repository }}"}, "origin"), "expressions cannot be resolved locally")
+	assert.Equal(t, getGitHubHost()+"/octo/memory.git", memoryRemote(&workflow.RepoMemoryEntry{TargetRepo: "octo/memory"}, "origin"))
+}
+
+// setupMemoryRemote creates a clone of a bare repository whose memory/test branch has two commits
+func setupMemoryRemote(t *testing.T) string {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "memory-branch-test")
+	remote := filepath.Join(tmpDir, "remote.git")
+	work := filepath.Join(tmpDir, "work")
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	git(tmpDir, "init", "--quiet", "--bare", remote)
+	git(tmpDir, "init", "--quiet", work)
+	git(work, "remote", "add", "origin", remote)
+	git(work, "checkout", "--quiet", "--orphan", "memory/test")
+	require.NoError(t, os.WriteFile(filepath.Join(work, "history.jsonl"), []byte("{\"n\":1}\n"), 0644))
+	git(work, "add", ".")
+	git(work, "commit", "--quiet", "-m", "first run")
+	require.NoError(t, os.WriteFile(filepath.Join(work, "history.jsonl"), []byte("{\"n\":1}\n{\"n\":2}\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(work, "notes"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(work, "notes", "todo.md"), []byte("- item\n"), 0644))
+	git(work, "add", ".")
+	git(work, "commit", "--quiet", "-m", "second run")
+	git(work, "push", "--quiet", "origin", "memory/test")
+	return work
+}
+
+func TestMemoryBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	work := setupMemoryRemote(t)
+
+	_, err := fetchMemoryBranch(work, "origin", "memory/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has not stored any memory yet")
+
+	commit, err := fetchMemoryBranch(work, "origin", "memory/test")
+	require.NoError(t, err)
+
+	info, err := readMemoryBranch(work, commit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Commits)
+	assert.Equal(t, []MemoryFile{{Path: "history.jsonl", Size: 16}, {Path: "notes/todo.md", Size: 7}}, info.Files)
+	assert.Equal(t, int64(23), info.TotalSize)
+	assert.NotEmpty(t, info.LastUpdated)
+
+	content, err := readMemoryFile(work, commit, "notes/todo.md")
+	require.NoError(t, err)
+	assert.Equal(t, "- item\n", content)
+
+	diff, err := diffMemoryBranch(work, commit, 1, false)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+{\"n\":2}")
+	assert.NotContains(t, diff, "+{\"n\":1}", "the first run should not be part of the last commit")
+
+	diff, err = diffMemoryBranch(work, commit, 5, true)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "2 files changed", "looking back past the first commit should show the whole memory")
+
+	require.NoError(t, deleteMemoryBranch(work, "origin", "memory/test"))
+	_, err = fetchMemoryBranch(work, "origin", "memory/test")
+	require.Error(t, err, "the branch should be gone after a reset")
+}
@@ -0,0 +1,569 @@
+package cli
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/tty"
+	"github.com/goccy/go-yaml"
+)
+
+var newTemplateLog = logger.New("cli:new_template")
+
+// WorkflowTemplatesDir is the repository directory searched for templates referenced by name
+const WorkflowTemplatesDir = ".github/aw/templates"
+
+// builtinTemplates holds the golden-path templates available by name in every repository
+//
+//go:embed templates/*.md
+var builtinTemplates embed.FS
+
+// Template delimiters. [[ ]] is used instead of {{ }} so that templates can contain
+// GitHub Actions expressions (${{ ... }}) verbatim.
+const (
+	templateLeftDelim  = "[["
+	templateRightDelim = "]]"
+)
+
+// Supported template parameter types
+const (
+	templateParamString  = "string"
+	templateParamNumber  = "number"
+	templateParamBoolean = "boolean"
+	templateParamChoice  = "choice"
+)
+
+// templateParamNamePattern restricts parameter names to identifiers usable as [[ .name ]]
+var templateParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// TemplateParameter is a parameter declared by a workflow template
+type TemplateParameter struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type,omitempty"` // string (default), number, boolean or choice
+	Default  any      `yaml:"default,omitempty"`
+	Prompt   string   `yaml:"prompt,omitempty"`
+	Options  []string `yaml:"options,omitempty"` // Allowed values for choice parameters
+	Required bool     `yaml:"required,omitempty"`
+}
+
+// WorkflowTemplate is a markdown workflow with a 'template:' frontmatter block declaring
+// parameters. The rest of the file is rendered with text/template using [[ ]] delimiters.
+type WorkflowTemplate struct {
+	Description string              `yaml:"description,omitempty"`
+	Parameters  []TemplateParameter `yaml:"parameters,omitempty"`
+
+	body string // Template content without the 'template:' block
+}
+
+// parseWorkflowTemplate extracts the 'template:' block from the frontmatter of a template.
+// The block is extracted textually because the remaining frontmatter may contain template
+// actions and is only valid YAML after rendering.
+func parseWorkflowTemplate(content string) (*WorkflowTemplate, error) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, errors.New("not a workflow template: missing frontmatter")
+	}
+
+	start, end := -1, -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		if line == "---" {
+			if start >= 0 && end < 0 {
+				end = i
+			}
+			break
+		}
+		if start < 0 {
+			if line == "template:" || strings.HasPrefix(line, "template: #") {
+				start = i
+			}
+			continue
+		}
+		if end < 0 && line != "" && line[0] != ' ' && line[0] != '\t' && line[0] != '#' {
+			end = i
+		}
+	}
+	if start < 0 {
+		return nil, errors.New("not a workflow template: missing 'template:' frontmatter block")
+	}
+	if end < 0 {
+		return nil, errors.New("invalid workflow template: unterminated frontmatter")
+	}
+
+	var parsed struct {
+		Template WorkflowTemplate `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[start:end], "\n")), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid 'template:' block: %w", err)
+	}
+	tmpl := &parsed.Template
+	if err := tmpl.validate(); err != nil {
+		return nil, err
+	}
+	tmpl.body = strings.Join(append(slices.Clone(lines[:start]), lines[end:]...), "\n")
+
+	newTemplateLog.Printf("Parsed workflow template: parameters=%d", len(tmpl.Parameters))
+	return tmpl, nil
+}
+
+// validate checks parameter names, types and defaults
+func (t *WorkflowTemplate) validate() error {
+	seen := make(map[string]bool)
+	for i := range t.Parameters {
+		param := &t.Parameters[i]
+		if !templateParamNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("invalid template parameter name %q: use letters, digits and underscores", param.Name)
+		}
+		if param.Name == "WorkflowName" {
+			return errors.New("template parameter name 'WorkflowName' is reserved")
+		}
+		if seen[param.Name] {
+			return fmt.Errorf("duplicate template parameter %q", param.Name)
+		}
+		seen[param.Name] = true
+
+		if param.Type == "" {
+			param.Type = templateParamString
+		}
+		switch param.Type {
+		case templateParamString, templateParamNumber, templateParamBoolean:
+		case templateParamChoice:
+			if len(param.Options) == 0 {
+				return fmt.Errorf("choice parameter %q requires options", param.Name)
+			}
+		default:
+			return fmt.Errorf("template parameter %q has unknown type %q (expected string, number, boolean or choice)", param.Name, param.Type)
+		}
+
+		if param.Default != nil {
+			if _, err := param.coerce(fmt.Sprint(param.Default)); err != nil {
+				return fmt.Errorf("invalid default for template parameter %q: %w", param.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// coerce converts a raw string value to the parameter's type
+func (p *TemplateParameter) coerce(raw string) (any, error) {
+	switch p.Type {
+	case templateParamNumber:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return f, nil
+	case templateParamBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return b, nil
+	case templateParamChoice:
+		if !slices.Contains(p.Options, raw) {
+			return nil, fmt.Errorf("%q is not one of %s", raw, strings.Join(p.Options, ", "))
+		}
+		return raw, nil
+	default:
+		return raw, nil
+	}
+}
+
+// zeroValue returns the value of an optional parameter without a default
+func (p *TemplateParameter) zeroValue() any {
+	switch p.Type {
+	case templateParamNumber:
+		return int64(0)
+	case templateParamBoolean:
+		return false
+	default:
+		return ""
+	}
+}
+
+// title returns the prompt shown for the parameter
+func (p *TemplateParameter) title() string {
+	if p.Prompt != "" {
+		return p.Prompt
+	}
+	return p.Name
+}
+
+// parseTemplateParamFlags parses --param name=value flags
+func parseTemplateParamFlags(params []string) (map[string]string, error) {
+	values := make(map[string]string, len(params))
+	for _, param := range params {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --param %q: expected name=value", param)
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values, nil
+}
+
+// resolveTemplateParameters determines a value for every template parameter from flags,
+// then from the interactive form (when prompt is set), then from defaults
+func resolveTemplateParameters(tmpl *WorkflowTemplate, flagValues map[string]string, prompt bool) (map[string]any, error) {
+	declared := make(map[string]bool, len(tmpl.Parameters))
+	for _, param := range tmpl.Parameters {
+		declared[param.Name] = true
+	}
+	for name := range flagValues {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown template parameter %q", name)
+		}
+	}
+
+	values := make(map[string]any, len(tmpl.Parameters))
+	var missing []*TemplateParameter
+	for i := range tmpl.Parameters {
+		param := &tmpl.Parameters[i]
+		raw, ok := flagValues[param.Name]
+		if !ok {
+			missing = append(missing, param)
+			continue
+		}
+		value, err := param.coerce(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for template parameter %q: %w", param.Name, err)
+		}
+		values[param.Name] = value
+	}
+
+	if prompt && len(missing) > 0 {
+		if err := promptTemplateParameters(missing, values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	for _, param := range missing {
+		switch {
+		case param.Default != nil:
+			value, _ := param.coerce(fmt.Sprint(param.Default))
+			values[param.Name] = value
+		case param.Required:
+			return nil, fmt.Errorf("missing value for required template parameter %q; pass --param %s=<value>", param.Name, param.Name)
+		default:
+			values[param.Name] = param.zeroValue()
+		}
+	}
+	return values, nil
+}
+
+// promptTemplateParameters asks for the given parameters with an interactive form
+func promptTemplateParameters(params []*TemplateParameter, values map[string]any) error {
+	strValues := make([]string, len(params))
+	boolValues := make([]bool, len(params))
+	fields := make([]console.FormField, 0, len(params))
+
+	for i, param := range params {
+		if param.Default != nil {
+			strValues[i] = fmt.Sprint(param.Default)
+			boolValues[i], _ = strconv.ParseBool(strValues[i])
+		}
+		switch param.Type {
+		case templateParamBoolean:
+			fields = append(fields, console.FormField{Type: "confirm", Title: param.title(), Value: &boolValues[i]})
+		case templateParamChoice:
+			options := make([]console.SelectOption, 0, len(param.Options))
+			for _, option := range param.Options {
+				options = append(options, console.SelectOption{Label: option, Value: option})
+			}
+			fields = append(fields, console.FormField{Type: "select", Title: param.title(), Description: param.Name, Options: options, Value: &strValues[i]})
+		default:
+			fields = append(fields, console.FormField{
+				Type:        "input",
+				Title:       param.title(),
+				Description: param.Name,
+				Value:       &strValues[i],
+				Validate: func(value string) error {
+					if value == "" {
+						if param.Required {
+							return errors.New("a value is required")
+						}
+						return nil
+					}
+					_, err := param.coerce(value)
+					return err
+				},
+			})
+		}
+	}
+
+	if err := console.RunForm(fields); err != nil {
+		return fmt.Errorf("failed to read template parameters: %w", err)
+	}
+
+	for i, param := range params {
+		if param.Type == templateParamBoolean {
+			values[param.Name] = boolValues[i]
+			continue
+		}
+		if strValues[i] == "" {
+			values[param.Name] = param.zeroValue()
+			continue
+		}
+		value, err := param.coerce(strValues[i])
+		if err != nil {
+			return fmt.Errorf("invalid value for template parameter %q: %w", param.Name, err)
+		}
+		values[param.Name] = value
+	}
+	return nil
+}
+
+// templateYAMLString is a string parameter value rendered into the frontmatter. It prints
+// as a YAML scalar that is quoted and escaped whenever the plain value could change the
+// structure of the frontmatter, e.g. "a: b", "# x", "yes" or multi-line values.
+type templateYAMLString string
+
+func (s templateYAMLString) String() string {
+	out, err := yaml.Marshal(string(s))
+	scalar := strings.TrimSuffix(string(out), "\n")
+	if err != nil || strings.Contains(scalar, "\n") {
+		return strconv.Quote(string(s))
+	}
+	return scalar
+}
+
+// templateString returns the raw string of a parameter value
+func templateString(v any) string {
+	if s, ok := v.(templateYAMLString); ok {
+		return string(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// renderWorkflowTemplate renders the template with parameter values. WorkflowName is
+// available to templates as [[ .WorkflowName ]]. String values are escaped for YAML in the
+// frontmatter and inserted verbatim in the markdown body, and the rendered frontmatter
+// must parse.
+func renderWorkflowTemplate(tmpl *WorkflowTemplate, workflowName string, values map[string]any) (string, error) {
+	funcs := template.FuncMap{
+		"lower": func(v any) string { return strings.ToLower(templateString(v)) },
+		"upper": func(v any) string { return strings.ToUpper(templateString(v)) },
+		"quote": func(v any) string { return strconv.Quote(templateString(v)) },
+	}
+	parsed, err := template.New(workflowName).
+		Delims(templateLeftDelim, templateRightDelim).
+		Option("missingkey=error").
+		Funcs(funcs).
+		Parse(tmpl.body)
+	if err != nil {
+		return "", fmt.Errorf("invalid workflow template: %w", err)
+	}
+
+	execute := func(escapeYAML bool) (string, error) {
+		data := make(map[string]any, len(values)+1)
+		for name, value := range values {
+			if s, ok := value.(string); ok && escapeYAML {
+				value = templateYAMLString(s)
+			}
+			data[name] = value
+		}
+		data["WorkflowName"] = workflowName
+		if escapeYAML {
+			data["WorkflowName"] = templateYAMLString(workflowName)
+		}
+
+		var sb strings.Builder
+		if err := parsed.Execute(&sb, data); err != nil {
+			return "", fmt.Errorf("failed to render workflow template: %w", err)
+		}
+		return sb.String(), nil
+	}
+
+	yamlRendered, err := execute(true)
+	if err != nil {
+		return "", err
+	}
+	rawRendered, err := execute(false)
+	if err != nil {
+		return "", err
+	}
+
+	// Take the frontmatter from the escaped rendering and the body from the raw one
+	frontmatter, _, ok := splitRenderedFrontmatter(yamlRendered)
+	_, body, rawOK := splitRenderedFrontmatter(rawRendered)
+	if !ok || !rawOK {
+		return "", errors.New("workflow template rendered without a terminated frontmatter")
+	}
+	rendered := frontmatter + body
+
+	if _, err := parser.ExtractFrontmatterFromContent(rendered); err != nil {
+		return "", fmt.Errorf("workflow template rendered invalid frontmatter, check the parameter values: %w", err)
+	}
+	return rendered, nil
+}
+
+// splitRenderedFrontmatter splits rendered content after the line closing the frontmatter
+func splitRenderedFrontmatter(content string) (string, string, bool) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", "", false
+	}
+	offset := len("---\n")
+	for offset < len(content) {
+		lineEnd := strings.IndexByte(content[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(content) - offset
+		} else {
+			lineEnd++
+		}
+		line := content[offset : offset+lineEnd]
+		offset += lineEnd
+		if strings.TrimRight(line, " \t\r\n") == "---" {
+			return content[:offset], content[offset:], true
+		}
+	}
+	return "", "", false
+}
+
+// loadWorkflowTemplate reads a template from a local path, a remote workflowspec
+// (owner/repo/path.md[@ref]) or by name from .github/aw/templates
+func loadWorkflowTemplate(ref string, verbose bool) (string, error) {
+	if isLocalWorkflowPath(ref) {
+		content, err := os.ReadFile(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template %s: %w", ref, err)
+		}
+		return string(content), nil
+	}
+
+	if strings.Contains(ref, "/") {
+		spec, err := parseWorkflowSpec(ref)
+		if err != nil {
+			return "", fmt.Errorf("invalid template reference '%s': %w", ref, err)
+		}
+		fetched, err := FetchWorkflowFromSource(spec, verbose)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch template '%s': %w", ref, err)
+		}
+		return string(fetched.Content), nil
+	}
+
+	// Repository templates take precedence over built-in templates with the same name
+	name := strings.TrimSuffix(ref, ".md") + ".md"
+	templatePath := filepath.Join(WorkflowTemplatesDir, name)
+	content, err := os.ReadFile(templatePath)
+	if err == nil {
+		return string(content), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+
+	content, err = builtinTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("template '%s' not found in %s or built-in templates (%s); use a local path or owner/repo/path.md", ref, WorkflowTemplatesDir, strings.Join(listBuiltinTemplates(), ", "))
+	}
+	newTemplateLog.Printf("Using built-in template: %s", name)
+	return string(content), nil
+}
+
+// listBuiltinTemplates returns the names of the built-in templates
+func listBuiltinTemplates() []string {
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".md"))
+	}
+	return names
+}
+
+// NewWorkflowFromTemplate creates a workflow by rendering a parameterized template, then
+// compiles it. The workflow is removed again if it does not compile.
+func NewWorkflowFromTemplate(workflowName, templateRef string, params []string, verbose bool, force bool) error {
+	newTemplateLog.Printf("Creating workflow from template: name=%s, template=%s, params=%d", workflowName, templateRef, len(params))
+
+	flagValues, err := parseTemplateParamFlags(params)
+	if err != nil {
+		return err
+	}
+
+	if workflowName == "" {
+		workflowName = filepath.Base(templateRef)
+	}
+	workflowName = strings.TrimSuffix(workflowName, ".md")
+	if at := strings.Index(workflowName, "@"); at >= 0 {
+		workflowName = workflowName[:at]
+		workflowName = strings.TrimSuffix(workflowName, ".md")
+	}
+
+	content, err := loadWorkflowTemplate(templateRef, verbose)
+	if err != nil {
+		return err
+	}
+	tmpl, err := parseWorkflowTemplate(content)
+	if err != nil {
+		return err
+	}
+	if tmpl.Description != "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Template %s: %s", templateRef, tmpl.Description)))
+	}
+
+	prompt := tty.IsStderrTerminal() && !IsRunningInCI()
+	values, err := resolveTemplateParameters(tmpl, flagValues, prompt)
+	if err != nil {
+		return err
+	}
+	rendered, err := renderWorkflowTemplate(tmpl, workflowName, values)
+	if err != nil {
+		return err
+	}
+
+	destFile, err := newWorkflowDestination(workflowName, force)
+	if err != nil {
+		return err
+	}
+
+	tracker, err := NewFileTracker()
+	if err != nil {
+		tracker = nil
+	}
+	if tracker != nil {
+		if _, statErr := os.Stat(destFile); statErr == nil {
+			tracker.TrackModified(destFile)
+		} else {
+			tracker.TrackCreated(destFile)
+		}
+	}
+
+	if err := os.WriteFile(destFile, []byte(rendered), 0600); err != nil {
+		return fmt.Errorf("failed to write workflow file '%s': %w", destFile, err)
+	}
+
+	// Compile to validate the rendered workflow; roll back if it is invalid
+	if err := compileWorkflowWithTracking(destFile, verbose, false, "", tracker); err != nil {
+		newTemplateLog.Printf("Rendered workflow failed to compile: %v", err)
+		if tracker != nil {
+			_ = tracker.RollbackAllFiles(verbose)
+		} else {
+			_ = os.Remove(destFile)
+		}
+		return fmt.Errorf("workflow rendered from template '%s' failed to compile: %w", templateRef, err)
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Created workflow from template: "+destFile))
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Review the workflow, then commit it together with its lock file. Run '%s compile' after further edits", string(constants.CLIExtensionPrefix))))
+	return nil
+}
@@ -0,0 +1,197 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkflowTemplate = `---
+template:
+  description: Test template
+  parameters:
+    - name: label
+      prompt: Label to apply
+      required: true
+    - name: max
+      type: number
+      default: 2
+    - name: comment
+      type: boolean
+    - name: engine
+      type: choice
+      options: [copilot, claude]
+      default: claude
+on:
+  issues:
+    types: [opened]
+engine: [[ .engine ]]
+safe-outputs:
+  add-labels:
+    allowed: [[ printf "[%s]" .label ]]
+    max: [[ .max ]]
+[[- if .comment ]]
+  add-comment:
+[[- end ]]
+---
+
+# [[ .WorkflowName ]]
+
+Label issue #${{ github.event.issue.number }} with [[ quote .label ]].
+`
+
+func TestParseWorkflowTemplate(t *testing.T) {
+	tmpl, err := parseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "Test template", tmpl.Description)
+	require.Len(t, tmpl.Parameters, 4)
+	assert.Equal(t, templateParamString, tmpl.Parameters[0].Type, "type should default to string")
+	assert.True(t, tmpl.Parameters[0].Required)
+	assert.NotContains(t, tmpl.body, "template:")
+	assert.Contains(t, tmpl.body, "---\non:\n")
+}
+
+func TestParseWorkflowTemplateInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no frontmatter", content: "# Title", wantErr: "missing frontmatter"},
+		{name: "no template block", content: "---\non: push\n---\n", wantErr: "missing 'template:'"},
+		{name: "bad name", content: "---\ntemplate:\n  parameters:\n    - name: my-param\n---\n", wantErr: "invalid template parameter name"},
+		{name: "reserved name", content: "---\ntemplate:\n  parameters:\n    - name: WorkflowName\n---\n", wantErr: "reserved"},
+		{name: "duplicate", content: "---\ntemplate:\n  parameters:\n    - name: a\n    - name: a\n---\n", wantErr: "duplicate"},
+		{name: "unknown type", content: "---\ntemplate:\n  parameters:\n    - name: a\n      type: list\n---\n", wantErr: "unknown type"},
+		{name: "choice without options", content: "---\ntemplate:\n  parameters:\n    - name: a\n      type: choice\n---\n", wantErr: "requires options"},
+		{name: "bad default", content: "---\ntemplate:\n  parameters:\n    - name: a\n      type: number\n      default: many\n---\n", wantErr: "invalid default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseWorkflowTemplate(tt.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestResolveTemplateParameters(t *testing.T) {
+	tmpl, err := parseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+
+	t.Run("flags and defaults", func(t *testing.T) {
+		values, err := resolveTemplateParameters(tmpl, map[string]string{"label": "bug", "comment": "true"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"label": "bug", "max": int64(2), "comment": true, "engine": "claude"}, values)
+	})
+
+	t.Run("missing required", func(t *testing.T) {
+		_, err := resolveTemplateParameters(tmpl, map[string]string{}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--param label=<value>")
+	})
+
+	t.Run("unknown parameter", func(t *testing.T) {
+		_, err := resolveTemplateParameters(tmpl, map[string]string{"label": "bug", "labels": "x"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown template parameter "labels"`)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := resolveTemplateParameters(tmpl, map[string]string{"label": "bug", "max": "lots"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a number")
+
+		_, err = resolveTemplateParameters(tmpl, map[string]string{"label": "bug", "engine": "gpt"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not one of copilot, claude")
+	})
+}
+
+func TestParseTemplateParamFlags(t *testing.T) {
+	values, err := parseTemplateParamFlags([]string{"label=bug", "title=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"label": "bug", "title": "a=b", "empty": ""}, values)
+
+	_, err = parseTemplateParamFlags([]string{"label"})
+	require.Error(t, err)
+}
+
+func TestRenderWorkflowTemplate(t *testing.T) {
+	tmpl, err := parseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+
+	rendered, err := renderWorkflowTemplate(tmpl, "labeler", map[string]any{"label": "bug", "max": int64(3), "comment": false, "engine": "copilot"})
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "engine: copilot\n")
+	assert.Contains(t, rendered, "allowed: [bug]\n    max: 3\n---")
+	assert.NotContains(t, rendered, "add-comment")
+	assert.Contains(t, rendered, "# labeler")
+	assert.Contains(t, rendered, "#${{ github.event.issue.number }} with \"bug\".", "GitHub expressions must be left untouched")
+
+	_, err = renderWorkflowTemplate(tmpl, "labeler", map[string]any{"label": "bug"})
+	require.Error(t, err, "missing values should fail rendering")
+}
+
+func TestRenderWorkflowTemplateEscapesFrontmatterValues(t *testing.T) {
+	tmpl, err := parseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+
+	label := "bug\non: push"
+	rendered, err := renderWorkflowTemplate(tmpl, "labeler", map[string]any{"label": label, "max": int64(3), "comment": false, "engine": "copilot"})
+	require.NoError(t, err)
+
+	result, err := parser.ExtractFrontmatterFromContent(rendered)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"issues": map[string]any{"types": []any{"opened"}}}, result.Frontmatter["on"], "parameter values must not inject frontmatter keys")
+	assert.Equal(t, []any{label}, result.Frontmatter["safe-outputs"].(map[string]any)["add-labels"].(map[string]any)["allowed"])
+	assert.Contains(t, result.Markdown, "with \"bug\\non: push\".", "body values are inserted verbatim")
+
+	rendered, err = renderWorkflowTemplate(tmpl, "labeler", map[string]any{"label": "yes", "max": int64(3), "comment": false, "engine": "copilot"})
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `allowed: ["yes"]`, "values YAML would reinterpret should be quoted")
+}
+
+func TestRenderWorkflowTemplateRejectsInvalidFrontmatter(t *testing.T) {
+	tmpl, err := parseWorkflowTemplate("---\ntemplate:\n  parameters:\n    - name: day\non:\n  schedule: weekly on [[ .day ]]\n---\n")
+	require.NoError(t, err)
+
+	_, err = renderWorkflowTemplate(tmpl, "report", map[string]any{"day": "monday: x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rendered invalid frontmatter")
+}
+
+func TestBuiltinTemplatesCompile(t *testing.T) {
+	names := listBuiltinTemplates()
+	assert.ElementsMatch(t, []string{"dependency-updater", "triage-bot", "weekly-report"}, names)
+
+	params := map[string]map[string]string{
+		"dependency-updater": {"ecosystem": "go"},
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			content, err := loadWorkflowTemplate(name, false)
+			require.NoError(t, err)
+			tmpl, err := parseWorkflowTemplate(content)
+			require.NoError(t, err)
+			values, err := resolveTemplateParameters(tmpl, params[name], false)
+			require.NoError(t, err)
+			rendered, err := renderWorkflowTemplate(tmpl, name, values)
+			require.NoError(t, err)
+
+			workflowPath := filepath.Join(t.TempDir(), name+".md")
+			require.NoError(t, os.WriteFile(workflowPath, []byte(rendered), 0600))
+			compiler := workflow.NewCompiler(workflow.WithVersion("test"), workflow.WithNoEmit(true), workflow.WithWorkflowIdentifier(name))
+			require.NoError(t, compiler.CompileWorkflow(workflowPath), "rendered template should compile:\n%s", rendered)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var packageManifestLog = logger.New("cli:package_manifest")
+
+// PackageManifestFile is the manifest at the root of a workflow package repository
+const PackageManifestFile = "aw-package.yml"
+
+// secretNamePattern matches GitHub Actions secret names
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PackageManifest describes a workflow package: a set of workflows installed and updated as a unit
+type PackageManifest struct {
+	Name         string            `yaml:"name"`
+	Description  string            `yaml:"description,omitempty"`
+	Workflows    []string          `yaml:"workflows"`              // Workflow paths relative to the repository root
+	Imports      []string          `yaml:"imports,omitempty"`      // Shared import paths installed alongside the workflows
+	Secrets      []PackageSecret   `yaml:"secrets,omitempty"`      // Secrets the workflows require
+	Dependencies map[string]string `yaml:"dependencies,omitempty"` // Other packages (owner/repo) and their version ranges
+}
+
+// PackageSecret is a secret required by the workflows of a package
+type PackageSecret struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// parsePackageManifest parses and validates an aw-package.yml manifest
+func parsePackageManifest(data []byte) (*PackageManifest, error) {
+	var manifest PackageManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", PackageManifestFile, err)
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PackageManifestFile, err)
+	}
+	packageManifestLog.Printf("Parsed package manifest: name=%s, workflows=%d, imports=%d, dependencies=%d", manifest.Name, len(manifest.Workflows), len(manifest.Imports), len(manifest.Dependencies))
+	return &manifest, nil
+}
+
+// validate checks manifest paths, secret names and dependency ranges
+func (m *PackageManifest) validate() error {
+	if m.Name == "" {
+		return errors.New("'name' is required")
+	}
+	if len(m.Workflows) == 0 {
+		return errors.New("'workflows' must list at least one workflow")
+	}
+	for _, workflowPath := range m.Workflows {
+		if err := validatePackagePath(workflowPath); err != nil {
+			return fmt.Errorf("workflow %q: %w", workflowPath, err)
+		}
+	}
+	for _, importPath := range m.Imports {
+		if err := validatePackagePath(importPath); err != nil {
+			return fmt.Errorf("import %q: %w", importPath, err)
+		}
+	}
+	for _, secret := range m.Secrets {
+		if !secretNamePattern.MatchString(secret.Name) {
+			return fmt.Errorf("invalid secret name %q", secret.Name)
+		}
+	}
+	for dep, rangeSpec := range m.Dependencies {
+		owner, repo, ok := strings.Cut(dep, "/")
+		if !ok || !parser.IsValidGitHubIdentifier(owner) || !parser.IsValidGitHubIdentifier(repo) {
+			return fmt.Errorf("dependency %q must be a repository in owner/repo format", dep)
+		}
+		if !isVersionRange(rangeSpec) {
+			return fmt.Errorf("dependency %q has invalid version range %q", dep, rangeSpec)
+		}
+	}
+	return nil
+}
+
+// validatePackagePath checks that a manifest path is a repository-relative markdown file
+func validatePackagePath(p string) error {
+	if p == "" {
+		return errors.New("path is empty")
+	}
+	if strings.HasPrefix(p, "/") || path.Clean(p) != p || strings.HasPrefix(p, "../") || p == ".." {
+		return errors.New("path must be relative to the repository root and must not contain '..'")
+	}
+	if !strings.HasSuffix(p, ".md") {
+		return errors.New("path must end with '.md'")
+	}
+	return nil
+}
+
+// requiredSecretNames returns the names of the secrets the package requires
+func (m *PackageManifest) requiredSecretNames() []string {
+	names := make([]string, 0, len(m.Secrets))
+	for _, secret := range m.Secrets {
+		names = append(names, secret.Name)
+	}
+	return names
+}
+
+// fetchPackageManifest downloads and parses the manifest of a package at a ref
+func fetchPackageManifest(repo, ref string, verbose bool) (*PackageManifest, error) {
+	content, err := downloadWorkflowContent(repo, PackageManifestFile, ref, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s is not a workflow package (no %s found): %w", repo, ref, PackageManifestFile, err)
+	}
+	return parsePackageManifest(content)
+}
+
+// listRepoTags returns the tag names of a repository
+func listRepoTags(repo string) ([]string, error) {
+	output, err := workflow.RunGH("Fetching tags...", "api", "--paginate", fmt.Sprintf("/repos/%s/tags", repo), "--jq", ".[].name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags for %s: %w", repo, err)
+	}
+
+	var tags []string
+	for tag := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	packageManifestLog.Printf("Fetched %d tags for %s", len(tags), repo)
+	return tags, nil
+}
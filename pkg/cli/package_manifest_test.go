@@ -0,0 +1,57 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageManifest(t *testing.T) {
+	manifest, err := parsePackageManifest([]byte(`name: platform-pack
+description: Workflows for platform teams
+workflows:
+  - workflows/triage.md
+  - workflows/ci-doctor.md
+imports:
+  - workflows/shared/tools.md
+secrets:
+  - name: COPILOT_GITHUB_TOKEN
+    description: Token for the Copilot engine
+dependencies:
+  octo/base-pack: ^2.0
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "platform-pack", manifest.Name)
+	assert.Equal(t, []string{"workflows/triage.md", "workflows/ci-doctor.md"}, manifest.Workflows)
+	assert.Equal(t, []string{"workflows/shared/tools.md"}, manifest.Imports)
+	assert.Equal(t, []string{"COPILOT_GITHUB_TOKEN"}, manifest.requiredSecretNames())
+	assert.Equal(t, map[string]string{"octo/base-pack": "^2.0"}, manifest.Dependencies)
+}
+
+func TestParsePackageManifestInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{name: "missing name", manifest: "workflows: [a.md]", wantErr: "'name' is required"},
+		{name: "no workflows", manifest: "name: p", wantErr: "at least one workflow"},
+		{name: "path traversal", manifest: "name: p\nworkflows: [../a.md]", wantErr: "must not contain '..'"},
+		{name: "absolute path", manifest: "name: p\nworkflows: [/a.md]", wantErr: "relative to the repository root"},
+		{name: "not markdown", manifest: "name: p\nworkflows: [a.yml]", wantErr: "must end with '.md'"},
+		{name: "bad secret", manifest: "name: p\nworkflows: [a.md]\nsecrets: [{name: 'MY-TOKEN'}]", wantErr: "invalid secret name"},
+		{name: "bad dependency", manifest: "name: p\nworkflows: [a.md]\ndependencies: {base-pack: ^1}", wantErr: "owner/repo format"},
+		{name: "bad dependency range", manifest: "name: p\nworkflows: [a.md]\ndependencies: {octo/base: main}", wantErr: "invalid version range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePackageManifest([]byte(tt.manifest))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var packageResolutionLog = logger.New("cli:package_resolution")
+
+// maxPackageResolutionSteps bounds dependency resolution when versions keep being re-selected
+const maxPackageResolutionSteps = 100
+
+// ResolvedPackage is a workflow package resolved to a concrete version
+type ResolvedPackage struct {
+	Repo     string // Package repository (owner/repo)
+	Range    string // Version range the package follows; empty when pinned to a branch or SHA
+	Version  string // Tag (or branch/SHA) the package resolved to
+	Manifest *PackageManifest
+}
+
+// packageConstraint is a version range required of a package, and who required it
+type packageConstraint struct {
+	rangeSpec    string
+	requiredBy   string
+	versionRange *versionRange
+}
+
+// packageResolver resolves packages and their dependencies to versions.
+// Fetch functions are fields so that resolution can be tested without GitHub.
+type packageResolver struct {
+	listTags      func(repo string) ([]string, error)
+	fetchManifest func(repo, ref string) (*PackageManifest, error)
+
+	tags map[string][]string
+}
+
+// newPackageResolver creates a resolver that fetches tags and manifests from GitHub
+func newPackageResolver(verbose bool) *packageResolver {
+	return &packageResolver{
+		listTags: listRepoTags,
+		fetchManifest: func(repo, ref string) (*PackageManifest, error) {
+			return fetchPackageManifest(repo, ref, verbose)
+		},
+	}
+}
+
+// repoTags returns the tags of a repository, fetching them once
+func (r *packageResolver) repoTags(repo string) ([]string, error) {
+	if tags, ok := r.tags[repo]; ok {
+		return tags, nil
+	}
+	tags, err := r.listTags(repo)
+	if err != nil {
+		return nil, err
+	}
+	if r.tags == nil {
+		r.tags = make(map[string][]string)
+	}
+	r.tags[repo] = tags
+	return tags, nil
+}
+
+// resolveVersion returns the highest tag of repo that satisfies every constraint
+func (r *packageResolver) resolveVersion(repo string, constraints []packageConstraint) (string, error) {
+	tags, err := r.repoTags(repo)
+	if err != nil {
+		return "", err
+	}
+
+	ranges := make([]*versionRange, 0, len(constraints))
+	for _, c := range constraints {
+		ranges = append(ranges, c.versionRange)
+	}
+	if version := selectHighestVersion(tags, ranges...); version != "" {
+		return version, nil
+	}
+
+	required := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		required = append(required, fmt.Sprintf("%s (required by %s)", c.rangeSpec, c.requiredBy))
+	}
+	return "", fmt.Errorf("no release of %s satisfies %s", repo, strings.Join(required, " and "))
+}
+
+// resolve resolves a package and its dependencies. ref is a version range, an exact
+// tag, or a branch or commit SHA (which installs that ref without following a range).
+// Packages are returned in resolution order, starting with the requested package.
+func (r *packageResolver) resolve(repo, ref string) ([]*ResolvedPackage, error) {
+	packageResolutionLog.Printf("Resolving package %s@%s", repo, ref)
+
+	resolved := make(map[string]*ResolvedPackage)
+	var order []string
+
+	// A branch or SHA installs the package as-is; dependencies still follow their ranges
+	if ref != "" && !isVersionRange(ref) {
+		manifest, err := r.fetchManifest(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved[repo] = &ResolvedPackage{Repo: repo, Version: ref, Manifest: manifest}
+		order = append(order, repo)
+	}
+
+	constraints := make(map[string][]packageConstraint)
+	var queue []string
+	addConstraint := func(target, rangeSpec, requiredBy string) error {
+		vr, err := parseVersionRange(rangeSpec)
+		if err != nil {
+			return err
+		}
+		constraints[target] = append(constraints[target], packageConstraint{rangeSpec: rangeSpec, requiredBy: requiredBy, versionRange: vr})
+		if existing := resolved[target]; existing == nil || !vr.allows(existing.Version) {
+			queue = append(queue, target)
+		}
+		return nil
+	}
+	enqueueDependencies := func(pkg *ResolvedPackage) error {
+		deps := make([]string, 0, len(pkg.Manifest.Dependencies))
+		for dep := range pkg.Manifest.Dependencies {
+			deps = append(deps, dep)
+		}
+		slices.Sort(deps)
+		for _, dep := range deps {
+			if dep == pkg.Repo {
+				continue
+			}
+			if err := addConstraint(dep, pkg.Manifest.Dependencies[dep], pkg.Repo+"@"+pkg.Version); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if root := resolved[repo]; root != nil {
+		if err := enqueueDependencies(root); err != nil {
+			return nil, err
+		}
+	} else if err := addConstraint(repo, ref, "the command line"); err != nil {
+		return nil, err
+	}
+
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps >= maxPackageResolutionSteps {
+			return nil, fmt.Errorf("could not resolve dependencies of %s: too many version changes", repo)
+		}
+		current := queue[0]
+		queue = queue[1:]
+
+		if existing := resolved[current]; existing != nil && existing.Range == "" {
+			return nil, fmt.Errorf("%s is installed at %s, which cannot satisfy version ranges required by its dependents", current, existing.Version)
+		}
+
+		version, err := r.resolveVersion(current, constraints[current])
+		if err != nil {
+			return nil, err
+		}
+		existing := resolved[current]
+		if existing != nil && existing.Version == version {
+			continue
+		}
+
+		packageResolutionLog.Printf("Selected %s@%s", current, version)
+		manifest, err := r.fetchManifest(current, version)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			order = append(order, current)
+		}
+		pkg := &ResolvedPackage{Repo: current, Range: combinedRange(constraints[current], version), Version: version, Manifest: manifest}
+		resolved[current] = pkg
+		if err := enqueueDependencies(pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	packages := make([]*ResolvedPackage, 0, len(order))
+	for _, name := range order {
+		packages = append(packages, resolved[name])
+	}
+	return packages, nil
+}
+
+// combinedRange returns a single range equivalent to all constraints, used by update.
+// Constraints with alternatives ("||") cannot be intersected textually, so the
+// resolved version is pinned exactly instead.
+func combinedRange(constraints []packageConstraint, version string) string {
+	specs := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		if strings.Contains(c.rangeSpec, "||") {
+			return version
+		}
+		if c.rangeSpec != "" {
+			specs = append(specs, c.rangeSpec)
+		}
+	}
+	if len(specs) == 0 {
+		return "*"
+	}
+	return strings.Join(specs, " ")
+}
+
+// expandPackageSpecs replaces repository-only specs (owner/repo[@range]) with the workflows
+// of the package in that repository and of its dependencies. Returns the expanded workflow
+// specs and the resolved packages keyed by expanded spec.
+func expandPackageSpecs(specs []string, verbose bool) ([]string, map[string]*ResolvedPackage, error) {
+	var expanded []string
+	packagesBySpec := make(map[string]*ResolvedPackage)
+	installed := make(map[string]*ResolvedPackage)
+	var resolver *packageResolver
+
+	for _, spec := range specs {
+		if !isRepoOnlySpec(spec) {
+			expanded = append(expanded, spec)
+			continue
+		}
+		if resolver == nil {
+			resolver = newPackageResolver(verbose)
+		}
+
+		repo, ref, _ := strings.Cut(spec, "@")
+		packages, err := resolver.resolve(repo, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve package '%s': %w", spec, err)
+		}
+
+		for _, pkg := range packages {
+			if existing := installed[pkg.Repo]; existing != nil {
+				if existing.Version != pkg.Version {
+					return nil, nil, fmt.Errorf("package %s resolved to both %s and %s; install the packages separately", pkg.Repo, existing.Version, pkg.Version)
+				}
+				continue
+			}
+			installed[pkg.Repo] = pkg
+
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Resolved package %s@%s (%d workflow(s))", pkg.Repo, pkg.Version, len(pkg.Manifest.Workflows))))
+			for _, workflowPath := range pkg.Manifest.Workflows {
+				workflowSpec := fmt.Sprintf("%s/%s@%s", pkg.Repo, workflowPath, pkg.Version)
+				expanded = append(expanded, workflowSpec)
+				packagesBySpec[workflowSpec] = pkg
+			}
+		}
+	}
+
+	return expanded, packagesBySpec, nil
+}
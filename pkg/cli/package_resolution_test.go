@@ -0,0 +1,96 @@
+//go:build !integration
+
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPackageResolver creates a resolver over in-memory tags and manifests keyed by repo@tag
+func newTestPackageResolver(tags map[string][]string, manifests map[string]*PackageManifest) *packageResolver {
+	return &packageResolver{
+		listTags: func(repo string) ([]string, error) {
+			return tags[repo], nil
+		},
+		fetchManifest: func(repo, ref string) (*PackageManifest, error) {
+			manifest, ok := manifests[repo+"@"+ref]
+			if !ok {
+				return nil, fmt.Errorf("no manifest for %s@%s", repo, ref)
+			}
+			return manifest, nil
+		},
+	}
+}
+
+func TestPackageResolverResolve(t *testing.T) {
+	tags := map[string][]string{
+		"octo/pack": {"v1.1.0", "v1.2.0", "v1.3.0", "v2.0.0"},
+		"octo/base": {"v2.0.0", "v2.1.0", "v2.4.0", "v3.0.0"},
+	}
+	manifests := map[string]*PackageManifest{
+		"octo/pack@v1.3.0": {Name: "pack", Workflows: []string{"workflows/triage.md"}, Dependencies: map[string]string{"octo/base": "~2.1"}},
+		"octo/pack@main":   {Name: "pack", Workflows: []string{"workflows/triage.md"}, Dependencies: map[string]string{"octo/base": "^2.0"}},
+		"octo/base@v2.1.0": {Name: "base", Workflows: []string{"workflows/base.md"}},
+		"octo/base@v2.4.0": {Name: "base", Workflows: []string{"workflows/base.md"}},
+	}
+
+	t.Run("range with dependency", func(t *testing.T) {
+		packages, err := newTestPackageResolver(tags, manifests).resolve("octo/pack", "^1.2")
+		require.NoError(t, err)
+		require.Len(t, packages, 2)
+		assert.Equal(t, "octo/pack", packages[0].Repo)
+		assert.Equal(t, "v1.3.0", packages[0].Version)
+		assert.Equal(t, "^1.2", packages[0].Range)
+		assert.Equal(t, "octo/base", packages[1].Repo)
+		assert.Equal(t, "v2.1.0", packages[1].Version, "dependency should resolve within its range")
+		assert.Equal(t, "~2.1", packages[1].Range)
+	})
+
+	t.Run("branch ref installs without a range", func(t *testing.T) {
+		packages, err := newTestPackageResolver(tags, manifests).resolve("octo/pack", "main")
+		require.NoError(t, err)
+		require.Len(t, packages, 2)
+		assert.Equal(t, "main", packages[0].Version)
+		assert.Empty(t, packages[0].Range)
+		assert.Equal(t, "v2.4.0", packages[1].Version)
+	})
+
+	t.Run("unsatisfiable range", func(t *testing.T) {
+		_, err := newTestPackageResolver(tags, manifests).resolve("octo/pack", "^4")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no release of octo/pack satisfies ^4")
+	})
+}
+
+func TestPackageResolverConflictingDependencies(t *testing.T) {
+	tags := map[string][]string{
+		"octo/pack":  {"v1.0.0"},
+		"octo/other": {"v1.0.0"},
+		"octo/base":  {"v1.0.0", "v2.0.0"},
+	}
+	manifests := map[string]*PackageManifest{
+		"octo/pack@v1.0.0":  {Name: "pack", Workflows: []string{"a.md"}, Dependencies: map[string]string{"octo/base": "^2", "octo/other": "^1"}},
+		"octo/other@v1.0.0": {Name: "other", Workflows: []string{"b.md"}, Dependencies: map[string]string{"octo/base": "^1"}},
+		"octo/base@v1.0.0":  {Name: "base", Workflows: []string{"c.md"}},
+		"octo/base@v2.0.0":  {Name: "base", Workflows: []string{"c.md"}},
+	}
+
+	_, err := newTestPackageResolver(tags, manifests).resolve("octo/pack", "^1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no release of octo/base satisfies")
+	assert.Contains(t, err.Error(), "required by octo/other@v1.0.0")
+}
+
+func TestCombinedRange(t *testing.T) {
+	constraint := func(spec string) packageConstraint {
+		return packageConstraint{rangeSpec: spec}
+	}
+	assert.Equal(t, "*", combinedRange([]packageConstraint{constraint("")}, "v1.0.0"))
+	assert.Equal(t, "^1.2", combinedRange([]packageConstraint{constraint("^1.2")}, "v1.3.0"))
+	assert.Equal(t, "^1.2 <1.5", combinedRange([]packageConstraint{constraint("^1.2"), constraint("<1.5")}, "v1.4.0"))
+	assert.Equal(t, "v1.4.0", combinedRange([]packageConstraint{constraint("^1.2 || ^2"), constraint("<1.5")}, "v1.4.0"))
+}
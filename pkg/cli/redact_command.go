@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/redact"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+var redactCommandLog = logger.New("cli:redact_command")
+
+// redactionReportFileName is the name of the report written at the root of each redacted directory
+const redactionReportFileName = "redaction-report.json"
+
+// Names of the redaction rules added on top of the secret detectors of the redact package
+const (
+	redactRuleEmails    = "emails"
+	redactRuleHostnames = "hostnames"
+	redactRuleUsernames = "usernames"
+	redactRulePatterns  = "patterns"
+)
+
+// binaryDetectionSize is the number of leading bytes checked for NUL bytes to detect binary files
+const binaryDetectionSize = 8000
+
+// RedactionRules configures what is redacted from downloaded run directories
+type RedactionRules struct {
+	Detectors []string           `yaml:"detectors,omitempty"` // Secret detectors (nil for the defaults, empty for none)
+	Emails    *bool              `yaml:"emails,omitempty"`    // Redact email addresses (default: true)
+	Hostnames []string           `yaml:"hostnames,omitempty"` // Internal domains, redacted together with their subdomains
+	Usernames []string           `yaml:"usernames,omitempty"` // User names and @handles
+	Patterns  []RedactionPattern `yaml:"patterns,omitempty"`  // Additional regular expressions
+}
+
+// RedactionPattern is a custom redaction rule
+type RedactionPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
+// RedactOptions holds the options of the redact command and of logs --redact
+type RedactOptions struct {
+	RulesFile  string   // YAML file with redaction rules
+	Detectors  string   // Comma-separated secret detectors overriding the rules file
+	Hostnames  []string // Internal domains added to the rules
+	Usernames  []string // User names added to the rules
+	NoEmails   bool     // Keep email addresses
+	DryRun     bool     // Report what would be redacted without rewriting files
+	JSONOutput bool
+	Verbose    bool
+}
+
+// RedactionReport summarizes the redaction of a directory. It never contains redacted values.
+type RedactionReport struct {
+	Directory     string         `json:"directory"`
+	GeneratedAt   string         `json:"generated_at"`
+	DryRun        bool           `json:"dry_run,omitempty"`
+	Rules         []string       `json:"rules"`
+	FilesScanned  int            `json:"files_scanned"`
+	FilesRedacted int            `json:"files_redacted"`
+	Redactions    int            `json:"redactions"`
+	ByRule        map[string]int `json:"by_rule"`
+	Files         []RedactedFile `json:"files,omitempty"`
+	SkippedBinary []string       `json:"skipped_binary,omitempty"`
+}
+
+// RedactedFile lists the redactions made in one file
+type RedactedFile struct {
+	Path       string         `json:"path"`
+	Redactions int            `json:"redactions"`
+	ByRule     map[string]int `json:"by_rule"`
+}
+
+// NewRedactCommand creates the redact command
+func NewRedactCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redact <directory>...",
+		Short: "Redact secrets and personal data from downloaded logs before sharing them",
+		Long: `Rewrite downloaded run directories (from the logs and audit commands) so they can be shared
+outside your organization, and write a redaction report to each directory.
+
+By default, tokens in well-known formats (GitHub, cloud and API keys, JWTs, private keys) and
+email addresses are redacted. Internal hostnames, user names and custom patterns can be added
+with flags or a rules file:
+
+  detectors: [github-tokens, cloud-keys, api-keys, jwt, private-keys, entropy]
+  emails: true
+  hostnames: [corp.example.com]
+  usernames: [octocat]
+  patterns:
+    - name: Ticket ID
+      regex: 'TICKET-[0-9]+'
+
+Binary files are left untouched and listed in the report. The report counts redactions per
+rule and per file and never contains the redacted values.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` redact .github/aw/logs/run-123456
+  ` + string(constants.CLIExtensionPrefix) + ` redact logs/ --hostname corp.example.com --username octocat
+  ` + string(constants.CLIExtensionPrefix) + ` redact logs/ --rules redaction.yml --dry-run
+  ` + string(constants.CLIExtensionPrefix) + ` redact logs/ --detectors github-tokens,entropy --no-emails`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := redactOptionsFromFlags(cmd, "rules")
+			opts.Detectors, _ = cmd.Flags().GetString("detectors")
+			opts.Hostnames, _ = cmd.Flags().GetStringArray("hostname")
+			opts.Usernames, _ = cmd.Flags().GetStringArray("username")
+			opts.NoEmails, _ = cmd.Flags().GetBool("no-emails")
+			opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+			opts.JSONOutput, _ = cmd.Flags().GetBool("json")
+			return RunRedact(args, opts)
+		},
+	}
+
+	cmd.Flags().String("rules", "", "YAML file with redaction rules")
+	cmd.Flags().String("detectors", "", "Comma-separated secret detectors: "+strings.Join(redact.BuiltinDetectorNames(), ", ")+" (default: all but entropy)")
+	cmd.Flags().StringArray("hostname", nil, "Internal domain to redact together with its subdomains (can be repeated)")
+	cmd.Flags().StringArray("username", nil, "User name to redact (can be repeated)")
+	cmd.Flags().Bool("no-emails", false, "Keep email addresses")
+	cmd.Flags().Bool("dry-run", false, "Report what would be redacted without rewriting files")
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// redactOptionsFromFlags reads the rules file flag and the global verbose flag of a command
+func redactOptionsFromFlags(cmd *cobra.Command, rulesFlag string) RedactOptions {
+	rulesFile, _ := cmd.Flags().GetString(rulesFlag)
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return RedactOptions{RulesFile: rulesFile, Verbose: verbose}
+}
+
+// RunRedact redacts every directory and prints one report per directory
+func RunRedact(dirs []string, opts RedactOptions) error {
+	rules, err := loadRedactionRules(opts)
+	if err != nil {
+		return err
+	}
+	redactor, ruleNames, err := buildRedactor(rules)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*RedactionReport, 0, len(dirs))
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("cannot redact %s: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("cannot redact %s: not a directory", dir)
+		}
+
+		report, err := redactDirectory(dir, redactor, ruleNames, opts.DryRun, opts.Verbose)
+		if err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			if err := writeRedactionReport(dir, report); err != nil {
+				return err
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	if opts.JSONOutput {
+		output, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal redaction report: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	for _, report := range reports {
+		renderRedactionReport(report)
+	}
+	return nil
+}
+
+// loadRedactionRules reads the rules file, if any, and applies the command-line overrides
+func loadRedactionRules(opts RedactOptions) (*RedactionRules, error) {
+	rules := &RedactionRules{}
+	if opts.RulesFile != "" {
+		content, err := os.ReadFile(opts.RulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redaction rules: %w", err)
+		}
+		if err := yaml.Unmarshal(content, rules); err != nil {
+			return nil, fmt.Errorf("failed to parse redaction rules %s: %w", opts.RulesFile, err)
+		}
+		redactCommandLog.Printf("Loaded redaction rules from %s", opts.RulesFile)
+	}
+
+	if opts.Detectors != "" {
+		rules.Detectors = nil
+		for name := range strings.SplitSeq(opts.Detectors, ",") {
+			if name = strings.TrimSpace(name); name != "" && name != "none" {
+				rules.Detectors = append(rules.Detectors, name)
+			}
+		}
+		if rules.Detectors == nil {
+			rules.Detectors = []string{}
+		}
+	}
+	rules.Hostnames = append(rules.Hostnames, opts.Hostnames...)
+	rules.Usernames = append(rules.Usernames, opts.Usernames...)
+	if opts.NoEmails {
+		noEmails := false
+		rules.Emails = &noEmails
+	}
+	return rules, nil
+}
+
+// buildRedactor creates a redactor applying the rules and returns the names of the applied rules
+func buildRedactor(rules *RedactionRules) (*redact.Redactor, []string, error) {
+	detectorNames := rules.Detectors
+	if detectorNames == nil {
+		detectorNames = redact.DefaultDetectorNames()
+	}
+	detectors, err := redact.DetectorsByName(detectorNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	ruleNames := append([]string{}, detectorNames...)
+
+	if rules.Emails == nil || *rules.Emails {
+		detectors = append(detectors, redact.NewPatternDetector(redactRuleEmails,
+			redact.Pattern{Name: "Email Address", Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)}))
+		ruleNames = append(ruleNames, redactRuleEmails)
+	}
+
+	if hostnames := quotedAlternatives(rules.Hostnames, func(h string) string { return strings.TrimPrefix(strings.TrimPrefix(h, "*."), ".") }); hostnames != "" {
+		detectors = append(detectors, redact.NewPatternDetector(redactRuleHostnames,
+			redact.Pattern{Name: "Internal Hostname", Regexp: regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)*(?:` + hostnames + `)\b`)}))
+		ruleNames = append(ruleNames, redactRuleHostnames)
+	}
+
+	if usernames := quotedAlternatives(rules.Usernames, func(u string) string { return strings.TrimPrefix(u, "@") }); usernames != "" {
+		detectors = append(detectors, redact.NewPatternDetector(redactRuleUsernames,
+			redact.Pattern{Name: "Username", Regexp: regexp.MustCompile(`(?i)(?:@|\b)(?:` + usernames + `)\b`)}))
+		ruleNames = append(ruleNames, redactRuleUsernames)
+	}
+
+	if len(rules.Patterns) > 0 {
+		patterns := make([]redact.Pattern, 0, len(rules.Patterns))
+		for _, p := range rules.Patterns {
+			if p.Name == "" || p.Regex == "" {
+				return nil, nil, errors.New("redaction patterns require a name and a regex")
+			}
+			re, err := regexp.Compile(p.Regex)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid regex of redaction pattern '%s': %w", p.Name, err)
+			}
+			patterns = append(patterns, redact.Pattern{Name: p.Name, Regexp: re})
+		}
+		detectors = append(detectors, redact.NewPatternDetector(redactRulePatterns, patterns...))
+		ruleNames = append(ruleNames, redactRulePatterns)
+	}
+
+	redactCommandLog.Printf("Redacting with rules: %s", strings.Join(ruleNames, ", "))
+	return redact.New(detectors...), ruleNames, nil
+}
+
+// quotedAlternatives normalizes and quotes values for a regular expression alternation,
+// longest first so that longer values win over their prefixes
+func quotedAlternatives(values []string, normalize func(string) string) string {
+	var quoted []string
+	for _, value := range values {
+		if value = normalize(strings.TrimSpace(value)); value != "" {
+			quoted = append(quoted, regexp.QuoteMeta(value))
+		}
+	}
+	sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return strings.Join(quoted, "|")
+}
+
+// redactDirectory redacts the text files of a directory in place
+func redactDirectory(dir string, redactor *redact.Redactor, ruleNames []string, dryRun, verbose bool) (*RedactionReport, error) {
+	redactCommandLog.Printf("Redacting directory %s (dry run: %t)", dir, dryRun)
+	report := &RedactionReport{
+		Directory:   dir,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DryRun:      dryRun,
+		Rules:       ruleNames,
+		ByRule:      make(map[string]int),
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == redactionReportFileName {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if bytes.IndexByte(content[:min(len(content), binaryDetectionSize)], 0) >= 0 {
+			report.SkippedBinary = append(report.SkippedBinary, rel)
+			return nil
+		}
+		report.FilesScanned++
+
+		redacted, matches := redactor.Redact(string(content))
+		if len(matches) == 0 {
+			return nil
+		}
+		counts := redact.CountByDetector(matches)
+		report.FilesRedacted++
+		report.Redactions += len(matches)
+		for rule, count := range counts {
+			report.ByRule[rule] += count
+		}
+		report.Files = append(report.Files, RedactedFile{Path: rel, Redactions: len(matches), ByRule: counts})
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("%s: %d redaction(s)", rel, len(matches))))
+		}
+
+		if dryRun {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(redacted), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// writeRedactionReport writes the report at the root of the redacted directory
+func writeRedactionReport(dir string, report *RedactionReport) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction report: %w", err)
+	}
+	path := filepath.Join(dir, redactionReportFileName)
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write redaction report: %w", err)
+	}
+	return nil
+}
+
+// renderRedactionReport prints a summary of a redaction report
+func renderRedactionReport(report *RedactionReport) {
+	verb := "Redacted"
+	if report.DryRun {
+		verb = "Would redact"
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s %d occurrence(s) in %d of %d file(s) in %s",
+		verb, report.Redactions, report.FilesRedacted, report.FilesScanned, report.Directory)))
+	if len(report.SkippedBinary) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipped %d binary file(s), review them before sharing", len(report.SkippedBinary))))
+	}
+	if len(report.ByRule) > 0 {
+		rules := make([]string, 0, len(report.ByRule))
+		for rule := range report.ByRule {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+		rows := make([][]string, 0, len(rules))
+		for _, rule := range rules {
+			rows = append(rows, []string{rule, fmt.Sprintf("%d", report.ByRule[rule])})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{Headers: []string{"Rule", "Redactions"}, Rows: rows}))
+	}
+	if !report.DryRun {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Report written to "+filepath.Join(report.Directory, redactionReportFileName)))
+	}
+}
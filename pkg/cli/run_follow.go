@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var runFollowLog = logger.New("cli:run_follow")
+
+// runFollowPollInterval is the interval between job status checks while following a run
+const runFollowPollInterval = 5 * time.Second
+
+// runFollowTimeout bounds how long a run is followed; it matches the maximum duration of
+// a GitHub Actions job so a run stuck in a queue does not keep the command attached forever
+const runFollowTimeout = 6 * time.Hour
+
+// runFollowMaxConsecutiveErrors is the number of consecutive failed API polls after which
+// following stops with an error
+const runFollowMaxConsecutiveErrors = 5
+
+// runLogTimestampPattern matches the timestamp GitHub Actions prefixes to every log line
+var runLogTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z ?`)
+
+// followedJob is a job of a followed run as returned by the jobs API
+type followedJob struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Conclusion  string         `json:"conclusion"`
+	StartedAt   time.Time      `json:"started_at,omitzero"`
+	CompletedAt time.Time      `json:"completed_at,omitzero"`
+	Steps       []followedStep `json:"steps"`
+}
+
+// followedStep is a step of a followed job
+type followedStep struct {
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Conclusion  string    `json:"conclusion"`
+	Number      int       `json:"number"`
+	StartedAt   time.Time `json:"started_at,omitzero"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+}
+
+// runFollower prints the progress of a workflow run: job and step transitions for every
+// job and the log of the agent job. The agent log is fetched on every poll while the job
+// runs and only the lines not printed yet are shown; when GitHub does not serve the log
+// of a running job yet, its lines are printed once the job completes.
+type runFollower struct {
+	out     io.Writer
+	verbose bool
+
+	fetchJobs   func() ([]followedJob, error)
+	fetchRun    func() (status string, conclusion string, err error)
+	fetchJobLog func(jobID int64) (string, error)
+
+	jobs              map[int64]followedJob // Last seen state of each job
+	logLines          map[int64]int         // Number of log lines already printed per job
+	consecutiveErrors int                   // Number of API polls that failed in a row
+
+	status     string
+	conclusion string
+}
+
+// newRunFollower creates a follower for a run in repo (owner/repo)
+func newRunFollower(repo string, runID int64, verbose bool) *runFollower {
+	runPath := fmt.Sprintf("repos/%s/actions/runs/%d", repo, runID)
+	return &runFollower{
+		out:     os.Stderr,
+		verbose: verbose,
+		fetchJobs: func() ([]followedJob, error) {
+			output, err := workflow.ExecGH("api", runPath+"/jobs?per_page=100").Output()
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+			}
+			var response struct {
+				Jobs []followedJob `json:"jobs"`
+			}
+			if err := json.Unmarshal(output, &response); err != nil {
+				return nil, fmt.Errorf("failed to parse jobs: %w", err)
+			}
+			return response.Jobs, nil
+		},
+		fetchRun: func() (string, string, error) {
+			output, err := workflow.ExecGH("api", runPath, "--jq", "{status: .status, conclusion: .conclusion}").Output()
+			if err != nil {
+				return "", "", fmt.Errorf("failed to fetch run status: %w", err)
+			}
+			var run struct {
+				Status     string `json:"status"`
+				Conclusion string `json:"conclusion"`
+			}
+			if err := json.Unmarshal(output, &run); err != nil {
+				return "", "", fmt.Errorf("failed to parse run status: %w", err)
+			}
+			return run.Status, run.Conclusion, nil
+		},
+		fetchJobLog: func(jobID int64) (string, error) {
+			output, err := workflow.ExecGH("api", fmt.Sprintf("repos/%s/actions/jobs/%d/logs", repo, jobID)).Output()
+			if err != nil {
+				return "", err
+			}
+			return string(output), nil
+		},
+		jobs:     make(map[int64]followedJob),
+		logLines: make(map[int64]int),
+	}
+}
+
+// followWorkflowRun streams the progress of a run until it completes, renders the items
+// created by safe outputs and returns an error unless the run concluded successfully
+func followWorkflowRun(repo string, runID int64, verbose bool) error {
+	runFollowLog.Printf("Following run: repo=%s, runID=%d", repo, runID)
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Following run %d (Ctrl-C stops following, the run continues)", runID)))
+
+	follower := newRunFollower(repo, runID, verbose)
+	err := PollWithSignalHandling(PollOptions{
+		PollInterval: runFollowPollInterval,
+		Timeout:      runFollowTimeout,
+		PollFunc:     follower.poll,
+		Verbose:      verbose,
+	})
+	if err != nil {
+		return fmt.Errorf("stopped following run %d: %w", runID, err)
+	}
+
+	renderFollowedSafeOutputs(repo, runID, verbose)
+	return follower.result(runID)
+}
+
+// poll fetches the current job states, prints what changed and reports whether the
+// run has completed
+func (f *runFollower) poll() (PollResult, error) {
+	jobs, err := f.fetchJobs()
+	if err != nil {
+		return f.pollError(err)
+	}
+	for _, job := range jobs {
+		f.updateJob(job)
+	}
+
+	status, conclusion, err := f.fetchRun()
+	if err != nil {
+		return f.pollError(err)
+	}
+	f.consecutiveErrors = 0
+	if status != "completed" {
+		return PollContinue, nil
+	}
+
+	// Pick up jobs that completed between the two requests
+	if jobs, err := f.fetchJobs(); err == nil {
+		for _, job := range jobs {
+			f.updateJob(job)
+		}
+	}
+	f.status, f.conclusion = status, conclusion
+	return PollSuccess, nil
+}
+
+// pollError tolerates transient API errors and stops following once too many polls
+// failed in a row
+func (f *runFollower) pollError(err error) (PollResult, error) {
+	f.consecutiveErrors++
+	runFollowLog.Printf("Poll failed (%d in a row): %v", f.consecutiveErrors, err)
+	if f.consecutiveErrors >= runFollowMaxConsecutiveErrors {
+		return PollFailure, fmt.Errorf("%d consecutive API errors: %w", f.consecutiveErrors, err)
+	}
+	console.LogVerbose(f.verbose, err.Error())
+	return PollContinue, nil
+}
+
+// updateJob prints the transitions of a job since it was last seen
+func (f *runFollower) updateJob(job followedJob) {
+	previous, seen := f.jobs[job.ID]
+	f.jobs[job.ID] = job
+	label := followedJobLabel(job.Name)
+
+	if job.Status != "queued" && job.Status != "waiting" && (!seen || previous.Status == "queued" || previous.Status == "waiting") {
+		fmt.Fprintln(f.out, console.FormatProgressMessage(label+" started"))
+	}
+
+	previousSteps := make(map[int]followedStep, len(previous.Steps))
+	for _, step := range previous.Steps {
+		previousSteps[step.Number] = step
+	}
+	for _, step := range job.Steps {
+		if step.Status != "completed" || previousSteps[step.Number].Status == "completed" {
+			continue
+		}
+		if step.Conclusion == "skipped" && !f.verbose {
+			continue
+		}
+		fmt.Fprintln(f.out, formatFollowedStep(job.Name, step))
+	}
+
+	if job.Name == string(constants.AgentJobName) && job.Status == "in_progress" {
+		f.printNewLogLines(job, false)
+	}
+
+	if job.Status == "completed" && previous.Status != "completed" {
+		if job.Name == string(constants.AgentJobName) {
+			f.printNewLogLines(job, true)
+		}
+		message := fmt.Sprintf("%s %s", label, job.Conclusion)
+		if !job.StartedAt.IsZero() && !job.CompletedAt.IsZero() {
+			message += fmt.Sprintf(" (%s)", job.CompletedAt.Sub(job.StartedAt).Round(time.Second))
+		}
+		switch job.Conclusion {
+		case "success":
+			fmt.Fprintln(f.out, console.FormatSuccessMessage(message))
+		case "skipped":
+			console.LogVerbose(f.verbose, message)
+		default:
+			fmt.Fprintln(f.out, console.FormatErrorMessage(message))
+		}
+	}
+}
+
+// printNewLogLines prints the lines of a job log that have not been printed yet. While
+// the job runs, a trailing partial line is held back until it is complete. A missing log
+// does not stop following.
+func (f *runFollower) printNewLogLines(job followedJob, completed bool) {
+	content, err := f.fetchJobLog(job.ID)
+	if err != nil {
+		runFollowLog.Printf("Log of job %d not available: %v", job.ID, err)
+		if completed {
+			console.LogVerbose(f.verbose, fmt.Sprintf("Log of job %s not available: %v", job.Name, err))
+		}
+		return
+	}
+
+	if !completed {
+		// Only print complete lines of a running job
+		end := strings.LastIndex(content, "\n")
+		if end < 0 {
+			return
+		}
+		content = content[:end]
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	printed := f.logLines[job.ID]
+	if printed > len(lines) {
+		return
+	}
+	for _, line := range lines[printed:] {
+		if formatted, ok := formatFollowedLogLine(line); ok {
+			fmt.Fprintln(f.out, formatted)
+		}
+	}
+	f.logLines[job.ID] = len(lines)
+}
+
+// result returns an error unless the run concluded successfully
+func (f *runFollower) result(runID int64) error {
+	if f.conclusion == "success" {
+		fmt.Fprintln(f.out, console.FormatSuccessMessage(fmt.Sprintf("Run %d completed successfully", runID)))
+		return nil
+	}
+	fmt.Fprintln(f.out, console.FormatInfoMessage(fmt.Sprintf("💡 To analyze this run, use: %s audit %d", string(constants.CLIExtensionPrefix), runID)))
+	conclusion := f.conclusion
+	if conclusion == "" {
+		conclusion = "unknown"
+	}
+	return fmt.Errorf("workflow run %d concluded with %s", runID, conclusion)
+}
+
+// followedJobLabel describes a job by its role in an agentic workflow
+func followedJobLabel(name string) string {
+	switch name {
+	case string(constants.PreActivationJobName), string(constants.ActivationJobName):
+		return "activation (" + name + ")"
+	case string(constants.AgentJobName):
+		return "agent"
+	case string(constants.DetectionJobName):
+		return "threat detection"
+	case "safe_outputs":
+		return "safe outputs"
+	default:
+		return name
+	}
+}
+
+// formatFollowedStep formats a completed step
+func formatFollowedStep(jobName string, step followedStep) string {
+	text := jobName + " › " + step.Name
+	if !step.StartedAt.IsZero() && !step.CompletedAt.IsZero() {
+		if d := step.CompletedAt.Sub(step.StartedAt).Round(time.Second); d > 0 {
+			text += " (" + d.String() + ")"
+		}
+	}
+	switch step.Conclusion {
+	case "success":
+		return "  ✓ " + text
+	case "skipped":
+		return "  - " + text + " (skipped)"
+	default:
+		return "  ✗ " + text + " (" + step.Conclusion + ")"
+	}
+}
+
+// formatFollowedLogLine strips the timestamp from a job log line and drops workflow
+// command markers. Errors and warnings are highlighted.
+func formatFollowedLogLine(line string) (string, bool) {
+	line = strings.TrimRight(runLogTimestampPattern.ReplaceAllString(line, ""), "\r")
+	switch {
+	case strings.HasPrefix(line, "##[group]"):
+		return console.FormatVerboseMessage(strings.TrimPrefix(line, "##[group]")), true
+	case strings.HasPrefix(line, "##[endgroup]"), strings.HasPrefix(line, "##[debug]"):
+		return "", false
+	case strings.HasPrefix(line, "##[error]"):
+		return console.FormatErrorMessage(strings.TrimPrefix(line, "##[error]")), true
+	case strings.HasPrefix(line, "##[warning]"):
+		return console.FormatWarningMessage(strings.TrimPrefix(line, "##[warning]")), true
+	case strings.TrimSpace(line) == "":
+		return "", false
+	default:
+		return "    " + line, true
+	}
+}
+
+// renderFollowedSafeOutputs downloads the run artifacts and lists the items created by
+// safe outputs
+func renderFollowedSafeOutputs(repo string, runID int64, verbose bool) {
+	outputDir, err := os.MkdirTemp("", "gh-aw-run-"+strconv.FormatInt(runID, 10)+"-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(outputDir)
+
+	owner, name, _ := strings.Cut(repo, "/")
+	if err := downloadRunArtifacts(runID, outputDir, verbose, owner, name, ""); err != nil {
+		runFollowLog.Printf("Failed to download artifacts of run %d: %v", runID, err)
+		console.LogVerbose(verbose, fmt.Sprintf("Could not download run artifacts: %v", err))
+		return
+	}
+
+	items := extractCreatedItemsFromManifest(outputDir)
+	if len(items) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No items were created by safe outputs"))
+		return
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Safe outputs created %d item(s):", len(items))))
+	renderCreatedItemsTable(items)
+}
@@ -0,0 +1,196 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowedJobLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"pre_activation", "activation (pre_activation)"},
+		{"activation", "activation (activation)"},
+		{"agent", "agent"},
+		{"detection", "threat detection"},
+		{"safe_outputs", "safe outputs"},
+		{"conclusion", "conclusion"},
+		{"custom_job", "custom_job"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, followedJobLabel(tt.name), "label should describe the job's role")
+		})
+	}
+}
+
+func TestFormatFollowedLogLine(t *testing.T) {
+	line, ok := formatFollowedLogLine("2026-01-02T03:04:05.1234567Z Analyzing issue #12")
+	require.True(t, ok, "plain lines should be printed")
+	assert.Equal(t, "    Analyzing issue #12", line, "timestamp should be stripped")
+
+	_, ok = formatFollowedLogLine("2026-01-02T03:04:05.1234567Z ##[endgroup]")
+	assert.False(t, ok, "endgroup markers should be dropped")
+
+	_, ok = formatFollowedLogLine("2026-01-02T03:04:05.1234567Z ")
+	assert.False(t, ok, "blank lines should be dropped")
+
+	line, ok = formatFollowedLogLine("2026-01-02T03:04:05Z ##[error]Process completed with exit code 1.")
+	require.True(t, ok, "error lines should be printed")
+	assert.Contains(t, line, "Process completed with exit code 1.", "error message should be kept")
+	assert.NotContains(t, line, "##[error]", "error marker should be removed")
+}
+
+func TestRunFollowerPrintsTransitionsAndStreamsAgentLog(t *testing.T) {
+	polls := [][]followedJob{
+		{
+			{ID: 1, Name: "activation", Status: "completed", Conclusion: "success", Steps: []followedStep{
+				{Number: 1, Name: "Check permissions", Status: "completed", Conclusion: "success"},
+			}},
+			{ID: 2, Name: "agent", Status: "in_progress", Steps: []followedStep{
+				{Number: 1, Name: "Checkout", Status: "completed", Conclusion: "success"},
+				{Number: 2, Name: "Execute agent", Status: "in_progress"},
+			}},
+		},
+		{
+			{ID: 1, Name: "activation", Status: "completed", Conclusion: "success", Steps: []followedStep{
+				{Number: 1, Name: "Check permissions", Status: "completed", Conclusion: "success"},
+			}},
+			{ID: 2, Name: "agent", Status: "completed", Conclusion: "success", Steps: []followedStep{
+				{Number: 1, Name: "Checkout", Status: "completed", Conclusion: "success"},
+				{Number: 2, Name: "Execute agent", Status: "completed", Conclusion: "success"},
+			}},
+			{ID: 3, Name: "detection", Status: "completed", Conclusion: "failure"},
+		},
+	}
+	runStatuses := []string{"in_progress", "completed"}
+
+	var out bytes.Buffer
+	poll, logCall := 0, 0
+	f := &runFollower{
+		out: &out,
+		fetchJobs: func() ([]followedJob, error) {
+			return polls[min(poll, len(polls)-1)], nil
+		},
+		fetchRun: func() (string, string, error) {
+			status := runStatuses[poll]
+			poll++
+			if status == "completed" {
+				return status, "failure", nil
+			}
+			return status, "", nil
+		},
+		fetchJobLog: func(jobID int64) (string, error) {
+			assert.Equal(t, int64(2), jobID, "only the agent job log should be fetched")
+			logCall++
+			if logCall == 1 {
+				return "2026-01-02T03:04:05Z first line\n2026-01-02T03:04:06Z second", nil
+			}
+			return "2026-01-02T03:04:05Z first line\n2026-01-02T03:04:06Z second line\n2026-01-02T03:04:07Z third line\n", nil
+		},
+		jobs:     make(map[int64]followedJob),
+		logLines: make(map[int64]int),
+	}
+
+	result, err := f.poll()
+	require.NoError(t, err, "first poll should succeed")
+	assert.Equal(t, PollContinue, result, "run in progress should continue polling")
+	assert.Contains(t, out.String(), "first line", "complete lines of the running agent job should be streamed")
+	assert.NotContains(t, out.String(), "second", "a partial line should be held back")
+
+	result, err = f.poll()
+	require.NoError(t, err, "second poll should succeed")
+	assert.Equal(t, PollSuccess, result, "completed run should stop polling")
+
+	output := out.String()
+	assert.Equal(t, 2, logCall, "the agent log should be fetched while running and once after completion")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("third line")), "new lines should be printed on completion")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("first line")), "log lines should be printed once")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("second line")), "every log line should be printed")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("agent › Checkout")), "completed steps should be printed once")
+	assert.Contains(t, output, "agent › Execute agent", "step completion should be printed")
+	assert.Contains(t, output, "threat detection failure", "failed detection job should be reported")
+
+	err = f.result(123)
+	require.Error(t, err, "failed run should return an error")
+	assert.Contains(t, err.Error(), "concluded with failure", "error should carry the run conclusion")
+}
+
+func TestRunFollowerToleratesAPIErrors(t *testing.T) {
+	f := &runFollower{
+		out:       &bytes.Buffer{},
+		fetchJobs: func() ([]followedJob, error) { return nil, errors.New("rate limited") },
+		jobs:      make(map[int64]followedJob),
+	}
+	for range runFollowMaxConsecutiveErrors - 1 {
+		result, err := f.poll()
+		require.NoError(t, err, "transient errors should not stop following")
+		assert.Equal(t, PollContinue, result, "polling should continue after an API error")
+	}
+
+	result, err := f.poll()
+	assert.Equal(t, PollFailure, result, "too many consecutive errors should stop following")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limited", "error should carry the last API error")
+}
+
+func TestRunFollowerResetsErrorCountAfterSuccess(t *testing.T) {
+	fail := true
+	f := &runFollower{
+		out: &bytes.Buffer{},
+		fetchJobs: func() ([]followedJob, error) {
+			if fail {
+				return nil, errors.New("rate limited")
+			}
+			return nil, nil
+		},
+		fetchRun: func() (string, string, error) { return "in_progress", "", nil },
+		jobs:     make(map[int64]followedJob),
+	}
+	for range runFollowMaxConsecutiveErrors - 1 {
+		_, err := f.poll()
+		require.NoError(t, err)
+	}
+	fail = false
+	_, err := f.poll()
+	require.NoError(t, err)
+	fail = true
+	result, err := f.poll()
+	require.NoError(t, err, "a successful poll should reset the error count")
+	assert.Equal(t, PollContinue, result)
+}
+
+func TestRunFollowerResultSuccess(t *testing.T) {
+	f := &runFollower{out: &bytes.Buffer{}, conclusion: "success"}
+	assert.NoError(t, f.result(1), "successful run should not return an error")
+}
+
+func TestRunFollowerWaitsForLogOfRunningJob(t *testing.T) {
+	var out bytes.Buffer
+	available := false
+	f := &runFollower{
+		out: &out,
+		fetchJobLog: func(int64) (string, error) {
+			if !available {
+				return "", errors.New("HTTP 404: Not Found")
+			}
+			return "2026-01-02T03:04:05Z only line\n", nil
+		},
+		jobs:     make(map[int64]followedJob),
+		logLines: make(map[int64]int),
+	}
+
+	f.updateJob(followedJob{ID: 2, Name: "agent", Status: "in_progress"})
+	assert.NotContains(t, out.String(), "only line", "nothing should be printed while the log is not served")
+
+	available = true
+	f.updateJob(followedJob{ID: 2, Name: "agent", Status: "completed", Conclusion: "success"})
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("only line")), "the log should be printed once the job completed")
+}
@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var runMatrixLog = logger.New("cli:run_matrix")
+
+// Reserved matrix axes. Any other axis name is a workflow input.
+const (
+	runMatrixAxisEngine = "engine"
+	runMatrixAxisModel  = "model"
+	runMatrixAxisPreset = "preset"
+)
+
+// maxRunMatrixCombinations bounds the number of runs a matrix can dispatch
+const maxRunMatrixCombinations = 50
+
+// runMatrixWaitTimeoutMinutes bounds the time spent waiting for each matrix run
+const runMatrixWaitTimeoutMinutes = 60
+
+// RunMatrixAxis is one dimension of a matrix run, e.g. engine=copilot,claude
+type RunMatrixAxis struct {
+	Name   string
+	Values []string
+}
+
+// runMatrixValue is the value of one axis in a combination
+type runMatrixValue struct {
+	Axis  string
+	Value string
+}
+
+// runMatrixCombination is one run of a matrix
+type runMatrixCombination []runMatrixValue
+
+// RunMatrixResult is the outcome of one matrix run, compared in the summary table
+type RunMatrixResult struct {
+	Combination string  `json:"combination" console:"header:Combination"`
+	RunID       int64   `json:"run_id,omitempty" console:"header:Run ID,omitempty"`
+	Conclusion  string  `json:"conclusion" console:"header:Conclusion"`
+	Duration    string  `json:"duration,omitempty" console:"header:Duration,omitempty"`
+	Tokens      int     `json:"tokens" console:"header:Tokens"`
+	Cost        float64 `json:"cost" console:"header:Cost ($)"`
+	Turns       int     `json:"turns" console:"header:Turns"`
+	SafeItems   int     `json:"safe_items" console:"header:Safe Items"`
+	Error       string  `json:"error,omitempty" console:"-"`
+}
+
+// parseRunMatrixAxes parses --matrix flags in axis=value1,value2 format. The preset
+// axis accepts '*' for all presets of the workflow.
+func parseRunMatrixAxes(specs []string, presets *RunPresetFile) ([]RunMatrixAxis, error) {
+	var axes []RunMatrixAxis
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		name, rawValues, ok := strings.Cut(spec, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid matrix axis '%s': expected axis=value1,value2", spec)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("matrix axis '%s' is specified more than once", name)
+		}
+		seen[name] = true
+
+		var values []string
+		for value := range strings.SplitSeq(rawValues, ",") {
+			if value = strings.TrimSpace(value); value != "" && !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+		if name == runMatrixAxisPreset && slices.Equal(values, []string{"*"}) {
+			values = presets.names()
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix axis '%s' has no values", name)
+		}
+
+		for _, value := range values {
+			switch name {
+			case runMatrixAxisEngine:
+				if err := validateRunEngine(value); err != nil {
+					return nil, err
+				}
+			case runMatrixAxisPreset:
+				if _, ok := presets.Presets[value]; !ok {
+					return nil, fmt.Errorf("matrix preset '%s' not found (available: %s)", value, strings.Join(presets.names(), ", "))
+				}
+			}
+		}
+		axes = append(axes, RunMatrixAxis{Name: name, Values: values})
+	}
+	return axes, nil
+}
+
+// expandRunMatrix returns every combination of the axis values, varying the last axis fastest
+func expandRunMatrix(axes []RunMatrixAxis) ([]runMatrixCombination, error) {
+	combinations := []runMatrixCombination{nil}
+	for _, axis := range axes {
+		var next []runMatrixCombination
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				next = append(next, append(slices.Clone(combination), runMatrixValue{Axis: axis.Name, Value: value}))
+			}
+		}
+		combinations = next
+		if len(combinations) > maxRunMatrixCombinations {
+			return nil, fmt.Errorf("matrix expands to more than %d runs", maxRunMatrixCombinations)
+		}
+	}
+	return combinations, nil
+}
+
+// String describes the combination, e.g. "engine=claude preset=large"
+func (c runMatrixCombination) String() string {
+	parts := make([]string, 0, len(c))
+	for _, value := range c {
+		parts = append(parts, value.Axis+"="+value.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// apply returns the run options for the combination. Inputs from --raw-field apply to
+// every combination; matrix inputs take precedence over them and over presets.
+func (c runMatrixCombination) apply(opts RunOptions, presets *RunPresetFile) RunOptions {
+	opts.Inputs = slices.Clone(opts.Inputs)
+	var inputs []string
+	for _, value := range c {
+		switch value.Axis {
+		case runMatrixAxisEngine:
+			opts.EngineOverride = value.Value
+		case runMatrixAxisModel:
+			opts.ModelOverride = value.Value
+		case runMatrixAxisPreset:
+			opts = applyRunPreset(opts, presets.Presets[value.Value])
+		default:
+			inputs = append(inputs, value.Axis+"="+value.Value)
+		}
+	}
+	opts.Inputs = mergeRunInputs(nil, append(opts.Inputs, inputs...))
+	return opts
+}
+
+// runMatrixRecompiles reports whether any combination changes the engine or model, which requires
+// recompiling and pushing the workflow for each run
+func runMatrixRecompiles(combinations []runMatrixCombination, opts RunOptions, presets *RunPresetFile) bool {
+	for _, combination := range combinations {
+		combined := combination.apply(opts, presets)
+		if combined.EngineOverride != opts.EngineOverride || combined.ModelOverride != opts.ModelOverride {
+			return true
+		}
+	}
+	return false
+}
+
+// RunWorkflowMatrix dispatches a workflow once per combination of the matrix axes, waits
+// for the runs to complete and prints a table comparing their metrics. Run artifacts are
+// downloaded to the logs directory like 'logs' does.
+func RunWorkflowMatrix(ctx context.Context, workflowIdOrName string, axisSpecs []string, opts RunOptions) error {
+	runMatrixLog.Printf("Starting matrix run: workflow=%s, axes=%v", workflowIdOrName, axisSpecs)
+	if opts.Preset != "" {
+		return errors.New("--preset cannot be combined with --matrix; use a preset matrix axis instead")
+	}
+	if opts.RepeatCount > 0 || opts.Follow || opts.AutoMergePRs {
+		return errors.New("--repeat, --follow and --auto-merge-prs cannot be combined with --matrix")
+	}
+
+	presets, err := loadRunPresets(workflowIdOrName)
+	if err != nil {
+		return err
+	}
+	axes, err := parseRunMatrixAxes(axisSpecs, presets)
+	if err != nil {
+		return err
+	}
+	combinations, err := expandRunMatrix(axes)
+	if err != nil {
+		return err
+	}
+
+	recompiles := runMatrixRecompiles(combinations, opts, presets)
+	if recompiles && opts.RepoOverride != "" {
+		return errors.New("engine and model matrix axes are not supported for remote repositories")
+	}
+	if recompiles && !opts.Push && !opts.DryRun {
+		return errors.New("engine and model matrix axes recompile the workflow; use --push so each run uses its own lock file")
+	}
+
+	// Keep the current lock file so that combinations without engine or model overrides run
+	// it, and so that it can be restored and pushed again once every combination has been
+	// dispatched. Dispatched runs keep the lock file of the commit they were triggered at.
+	restoreLockFile := func() {}
+	if recompiles {
+		workflowFile, err := resolveWorkflowFile(workflowIdOrName, opts.Verbose)
+		if err != nil {
+			return err
+		}
+		lockFile := stringutil.MarkdownToLockFile(workflowFile)
+		if original, err := os.ReadFile(lockFile); err == nil {
+			restoreLockFile = func() {
+				if err := os.WriteFile(lockFile, original, 0644); err != nil {
+					runMatrixLog.Printf("Failed to restore lock file %s: %v", lockFile, err)
+				}
+			}
+			defer func() {
+				restoreLockFile()
+				if opts.DryRun {
+					return
+				}
+				// Push the original lock file so that the branch does not keep the engine and
+				// model of the last combination; this is a no-op when it is already pushed
+				if err := pushWorkflowFiles(workflowIdOrName, []string{lockFile}, opts.RefOverride, opts.Verbose); err != nil {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to push the original lock file %s: %v. Push it to restore the workflow's engine and model", lockFile, err)))
+					return
+				}
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Restored the original lock file "+lockFile))
+			}()
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Dispatching %s for %d matrix combinations", workflowIdOrName, len(combinations))))
+
+	results := make([]RunMatrixResult, len(combinations))
+	var dispatched []int
+	for i, combination := range combinations {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Operation cancelled"))
+			return ctx.Err()
+		default:
+		}
+
+		fmt.Fprintln(os.Stderr, console.FormatProgressMessage(fmt.Sprintf("[%d/%d] %s", i+1, len(combinations), combination)))
+		results[i] = RunMatrixResult{Combination: combination.String()}
+
+		runOpts := combination.apply(opts, presets)
+		if runOpts.EngineOverride == "" && runOpts.ModelOverride == "" {
+			restoreLockFile()
+		}
+		runOpts.WaitForCompletion = false
+		runOpts.runStarted = func(info *WorkflowRunInfo) { results[i].RunID = info.DatabaseID }
+		if err := RunWorkflowOnGitHub(ctx, workflowIdOrName, runOpts); err != nil {
+			runMatrixLog.Printf("Dispatch failed for %s: %v", combination, err)
+			results[i].Conclusion = "dispatch failed"
+			results[i].Error = err.Error()
+			continue
+		}
+		if opts.DryRun {
+			results[i].Conclusion = "dry run"
+			continue
+		}
+		if results[i].RunID == 0 {
+			results[i].Conclusion = "unknown"
+			results[i].Error = "could not find the triggered run"
+			continue
+		}
+		dispatched = append(dispatched, i)
+	}
+
+	if len(dispatched) > 0 {
+		targetRepo := opts.RepoOverride
+		if targetRepo == "" {
+			if targetRepo, err = GetCurrentRepoSlug(); err != nil {
+				return fmt.Errorf("could not determine the repository of the runs: %w", err)
+			}
+		}
+		if err := ensureLogsGitignore(); err != nil {
+			runMatrixLog.Printf("Failed to ensure logs .gitignore: %v", err)
+		}
+		for _, i := range dispatched {
+			// Runs execute concurrently on GitHub; waiting for them in order only bounds the total wait
+			_ = WaitForWorkflowCompletion(targetRepo, strconv.FormatInt(results[i].RunID, 10), runMatrixWaitTimeoutMinutes, opts.Verbose)
+			collectRunMatrixMetrics(&results[i], targetRepo, defaultLogsOutputDir, opts.Verbose)
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Print(console.RenderStruct(results))
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+			fmt.Fprintln(os.Stderr, console.FormatListItem(result.Combination+": "+result.Error))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d matrix runs failed", failed, len(results))
+	}
+	return nil
+}
+
+// collectRunMatrixMetrics downloads the artifacts of a matrix run into the logs directory
+// and fills in the metrics reported by 'logs'
+func collectRunMatrixMetrics(result *RunMatrixResult, repo, outputDir string, verbose bool) {
+	owner, name, _ := strings.Cut(repo, "/")
+	run, err := fetchWorkflowRunMetadata(result.RunID, owner, name, "", verbose)
+	if err != nil {
+		result.Conclusion = "unknown"
+		result.Error = err.Error()
+		return
+	}
+	result.Conclusion = run.Conclusion
+	if !run.StartedAt.IsZero() && !run.UpdatedAt.IsZero() {
+		result.Duration = run.UpdatedAt.Sub(run.StartedAt).Round(time.Second).String()
+	}
+	if run.Conclusion != "success" {
+		result.Error = "run concluded with " + run.Conclusion
+	}
+
+	runDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", result.RunID))
+	if err := downloadRunArtifacts(result.RunID, runDir, verbose, owner, name, ""); err != nil && !errors.Is(err, ErrNoArtifacts) {
+		runMatrixLog.Printf("Failed to download artifacts of run %d: %v", result.RunID, err)
+		console.LogVerbose(verbose, fmt.Sprintf("Could not download artifacts of run %d: %v", result.RunID, err))
+		return
+	}
+	metrics, err := extractLogMetrics(runDir, verbose, run.WorkflowPath)
+	if err != nil {
+		runMatrixLog.Printf("Failed to extract metrics of run %d: %v", result.RunID, err)
+		return
+	}
+	result.Tokens = metrics.TokenUsage
+	result.Cost = metrics.EstimatedCost
+	result.Turns = metrics.Turns
+	result.SafeItems = len(extractCreatedItemsFromManifest(runDir))
+}
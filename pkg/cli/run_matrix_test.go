@@ -0,0 +1,85 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMatrixPresets() *RunPresetFile {
+	return &RunPresetFile{Presets: map[string]RunPreset{
+		"small": {Inputs: map[string]string{"issue_number": "12"}},
+		"large": {Inputs: map[string]string{"issue_number": "500"}, Model: "big-model"},
+	}}
+}
+
+func TestParseRunMatrixAxes(t *testing.T) {
+	axes, err := parseRunMatrixAxes([]string{"engine=copilot, claude,copilot", "preset=*", "tone=formal,casual"}, testMatrixPresets())
+	require.NoError(t, err, "valid axes should parse")
+	require.Len(t, axes, 3)
+	assert.Equal(t, []string{"copilot", "claude"}, axes[0].Values, "values should be trimmed and deduplicated")
+	assert.Equal(t, []string{"large", "small"}, axes[1].Values, "'*' should expand to all presets")
+	assert.Equal(t, "tone", axes[2].Name, "other axes should be inputs")
+}
+
+func TestParseRunMatrixAxesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+		want  string
+	}{
+		{"missing values", []string{"engine"}, "expected axis=value1,value2"},
+		{"empty values", []string{"tone=,"}, "has no values"},
+		{"duplicate axis", []string{"tone=a", "tone=b"}, "more than once"},
+		{"invalid engine", []string{"engine=nope"}, "invalid engine value"},
+		{"unknown preset", []string{"preset=medium"}, "matrix preset 'medium' not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRunMatrixAxes(tt.specs, testMatrixPresets())
+			require.Error(t, err, "invalid axes should be rejected")
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
+
+func TestExpandRunMatrix(t *testing.T) {
+	combinations, err := expandRunMatrix([]RunMatrixAxis{
+		{Name: "engine", Values: []string{"copilot", "claude"}},
+		{Name: "tone", Values: []string{"formal", "casual", "terse"}},
+	})
+	require.NoError(t, err, "matrix should expand")
+	require.Len(t, combinations, 6, "matrix should have one combination per value pair")
+	assert.Equal(t, "engine=copilot tone=formal", combinations[0].String())
+	assert.Equal(t, "engine=copilot tone=casual", combinations[1].String(), "last axis should vary fastest")
+	assert.Equal(t, "engine=claude tone=terse", combinations[5].String())
+
+	values := make([]string, maxRunMatrixCombinations+1)
+	for i := range values {
+		values[i] = "v"
+	}
+	_, err = expandRunMatrix([]RunMatrixAxis{{Name: "x", Values: values}})
+	require.Error(t, err, "oversized matrix should be rejected")
+}
+
+func TestRunMatrixCombinationApply(t *testing.T) {
+	presets := testMatrixPresets()
+	combination := runMatrixCombination{
+		{Axis: "preset", Value: "large"},
+		{Axis: "engine", Value: "claude"},
+		{Axis: "issue_number", Value: "7"},
+	}
+	base := RunOptions{Inputs: []string{"label=bug"}}
+
+	opts := combination.apply(base, presets)
+	assert.Equal(t, "claude", opts.EngineOverride, "engine axis should override the engine")
+	assert.Equal(t, "big-model", opts.ModelOverride, "preset model should apply")
+	assert.ElementsMatch(t, []string{"issue_number=7", "label=bug"}, opts.Inputs, "matrix inputs should override preset inputs")
+	assert.Equal(t, []string{"label=bug"}, base.Inputs, "base options should not be modified")
+
+	assert.True(t, runMatrixRecompiles([]runMatrixCombination{combination}, base, presets), "engine axis requires recompiling")
+	assert.False(t, runMatrixRecompiles([]runMatrixCombination{{{Axis: "preset", Value: "small"}}}, base, presets), "input-only presets do not recompile")
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var runPresetsLog = logger.New("cli:run_presets")
+
+// RunPresetsDir is the directory holding the run presets of each workflow (<workflow>.yml)
+const RunPresetsDir = ".github/aw/presets"
+
+// RunPreset is a named set of inputs, and optionally an engine and model, for 'run'
+type RunPreset struct {
+	Description string            `yaml:"description,omitempty"`
+	Inputs      map[string]string `yaml:"inputs,omitempty"`
+	Engine      string            `yaml:"engine,omitempty"`
+	Model       string            `yaml:"model,omitempty"`
+}
+
+// RunPresetFile is the content of a workflow's presets file
+type RunPresetFile struct {
+	Presets map[string]RunPreset `yaml:"presets,omitempty"`
+}
+
+// runPresetsPath returns the presets file path of a workflow
+func runPresetsPath(workflowIdOrName string) string {
+	return filepath.Join(RunPresetsDir, normalizeWorkflowID(workflowIdOrName)+".yml")
+}
+
+// loadRunPresets reads the presets file of a workflow. A missing file yields no presets.
+func loadRunPresets(workflowIdOrName string) (*RunPresetFile, error) {
+	path := runPresetsPath(workflowIdOrName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		runPresetsLog.Printf("No presets file for %s", workflowIdOrName)
+		return &RunPresetFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets file %s: %w", path, err)
+	}
+	return parseRunPresets(content, path)
+}
+
+// parseRunPresets parses and validates a presets file
+func parseRunPresets(content []byte, path string) (*RunPresetFile, error) {
+	var file RunPresetFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid presets file %s: %w", path, err)
+	}
+	for name, preset := range file.Presets {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid presets file %s: preset name cannot be empty", path)
+		}
+		if preset.Engine != "" {
+			if err := validateRunEngine(preset.Engine); err != nil {
+				return nil, fmt.Errorf("invalid presets file %s: preset %q: %w", path, name, err)
+			}
+		}
+	}
+	runPresetsLog.Printf("Loaded %d presets from %s", len(file.Presets), path)
+	return &file, nil
+}
+
+// lookup returns the named preset or an error listing the available presets
+func (f *RunPresetFile) lookup(workflowIdOrName, name string) (RunPreset, error) {
+	if preset, ok := f.Presets[name]; ok {
+		return preset, nil
+	}
+	if len(f.Presets) == 0 {
+		return RunPreset{}, fmt.Errorf("preset %q not found: %s does not exist or defines no presets", name, runPresetsPath(workflowIdOrName))
+	}
+	return RunPreset{}, fmt.Errorf("preset %q not found in %s (available: %s)", name, runPresetsPath(workflowIdOrName), strings.Join(f.names(), ", "))
+}
+
+// names returns the preset names in sorted order
+func (f *RunPresetFile) names() []string {
+	return slices.Sorted(maps.Keys(f.Presets))
+}
+
+// applyRunPreset applies a preset to the run options. Inputs given with --raw-field and
+// an explicit --engine or --model take precedence over the preset.
+func applyRunPreset(opts RunOptions, preset RunPreset) RunOptions {
+	opts.Inputs = mergeRunInputs(preset.Inputs, opts.Inputs)
+	if opts.EngineOverride == "" {
+		opts.EngineOverride = preset.Engine
+	}
+	if opts.ModelOverride == "" {
+		opts.ModelOverride = preset.Model
+	}
+	return opts
+}
+
+// mergeRunInputs combines base inputs with key=value overrides. Keys keep the order of
+// base followed by new keys from overrides.
+func mergeRunInputs(base map[string]string, overrides []string) []string {
+	values := make(map[string]string, len(base)+len(overrides))
+	var keys []string
+	for _, key := range slices.Sorted(maps.Keys(base)) {
+		values[key] = base[key]
+		keys = append(keys, key)
+	}
+	for _, input := range overrides {
+		key, value, _ := strings.Cut(input, "=")
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	merged := make([]string, 0, len(keys))
+	for _, key := range keys {
+		merged = append(merged, key+"="+values[key])
+	}
+	return merged
+}
+
+// validateRunEngine checks an engine from a preset or matrix against the engine registry
+func validateRunEngine(engine string) error {
+	registry := workflow.GetGlobalEngineRegistry()
+	if !registry.IsValidEngine(engine) {
+		return fmt.Errorf("invalid engine value '%s'. Must be one of: %s", engine, strings.Join(registry.GetSupportedEngines(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRunPresets = `presets:
+  small-issue:
+    description: A short bug report
+    inputs:
+      issue_number: "12"
+      label: bug
+  large-issue:
+    inputs:
+      issue_number: "500"
+    engine: claude
+    model: claude-sonnet-4
+`
+
+func TestParseRunPresets(t *testing.T) {
+	file, err := parseRunPresets([]byte(testRunPresets), "triage.yml")
+	require.NoError(t, err, "valid presets should parse")
+	assert.Equal(t, []string{"large-issue", "small-issue"}, file.names(), "preset names should be sorted")
+	assert.Equal(t, "claude", file.Presets["large-issue"].Engine, "preset engine should be parsed")
+	assert.Equal(t, "bug", file.Presets["small-issue"].Inputs["label"], "preset inputs should be parsed")
+}
+
+func TestParseRunPresetsRejectsUnknownEngine(t *testing.T) {
+	_, err := parseRunPresets([]byte("presets:\n  x:\n    engine: nope\n"), "triage.yml")
+	require.Error(t, err, "unknown engine should be rejected")
+	assert.Contains(t, err.Error(), `preset "x"`, "error should name the preset")
+}
+
+func TestLoadRunPresets(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	file, err := loadRunPresets("triage")
+	require.NoError(t, err, "missing presets file should not be an error")
+	assert.Empty(t, file.Presets, "missing presets file should yield no presets")
+	_, err = file.lookup("triage", "small-issue")
+	require.Error(t, err, "lookup should fail without presets")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, RunPresetsDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RunPresetsDir, "triage.yml"), []byte(testRunPresets), 0644))
+
+	file, err = loadRunPresets(".github/workflows/triage.md")
+	require.NoError(t, err, "presets should load by workflow path")
+	preset, err := file.lookup("triage", "small-issue")
+	require.NoError(t, err, "existing preset should be found")
+	assert.Equal(t, "12", preset.Inputs["issue_number"], "preset inputs should be loaded")
+
+	_, err = file.lookup("triage", "medium")
+	require.Error(t, err, "unknown preset should be rejected")
+	assert.Contains(t, err.Error(), "large-issue, small-issue", "error should list available presets")
+}
+
+func TestApplyRunPreset(t *testing.T) {
+	preset := RunPreset{Inputs: map[string]string{"issue_number": "12", "label": "bug"}, Engine: "claude", Model: "m1"}
+
+	opts := applyRunPreset(RunOptions{Inputs: []string{"label=feature", "extra=1"}, EngineOverride: "copilot"}, preset)
+	assert.Equal(t, []string{"issue_number=12", "label=feature", "extra=1"}, opts.Inputs, "explicit inputs should override preset inputs")
+	assert.Equal(t, "copilot", opts.EngineOverride, "explicit engine should take precedence")
+	assert.Equal(t, "m1", opts.ModelOverride, "preset model should apply when no model is given")
+}
@@ -24,6 +24,8 @@ var executionLog = logger.New("cli:run_workflow_execution")
 type RunOptions struct {
 	Enable            bool     // Enable the workflow if it's disabled
 	EngineOverride    string   // Override AI engine
+	ModelOverride     string   // Override AI model
+	Preset            string   // Named input preset from .github/aw/presets/<workflow>.yml
 	RepoOverride      string   // Target repository (owner/repo format)
 	RefOverride       string   // Branch or tag name
 	AutoMergePRs      bool     // Auto-merge PRs created during execution
@@ -33,6 +35,9 @@ type RunOptions struct {
 	Inputs            []string // Workflow inputs in key=value format
 	Verbose           bool     // Enable verbose output
 	DryRun            bool     // Validate without actually triggering
+	Follow            bool     // Stream job progress and agent logs until the run completes
+
+	runStarted func(*WorkflowRunInfo) // Called with the triggered run, used by matrix runs
 }
 
 // RunWorkflowOnGitHub runs an agentic workflow on GitHub Actions
@@ -51,6 +56,20 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 		return errors.New("workflow name or ID is required")
 	}
 
+	// Apply the named input preset before validating inputs
+	if opts.Preset != "" {
+		presets, err := loadRunPresets(workflowIdOrName)
+		if err != nil {
+			return err
+		}
+		preset, err := presets.lookup(workflowIdOrName, opts.Preset)
+		if err != nil {
+			return err
+		}
+		executionLog.Printf("Applying preset %s: inputs=%d, engine=%s, model=%s", opts.Preset, len(preset.Inputs), preset.Engine, preset.Model)
+		opts = applyRunPreset(opts, preset)
+	}
+
 	// Validate input format early before attempting workflow validation
 	for _, input := range opts.Inputs {
 		if !strings.Contains(input, "=") {
@@ -199,10 +218,10 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 		executionLog.Printf("Found lock file: %s", lockFilePath)
 	}
 
-	// Recompile workflow if engine override is provided (only for local workflows)
-	if opts.EngineOverride != "" && opts.RepoOverride == "" {
+	// Recompile workflow if engine or model override is provided (only for local workflows)
+	if (opts.EngineOverride != "" || opts.ModelOverride != "") && opts.RepoOverride == "" {
 		if opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Recompiling workflow with engine override: "+opts.EngineOverride))
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Recompiling workflow with engine override: %s, model override: %s", opts.EngineOverride, opts.ModelOverride)))
 		}
 
 		workflowMarkdownPath := stringutil.LockFileToMarkdown(lockFilePath)
@@ -210,6 +229,7 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 			MarkdownFiles:        []string{workflowMarkdownPath},
 			Verbose:              opts.Verbose,
 			EngineOverride:       opts.EngineOverride,
+			ModelOverride:        opts.ModelOverride,
 			Validate:             true,
 			Watch:                false,
 			WorkflowDir:          "",
@@ -227,9 +247,9 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 		if opts.Verbose {
 			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Successfully recompiled workflow with engine: "+opts.EngineOverride))
 		}
-	} else if opts.EngineOverride != "" && opts.RepoOverride != "" {
+	} else if (opts.EngineOverride != "" || opts.ModelOverride != "") && opts.RepoOverride != "" {
 		if opts.Verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Note: Engine override ignored for remote repository workflows"))
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Note: Engine and model overrides ignored for remote repository workflows"))
 		}
 	}
 
@@ -407,6 +427,18 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 	} else if opts.Verbose && runErr != nil {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Note: Could not get workflow run URL: %v", runErr)))
 	}
+	if opts.runStarted != nil && runErr == nil {
+		opts.runStarted(runInfo)
+	}
+
+	// Follow the run until it completes and exit with its conclusion
+	if opts.Follow {
+		followErr := followTriggeredRun(runInfo, runErr, workflowStartTime, opts)
+		if opts.Enable && wasDisabled && workflowID != 0 {
+			restoreWorkflowState(workflowIdOrName, workflowID, opts.RepoOverride, opts.Verbose)
+		}
+		return followErr
+	}
 
 	// Wait for workflow completion if requested (for --repeat or --auto-merge-prs)
 	if opts.WaitForCompletion || opts.AutoMergePRs {
@@ -465,6 +497,36 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 	return nil
 }
 
+// followTriggeredRun follows a triggered run with --follow and auto-merges the pull requests
+// it created when --auto-merge-prs is set and the run succeeded
+func followTriggeredRun(runInfo *WorkflowRunInfo, runErr error, workflowStartTime time.Time, opts RunOptions) error {
+	if runErr != nil {
+		return fmt.Errorf("could not find the triggered run to follow: %w", runErr)
+	}
+
+	targetRepo := opts.RepoOverride
+	if targetRepo == "" {
+		currentRepo, err := GetCurrentRepoSlug()
+		if err != nil {
+			return fmt.Errorf("could not determine the repository of the run: %w", err)
+		}
+		targetRepo = currentRepo
+	}
+
+	if err := followWorkflowRun(targetRepo, runInfo.DatabaseID, opts.Verbose); err != nil {
+		if opts.AutoMergePRs {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Workflow did not complete successfully, skipping auto-merge"))
+		}
+		return err
+	}
+	if opts.AutoMergePRs {
+		if err := AutoMergePullRequestsCreatedAfter(targetRepo, workflowStartTime, opts.Verbose); err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to auto-merge pull requests: %v", err)))
+		}
+	}
+	return nil
+}
+
 // RunWorkflowsOnGitHub runs multiple agentic workflows on GitHub Actions, optionally repeating a specified number of times
 func RunWorkflowsOnGitHub(ctx context.Context, workflowNames []string, opts RunOptions) error {
 	if len(workflowNames) == 0 {
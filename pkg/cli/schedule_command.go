@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCommandLog = logger.New("cli:schedule_command")
+
+// maxScheduleCollisionsShown limits the collisions listed in the text output
+const maxScheduleCollisionsShown = 20
+
+// ScheduleOptions holds the options of the schedule command
+type ScheduleOptions struct {
+	Count      int    // Number of upcoming runs to list
+	Timezone   string // IANA timezone used to display times
+	From       string // RFC 3339 start time (now when empty)
+	Days       int    // Length of the load simulation window
+	JSONOutput bool
+	Verbose    bool
+}
+
+// ScheduleReport is the output of the schedule command
+type ScheduleReport struct {
+	Timezone  string              `json:"timezone"`
+	Workflows []ScheduledWorkflow `json:"workflows"`
+	NextRuns  []ScheduleFireTime  `json:"next_runs"`
+	Load      *ScheduleLoadReport `json:"load,omitempty"`
+}
+
+// NewScheduleCommand creates the schedule command
+func NewScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule [workflow]",
+		Short: "Show when scheduled workflows will run and how their runs are spread over the week",
+		Long: `Show the upcoming runs of scheduled workflows, computed from the cron schedules in the
+compiled lock files. Fuzzy schedules such as "daily around 9am" are scattered to a fixed
+time at compile time, so the times shown are the ones GitHub Actions will use.
+
+With a workflow argument, the next runs of that workflow are listed. Without one, the next
+runs of all scheduled workflows in the repository are listed, followed by a simulation of
+the next --days days: an hourly and a weekday load histogram and the minutes at which
+several workflows start together. Use 'fleet schedule' to combine the load of many
+repositories.
+
+Cron schedules run in UTC; --timezone only changes how times are displayed and how runs
+are bucketed in the histograms.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` schedule
+  ` + string(constants.CLIExtensionPrefix) + ` schedule daily-report --count 5 --timezone Europe/Paris
+  ` + string(constants.CLIExtensionPrefix) + ` schedule --days 14 --from 2026-01-05T00:00:00Z
+  ` + string(constants.CLIExtensionPrefix) + ` schedule --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := scheduleOptionsFromFlags(cmd)
+			var workflowName string
+			if len(args) > 0 {
+				workflowName = args[0]
+			}
+			return RunSchedule(workflowName, opts)
+		},
+	}
+
+	addScheduleFlags(cmd)
+	cmd.Flags().IntP("count", "n", 10, "Number of upcoming runs to list")
+	cmd.Flags().BoolP("json", "j", false, "Output in JSON format")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// addScheduleFlags adds the flags shared by 'schedule' and 'fleet schedule'
+func addScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("timezone", "UTC", "IANA timezone used to display times, e.g. America/New_York")
+	cmd.Flags().String("from", "", "Start time in RFC 3339 format (default: now)")
+	cmd.Flags().Int("days", 7, "Number of days simulated for the load histograms")
+}
+
+// scheduleOptionsFromFlags reads the schedule flags of a command
+func scheduleOptionsFromFlags(cmd *cobra.Command) ScheduleOptions {
+	count, _ := cmd.Flags().GetInt("count")
+	timezone, _ := cmd.Flags().GetString("timezone")
+	from, _ := cmd.Flags().GetString("from")
+	days, _ := cmd.Flags().GetInt("days")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return ScheduleOptions{Count: count, Timezone: timezone, From: from, Days: days, JSONOutput: jsonOutput, Verbose: verbose}
+}
+
+// window returns the display location and the simulation window of the options
+func (opts ScheduleOptions) window() (*time.Location, time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(opts.Timezone)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("invalid timezone %q: %w", opts.Timezone, err)
+	}
+	from := time.Now()
+	if opts.From != "" {
+		if from, err = time.Parse(time.RFC3339, opts.From); err != nil {
+			return nil, time.Time{}, time.Time{}, fmt.Errorf("invalid --from time %q: expected RFC 3339 format such as 2026-01-05T09:00:00Z", opts.From)
+		}
+	}
+	if opts.Days < 1 {
+		return nil, time.Time{}, time.Time{}, errors.New("--days must be at least 1")
+	}
+	from = from.UTC().Truncate(time.Minute)
+	return loc, from, from.AddDate(0, 0, opts.Days), nil
+}
+
+// RunSchedule lists the upcoming runs of one workflow, or of all scheduled workflows in the
+// repository together with their simulated load
+func RunSchedule(workflowIdOrName string, opts ScheduleOptions) error {
+	scheduleCommandLog.Printf("Running schedule: workflow=%s, count=%d, timezone=%s, days=%d", workflowIdOrName, opts.Count, opts.Timezone, opts.Days)
+	if opts.Count < 1 {
+		return errors.New("--count must be at least 1")
+	}
+	loc, from, until, err := opts.window()
+	if err != nil {
+		return err
+	}
+
+	var workflows []ScheduledWorkflow
+	if workflowIdOrName != "" {
+		workflowFile, err := resolveWorkflowFile(workflowIdOrName, opts.Verbose)
+		if err != nil {
+			return err
+		}
+		wf, err := readScheduledWorkflow(workflowFile)
+		if err != nil {
+			return err
+		}
+		if len(wf.Crons) == 0 {
+			return fmt.Errorf("workflow '%s' has no schedule trigger", wf.Workflow)
+		}
+		workflows = []ScheduledWorkflow{wf}
+	} else {
+		if workflows, err = loadScheduledWorkflows(getWorkflowsDir(), opts.Verbose); err != nil {
+			return err
+		}
+		if len(workflows) == 0 {
+			if !opts.JSONOutput {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No scheduled workflows found"))
+			}
+			return nil
+		}
+	}
+
+	nextRuns, warnings := nextScheduleFireTimes(workflows, from, opts.Count, loc)
+	report := ScheduleReport{Timezone: loc.String(), Workflows: workflows, NextRuns: nextRuns}
+	if workflowIdOrName == "" {
+		load, _ := buildScheduleLoad(workflows, from, until, loc)
+		report.Load = &load
+	}
+
+	if !opts.JSONOutput {
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+		}
+	}
+	return printScheduleReport(report, opts.JSONOutput)
+}
+
+// loadScheduledWorkflows reads the schedules of all compiled workflows in a directory and
+// returns the workflows that have at least one
+func loadScheduledWorkflows(workflowsDir string, verbose bool) ([]ScheduledWorkflow, error) {
+	mdFiles, err := getMarkdownWorkflowFiles(workflowsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflows []ScheduledWorkflow
+	for _, mdFile := range mdFiles {
+		wf, err := readScheduledWorkflow(mdFile)
+		if err != nil {
+			console.LogVerbose(verbose, fmt.Sprintf("Skipping %s: %v", filepath.Base(mdFile), err))
+			continue
+		}
+		if len(wf.Crons) > 0 {
+			workflows = append(workflows, wf)
+		}
+	}
+	scheduleCommandLog.Printf("Found %d scheduled workflows of %d in %s", len(workflows), len(mdFiles), workflowsDir)
+	return workflows, nil
+}
+
+// readScheduledWorkflow reads the cron schedules from the lock file of a markdown workflow
+func readScheduledWorkflow(markdownPath string) (ScheduledWorkflow, error) {
+	wf := ScheduledWorkflow{Workflow: normalizeWorkflowID(markdownPath)}
+	lockPath := filepath.Clean(stringutil.MarkdownToLockFile(markdownPath))
+	content, err := os.ReadFile(lockPath) // #nosec G304 -- derived from a workflow path
+	if errors.Is(err, os.ErrNotExist) {
+		return wf, fmt.Errorf("workflow '%s' has not been compiled yet - run '%s compile' first", wf.Workflow, constants.CLIExtensionPrefix)
+	}
+	if err != nil {
+		return wf, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	wf.Crons, err = extractLockFileCrons(content)
+	if err != nil {
+		return wf, fmt.Errorf("failed to parse lock file %s: %w", lockPath, err)
+	}
+	return wf, nil
+}
+
+// extractLockFileCrons returns the cron expressions of the schedule trigger of a lock file
+func extractLockFileCrons(content []byte) ([]string, error) {
+	var lockFile struct {
+		On map[string]any `yaml:"on"`
+	}
+	if err := yaml.Unmarshal(content, &lockFile); err != nil {
+		return nil, err
+	}
+	entries, _ := lockFile.On["schedule"].([]any)
+	var crons []string
+	for _, entry := range entries {
+		if entryMap, ok := entry.(map[string]any); ok {
+			if cron, ok := entryMap["cron"].(string); ok && strings.TrimSpace(cron) != "" {
+				crons = append(crons, strings.TrimSpace(cron))
+			}
+		}
+	}
+	return crons, nil
+}
+
+// printScheduleReport prints the report as JSON to stdout or as tables
+func printScheduleReport(report ScheduleReport, jsonOutput bool) error {
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Next %d runs (%s)", len(report.NextRuns), report.Timezone)))
+	fmt.Print(console.RenderStruct(report.NextRuns))
+	if report.Load != nil {
+		printScheduleLoad(*report.Load)
+	}
+	return nil
+}
+
+// printScheduleLoad prints the load histograms and the collisions of a load report
+func printScheduleLoad(load ScheduleLoadReport) {
+	window := fmt.Sprintf("%s to %s", load.From.Format("2006-01-02 15:04"), load.Until.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%d runs of %d workflows from %s", load.TotalRuns, load.Workflows, window)))
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Runs by hour of day (%s)", load.Timezone)))
+	fmt.Print(console.RenderStruct(load.Hourly))
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Runs by day of week (%s)", load.Timezone)))
+	fmt.Print(console.RenderStruct(load.Weekly))
+
+	fmt.Fprintln(os.Stderr, "")
+	if len(load.Collisions) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("No two workflows start in the same minute"))
+		return
+	}
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d schedules start several workflows in the same minute (peak: %d workflows)", len(load.Collisions), load.PeakMinuteRuns)))
+	collisions := load.Collisions
+	if len(collisions) > maxScheduleCollisionsShown {
+		collisions = collisions[:maxScheduleCollisionsShown]
+	}
+	fmt.Print(console.RenderStruct(collisions))
+	if hidden := len(load.Collisions) - len(collisions); hidden > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%d more collisions not shown (use --json to list all)", hidden)))
+	}
+}
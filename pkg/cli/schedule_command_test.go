@@ -0,0 +1,62 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLockFileCrons(t *testing.T) {
+	content := []byte(`name: "Daily Report"
+"on":
+  schedule:
+  - cron: "17 9 * * *"
+  - cron: " 0 */6 * * * "
+  workflow_dispatch:
+jobs: {}
+`)
+	crons, err := extractLockFileCrons(content)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"17 9 * * *", "0 */6 * * *"}, crons, "crons should be read and trimmed")
+
+	crons, err = extractLockFileCrons([]byte("\"on\":\n  workflow_dispatch:\n"))
+	require.NoError(t, err)
+	assert.Empty(t, crons, "workflows without schedule should have no crons")
+}
+
+func TestLoadScheduledWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("daily.md", "---\non: daily\n---\n")
+	write("daily.lock.yml", "\"on\":\n  schedule:\n  - cron: \"17 9 * * *\"\n")
+	write("manual.md", "---\non: workflow_dispatch\n---\n")
+	write("manual.lock.yml", "\"on\":\n  workflow_dispatch:\n")
+	write("draft.md", "---\non: weekly\n---\n")
+
+	workflows, err := loadScheduledWorkflows(dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, []ScheduledWorkflow{{Workflow: "daily", Crons: []string{"17 9 * * *"}}}, workflows,
+		"only compiled workflows with a schedule should be returned")
+}
+
+func TestScheduleOptionsWindow(t *testing.T) {
+	loc, from, until, err := ScheduleOptions{Timezone: "Asia/Tokyo", From: "2026-01-05T09:30:45+09:00", Days: 2}.window()
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+	assert.Equal(t, "2026-01-05T00:30:00Z", from.Format("2006-01-02T15:04:05Z07:00"), "from should be UTC and truncated to the minute")
+	assert.Equal(t, "2026-01-07T00:30:00Z", until.Format("2006-01-02T15:04:05Z07:00"))
+
+	_, _, _, err = ScheduleOptions{Timezone: "Mars/Olympus", Days: 7}.window()
+	require.Error(t, err, "unknown timezone should be rejected")
+	_, _, _, err = ScheduleOptions{Timezone: "UTC", From: "tomorrow", Days: 7}.window()
+	require.Error(t, err, "invalid start time should be rejected")
+	_, _, _, err = ScheduleOptions{Timezone: "UTC", Days: 0}.window()
+	require.Error(t, err, "empty window should be rejected")
+}
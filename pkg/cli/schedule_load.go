@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var scheduleLoadLog = logger.New("cli:schedule_load")
+
+// scheduleBarWidth is the width of the longest bar in the load histograms
+const scheduleBarWidth = 40
+
+// ScheduledWorkflow is a workflow with the cron schedules of its compiled lock file
+type ScheduledWorkflow struct {
+	Workflow string   `json:"workflow"`
+	Crons    []string `json:"crons"`
+}
+
+// ScheduleFireTime is one upcoming run of a scheduled workflow
+type ScheduleFireTime struct {
+	Time     string    `json:"-" console:"header:Time"`
+	In       string    `json:"-" console:"header:In"`
+	Workflow string    `json:"workflow" console:"header:Workflow"`
+	Cron     string    `json:"cron" console:"header:Cron"`
+	At       time.Time `json:"time" console:"-"`
+}
+
+// ScheduleLoadBucket is one bar of a load histogram
+type ScheduleLoadBucket struct {
+	Slot string `json:"slot" console:"header:Slot"`
+	Runs int    `json:"runs" console:"header:Runs"`
+	Bar  string `json:"-" console:"header:Load"`
+}
+
+// ScheduleCollision is a minute at which several workflows start together
+type ScheduleCollision struct {
+	Time        string   `json:"time" console:"header:Time"`
+	Count       int      `json:"count" console:"header:Workflows"`
+	Occurrences int      `json:"occurrences" console:"header:Occurrences"` // Times the collision happens in the window
+	Names       string   `json:"-" console:"header:Names"`
+	Workflows   []string `json:"workflows" console:"-"`
+}
+
+// ScheduleLoadReport is the simulated load of scheduled workflows over a time window
+type ScheduleLoadReport struct {
+	From           time.Time            `json:"from"`
+	Until          time.Time            `json:"until"`
+	Timezone       string               `json:"timezone"`
+	Workflows      int                  `json:"workflows"`
+	TotalRuns      int                  `json:"total_runs"`
+	PeakMinuteRuns int                  `json:"peak_minute_runs"`
+	Hourly         []ScheduleLoadBucket `json:"hourly"`
+	Weekly         []ScheduleLoadBucket `json:"weekly"`
+	Collisions     []ScheduleCollision  `json:"collisions"`
+}
+
+// scheduleRun is a simulated run of a workflow
+type scheduleRun struct {
+	workflow string
+	cron     string
+	at       time.Time
+}
+
+// simulateScheduleRuns computes the runs of the workflows in [from, until), ordered by time.
+// Crons that cannot be parsed are skipped and returned as warnings.
+func simulateScheduleRuns(workflows []ScheduledWorkflow, from, until time.Time) ([]scheduleRun, []string) {
+	var runs []scheduleRun
+	var warnings []string
+	for _, wf := range workflows {
+		for _, cron := range wf.Crons {
+			schedule, err := parser.ParseCronSchedule(cron)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", wf.Workflow, err))
+				continue
+			}
+			// Next returns times after its argument, so start one minute early to include from
+			for at := schedule.Next(from.Add(-time.Minute)); !at.IsZero() && at.Before(until); at = schedule.Next(at) {
+				runs = append(runs, scheduleRun{workflow: wf.Workflow, cron: cron, at: at})
+			}
+		}
+	}
+	slices.SortStableFunc(runs, func(a, b scheduleRun) int {
+		return cmp.Or(a.at.Compare(b.at), cmp.Compare(a.workflow, b.workflow))
+	})
+	scheduleLoadLog.Printf("Simulated %d runs of %d workflows between %s and %s", len(runs), len(workflows), from, until)
+	return runs, warnings
+}
+
+// nextScheduleFireTimes returns the next count runs of the workflows from the given minute on
+func nextScheduleFireTimes(workflows []ScheduledWorkflow, from time.Time, count int, loc *time.Location) ([]ScheduleFireTime, []string) {
+	var runs []scheduleRun
+	var warnings []string
+	for _, wf := range workflows {
+		for _, cron := range wf.Crons {
+			times, err := parser.NextCronTimes(cron, from.Add(-time.Minute), count)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", wf.Workflow, err))
+				continue
+			}
+			for _, at := range times {
+				runs = append(runs, scheduleRun{workflow: wf.Workflow, cron: cron, at: at})
+			}
+		}
+	}
+	slices.SortStableFunc(runs, func(a, b scheduleRun) int {
+		return cmp.Or(a.at.Compare(b.at), cmp.Compare(a.workflow, b.workflow))
+	})
+
+	fireTimes := make([]ScheduleFireTime, 0, count)
+	for _, run := range runs {
+		if len(fireTimes) == count {
+			break
+		}
+		fireTimes = append(fireTimes, ScheduleFireTime{
+			Time:     run.at.In(loc).Format("Mon 2006-01-02 15:04 MST"),
+			In:       formatScheduleDelay(run.at.Sub(from)),
+			Workflow: run.workflow,
+			Cron:     run.cron,
+			At:       run.at,
+		})
+	}
+	return fireTimes, warnings
+}
+
+// buildScheduleLoad simulates the runs of the workflows in [from, until) and reports the
+// hourly and weekday load in the given location and the minutes shared by several workflows
+func buildScheduleLoad(workflows []ScheduledWorkflow, from, until time.Time, loc *time.Location) (ScheduleLoadReport, []string) {
+	runs, warnings := simulateScheduleRuns(workflows, from, until)
+	report := ScheduleLoadReport{
+		From:      from,
+		Until:     until,
+		Timezone:  loc.String(),
+		Workflows: len(workflows),
+		TotalRuns: len(runs),
+	}
+
+	hourly := make([]int, 24)
+	weekly := make([]int, 7)
+	byMinute := make(map[time.Time][]string)
+	var minutes []time.Time
+	for _, run := range runs {
+		local := run.at.In(loc)
+		hourly[local.Hour()]++
+		weekly[(int(local.Weekday())+6)%7]++ // Monday first
+		if _, seen := byMinute[run.at]; !seen {
+			minutes = append(minutes, run.at)
+		}
+		if !slices.Contains(byMinute[run.at], run.workflow) {
+			byMinute[run.at] = append(byMinute[run.at], run.workflow)
+		}
+		report.PeakMinuteRuns = max(report.PeakMinuteRuns, len(byMinute[run.at]))
+	}
+
+	hourLabels := make([]string, 24)
+	for hour := range hourLabels {
+		hourLabels[hour] = fmt.Sprintf("%02d:00", hour)
+	}
+	report.Hourly = newScheduleLoadBuckets(hourLabels, hourly)
+	report.Weekly = newScheduleLoadBuckets([]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}, weekly)
+	report.Collisions = groupScheduleCollisions(minutes, byMinute, loc)
+	return report, warnings
+}
+
+// groupScheduleCollisions groups the minutes shared by several workflows by time of day and
+// workflow set, so a daily collision is reported once with its number of occurrences
+func groupScheduleCollisions(minutes []time.Time, byMinute map[time.Time][]string, loc *time.Location) []ScheduleCollision {
+	index := make(map[string]int)
+	var collisions []ScheduleCollision
+	for _, minute := range minutes {
+		workflows := byMinute[minute]
+		if len(workflows) < 2 {
+			continue
+		}
+		slices.Sort(workflows)
+		timeOfDay := minute.In(loc).Format("15:04 MST")
+		key := timeOfDay + "|" + strings.Join(workflows, ",")
+		if i, ok := index[key]; ok {
+			collisions[i].Occurrences++
+			continue
+		}
+		index[key] = len(collisions)
+		collisions = append(collisions, ScheduleCollision{
+			Time:        timeOfDay,
+			Count:       len(workflows),
+			Occurrences: 1,
+			Names:       strings.Join(workflows, ", "),
+			Workflows:   workflows,
+		})
+	}
+	slices.SortStableFunc(collisions, func(a, b ScheduleCollision) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(b.Occurrences, a.Occurrences), cmp.Compare(a.Time, b.Time))
+	})
+	return collisions
+}
+
+// newScheduleLoadBuckets creates histogram buckets with bars scaled to the busiest bucket
+func newScheduleLoadBuckets(labels []string, counts []int) []ScheduleLoadBucket {
+	peak := slices.Max(counts)
+	buckets := make([]ScheduleLoadBucket, len(counts))
+	for i, count := range counts {
+		width := 0
+		if peak > 0 && count > 0 {
+			width = max(1, count*scheduleBarWidth/peak)
+		}
+		buckets[i] = ScheduleLoadBucket{Slot: labels[i], Runs: count, Bar: strings.Repeat("█", width)}
+	}
+	return buckets
+}
+
+// formatScheduleDelay formats the time until a run, e.g. "2d 3h", "3h 12m" or "12m"
+func formatScheduleDelay(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	days, hours := minutes/(24*60), minutes/60%24
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes%60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Monday 2026-01-05 00:00 UTC
+var testScheduleFrom = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+func testScheduledWorkflows() []ScheduledWorkflow {
+	return []ScheduledWorkflow{
+		{Workflow: "daily-report", Crons: []string{"17 9 * * *"}},
+		{Workflow: "weekday-triage", Crons: []string{"17 9 * * 1-5"}},
+		{Workflow: "sweeper", Crons: []string{"0 */6 * * *"}},
+	}
+}
+
+func TestNextScheduleFireTimes(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	fireTimes, warnings := nextScheduleFireTimes(testScheduledWorkflows(), testScheduleFrom, 4, paris)
+	assert.Empty(t, warnings)
+	require.Len(t, fireTimes, 4)
+	assert.Equal(t, "sweeper", fireTimes[0].Workflow, "the earliest run should come first")
+	assert.Equal(t, "Mon 2026-01-05 07:00 CET", fireTimes[1].Time, "times should be shown in the timezone")
+	assert.Equal(t, "daily-report", fireTimes[2].Workflow, "runs at the same minute should be ordered by workflow")
+	assert.Equal(t, "weekday-triage", fireTimes[3].Workflow)
+	assert.Equal(t, "9h 17m", fireTimes[3].In)
+}
+
+func TestNextScheduleFireTimesWarnings(t *testing.T) {
+	_, warnings := nextScheduleFireTimes([]ScheduledWorkflow{{Workflow: "broken", Crons: []string{"61 * * * *"}}}, testScheduleFrom, 1, time.UTC)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "broken:")
+}
+
+func TestBuildScheduleLoad(t *testing.T) {
+	load, warnings := buildScheduleLoad(testScheduledWorkflows(), testScheduleFrom, testScheduleFrom.AddDate(0, 0, 7), time.UTC)
+	assert.Empty(t, warnings)
+	assert.Equal(t, 7+5+28, load.TotalRuns, "a week should have every run of every workflow")
+	assert.Equal(t, 12, load.Hourly[9].Runs, "09:00 should have daily and weekday runs")
+	assert.Equal(t, 7, load.Hourly[6].Runs)
+	assert.Equal(t, 1+4, load.Weekly[5].Runs, "Saturday has no weekday runs")
+	assert.Len(t, load.Hourly[9].Bar, len("█")*scheduleBarWidth, "the busiest hour should have the longest bar")
+	assert.Equal(t, 2, load.PeakMinuteRuns)
+
+	require.Len(t, load.Collisions, 1, "the weekday collision should be reported once")
+	assert.Equal(t, "09:17 UTC", load.Collisions[0].Time)
+	assert.Equal(t, 5, load.Collisions[0].Occurrences)
+	assert.Equal(t, []string{"daily-report", "weekday-triage"}, load.Collisions[0].Workflows)
+}
+
+func TestFormatScheduleDelay(t *testing.T) {
+	assert.Equal(t, "12m", formatScheduleDelay(12*time.Minute))
+	assert.Equal(t, "3h 5m", formatScheduleDelay(3*time.Hour+5*time.Minute))
+	assert.Equal(t, "2d 1h", formatScheduleDelay(49*time.Hour+30*time.Minute))
+}
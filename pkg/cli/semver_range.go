@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// versionComparator is a single comparison against a canonical version, e.g. ">=v1.2.0"
+type versionComparator struct {
+	op      string // One of "=", ">", ">=", "<", "<="
+	version string // Canonical version with v prefix (e.g. "v1.2.0")
+}
+
+// versionRange is a semantic version range such as "^1.2", "~1.2.3", "1.x" or ">=1.0.0 <2.0.0".
+// Alternatives are separated by "||"; comparators within an alternative must all match.
+type versionRange struct {
+	raw          string
+	alternatives [][]versionComparator
+}
+
+// parseVersionRange parses a semantic version range. Supported forms:
+//   - "", "*", "x", "latest": any release
+//   - "^1.2.3", "^1.2", "^1": compatible with the version (same major, or same minor for 0.x)
+//   - "~1.2.3", "~1.2": same major and minor
+//   - "1", "1.x", "1.2", "1.2.x": partial versions, matching any version with that prefix
+//   - "1.2.3", "v1.2.3": exactly that version
+//   - ">=1.2.0 <2.0.0": comparators (=, >, >=, <, <=) that must all match
+//   - "^1.2 || ^2.0": alternatives
+func parseVersionRange(spec string) (*versionRange, error) {
+	spec = strings.TrimSpace(spec)
+	semverLog.Printf("Parsing version range: %q", spec)
+	r := &versionRange{raw: spec}
+
+	for alternative := range strings.SplitSeq(spec, "||") {
+		terms := strings.Fields(alternative)
+		if len(terms) == 0 && strings.Contains(spec, "||") {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", spec)
+		}
+
+		var comparators []versionComparator
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// Allow a space between an operator and its version (">= 1.2.0")
+			if isRangeOperator(term) && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+			termComparators, err := parseRangeTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+			}
+			comparators = append(comparators, termComparators...)
+		}
+		r.alternatives = append(r.alternatives, comparators)
+	}
+
+	return r, nil
+}
+
+// isRangeOperator returns true if term is a bare comparison operator
+func isRangeOperator(term string) bool {
+	switch term {
+	case "=", ">", ">=", "<", "<=":
+		return true
+	}
+	return false
+}
+
+// parseRangeTerm converts a single range term into comparators
+func parseRangeTerm(term string) ([]versionComparator, error) {
+	switch term {
+	case "*", "x", "X", "latest":
+		return nil, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(term, op); ok {
+			v, err := parsePartialVersion(rest)
+			if err != nil {
+				return nil, err
+			}
+			return []versionComparator{{op: op, version: v.canonical()}}, nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(term, "^"); ok {
+		v, err := parsePartialVersion(rest)
+		if err != nil {
+			return nil, err
+		}
+		var upper string
+		switch {
+		case v.major > 0 || v.parts == 1:
+			upper = fmt.Sprintf("v%d.0.0", v.major+1)
+		case v.minor > 0 || v.parts == 2:
+			upper = fmt.Sprintf("v0.%d.0", v.minor+1)
+		default:
+			upper = fmt.Sprintf("v0.0.%d", v.patch+1)
+		}
+		return []versionComparator{{op: ">=", version: v.canonical()}, {op: "<", version: upper}}, nil
+	}
+
+	rest, tilde := strings.CutPrefix(term, "~")
+	v, err := parsePartialVersion(rest)
+	if err != nil {
+		return nil, err
+	}
+	if !tilde && v.parts == 3 {
+		return []versionComparator{{op: "=", version: v.canonical()}}, nil
+	}
+	upper := fmt.Sprintf("v%d.%d.0", v.major, v.minor+1)
+	if v.parts == 1 {
+		upper = fmt.Sprintf("v%d.0.0", v.major+1)
+	}
+	return []versionComparator{{op: ">=", version: v.canonical()}, {op: "<", version: upper}}, nil
+}
+
+// partialVersion is a version with one to three numeric components
+type partialVersion struct {
+	major, minor, patch int
+	pre                 string
+	parts               int // Number of numeric components given (1-3)
+}
+
+// canonical returns the version with missing components filled with zeros
+func (v partialVersion) canonical() string {
+	canonical := fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	if v.pre != "" {
+		canonical += "-" + v.pre
+	}
+	return canonical
+}
+
+// parsePartialVersion parses "1", "1.2", "1.2.3", "1.2.3-rc.1" or wildcard forms like "1.x"
+func parsePartialVersion(s string) (partialVersion, error) {
+	var v partialVersion
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return v, errors.New("missing version")
+	}
+
+	core, pre, hasPre := strings.Cut(s, "-")
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+
+	numbers := make([]int, 0, 3)
+	for _, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			break
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		numbers = append(numbers, n)
+	}
+	if len(numbers) == 0 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	if hasPre && len(numbers) != 3 {
+		return v, fmt.Errorf("invalid version %q: prerelease requires major.minor.patch", s)
+	}
+
+	v.parts = len(numbers)
+	v.major = numbers[0]
+	if len(numbers) > 1 {
+		v.minor = numbers[1]
+	}
+	if len(numbers) > 2 {
+		v.patch = numbers[2]
+	}
+	v.pre = pre
+	return v, nil
+}
+
+// String returns the range as written
+func (r *versionRange) String() string {
+	return r.raw
+}
+
+// allows returns true if the tag is a precise semantic version within the range.
+// Prereleases only match alternatives that mention a prerelease version.
+func (r *versionRange) allows(tag string) bool {
+	ver := parseVersion(tag)
+	if ver == nil || !ver.isPreciseVersion() {
+		return false
+	}
+	v := "v" + ver.raw
+
+	for _, alternative := range r.alternatives {
+		if ver.pre != "" && !mentionsPrerelease(alternative) {
+			continue
+		}
+		matches := true
+		for _, c := range alternative {
+			if !c.matches(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsPrerelease returns true if any comparator compares against a prerelease
+func mentionsPrerelease(comparators []versionComparator) bool {
+	for _, c := range comparators {
+		if semver.Prerelease(c.version) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if v satisfies the comparator
+func (c versionComparator) matches(v string) bool {
+	cmp := semver.Compare(v, c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// isVersionRange returns true if ref is a semantic version or version range rather
+// than a branch name or commit SHA
+func isVersionRange(ref string) bool {
+	if ref == "" || IsCommitSHA(ref) {
+		return false
+	}
+	_, err := parseVersionRange(ref)
+	return err == nil
+}
+
+// selectHighestVersion returns the highest tag allowed by every range, or "" if none match
+func selectHighestVersion(tags []string, ranges ...*versionRange) string {
+	var best string
+	var bestVersion *semanticVersion
+	for _, tag := range tags {
+		allowed := true
+		for _, r := range ranges {
+			if !r.allows(tag) {
+				allowed = false
+				break
+			}
+		}
+		if !allowed {
+			continue
+		}
+		ver := parseVersion(tag)
+		if bestVersion == nil || ver.isNewer(bestVersion) {
+			best = tag
+			bestVersion = ver
+		}
+	}
+	semverLog.Printf("Selected highest version %q from %d tags", best, len(tags))
+	return best
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionRangeAllows(t *testing.T) {
+	tests := []struct {
+		rangeSpec string
+		allowed   []string
+		rejected  []string
+	}{
+		{rangeSpec: "", allowed: []string{"v0.1.0", "v3.2.1"}, rejected: []string{"v1.0.0-rc.1", "v1", "main"}},
+		{rangeSpec: "^1.2", allowed: []string{"v1.2.0", "v1.9.3", "1.2.5"}, rejected: []string{"v1.1.9", "v2.0.0"}},
+		{rangeSpec: "^0.3.1", allowed: []string{"v0.3.1", "v0.3.9"}, rejected: []string{"v0.4.0", "v0.3.0"}},
+		{rangeSpec: "~1.2.3", allowed: []string{"v1.2.3", "v1.2.9"}, rejected: []string{"v1.3.0", "v1.2.2"}},
+		{rangeSpec: "1.x", allowed: []string{"v1.0.0", "v1.8.0"}, rejected: []string{"v2.0.0", "v0.9.0"}},
+		{rangeSpec: "v1.2.3", allowed: []string{"v1.2.3"}, rejected: []string{"v1.2.4"}},
+		{rangeSpec: ">= 1.0.0 <1.5", allowed: []string{"v1.0.0", "v1.4.9"}, rejected: []string{"v1.5.0", "v0.9.9"}},
+		{rangeSpec: "^1.0 || ^3.0", allowed: []string{"v1.4.0", "v3.1.0"}, rejected: []string{"v2.0.0"}},
+		{rangeSpec: ">=2.0.0-rc.1", allowed: []string{"v2.0.0-rc.2", "v2.1.0"}, rejected: []string{"v1.9.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rangeSpec, func(t *testing.T) {
+			r, err := parseVersionRange(tt.rangeSpec)
+			require.NoError(t, err)
+			for _, tag := range tt.allowed {
+				assert.True(t, r.allows(tag), "%s should allow %s", tt.rangeSpec, tag)
+			}
+			for _, tag := range tt.rejected {
+				assert.False(t, r.allows(tag), "%s should reject %s", tt.rangeSpec, tag)
+			}
+		})
+	}
+}
+
+func TestParseVersionRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"main", "^", ">=abc", "1.2.3.4", "^1 ||", "1.2-rc.1"} {
+		_, err := parseVersionRange(spec)
+		assert.Error(t, err, "range %q should be invalid", spec)
+	}
+}
+
+func TestIsVersionRange(t *testing.T) {
+	assert.True(t, isVersionRange("^1.2"))
+	assert.True(t, isVersionRange("v1.2.3"))
+	assert.False(t, isVersionRange(""))
+	assert.False(t, isVersionRange("main"))
+	assert.False(t, isVersionRange("0123456789abcdef0123456789abcdef01234567"))
+}
+
+func TestSelectHighestVersion(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.2.0", "v1.10.0", "v2.0.0", "v2.1.0-beta", "v1", "latest"}
+
+	caret, err := parseVersionRange("^1.0")
+	require.NoError(t, err)
+	below, err := parseVersionRange("<1.5")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.10.0", selectHighestVersion(tags, caret))
+	assert.Equal(t, "v1.2.0", selectHighestVersion(tags, caret, below), "every range must match")
+
+	none, err := parseVersionRange("^3")
+	require.NoError(t, err)
+	assert.Empty(t, selectHighestVersion(tags, none))
+}
@@ -64,6 +64,8 @@ type TrialOptions struct {
 	AppendText             string
 	Verbose                bool
 	DisableSecurityScanner bool
+
+	onResult func(WorkflowTrialResult) // Called with each workflow result, used by eval
 }
 
 // NewTrialCommand creates the trial command
@@ -503,6 +505,9 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 				Timestamp:           time.Now(),
 			}
 			workflowResults = append(workflowResults, result)
+			if opts.onResult != nil {
+				opts.onResult(result)
+			}
 
 			// Save individual trial file
 			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
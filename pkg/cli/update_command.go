@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/tty"
 	"github.com/spf13/cobra"
 )
 
@@ -24,12 +26,29 @@ If no workflow names are specified, all workflows with a 'source' field are upda
 
 By default, the update performs a 3-way merge to preserve your local changes.
 Use --no-merge to override local changes with the upstream version.
+Frontmatter is merged key by key, so independent changes to different YAML keys
+do not conflict. Use --interactive to resolve remaining conflicts hunk by hunk
+(keep local, take upstream, or keep both), and --dry-run to print the merged
+result and any conflicts as a unified diff without writing files.
+
+Each updated workflow, and the remote imports it uses, are pinned to a commit SHA
+and content hash in .github/aw/aw.lock.json. Compilation fetches pinned imports at
+the pinned commit (or from the import cache, offline) and fails on a content hash
+mismatch. If a pinned tag was force-pushed upstream, update fails until --force is used.
 
 For workflow updates, it fetches the latest version based on the current ref:
 - If the ref is a tag, it updates to the latest release (use --major for major version updates)
 - If the ref is a branch, it fetches the latest commit from that branch
 - If the ref is a commit SHA, it fetches the latest commit from the default branch
 
+Workflows installed from a workflow package ('add owner/repo@range') update to the
+highest release within the package's version range recorded in aw.lock.json. Workflows
+newly listed in the package manifest are installed and the package's shared imports are
+refreshed.
+
+Use --create-pull-request (or --pr) to make the changes on a new branch and open a
+pull request instead of leaving them in the working tree.
+
 For extension updates, action updates, agent files, and codemods, use 'gh aw upgrade'.
 
 ` + WorkflowIDExplanation + `
@@ -39,8 +58,11 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` update repo-assist        # Update a specific workflow
   ` + string(constants.CLIExtensionPrefix) + ` update repo-assist.md     # Same (alternative format)
   ` + string(constants.CLIExtensionPrefix) + ` update --no-merge         # Override local changes with upstream
+  ` + string(constants.CLIExtensionPrefix) + ` update --interactive      # Resolve merge conflicts interactively
+  ` + string(constants.CLIExtensionPrefix) + ` update --dry-run          # Preview the merged result as a diff
   ` + string(constants.CLIExtensionPrefix) + ` update repo-assist --major # Allow major version updates
   ` + string(constants.CLIExtensionPrefix) + ` update --force            # Force update even if no changes
+  ` + string(constants.CLIExtensionPrefix) + ` update --pr               # Open a pull request with the updates
   ` + string(constants.CLIExtensionPrefix) + ` update --dir custom/workflows  # Update workflows in custom directory`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			majorFlag, _ := cmd.Flags().GetBool("major")
@@ -51,12 +73,34 @@ Examples:
 			noStopAfter, _ := cmd.Flags().GetBool("no-stop-after")
 			stopAfter, _ := cmd.Flags().GetString("stop-after")
 			noMergeFlag, _ := cmd.Flags().GetBool("no-merge")
+			interactiveFlag, _ := cmd.Flags().GetBool("interactive")
+			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+			createPRFlag, _ := cmd.Flags().GetBool("create-pull-request")
+			prFlagAlias, _ := cmd.Flags().GetBool("pr")
+			prFlag := createPRFlag || prFlagAlias
 
 			if err := validateEngine(engineOverride); err != nil {
 				return err
 			}
+			if interactiveFlag && noMergeFlag {
+				return errors.New("--interactive cannot be used with --no-merge")
+			}
+			if interactiveFlag && !tty.IsStderrTerminal() {
+				return errors.New("--interactive requires an interactive terminal")
+			}
 
-			return RunUpdateWorkflows(args, majorFlag, forceFlag, verbose, engineOverride, workflowDir, noStopAfter, stopAfter, noMergeFlag)
+			if prFlag && dryRunFlag {
+				return errors.New("--create-pull-request cannot be used with --dry-run")
+			}
+
+			run := func() error {
+				return RunUpdateWorkflows(args, majorFlag, forceFlag, verbose, engineOverride, workflowDir, noStopAfter, stopAfter, noMergeFlag, interactiveFlag, dryRunFlag)
+			}
+			if prFlag {
+				_, _, err := updateWorkflowsWithPR(args, run, verbose)
+				return err
+			}
+			return run()
 		},
 	}
 
@@ -67,6 +111,11 @@ Examples:
 	cmd.Flags().Bool("no-stop-after", false, "Remove any stop-after field from the workflow")
 	cmd.Flags().String("stop-after", "", "Override stop-after value in the workflow (e.g., '+48h', '2025-12-31 23:59:59')")
 	cmd.Flags().Bool("no-merge", false, "Override local changes with upstream version instead of merging")
+	cmd.Flags().BoolP("interactive", "i", false, "Resolve merge conflicts interactively, hunk by hunk")
+	cmd.Flags().Bool("dry-run", false, "Print the merged result and conflicts as a unified diff without writing files")
+	cmd.Flags().Bool("create-pull-request", false, "Create a pull request with the workflow updates")
+	cmd.Flags().Bool("pr", false, "Alias for --create-pull-request")
+	_ = cmd.Flags().MarkHidden("pr") // Hide the short alias from help output
 
 	// Register completions for update command
 	cmd.ValidArgsFunction = CompleteWorkflowNames
@@ -78,10 +127,10 @@ Examples:
 
 // RunUpdateWorkflows updates workflows from their source repositories.
 // Each workflow is compiled immediately after update.
-func RunUpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, engineOverride string, workflowsDir string, noStopAfter bool, stopAfter string, noMerge bool) error {
-	updateLog.Printf("Starting update process: workflows=%v, allowMajor=%v, force=%v, noMerge=%v", workflowNames, allowMajor, force, noMerge)
+func RunUpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, engineOverride string, workflowsDir string, noStopAfter bool, stopAfter string, noMerge, interactive, dryRun bool) error {
+	updateLog.Printf("Starting update process: workflows=%v, allowMajor=%v, force=%v, noMerge=%v, interactive=%v, dryRun=%v", workflowNames, allowMajor, force, noMerge, interactive, dryRun)
 
-	if err := UpdateWorkflows(workflowNames, allowMajor, force, verbose, engineOverride, workflowsDir, noStopAfter, stopAfter, noMerge); err != nil {
+	if err := UpdateWorkflows(workflowNames, allowMajor, force, verbose, engineOverride, workflowsDir, noStopAfter, stopAfter, noMerge, interactive, dryRun); err != nil {
 		return fmt.Errorf("workflow update failed: %w", err)
 	}
 
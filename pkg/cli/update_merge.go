@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
@@ -124,6 +125,116 @@ func MergeWorkflowContent(base, current, new, oldSourceSpec, newRef string, verb
 	currentNormalized := stringutil.NormalizeWhitespace(current)
 	newNormalized := stringutil.NormalizeWhitespace(newWithUpdatedSource)
 
+	mergedStr, hasConflicts, err := mergeWorkflowText(baseNormalized, currentNormalized, newNormalized, verbose)
+	if err != nil {
+		return "", false, err
+	}
+
+	updateMergeLog.Printf("Merge completed: has_conflicts=%v", hasConflicts)
+
+	// Process @include directives if present and no conflicts
+	// Skip include processing if there are conflicts to avoid errors
+	if !hasConflicts {
+		mergedStr = processMergedIncludes(mergedStr, oldSourceSpec, newRef, verbose)
+	}
+
+	return mergedStr, hasConflicts, nil
+}
+
+// processMergedIncludes processes @include directives in merged content against the new ref.
+// The unprocessed content is returned if processing fails.
+func processMergedIncludes(merged, oldSourceSpec, newRef string, verbose bool) string {
+	sourceSpec, err := parseSourceSpec(oldSourceSpec)
+	if err != nil {
+		return merged
+	}
+	workflow := &WorkflowSpec{
+		RepoSpec: RepoSpec{
+			RepoSlug: sourceSpec.Repo,
+			Version:  newRef,
+		},
+		WorkflowPath: sourceSpec.Path,
+	}
+
+	processedContent, err := processIncludesInContent(merged, workflow, newRef, verbose)
+	if err != nil {
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to process includes: %v", err)))
+		}
+		// Return unprocessed content on error
+		return merged
+	}
+	return processedContent
+}
+
+// mergeWorkflowText merges the frontmatter and the markdown body of a workflow separately.
+// Frontmatter that conflicts textually is merged structurally, key by key, so that
+// independent changes to the same YAML block (e.g. different permissions) do not conflict.
+func mergeWorkflowText(base, current, new string, verbose bool) (string, bool, error) {
+	baseFM, baseBody, baseOK := splitWorkflowFrontmatter(base)
+	currentFM, currentBody, currentOK := splitWorkflowFrontmatter(current)
+	newFM, newBody, newOK := splitWorkflowFrontmatter(new)
+	if !baseOK || !currentOK || !newOK {
+		updateMergeLog.Print("Workflow without frontmatter, merging whole file textually")
+		return gitMergeFile(base, current, new, verbose)
+	}
+
+	// Merge frontmatter with a trailing newline so conflict markers end on their own line
+	mergedFM, fmConflicts, err := gitMergeFile(baseFM+"\n", currentFM+"\n", newFM+"\n", verbose)
+	if err != nil {
+		return "", false, err
+	}
+	mergedFM = strings.TrimSuffix(mergedFM, "\n")
+	if fmConflicts {
+		if structural, conflicts, err := mergeFrontmatterStructurally(baseFM, currentFM, newFM); err != nil {
+			updateMergeLog.Printf("Structural frontmatter merge unavailable: %v", err)
+		} else if len(conflicts) > 0 {
+			updateMergeLog.Printf("Structural frontmatter merge has conflicting keys: %v", conflicts)
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Conflicting frontmatter keys: "+strings.Join(conflicts, ", ")))
+			}
+		} else {
+			updateMergeLog.Print("Resolved frontmatter conflicts with structural merge")
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Merged frontmatter keys structurally"))
+			}
+			mergedFM, fmConflicts = structural, false
+		}
+	}
+
+	mergedBody, bodyConflicts, err := gitMergeFile(baseBody, currentBody, newBody, verbose)
+	if err != nil {
+		return "", false, err
+	}
+
+	return joinWorkflowFrontmatter(mergedFM, mergedBody), fmConflicts || bodyConflicts, nil
+}
+
+// splitWorkflowFrontmatter splits workflow content into its raw frontmatter and body,
+// preserving formatting so that joinWorkflowFrontmatter restores the original content
+func splitWorkflowFrontmatter(content string) (string, string, bool) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", "", false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	return "", "", false
+}
+
+// joinWorkflowFrontmatter is the inverse of splitWorkflowFrontmatter
+func joinWorkflowFrontmatter(frontmatter, body string) string {
+	if frontmatter == "" {
+		return "---\n---\n" + body
+	}
+	return "---\n" + frontmatter + "\n---\n" + body
+}
+
+// gitMergeFile performs a textual 3-way merge using git merge-file with diff3 conflict markers
+func gitMergeFile(base, current, new string, verbose bool) (string, bool, error) {
 	// Create temporary directory for merge files
 	tmpDir, err := os.MkdirTemp("", "gh-aw-merge-*")
 	if err != nil {
@@ -136,13 +247,13 @@ func MergeWorkflowContent(base, current, new, oldSourceSpec, newRef string, verb
 	currentFile := filepath.Join(tmpDir, "current.md")
 	newFile := filepath.Join(tmpDir, "new.md")
 
-	if err := os.WriteFile(baseFile, []byte(baseNormalized), 0644); err != nil {
+	if err := os.WriteFile(baseFile, []byte(base), 0644); err != nil {
 		return "", false, fmt.Errorf("failed to write base file: %w", err)
 	}
-	if err := os.WriteFile(currentFile, []byte(currentNormalized), 0644); err != nil {
+	if err := os.WriteFile(currentFile, []byte(current), 0644); err != nil {
 		return "", false, fmt.Errorf("failed to write current file: %w", err)
 	}
-	if err := os.WriteFile(newFile, []byte(newNormalized), 0644); err != nil {
+	if err := os.WriteFile(newFile, []byte(new), 0644); err != nil {
 		return "", false, fmt.Errorf("failed to write new file: %w", err)
 	}
 
@@ -184,40 +295,11 @@ func MergeWorkflowContent(base, current, new, oldSourceSpec, newRef string, verb
 		}
 	}
 
-	updateMergeLog.Printf("Merge completed: has_conflicts=%v", hasConflicts)
-
 	// Read the merged content from the current file (git merge-file updates it in-place)
 	mergedContent, err := os.ReadFile(currentFile)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to read merged content: %w", err)
 	}
 
-	mergedStr := string(mergedContent)
-
-	// Process @include directives if present and no conflicts
-	// Skip include processing if there are conflicts to avoid errors
-	if !hasConflicts {
-		sourceSpec, err := parseSourceSpec(oldSourceSpec)
-		if err == nil {
-			workflow := &WorkflowSpec{
-				RepoSpec: RepoSpec{
-					RepoSlug: sourceSpec.Repo,
-					Version:  newRef,
-				},
-				WorkflowPath: sourceSpec.Path,
-			}
-
-			processedContent, err := processIncludesInContent(mergedStr, workflow, newRef, verbose)
-			if err != nil {
-				if verbose {
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to process includes: %v", err)))
-				}
-				// Return unprocessed content on error
-			} else {
-				mergedStr = processedContent
-			}
-		}
-	}
-
-	return mergedStr, hasConflicts, nil
+	return string(mergedContent), hasConflicts, nil
 }
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var updateMergeConflictsLog = logger.New("cli:update_merge_conflicts")
+
+// Conflict resolutions offered for each merge conflict hunk
+const (
+	conflictKeepLocal    = "local"
+	conflictTakeUpstream = "upstream"
+	conflictKeepBoth     = "both"
+	conflictSkip         = "skip"
+)
+
+// mergeConflict is a single diff3 conflict hunk produced by git merge-file
+type mergeConflict struct {
+	Local    string
+	Base     string
+	Upstream string
+}
+
+// mergeSegment is either a run of cleanly merged text or a conflict hunk
+type mergeSegment struct {
+	Text     string
+	Conflict *mergeConflict
+}
+
+// conflictChooser picks a resolution for conflict index (0-based) out of total
+type conflictChooser func(index, total int, conflict *mergeConflict) (string, error)
+
+// parseMergeConflicts splits merged content with diff3 conflict markers into segments
+func parseMergeConflicts(content string) ([]mergeSegment, error) {
+	var segments []mergeSegment
+	var text strings.Builder
+	var current *mergeConflict
+	var section *strings.Builder
+	var local, base, upstream strings.Builder
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case current == nil && strings.HasPrefix(trimmed, "<<<<<<< "):
+			if text.Len() > 0 {
+				segments = append(segments, mergeSegment{Text: text.String()})
+				text.Reset()
+			}
+			current = &mergeConflict{}
+			local.Reset()
+			base.Reset()
+			upstream.Reset()
+			section = &local
+		case current != nil && strings.HasPrefix(trimmed, "||||||| "):
+			section = &base
+		case current != nil && trimmed == "=======":
+			section = &upstream
+		case current != nil && strings.HasPrefix(trimmed, ">>>>>>> "):
+			current.Local = local.String()
+			current.Base = base.String()
+			current.Upstream = upstream.String()
+			segments = append(segments, mergeSegment{Conflict: current})
+			current = nil
+		case current != nil:
+			section.WriteString(line)
+		default:
+			text.WriteString(line)
+		}
+	}
+
+	if current != nil {
+		return nil, errors.New("unterminated merge conflict")
+	}
+	if text.Len() > 0 {
+		segments = append(segments, mergeSegment{Text: text.String()})
+	}
+	return segments, nil
+}
+
+// resolveMergeConflicts resolves each conflict hunk in merged content using choose.
+// Skipped conflicts keep their conflict markers. Returns the resolved content and
+// the number of conflicts left unresolved.
+func resolveMergeConflicts(content string, choose conflictChooser) (string, int, error) {
+	segments, err := parseMergeConflicts(content)
+	if err != nil {
+		return "", 0, err
+	}
+
+	total := 0
+	for _, segment := range segments {
+		if segment.Conflict != nil {
+			total++
+		}
+	}
+	updateMergeConflictsLog.Printf("Resolving %d merge conflicts", total)
+
+	var result strings.Builder
+	index := 0
+	remaining := 0
+	for _, segment := range segments {
+		if segment.Conflict == nil {
+			result.WriteString(segment.Text)
+			continue
+		}
+
+		choice, err := choose(index, total, segment.Conflict)
+		if err != nil {
+			return "", 0, err
+		}
+		index++
+
+		switch choice {
+		case conflictKeepLocal:
+			result.WriteString(segment.Conflict.Local)
+		case conflictTakeUpstream:
+			result.WriteString(segment.Conflict.Upstream)
+		case conflictKeepBoth:
+			result.WriteString(segment.Conflict.Local)
+			result.WriteString(segment.Conflict.Upstream)
+		case conflictSkip:
+			result.WriteString(formatMergeConflict(segment.Conflict))
+			remaining++
+		default:
+			return "", 0, fmt.Errorf("unknown conflict resolution: %s", choice)
+		}
+	}
+
+	updateMergeConflictsLog.Printf("Resolved %d of %d merge conflicts", total-remaining, total)
+	return result.String(), remaining, nil
+}
+
+// formatMergeConflict renders a conflict hunk with the diff3 markers written by gitMergeFile
+func formatMergeConflict(conflict *mergeConflict) string {
+	var sb strings.Builder
+	sb.WriteString("<<<<<<< current (local changes)\n")
+	sb.WriteString(conflict.Local)
+	sb.WriteString("||||||| base (original)\n")
+	sb.WriteString(conflict.Base)
+	sb.WriteString("=======\n")
+	sb.WriteString(conflict.Upstream)
+	sb.WriteString(">>>>>>> new (upstream)\n")
+	return sb.String()
+}
+
+// promptConflictResolution asks the user how to resolve a conflict hunk
+func promptConflictResolution(index, total int, conflict *mergeConflict) (string, error) {
+	description := fmt.Sprintf("Local:\n%s\nUpstream:\n%s",
+		indentConflictText(conflict.Local), indentConflictText(conflict.Upstream))
+
+	return console.PromptSelect(
+		fmt.Sprintf("Conflict %d of %d", index+1, total),
+		description,
+		[]console.SelectOption{
+			{Label: "Keep local changes", Value: conflictKeepLocal},
+			{Label: "Take upstream changes", Value: conflictTakeUpstream},
+			{Label: "Keep both (local first)", Value: conflictKeepBoth},
+			{Label: "Leave conflict markers", Value: conflictSkip},
+		},
+	)
+}
+
+// indentConflictText indents a conflict side for display, marking empty sides
+func indentConflictText(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return "  (empty)"
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unifiedDiff returns a unified diff between two versions of a file using git diff --no-index.
+// Returns an empty string when the contents are identical.
+func unifiedDiff(name, oldContent, newContent string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "gh-aw-diff-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Keep the file inside the temp directory for absolute or parent-relative names
+	name = filepath.Clean(name)
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+		name = filepath.Base(name)
+	}
+	oldPath := filepath.Join(tmpDir, "a", name)
+	newPath := filepath.Join(tmpDir, "b", name)
+	for path, content := range map[string]string{oldPath: oldContent, newPath: newContent} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create diff directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", fmt.Errorf("failed to write diff file: %w", err)
+		}
+	}
+
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--no-prefix", "--", filepath.Join("a", name), filepath.Join("b", name))
+	cmd.Dir = tmpDir
+	output, err := cmd.Output()
+	if err != nil {
+		// git diff exits with 1 when the files differ
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return "", fmt.Errorf("git diff failed: %w", err)
+		}
+	}
+	return string(output), nil
+}
@@ -0,0 +1,77 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConflictedContent = `# Workflow
+
+<<<<<<< current (local changes)
+local line
+||||||| base (original)
+base line
+=======
+upstream line
+>>>>>>> new (upstream)
+
+Shared text
+`
+
+func TestParseMergeConflicts(t *testing.T) {
+	segments, err := parseMergeConflicts(testConflictedContent)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	assert.Equal(t, "# Workflow\n\n", segments[0].Text)
+	require.NotNil(t, segments[1].Conflict)
+	assert.Equal(t, "local line\n", segments[1].Conflict.Local)
+	assert.Equal(t, "base line\n", segments[1].Conflict.Base)
+	assert.Equal(t, "upstream line\n", segments[1].Conflict.Upstream)
+	assert.Equal(t, "\nShared text\n", segments[2].Text)
+
+	_, err = parseMergeConflicts("<<<<<<< current (local changes)\nlocal\n")
+	require.Error(t, err, "unterminated conflicts should be rejected")
+}
+
+func TestResolveMergeConflicts(t *testing.T) {
+	tests := []struct {
+		choice        string
+		want          string
+		wantRemaining int
+	}{
+		{choice: conflictKeepLocal, want: "# Workflow\n\nlocal line\n\nShared text\n"},
+		{choice: conflictTakeUpstream, want: "# Workflow\n\nupstream line\n\nShared text\n"},
+		{choice: conflictKeepBoth, want: "# Workflow\n\nlocal line\nupstream line\n\nShared text\n"},
+		{choice: conflictSkip, want: testConflictedContent, wantRemaining: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.choice, func(t *testing.T) {
+			resolved, remaining, err := resolveMergeConflicts(testConflictedContent, func(index, total int, conflict *mergeConflict) (string, error) {
+				assert.Equal(t, 0, index)
+				assert.Equal(t, 1, total)
+				return tt.choice, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resolved)
+			assert.Equal(t, tt.wantRemaining, remaining)
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	diff, err := unifiedDiff(".github/workflows/test.md", "one\ntwo\n", "one\nthree\n")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- a/.github/workflows/test.md")
+	assert.Contains(t, diff, "+++ b/.github/workflows/test.md")
+	assert.Contains(t, diff, "-two")
+	assert.Contains(t, diff, "+three")
+
+	diff, err = unifiedDiff("test.md", "same\n", "same\n")
+	require.NoError(t, err)
+	assert.Empty(t, diff, "identical content should produce no diff")
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+var updateMergeFrontmatterLog = logger.New("cli:update_merge_frontmatter")
+
+// mergeFrontmatterStructurally performs a 3-way merge of YAML frontmatter key by key.
+// A key changed on only one side takes that side's value; nested mappings are merged
+// recursively. Returns the merged YAML and the dotted paths of keys changed differently
+// on both sides. The merge edits the syntax tree of the local frontmatter, so its
+// comments, key order and quoting are kept; upstream values are spliced in as written
+// upstream, re-indented to the local nesting, and new upstream keys are appended.
+func mergeFrontmatterStructurally(base, current, new string) (string, []string, error) {
+	_, baseMap, err := parseFrontmatterAST(base)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse base frontmatter: %w", err)
+	}
+	currentFile, currentMap, err := parseFrontmatterAST(current)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse local frontmatter: %w", err)
+	}
+	_, newMap, err := parseFrontmatterAST(new)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse upstream frontmatter: %w", err)
+	}
+	if currentMap == nil {
+		return "", nil, errors.New("local frontmatter is empty")
+	}
+
+	var conflicts []string
+	if err := mergeYAMLMappingNodes("", baseMap, currentMap, newMap, &conflicts); err != nil {
+		return "", nil, err
+	}
+	if len(conflicts) > 0 {
+		updateMergeFrontmatterLog.Printf("Structural merge found %d conflicting keys", len(conflicts))
+		return "", conflicts, nil
+	}
+	merged := strings.TrimRight(currentFile.String(), "\n")
+	// Spliced nodes must render as valid YAML; otherwise the caller keeps the conflict markers
+	if _, err := parser.ParseBytes([]byte(merged), 0); err != nil {
+		return "", nil, fmt.Errorf("structurally merged frontmatter is not valid YAML: %w", err)
+	}
+	return merged, nil, nil
+}
+
+// parseFrontmatterAST parses YAML frontmatter with its comments and returns the top-level
+// mapping, which is nil for empty frontmatter
+func parseFrontmatterAST(content string) (*ast.File, *ast.MappingNode, error) {
+	file, err := parser.ParseBytes([]byte(content), parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		return file, nil, nil
+	}
+	mapping, ok := file.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return nil, nil, errors.New("frontmatter is not a mapping")
+	}
+	return file, mapping, nil
+}
+
+// mergeYAMLMappingNodes merges three versions of a YAML mapping into current, appending
+// conflicting key paths to conflicts. base and new may be nil.
+func mergeYAMLMappingNodes(path string, base, current, new *ast.MappingNode, conflicts *[]string) error {
+	var merged []*ast.MappingValueNode
+	seen := make(map[string]bool)
+	column := current.Values[0].Key.GetToken().Position.Column
+
+	for _, item := range current.Values {
+		key := yamlNodeKey(item)
+		seen[key] = true
+		baseItem := lookupMappingValue(base, key)
+		newItem := lookupMappingValue(new, key)
+		keep, err := mergeYAMLValueNode(joinYAMLPath(path, key), baseItem, item, newItem, conflicts)
+		if err != nil {
+			return err
+		}
+		if keep != nil {
+			merged = append(merged, keep)
+		}
+	}
+
+	if new != nil {
+		for _, item := range new.Values {
+			key := yamlNodeKey(item)
+			if seen[key] {
+				continue
+			}
+			keep, err := mergeYAMLValueNode(joinYAMLPath(path, key), lookupMappingValue(base, key), nil, item, conflicts)
+			if err != nil {
+				return err
+			}
+			if keep != nil {
+				if keep, err = reindentYAMLEntry(keep, column); err != nil {
+					return err
+				}
+				merged = append(merged, keep)
+			}
+		}
+	}
+
+	current.Values = merged
+	return nil
+}
+
+// mergeYAMLValueNode merges a single key whose entry is nil on the sides that do not have
+// it. It returns the entry to keep, or nil when the merged result removes the key.
+func mergeYAMLValueNode(path string, base, current, new *ast.MappingValueNode, conflicts *[]string) (*ast.MappingValueNode, error) {
+	baseValue, err := yamlEntryValue(base)
+	if err != nil {
+		return nil, err
+	}
+	currentValue, err := yamlEntryValue(current)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := yamlEntryValue(new)
+	if err != nil {
+		return nil, err
+	}
+
+	inBase, inCurrent, inNew := base != nil, current != nil, new != nil
+	switch {
+	case inCurrent == inNew && reflect.DeepEqual(currentValue, newValue):
+		// Both sides agree (including both removing the key)
+		return current, nil
+	case inCurrent == inBase && reflect.DeepEqual(currentValue, baseValue):
+		// Only upstream changed the key: use the upstream value as written upstream
+		if current == nil || new == nil {
+			return new, nil
+		}
+		spliced, err := reindentYAMLEntry(new, current.Key.GetToken().Position.Column)
+		if err != nil {
+			return nil, err
+		}
+		current.Value = spliced.Value
+		return current, nil
+	case inNew == inBase && reflect.DeepEqual(newValue, baseValue):
+		// Only the local copy changed the key
+		return current, nil
+	}
+
+	currentMap, currentIsMap := yamlMappingValue(current)
+	newMap, newIsMap := yamlMappingValue(new)
+	if currentIsMap && newIsMap {
+		baseMap, _ := yamlMappingValue(base)
+		if err := mergeYAMLMappingNodes(path, baseMap, currentMap, newMap, conflicts); err != nil {
+			return nil, err
+		}
+		return current, nil
+	}
+
+	*conflicts = append(*conflicts, path)
+	return current, nil
+}
+
+// reindentYAMLEntry re-parses an upstream mapping entry with its key at column, shifting
+// all of its lines by the same amount, so it can be spliced into a local mapping whose
+// indentation differs from upstream
+func reindentYAMLEntry(entry *ast.MappingValueNode, column int) (*ast.MappingValueNode, error) {
+	shift := column - entry.Key.GetToken().Position.Column
+	lines := strings.Split(entry.String(), "\n")
+	lines[0] = strings.Repeat(" ", column-1) + strings.TrimLeft(lines[0], " ")
+	for i, line := range lines[1:] {
+		if shift >= 0 {
+			lines[i+1] = strings.Repeat(" ", shift) + line
+		} else if indent := len(line) - len(strings.TrimLeft(line, " ")); indent >= -shift {
+			lines[i+1] = line[-shift:]
+		}
+	}
+
+	_, mapping, err := parseFrontmatterAST(strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-indent frontmatter key %s: %w", yamlNodeKey(entry), err)
+	}
+	if mapping == nil || len(mapping.Values) != 1 {
+		return nil, fmt.Errorf("failed to re-indent frontmatter key %s", yamlNodeKey(entry))
+	}
+	return mapping.Values[0], nil
+}
+
+// yamlEntryValue decodes the value of a mapping entry for comparison
+func yamlEntryValue(entry *ast.MappingValueNode) (any, error) {
+	if entry == nil {
+		return nil, nil
+	}
+	var value any
+	if err := yaml.NodeToValue(entry.Value, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode frontmatter key %s: %w", yamlNodeKey(entry), err)
+	}
+	return value, nil
+}
+
+// yamlMappingValue returns the value of a mapping entry when it is a block mapping
+func yamlMappingValue(entry *ast.MappingValueNode) (*ast.MappingNode, bool) {
+	if entry == nil {
+		return nil, false
+	}
+	mapping, ok := entry.Value.(*ast.MappingNode)
+	return mapping, ok && !mapping.IsFlowStyle
+}
+
+// yamlNodeKey returns the unquoted key of a mapping entry
+func yamlNodeKey(entry *ast.MappingValueNode) string {
+	if token := entry.Key.GetToken(); token != nil {
+		return token.Value
+	}
+	return entry.Key.String()
+}
+
+// lookupMappingValue returns the entry of key in a mapping, or nil
+func lookupMappingValue(mapping *ast.MappingNode, key string) *ast.MappingValueNode {
+	if mapping == nil {
+		return nil
+	}
+	index := slices.IndexFunc(mapping.Values, func(entry *ast.MappingValueNode) bool { return yamlNodeKey(entry) == key })
+	if index < 0 {
+		return nil
+	}
+	return mapping.Values[index]
+}
+
+// joinYAMLPath appends key to a dotted YAML path
+func joinYAMLPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
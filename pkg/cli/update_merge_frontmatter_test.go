@@ -0,0 +1,175 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFrontmatterStructurally(t *testing.T) {
+	tests := []struct {
+		name          string
+		base          string
+		current       string
+		new           string
+		wantContains  []string
+		wantMissing   []string
+		wantConflicts []string
+	}{
+		{
+			name:         "independent nested keys",
+			base:         "on: push\npermissions:\n  contents: read",
+			current:      "on: push\npermissions:\n  contents: read\n  issues: write",
+			new:          "on: push\npermissions:\n  contents: read\n  pull-requests: write",
+			wantContains: []string{"contents: read", "issues: write", "pull-requests: write"},
+		},
+		{
+			name:         "upstream removes a key",
+			base:         "on: push\nengine: claude\ntimeout-minutes: 10",
+			current:      "on: push\nengine: copilot\ntimeout-minutes: 10",
+			new:          "on: push\nengine: claude",
+			wantContains: []string{"engine: copilot"},
+			wantMissing:  []string{"timeout-minutes"},
+		},
+		{
+			name:          "same key changed on both sides",
+			base:          "on: push\ntools:\n  bash: [ls]",
+			current:       "on: push\ntools:\n  bash: [ls, cat]",
+			new:           "on: push\ntools:\n  bash: [ls, grep]",
+			wantConflicts: []string{"tools.bash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts, err := mergeFrontmatterStructurally(tt.base, tt.current, tt.new)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantConflicts, conflicts, "conflicting key paths")
+			for _, want := range tt.wantContains {
+				assert.Contains(t, merged, want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, merged, missing)
+			}
+		})
+	}
+}
+
+func TestMergeFrontmatterStructurallyKeepsLocalFormatting(t *testing.T) {
+	base := `on: push
+permissions:
+  contents: read
+engine: claude
+timeout-minutes: 10`
+	current := `# Local triage workflow
+on: push # runs on every push
+permissions:
+  contents: read
+  # needed to label issues
+  issues: write
+engine: 'claude'
+timeout-minutes: 10`
+	new := `on: push
+permissions:
+  contents: read
+  pull-requests: write
+engine: claude
+timeout-minutes: 20
+network:
+  allowed: [defaults]`
+
+	merged, conflicts, err := mergeFrontmatterStructurally(base, current, new)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, `# Local triage workflow
+on: push # runs on every push
+permissions:
+  contents: read
+  # needed to label issues
+  issues: write
+  pull-requests: write
+engine: 'claude'
+timeout-minutes: 20
+network:
+  allowed: [defaults]`, merged, "comments, key order and quoting of the local frontmatter should be kept")
+}
+
+func TestMergeFrontmatterStructurallyReindentsUpstreamValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		current  string
+		new      string
+		expected string
+	}{
+		{
+			name:    "local indents deeper than upstream",
+			base:    "on: push\ntools:\n  bash: [ls]",
+			current: "on: push\ntools:\n    bash: [ls]\n    web-fetch:",
+			new:     "on: push\ntools:\n  bash:\n    - ls\n    - cat\n  github:\n    toolsets: [issues]",
+			expected: `on: push
+tools:
+    bash:
+      - ls
+      - cat
+    web-fetch:
+    github:
+      toolsets: [issues]`,
+		},
+		{
+			name:    "local indents less than upstream",
+			base:    "on: push\ntools:\n    bash: [ls]",
+			current: "on: push\ntools:\n  bash: [ls]\n  web-fetch:",
+			new:     "on: push\ntools:\n    bash:\n        - ls\n        - cat\n    github:\n        toolsets: [issues]",
+			expected: `on: push
+tools:
+  bash:
+      - ls
+      - cat
+  web-fetch:
+  github:
+      toolsets: [issues]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts, err := mergeFrontmatterStructurally(tt.base, tt.current, tt.new)
+			require.NoError(t, err)
+			assert.Empty(t, conflicts)
+			assert.Equal(t, tt.expected, merged, "upstream values should be re-indented to the local nesting")
+
+			var value map[string]any
+			require.NoError(t, yaml.Unmarshal([]byte(merged), &value), "merged frontmatter should be valid YAML")
+		})
+	}
+}
+
+func TestMergeWorkflowContent_StructuralFrontmatter(t *testing.T) {
+	base := "---\non: push\npermissions:\n  contents: read\n---\n\n# Workflow\n"
+	current := "---\non: push\npermissions:\n  contents: read\n  issues: read\nsource: test/repo/workflow.md@v1.0.0\n---\n\n# Workflow\n"
+	new := "---\non: push\npermissions:\n  contents: read\n  actions: read\n---\n\n# Workflow\n"
+
+	merged, hasConflicts, err := MergeWorkflowContent(base, current, new, "test/repo/workflow.md@v1.0.0", "v1.1.0", false)
+	require.NoError(t, err)
+	assert.False(t, hasConflicts, "changes to different permission keys should merge cleanly")
+	assert.Contains(t, merged, "issues: read")
+	assert.Contains(t, merged, "actions: read")
+	assert.Contains(t, merged, "source: test/repo/workflow.md@v1.1.0")
+	assert.NotContains(t, merged, "<<<<<<<")
+}
+
+func TestSplitWorkflowFrontmatter(t *testing.T) {
+	content := "---\non: push\nengine: claude\n---\n\n# Workflow\n"
+	frontmatter, body, ok := splitWorkflowFrontmatter(content)
+	require.True(t, ok)
+	assert.Equal(t, "on: push\nengine: claude", frontmatter)
+	assert.Equal(t, content, joinWorkflowFrontmatter(frontmatter, body), "split and join should round-trip")
+
+	_, _, ok = splitWorkflowFrontmatter("# No frontmatter\n")
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var updatePackagesLog = logger.New("cli:update_packages")
+
+// fetchUpdatedPackageManifest fetches the manifest of an updated package; it is a variable
+// so that tests can replace GitHub
+var fetchUpdatedPackageManifest = fetchPackageManifest
+
+// installUpdatedPackageWorkflows adds the given workflows of a package through the add path
+// and refreshes the package's shared imports at its new version. It is a variable so that
+// tests can replace the add path.
+var installUpdatedPackageWorkflows = func(specs []string, pkg *ResolvedPackage, opts AddOptions) error {
+	if len(specs) > 0 {
+		if _, err := AddWorkflows(specs, opts); err != nil {
+			return err
+		}
+	}
+	opts.Force = true
+	return installPackageImports([]*ResolvedWorkflow{{Package: pkg}}, nil, opts)
+}
+
+// syncPackageWorkflows installs the workflows that the manifests of the updated packages
+// list but that are not installed yet, and refreshes the shared imports of those packages.
+// updated are the workflows update processed; workflowsDir holds every installed workflow.
+func syncPackageWorkflows(updated []*workflowWithSource, workflowsDir string, verbose, dryRun bool) error {
+	lock, err := parser.LoadAWLock(awLockRoot())
+	if err != nil || lock == nil {
+		return err
+	}
+
+	var repos []string
+	for _, wf := range updated {
+		spec, err := parseSourceSpec(wf.SourceSpec)
+		if err != nil || lock.Package(spec.Repo) == nil || slices.Contains(repos, spec.Repo) {
+			continue
+		}
+		repos = append(repos, spec.Repo)
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+	slices.Sort(repos)
+
+	// Workflows installed from each package, by source path, including those outside the name filter
+	all, err := findWorkflowsWithSource(workflowsDir, nil, verbose)
+	if err != nil {
+		return err
+	}
+	installed := make(map[string]bool)
+	for _, wf := range all {
+		if spec, err := parseSourceSpec(wf.SourceSpec); err == nil {
+			installed[spec.Repo+"/"+spec.Path] = true
+		}
+	}
+
+	for _, repo := range repos {
+		locked := lock.Package(repo)
+		manifest, err := fetchUpdatedPackageManifest(repo, locked.Version, verbose)
+		if err != nil {
+			return err
+		}
+
+		var specs []string
+		for _, workflowPath := range manifest.Workflows {
+			if !installed[repo+"/"+workflowPath] {
+				specs = append(specs, fmt.Sprintf("%s/%s@%s", repo, workflowPath, locked.Version))
+			}
+		}
+		updatePackagesLog.Printf("Package %s@%s lists %d workflow(s), %d not installed", repo, locked.Version, len(manifest.Workflows), len(specs))
+
+		if dryRun {
+			if len(specs) > 0 {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would install %d new workflow(s) of package %s@%s: %s", len(specs), repo, locked.Version, strings.Join(specs, ", "))))
+			}
+			continue
+		}
+
+		// add places workflows under .github/workflows, so other directories cannot receive new workflows
+		workflowDir := filepath.ToSlash(filepath.Clean(workflowsDir))
+		if len(specs) > 0 && !strings.HasPrefix(workflowDir, getWorkflowsDir()) {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Package %s@%s lists new workflows that cannot be installed into %s; add them with: %s add %s", repo, locked.Version, workflowsDir, string(constants.CLIExtensionPrefix), strings.Join(specs, " "))))
+			specs = nil
+		}
+		if len(specs) > 0 {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Installing %d new workflow(s) of package %s@%s", len(specs), repo, locked.Version)))
+		}
+
+		pkg := &ResolvedPackage{Repo: repo, Range: locked.Range, Version: locked.Version, Manifest: manifest}
+		if err := installUpdatedPackageWorkflows(specs, pkg, AddOptions{Verbose: verbose, Quiet: !verbose, WorkflowDir: workflowDir}); err != nil {
+			return fmt.Errorf("failed to install workflows of package %s: %w", repo, err)
+		}
+	}
+	return nil
+}
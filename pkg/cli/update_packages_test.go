@@ -0,0 +1,59 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncPackageWorkflowsInstallsNewManifestWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	workflowsDir := filepath.Join(".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "triage.md"), []byte("---\non: issues\nsource: acme/pack/workflows/triage.md@v1.2.0\n---\n# Triage\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "local.md"), []byte("---\non: push\n---\n# Local\n"), 0644))
+
+	lock := parser.NewAWLock()
+	lock.SetPackage("acme/pack", &parser.AWLockPackage{Range: "^1.0.0", Version: "v1.2.0"})
+	require.NoError(t, lock.Save(dir))
+
+	originalFetch := fetchUpdatedPackageManifest
+	originalInstall := installUpdatedPackageWorkflows
+	t.Cleanup(func() {
+		fetchUpdatedPackageManifest = originalFetch
+		installUpdatedPackageWorkflows = originalInstall
+	})
+	fetchUpdatedPackageManifest = func(repo, ref string, verbose bool) (*PackageManifest, error) {
+		assert.Equal(t, "acme/pack", repo)
+		assert.Equal(t, "v1.2.0", ref, "the manifest should be read at the updated version")
+		return &PackageManifest{Name: "pack", Workflows: []string{"workflows/triage.md", "workflows/report.md"}, Imports: []string{"shared/tools.md"}}, nil
+	}
+	var installedSpecs []string
+	var installedPkg *ResolvedPackage
+	installUpdatedPackageWorkflows = func(specs []string, pkg *ResolvedPackage, opts AddOptions) error {
+		installedSpecs = specs
+		installedPkg = pkg
+		return nil
+	}
+
+	updated, err := findWorkflowsWithSource(workflowsDir, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, syncPackageWorkflows(updated, workflowsDir, false, false))
+
+	assert.Equal(t, []string{"acme/pack/workflows/report.md@v1.2.0"}, installedSpecs, "only the workflow added to the manifest should be installed")
+	require.NotNil(t, installedPkg, "the package imports should be refreshed")
+	assert.Equal(t, "^1.0.0", installedPkg.Range)
+	assert.Equal(t, []string{"shared/tools.md"}, installedPkg.Manifest.Imports)
+
+	// A dry run only reports the new workflows
+	installedPkg = nil
+	require.NoError(t, syncPackageWorkflows(updated, workflowsDir, false, true))
+	assert.Nil(t, installedPkg, "dry run should not install anything")
+}
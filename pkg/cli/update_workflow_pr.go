@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var updateWorkflowPRLog = logger.New("cli:update_workflow_pr")
+
+// updateWorkflowsWithPR runs update on a temporary branch and opens a pull request with the
+// resulting changes. It returns a zero PR number and empty URL when the update changed nothing.
+func updateWorkflowsWithPR(workflowNames []string, update func() error, verbose bool) (int, string, error) {
+	updateWorkflowPRLog.Printf("Updating workflows with PR creation: workflows=%v", workflowNames)
+
+	// The branch is reset on failure, so only start from a clean working tree
+	if err := checkCleanWorkingDirectory(verbose); err != nil {
+		return 0, "", fmt.Errorf("--create-pull-request requires a clean working directory: %w", err)
+	}
+
+	branchSuffix := "all"
+	if len(workflowNames) == 1 {
+		branchSuffix = sanitizeBranchName(workflowNames[0])
+	}
+	title := "Update agentic workflows"
+	if len(workflowNames) > 0 {
+		title = "Update agentic workflows: " + strings.Join(workflowNames, ", ")
+	}
+
+	return createWorkflowPR(workflowPROptions{
+		BranchPrefix: "update-workflows-" + branchSuffix,
+		Title:        title,
+		Body:         updatePRBody,
+		Apply:        update,
+		Stage:        func() error { return stageAllChanges(verbose) },
+		Discard: func() {
+			_ = exec.Command("git", "reset", "--hard").Run()
+			_ = exec.Command("git", "clean", "-fd").Run()
+		},
+		SkipUnchanged: true,
+		Verbose:       verbose,
+	})
+}
+
+// updatePRBody lists the staged files in the pull request description
+func updatePRBody() string {
+	var sb strings.Builder
+	sb.WriteString("Update agentic workflows from their source repositories.\n")
+
+	output, err := exec.Command("git", "diff", "--cached", "--name-only").Output()
+	if err != nil {
+		return sb.String()
+	}
+	sb.WriteString("\nChanged files:\n\n")
+	for file := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if file != "" {
+			fmt.Fprintf(&sb, "- `%s`\n", file)
+		}
+	}
+	return sb.String()
+}
@@ -14,8 +14,8 @@ import (
 )
 
 // UpdateWorkflows updates workflows from their source repositories
-func UpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, engineOverride string, workflowsDir string, noStopAfter bool, stopAfter string, noMerge bool) error {
-	updateLog.Printf("Scanning for workflows with source field: dir=%s, filter=%v, noMerge=%v", workflowsDir, workflowNames, noMerge)
+func UpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, engineOverride string, workflowsDir string, noStopAfter bool, stopAfter string, noMerge, interactive, dryRun bool) error {
+	updateLog.Printf("Scanning for workflows with source field: dir=%s, filter=%v, noMerge=%v, interactive=%v, dryRun=%v", workflowsDir, workflowNames, noMerge, interactive, dryRun)
 
 	// Use provided workflows directory or default
 	if workflowsDir == "" {
@@ -47,7 +47,7 @@ func UpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, en
 	// Update each workflow
 	for _, wf := range workflows {
 		updateLog.Printf("Updating workflow: %s (source: %s)", wf.Name, wf.SourceSpec)
-		if err := updateWorkflow(wf, allowMajor, force, verbose, engineOverride, noStopAfter, stopAfter, noMerge); err != nil {
+		if err := updateWorkflow(wf, allowMajor, force, verbose, engineOverride, noStopAfter, stopAfter, noMerge, interactive, dryRun); err != nil {
 			updateLog.Printf("Failed to update workflow %s: %v", wf.Name, err)
 			failedUpdates = append(failedUpdates, updateFailure{
 				Name:  wf.Name,
@@ -59,6 +59,15 @@ func UpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, en
 		successfulUpdates = append(successfulUpdates, wf.Name)
 	}
 
+	// Install workflows newly listed by updated packages and refresh their shared imports
+	if err := syncPackageWorkflows(workflows, workflowsDir, verbose, dryRun); err != nil {
+		updateLog.Printf("Failed to sync package workflows: %v", err)
+		failedUpdates = append(failedUpdates, updateFailure{
+			Name:  "workflow packages",
+			Error: err.Error(),
+		})
+	}
+
 	// Show summary
 	showUpdateSummary(successfulUpdates, failedUpdates)
 
@@ -196,6 +205,43 @@ func resolveLatestRef(repo, currentRef string, allowMajor, verbose bool) (string
 	return latestSHA, nil
 }
 
+// resolvePackageUpdateRef resolves the highest tag within a package's version range.
+// Workflows added from a package record the installed commit SHA as their ref; when the
+// selected tag still points at that commit, the current ref is returned unchanged.
+func resolvePackageUpdateRef(repo, currentRef, rangeSpec string, verbose bool) (string, error) {
+	updateLog.Printf("Resolving package version: repo=%s, currentRef=%s, range=%s", repo, currentRef, rangeSpec)
+
+	versionRange, err := parseVersionRange(rangeSpec)
+	if err != nil {
+		return "", err
+	}
+	tags, err := listRepoTags(repo)
+	if err != nil {
+		return "", err
+	}
+	latestTag := selectHighestVersion(tags, versionRange)
+	if latestTag == "" {
+		return "", fmt.Errorf("no release of %s satisfies the package version range %s", repo, rangeSpec)
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Package %s follows %s, latest matching release: %s", repo, rangeSpec, latestTag)))
+	}
+
+	if IsCommitSHA(currentRef) {
+		owner, name, _ := strings.Cut(repo, "/")
+		tagSHA, err := parser.ResolveRefToSHA(owner, name, latestTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s@%s: %w", repo, latestTag, err)
+		}
+		if strings.EqualFold(tagSHA, currentRef) {
+			return currentRef, nil
+		}
+	}
+
+	return latestTag, nil
+}
+
 // resolveLatestCommitFromDefaultBranch fetches the latest commit SHA from
 // the default branch of a repo. This is used when the source field is pinned
 // to a commit SHA with no branch information — in that case we can only
@@ -319,8 +365,8 @@ func resolveLatestRelease(repo, currentRef string, allowMajor, verbose bool) (st
 }
 
 // updateWorkflow updates a single workflow from its source
-func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, engineOverride string, noStopAfter bool, stopAfter string, noMerge bool) error {
-	updateLog.Printf("Updating workflow: name=%s, source=%s, force=%v, noMerge=%v", wf.Name, wf.SourceSpec, force, noMerge)
+func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, engineOverride string, noStopAfter bool, stopAfter string, noMerge, interactive, dryRun bool) error {
+	updateLog.Printf("Updating workflow: name=%s, source=%s, force=%v, noMerge=%v, interactive=%v, dryRun=%v", wf.Name, wf.SourceSpec, force, noMerge, interactive, dryRun)
 
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("\nUpdating workflow: "+wf.Name))
@@ -340,8 +386,33 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 		currentRef = "main"
 	}
 
+	// The aw.lock.json pin, if any, records the exact upstream commit and content the
+	// workflow was taken from. Three-way merges use it as base since the ref may have moved.
+	pinned, err := lockedWorkflowPin(wf.Path, wf.SourceSpec)
+	if err != nil {
+		return err
+	}
+	baseRef := currentRef
+	if pinned != nil {
+		baseRef = pinned.SHA
+	}
+
+	// Workflows installed from a package follow the package's version range
+	pkg, err := lockedPackage(sourceSpec.Repo)
+	if err != nil {
+		return err
+	}
+
 	// Resolve latest ref
-	latestRef, err := resolveLatestRef(sourceSpec.Repo, currentRef, allowMajor, verbose)
+	var latestRef string
+	if pkg != nil && pkg.Range != "" {
+		if allowMajor {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s is installed from package %s with version range %s; --major does not apply, re-add the package to change the range", wf.Name, sourceSpec.Repo, pkg.Range)))
+		}
+		latestRef, err = resolvePackageUpdateRef(sourceSpec.Repo, currentRef, pkg.Range, verbose)
+	} else {
+		latestRef, err = resolveLatestRef(sourceSpec.Repo, currentRef, allowMajor, verbose)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to resolve latest ref: %w", err)
 	}
@@ -374,6 +445,17 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 			return nil
 		}
 
+		// Content that changed under a pinned ref means the upstream tag was moved or force-pushed
+		if pinned != nil {
+			if err := pinned.Verify(sourceContent); err != nil {
+				return fmt.Errorf("%w; %s may have been force-pushed upstream, review the change and use --force to re-pin", err, currentRef)
+			}
+		} else if dryRun {
+			updateLog.Printf("Dry run, not pinning workflow %s", wf.Name)
+		} else if err := pinWorkflowInLock(wf.Path, wf.SourceSpec, sourceSpec.Repo, currentRef, sourceContent, true); err != nil {
+			return fmt.Errorf("failed to pin workflow in %s: %w", parser.AWLockFile, err)
+		}
+
 		// Read current workflow content
 		currentContent, err := os.ReadFile(wf.Path)
 		if err != nil {
@@ -411,7 +493,7 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 	// When merge mode is on, detect local modifications to confirm we
 	// actually need to merge (if no local mods, override is fine either way).
 	if merge {
-		baseContent, dlErr := downloadWorkflowContent(sourceSpec.Repo, sourceSpec.Path, currentRef, verbose)
+		baseContent, dlErr := downloadWorkflowContent(sourceSpec.Repo, sourceSpec.Path, baseRef, verbose)
 		if dlErr == nil {
 			localContent, readErr := os.ReadFile(wf.Path)
 			if readErr == nil && hasLocalModifications(string(baseContent), string(localContent), wf.SourceSpec, verbose) {
@@ -436,10 +518,10 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 
 		// Download the base version (current ref from source)
 		if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Downloading base version from %s/%s@%s", sourceSpec.Repo, sourceSpec.Path, currentRef)))
+			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Downloading base version from %s/%s@%s", sourceSpec.Repo, sourceSpec.Path, baseRef)))
 		}
 
-		baseContent, err := downloadWorkflowContent(sourceSpec.Repo, sourceSpec.Path, currentRef, verbose)
+		baseContent, err := downloadWorkflowContent(sourceSpec.Repo, sourceSpec.Path, baseRef, verbose)
 		if err != nil {
 			return fmt.Errorf("failed to download base workflow: %w", err)
 		}
@@ -464,6 +546,19 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 		if hasConflicts {
 			updateLog.Printf("Merge conflicts detected in workflow: %s", wf.Name)
 		}
+
+		// Walk through each conflict hunk and let the user pick a side
+		if hasConflicts && interactive {
+			resolved, remaining, err := resolveMergeConflicts(finalContent, promptConflictResolution)
+			if err != nil {
+				return fmt.Errorf("failed to resolve merge conflicts: %w", err)
+			}
+			finalContent = resolved
+			if remaining == 0 {
+				hasConflicts = false
+				finalContent = processMergedIncludes(finalContent, wf.SourceSpec, sourceFieldRef, verbose)
+			}
+		}
 	} else {
 		// Override mode (default): replace local file with new content from source
 		if verbose {
@@ -531,6 +626,11 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 		}
 	}
 
+	// In dry-run mode, show the result as a diff against the local file without writing anything
+	if dryRun {
+		return showUpdateDryRun(wf, finalContent, hasConflicts, currentRef, latestRef)
+	}
+
 	// Write updated content
 	if err := os.WriteFile(wf.Path, []byte(finalContent), 0644); err != nil {
 		return fmt.Errorf("failed to write updated workflow: %w", err)
@@ -544,9 +644,25 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 	updateLog.Printf("Successfully updated workflow %s from %s to %s", wf.Name, currentRef, latestRef)
 	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated %s from %s to %s", wf.Name, shortRef(currentRef), shortRef(latestRef))))
 
-	// Compile the updated workflow with refreshStopTime enabled
+	// Re-pin the workflow in aw.lock.json to the source written into the file
+	newSource := wf.SourceSpec
+	if result, err := parser.ExtractFrontmatterFromContent(finalContent); err == nil {
+		if source, ok := result.Frontmatter["source"].(string); ok && source != "" {
+			newSource = strings.TrimSpace(source)
+		}
+	}
+	if err := pinWorkflowInLock(wf.Path, newSource, sourceSpec.Repo, latestRef, newContent, true); err != nil {
+		return fmt.Errorf("failed to pin workflow in %s: %w", parser.AWLockFile, err)
+	}
+	if pkg != nil && pkg.Range != "" && isSemanticVersionTag(latestRef) {
+		if err := recordPackageVersion(sourceSpec.Repo, latestRef); err != nil {
+			return fmt.Errorf("failed to record package version in %s: %w", parser.AWLockFile, err)
+		}
+	}
+
+	// Compile the updated workflow with refreshStopTime enabled; remote imports are re-pinned
 	updateLog.Printf("Compiling updated workflow: %s", wf.Name)
-	if err := compileWorkflowWithRefresh(wf.Path, verbose, false, engineOverride, true); err != nil {
+	if err := compileWorkflowWithRefresh(wf.Path, verbose, false, engineOverride, true, true); err != nil {
 		updateLog.Printf("Compilation failed for workflow %s: %v", wf.Name, err)
 		return fmt.Errorf("failed to compile updated workflow: %w", err)
 	}
@@ -554,6 +670,42 @@ func updateWorkflow(wf *workflowWithSource, allowMajor, force, verbose bool, eng
 	return nil
 }
 
+// showUpdateDryRun prints the changes an update would make to a workflow as a unified diff
+func showUpdateDryRun(wf *workflowWithSource, finalContent string, hasConflicts bool, currentRef, latestRef string) error {
+	currentContent, err := os.ReadFile(wf.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read current workflow: %w", err)
+	}
+
+	diff, err := unifiedDiff(wf.Path, string(currentContent), finalContent)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would update %s from %s to %s with no changes to the local file", wf.Name, shortRef(currentRef), shortRef(latestRef))))
+		return nil
+	}
+	fmt.Fprint(os.Stdout, diff)
+
+	if hasConflicts {
+		segments, err := parseMergeConflicts(finalContent)
+		if err != nil {
+			return err
+		}
+		conflicts := 0
+		for _, segment := range segments {
+			if segment.Conflict != nil {
+				conflicts++
+			}
+		}
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Would update %s from %s to %s with %d CONFLICT(S) - rerun with --interactive to resolve them", wf.Name, shortRef(currentRef), shortRef(latestRef), conflicts)))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would update %s from %s to %s (dry run, no files written)", wf.Name, shortRef(currentRef), shortRef(latestRef))))
+	return nil
+}
+
 // isBranchRef returns true when the ref is a branch name — i.e. it is
 // neither a semantic-version tag nor a full commit SHA.
 func isBranchRef(ref string) bool {
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var workflowPRLog = logger.New("cli:workflow_pr")
+
+// workflowPROptions describes a pull request opened from changes made on a temporary branch
+type workflowPROptions struct {
+	BranchPrefix string        // Temporary branch name without its random suffix
+	Title        string        // Commit message and pull request title
+	Body         func() string // Pull request description, computed after the changes are staged
+	Apply        func() error  // Makes the changes on the temporary branch
+	Stage        func() error  // Stages the changes made by Apply
+	Discard      func()        // Drops the changes made by Apply after a failure
+	// SkipUnchanged returns without a pull request when Apply changed nothing
+	SkipUnchanged bool
+	// CommitError, when set, builds the error returned when committing fails; the staged
+	// changes and the temporary branch are then kept for manual recovery
+	CommitError func(err error, branchName string) error
+	Verbose     bool
+}
+
+// createWorkflowPR creates a temporary branch, applies and commits the changes, pushes the
+// branch and opens a pull request, then switches back to the original branch. On failure
+// the changes and the temporary branch are discarded. It returns a zero PR number and an
+// empty URL when SkipUnchanged is set and nothing changed.
+func createWorkflowPR(opts workflowPROptions) (int, string, error) {
+	currentBranch, err := getCurrentBranch()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	randomNum := rand.Intn(9000) + 1000 // Generate number between 1000-9999
+	branchName := fmt.Sprintf("%s-%04d", opts.BranchPrefix, randomNum)
+	workflowPRLog.Printf("Creating temporary branch %s from %s", branchName, currentBranch)
+	if err := createAndSwitchBranch(branchName, opts.Verbose); err != nil {
+		return 0, "", fmt.Errorf("failed to create branch %s: %w", branchName, err)
+	}
+
+	switchBack := func() bool {
+		if err := switchBranch(currentBranch, opts.Verbose); err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to switch back to branch %s: %v", currentBranch, err)))
+			return false
+		}
+		return true
+	}
+	// discard drops the changes and the temporary branch
+	discard := func() {
+		opts.Discard()
+		if switchBack() {
+			_ = exec.Command("git", "branch", "-D", branchName).Run()
+		}
+	}
+
+	if err := opts.Apply(); err != nil {
+		discard()
+		return 0, "", err
+	}
+
+	if opts.SkipUnchanged {
+		hasChanges, err := hasChangesToCommit()
+		if err != nil {
+			discard()
+			return 0, "", err
+		}
+		if !hasChanges {
+			workflowPRLog.Print("No changes, skipping pull request")
+			discard()
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflow changes, pull request not created"))
+			return 0, "", nil
+		}
+	}
+
+	if err := opts.Stage(); err != nil {
+		discard()
+		return 0, "", err
+	}
+	if err := stageGitAttributesIfChanged(); err != nil && opts.Verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to stage .gitattributes: %v", err)))
+	}
+	body := opts.Body()
+
+	if err := commitChanges(opts.Title, opts.Verbose); err != nil {
+		if opts.CommitError != nil {
+			switchBack()
+			return 0, "", opts.CommitError(err, branchName)
+		}
+		discard()
+		return 0, "", fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	workflowPRLog.Printf("Pushing branch %s to remote", branchName)
+	if err := pushBranch(branchName, opts.Verbose); err != nil {
+		discard()
+		return 0, "", fmt.Errorf("failed to push branch %s: %w", branchName, err)
+	}
+
+	workflowPRLog.Printf("Creating pull request: %s", opts.Title)
+	prNumber, prURL, err := createPR(branchName, opts.Title, body, opts.Verbose)
+	if err != nil {
+		discard()
+		return 0, "", fmt.Errorf("failed to create PR: %w", err)
+	}
+	workflowPRLog.Printf("Successfully created PR #%d: %s", prNumber, prURL)
+
+	if err := switchBranch(currentBranch, opts.Verbose); err != nil {
+		return prNumber, prURL, fmt.Errorf("failed to switch back to branch %s: %w", currentBranch, err)
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Created pull request "+prURL))
+	return prNumber, prURL, nil
+}
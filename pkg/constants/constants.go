@@ -638,6 +638,9 @@ const AgentJobName JobName = "agent"
 const ActivationJobName JobName = "activation"
 const PreActivationJobName JobName = "pre_activation"
 const DetectionJobName JobName = "detection"
+const BatchAccumulateJobName JobName = "batch_accumulate"
+const BatchFlushJobName JobName = "batch_flush"
+const DebounceJobName JobName = "debounce"
 const SafeOutputArtifactName = "safe-output"
 const AgentOutputArtifactName = "agent-output"
 
@@ -701,6 +704,10 @@ const CheckCommandPositionStepID StepID = "check_command_position"
 const CheckRateLimitStepID StepID = "check_rate_limit"
 const CheckSkipRolesStepID StepID = "check_skip_roles"
 const CheckSkipBotsStepID StepID = "check_skip_bots"
+const CheckScheduleCalendarStepID StepID = "check_schedule_calendar"
+const CheckConcurrencyPolicyStepID StepID = "check_concurrency_policy"
+const CheckBatchEventsStepID StepID = "check_batch_events"
+const CollectBatchEventsStepID StepID = "collect_batch_events"
 
 // Output names for pre-activation job steps
 const IsTeamMemberOutput = "is_team_member"
@@ -712,12 +719,26 @@ const MatchedCommandOutput = "matched_command"
 const RateLimitOkOutput = "rate_limit_ok"
 const SkipRolesOkOutput = "skip_roles_ok"
 const SkipBotsOkOutput = "skip_bots_ok"
+const ScheduleCalendarOkOutput = "schedule_calendar_ok"
+const ConcurrencyPolicyOkOutput = "concurrency_policy_ok"
+const BatchOkOutput = "batch_ok"
+const BatchRecordOutput = "batch_record"
+const BatchEventsOutput = "batch_events"
 const ActivatedOutput = "activated"
 
 // Rate limit defaults
 const DefaultRateLimitMax = 5     // Default maximum runs per time window
 const DefaultRateLimitWindow = 60 // Default time window in minutes (1 hour)
 
+// Concurrency policy defaults
+const DefaultConcurrencyQueueDepth = 5      // Default number of runs allowed to wait in a queue
+const DefaultConcurrencyQuietMinutes = 5    // Default quiet period of a debounced workflow in minutes
+const DefaultConcurrencyMaxWaitMinutes = 60 // Default time a queued run waits for the runs ahead of it
+
+// Batch defaults
+const DefaultBatchWindow = "1h"   // Default flush interval of batched events
+const DefaultBatchMaxEvents = 100 // Default maximum number of events passed to one flush run
+
 // Agentic engine name constants using EngineName type for type safety
 const (
 	// CopilotEngine is the GitHub Copilot engine identifier
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var awLockLog = logger.New("parser:aw_lock")
+
+const (
+	// AWLockFile is the repository-relative path of the lockfile pinning remote workflows and imports
+	AWLockFile = ".github/aw/aw.lock.json"
+
+	// awLockVersion is the current lockfile format version
+	awLockVersion = 1
+
+	// contentHashPrefix prefixes content hashes to identify the algorithm
+	contentHashPrefix = "sha256:"
+)
+
+// AWLockEntry pins a remote workflow or import to a commit SHA and content hash
+type AWLockEntry struct {
+	Source string `json:"source"` // Workflowspec as written (owner/repo/path@ref)
+	SHA    string `json:"sha"`    // Commit SHA the ref resolved to
+	Hash   string `json:"hash"`   // Content hash of the file at SHA (sha256:<hex>)
+}
+
+// AWLockPackage records an installed workflow package and the version range it follows
+type AWLockPackage struct {
+	Range   string `json:"range,omitempty"` // Semantic version range followed by update (e.g. ^1.2); empty when pinned to a branch or SHA
+	Version string `json:"version"`         // Tag, branch or SHA the package was installed from
+}
+
+// AWLock is the repository-level lockfile (aw.lock.json) pinning every remote
+// workflow source and remote import to a commit SHA and content hash
+type AWLock struct {
+	Version   int                       `json:"version"`
+	Workflows map[string]*AWLockEntry   `json:"workflows,omitempty"` // Keyed by workflow path relative to the repository root
+	Imports   map[string]*AWLockEntry   `json:"imports,omitempty"`   // Keyed by import workflowspec (owner/repo/path@ref)
+	Packages  map[string]*AWLockPackage `json:"packages,omitempty"`  // Keyed by package repository (owner/repo)
+
+	mu sync.Mutex
+}
+
+// NewAWLock creates an empty lockfile
+func NewAWLock() *AWLock {
+	return &AWLock{
+		Version:   awLockVersion,
+		Workflows: make(map[string]*AWLockEntry),
+		Imports:   make(map[string]*AWLockEntry),
+		Packages:  make(map[string]*AWLockPackage),
+	}
+}
+
+// LoadAWLock reads the lockfile from the repository root.
+// Returns nil without error when the repository has no lockfile.
+func LoadAWLock(repoRoot string) (*AWLock, error) {
+	lockPath := filepath.Join(repoRoot, AWLockFile)
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			awLockLog.Printf("No lockfile at %s", lockPath)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", AWLockFile, err)
+	}
+
+	lock := NewAWLock()
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", AWLockFile, err)
+	}
+	if lock.Version > awLockVersion {
+		return nil, fmt.Errorf("%s has version %d, but this version of gh-aw supports up to version %d; upgrade gh-aw", AWLockFile, lock.Version, awLockVersion)
+	}
+	if lock.Workflows == nil {
+		lock.Workflows = make(map[string]*AWLockEntry)
+	}
+	if lock.Imports == nil {
+		lock.Imports = make(map[string]*AWLockEntry)
+	}
+	if lock.Packages == nil {
+		lock.Packages = make(map[string]*AWLockPackage)
+	}
+	awLockLog.Printf("Loaded lockfile: workflows=%d, imports=%d", len(lock.Workflows), len(lock.Imports))
+	return lock, nil
+}
+
+// Save writes the lockfile to the repository root
+func (l *AWLock) Save(repoRoot string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Version = awLockVersion
+	// encoding/json sorts map keys, which keeps the file diff-friendly
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", AWLockFile, err)
+	}
+
+	lockPath := filepath.Join(repoRoot, AWLockFile)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", AWLockFile, err)
+	}
+	if err := os.WriteFile(lockPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", AWLockFile, err)
+	}
+	awLockLog.Printf("Saved lockfile: workflows=%d, imports=%d", len(l.Workflows), len(l.Imports))
+	return nil
+}
+
+// Import returns the pinned entry for an import workflowspec, or nil if not locked
+func (l *AWLock) Import(spec string) *AWLockEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Imports[spec]
+}
+
+// SetImport pins an import workflowspec
+func (l *AWLock) SetImport(spec string, entry *AWLockEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Imports[spec] = entry
+}
+
+// Workflow returns the pinned entry for a workflow path, or nil if not locked
+func (l *AWLock) Workflow(path string) *AWLockEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Workflows[filepath.ToSlash(path)]
+}
+
+// SetWorkflow pins the source of a workflow path
+func (l *AWLock) SetWorkflow(path string, entry *AWLockEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Workflows[filepath.ToSlash(path)] = entry
+}
+
+// Package returns the installed package for a repository (owner/repo), or nil if not installed
+func (l *AWLock) Package(repo string) *AWLockPackage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Packages[repo]
+}
+
+// SetPackage records an installed package
+func (l *AWLock) SetPackage(repo string, pkg *AWLockPackage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Packages[repo] = pkg
+}
+
+// ComputeContentHash returns the lockfile content hash (sha256:<hex>) of a file
+func ComputeContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return contentHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// Verify checks that content matches the pinned content hash
+func (e *AWLockEntry) Verify(content []byte) error {
+	if actual := ComputeContentHash(content); actual != e.Hash {
+		return fmt.Errorf("content hash mismatch for %s at %s: %s pins %s but the content hashes to %s", e.Source, e.SHA, AWLockFile, e.Hash, actual)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func TestLoadAWLockMissing(t *testing.T) {
+	lock, err := LoadAWLock(t.TempDir())
+	require.NoError(t, err, "a missing lockfile is not an error")
+	assert.Nil(t, lock, "a missing lockfile should load as nil")
+}
+
+func TestAWLockSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	lock := NewAWLock()
+	lock.SetWorkflow(filepath.Join(".github", "workflows", "triage.md"), &AWLockEntry{Source: "octo/aw/workflows/triage.md@v1.0.0", SHA: testLockSHA, Hash: ComputeContentHash([]byte("triage"))})
+	lock.SetImport("octo/aw/shared/tools.md@v1.0.0", &AWLockEntry{Source: "octo/aw/shared/tools.md@v1.0.0", SHA: testLockSHA, Hash: ComputeContentHash([]byte("tools"))})
+	require.NoError(t, lock.Save(dir))
+
+	loaded, err := LoadAWLock(dir)
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, 1, loaded.Version)
+	require.NotNil(t, loaded.Workflow(".github/workflows/triage.md"), "workflow keys should use forward slashes")
+	assert.Equal(t, testLockSHA, loaded.Import("octo/aw/shared/tools.md@v1.0.0").SHA)
+	assert.Nil(t, loaded.Import("octo/aw/shared/other.md@v1.0.0"))
+}
+
+func TestLoadAWLockRejectsNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, AWLockFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(lockPath), 0755))
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{"version": 99}`), 0644))
+
+	_, err := LoadAWLock(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version 99")
+}
+
+func TestAWLockEntryVerify(t *testing.T) {
+	entry := &AWLockEntry{Source: "octo/aw/shared/tools.md@v1.0.0", SHA: testLockSHA, Hash: ComputeContentHash([]byte("tools"))}
+	require.NoError(t, entry.Verify([]byte("tools")))
+
+	err := entry.Verify([]byte("tampered"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "content hash mismatch")
+}
+
+func TestDownloadIncludeUsesLockfilePin(t *testing.T) {
+	const spec = "octo/aw/shared/tools.md@v1.0.0"
+	content := []byte("# Tools\n")
+
+	setup := func(t *testing.T, cached []byte) *ImportCache {
+		dir := t.TempDir()
+		lock := NewAWLock()
+		lock.SetImport(spec, &AWLockEntry{Source: spec, SHA: testLockSHA, Hash: ComputeContentHash(content)})
+		require.NoError(t, lock.Save(dir))
+
+		cache := NewImportCache(dir)
+		_, err := cache.Set("octo", "aw", "shared/tools.md", testLockSHA, cached)
+		require.NoError(t, err)
+		return cache
+	}
+
+	t.Run("pinned import resolves offline from the cache", func(t *testing.T) {
+		cache := setup(t, content)
+		path, err := downloadIncludeFromWorkflowSpec(spec, cache)
+		require.NoError(t, err)
+		expectedPath, _ := cache.Get("octo", "aw", "shared/tools.md", testLockSHA)
+		assert.Equal(t, expectedPath, path)
+	})
+
+	t.Run("modified cached copy fails verification", func(t *testing.T) {
+		cache := setup(t, []byte("# Tampered\n"))
+		_, err := downloadIncludeFromWorkflowSpec(spec+"#Section", cache)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "content hash mismatch")
+	})
+}
+
+func TestAWLockPackages(t *testing.T) {
+	dir := t.TempDir()
+	lock := NewAWLock()
+	lock.SetPackage("octo/pack", &AWLockPackage{Range: "^1.2", Version: "v1.3.0"})
+	require.NoError(t, lock.Save(dir))
+
+	loaded, err := LoadAWLock(dir)
+	require.NoError(t, err)
+	require.NotNil(t, loaded.Package("octo/pack"))
+	assert.Equal(t, "^1.2", loaded.Package("octo/pack").Range)
+	assert.Equal(t, "v1.3.0", loaded.Package("octo/pack").Version)
+	assert.Nil(t, loaded.Package("octo/other"))
+}
+
+func TestImportCacheRecordImport(t *testing.T) {
+	const spec = "octo/aw/shared/tools.md@v1.0.0"
+	content := []byte("# Tools\n")
+
+	setup := func(t *testing.T) (*ImportCache, string, []byte) {
+		dir := t.TempDir()
+		require.NoError(t, NewAWLock().Save(dir))
+		lockPath := filepath.Join(dir, AWLockFile)
+		before, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		return NewImportCache(dir), lockPath, before
+	}
+
+	t.Run("unpinned import is rejected without recording", func(t *testing.T) {
+		cache, lockPath, before := setup(t)
+		err := cache.recordImport(spec, testLockSHA, content)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not pinned")
+
+		require.NoError(t, cache.SaveLock())
+		after, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "the lockfile should be left untouched")
+	})
+
+	t.Run("recorded pins are only written by SaveLock", func(t *testing.T) {
+		cache, lockPath, before := setup(t)
+		cache.SetRecordLock(true)
+		require.NoError(t, cache.recordImport(spec, testLockSHA, content))
+
+		after, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "recording should not write the lockfile")
+
+		require.NoError(t, cache.SaveLock())
+		loaded, err := LoadAWLock(filepath.Dir(filepath.Dir(filepath.Dir(lockPath))))
+		require.NoError(t, err)
+		require.NotNil(t, loaded.Import(spec))
+		assert.Equal(t, testLockSHA, loaded.Import(spec).SHA)
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -55,6 +57,13 @@ func validatePathComponents(owner, repo, path, sha string) error {
 // ImportCache manages cached imported workflow files
 type ImportCache struct {
 	baseDir string // Base directory for cache (typically repo root)
+
+	lockOnce    sync.Once
+	lock        *AWLock // Lockfile pinning remote imports (nil when the repository has none)
+	lockErr     error
+	refreshLock bool // Re-resolve pinned imports and rewrite their lockfile entries
+	recordLock  bool // Pin unpinned imports in the lockfile (add and update) instead of rejecting them
+	lockDirty   atomic.Bool
 }
 
 // NewImportCache creates a new import cache instance
@@ -172,3 +181,76 @@ func (c *ImportCache) ensureGitAttributes() error {
 	importCacheLog.Printf("Created .gitattributes in cache directory: %s", gitAttributesPath)
 	return nil
 }
+
+// Lock returns the lockfile of the repository, loading it on first use.
+// Returns nil when the repository has no aw.lock.json.
+func (c *ImportCache) Lock() (*AWLock, error) {
+	c.lockOnce.Do(func() {
+		if c.lock == nil {
+			c.lock, c.lockErr = LoadAWLock(c.baseDir)
+		}
+	})
+	return c.lock, c.lockErr
+}
+
+// SetLock sets the lockfile used to pin and record remote imports
+func (c *ImportCache) SetLock(lock *AWLock) {
+	c.lockOnce.Do(func() {})
+	c.lock = lock
+	c.lockErr = nil
+}
+
+// SetRefreshLock configures whether pinned imports are re-resolved from their ref
+// and re-recorded, as done by 'gh aw update'
+func (c *ImportCache) SetRefreshLock(refresh bool) {
+	c.refreshLock = refresh
+}
+
+// SetRecordLock configures whether imports missing from the lockfile are pinned, as done
+// by 'gh aw add' and 'gh aw update'. Pins are only written to disk by SaveLock; compile
+// never writes the lockfile and rejects imports that are not pinned.
+func (c *ImportCache) SetRecordLock(record bool) {
+	c.recordLock = record
+}
+
+// SaveLock writes the lockfile when imports were pinned since it was loaded
+func (c *ImportCache) SaveLock() error {
+	if !c.lockDirty.Load() {
+		return nil
+	}
+	lock, err := c.Lock()
+	if err != nil || lock == nil {
+		return err
+	}
+	if err := lock.Save(c.baseDir); err != nil {
+		return err
+	}
+	c.lockDirty.Store(false)
+	return nil
+}
+
+// pinnedImport returns the lockfile entry for an import workflowspec, or nil when
+// the import is not pinned or pins are being refreshed
+func (c *ImportCache) pinnedImport(spec string) (*AWLockEntry, error) {
+	lock, err := c.Lock()
+	if err != nil || lock == nil || c.refreshLock {
+		return nil, err
+	}
+	return lock.Import(spec), nil
+}
+
+// recordImport pins an import in the in-memory lockfile, if the repository has one.
+// Unless pins are being recorded, an import missing from the lockfile is an error.
+func (c *ImportCache) recordImport(spec, sha string, content []byte) error {
+	lock, err := c.Lock()
+	if err != nil || lock == nil {
+		return err
+	}
+	if !c.recordLock {
+		return fmt.Errorf("import %s is not pinned in %s. Run 'gh aw update' to pin it", spec, AWLockFile)
+	}
+	importCacheLog.Printf("Recording import in lockfile: %s@%s", spec, sha)
+	lock.SetImport(spec, &AWLockEntry{Source: spec, SHA: sha, Hash: ComputeContentHash(content)})
+	c.lockDirty.Store(true)
+	return nil
+}
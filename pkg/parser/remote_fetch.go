@@ -232,6 +232,19 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 	filePath := strings.Join(slashParts[2:], "/")
 	remoteLog.Printf("Parsed workflowspec: owner=%s, repo=%s, file=%s, ref=%s", owner, repo, filePath, ref)
 
+	// Imports pinned in aw.lock.json are fetched at the pinned commit and verified
+	// against the pinned content hash, without resolving the (possibly moved) ref
+	if cache != nil {
+		pinned, err := cache.pinnedImport(cleanSpec)
+		if err != nil {
+			return "", err
+		}
+		if pinned != nil {
+			remoteLog.Printf("Using lockfile pin for %s: %s", cleanSpec, pinned.SHA)
+			return downloadPinnedInclude(owner, repo, filePath, pinned, cache)
+		}
+	}
+
 	// Resolve ref to SHA for cache lookup
 	var sha string
 	if cache != nil {
@@ -250,6 +263,11 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 			// Check cache using SHA
 			if cachedPath, found := cache.Get(owner, repo, filePath, sha); found {
 				remoteLog.Printf("Using cached import: %s/%s/%s@%s (SHA: %s)", owner, repo, filePath, ref, sha)
+				if content, err := os.ReadFile(cachedPath); err == nil {
+					if err := cache.recordImport(cleanSpec, sha, content); err != nil {
+						return "", err
+					}
+				}
 				return cachedPath, nil
 			}
 		}
@@ -265,6 +283,9 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 
 	// If cache is available and we have a SHA, store in cache
 	if cache != nil && sha != "" {
+		if err := cache.recordImport(cleanSpec, sha, content); err != nil {
+			return "", err
+		}
 		cachedPath, err := cache.Set(owner, repo, filePath, sha, content)
 		if err != nil {
 			remoteLog.Printf("Failed to cache import: %v", err)
@@ -303,6 +324,31 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 	return tempFile.Name(), nil
 }
 
+// downloadPinnedInclude returns an import pinned in the lockfile, reading it from the
+// cache when available and downloading it at the pinned commit otherwise. The content
+// must match the pinned content hash.
+func downloadPinnedInclude(owner, repo, filePath string, pinned *AWLockEntry, cache *ImportCache) (string, error) {
+	if cachedPath, found := cache.Get(owner, repo, filePath, pinned.SHA); found {
+		content, err := os.ReadFile(cachedPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read cached import %s: %w", cachedPath, err)
+		}
+		if err := pinned.Verify(content); err != nil {
+			return "", fmt.Errorf("%w; the cached copy %s was modified, delete it or run 'gh aw update' to re-pin", err, cachedPath)
+		}
+		return cachedPath, nil
+	}
+
+	content, err := downloadFileFromGitHub(owner, repo, filePath, pinned.SHA)
+	if err != nil {
+		return "", fmt.Errorf("failed to download pinned include %s at %s: %w", pinned.Source, pinned.SHA, err)
+	}
+	if err := pinned.Verify(content); err != nil {
+		return "", fmt.Errorf("%w; review the upstream change and run 'gh aw update' to re-pin", err)
+	}
+	return cache.Set(owner, repo, filePath, pinned.SHA, content)
+}
+
 // resolveRefToSHAViaGit resolves a git ref to SHA using git ls-remote
 // This is a fallback for when GitHub API authentication fails
 func resolveRefToSHAViaGit(owner, repo, ref string) (string, error) {
@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var scheduleCronNextLog = logger.New("parser:schedule_cron_next")
+
+// This file evaluates standard 5-field cron expressions the way GitHub Actions does:
+// fire times are computed in UTC, and when both day-of-month and day-of-week are
+// restricted a day matches if either field matches.
+
+// maxCronSearchYears bounds the search for the next fire time of expressions that
+// rarely or never match (e.g. "0 0 31 2 *")
+const maxCronSearchYears = 5
+
+// CronSchedule is a parsed cron expression
+type CronSchedule struct {
+	Expression string
+
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	dayOfMonthStar, dayOfWeekStar              bool
+}
+
+// cronField describes the valid range of a cron field
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 7 is an alias for Sunday
+}
+
+// ParseCronSchedule parses a 5-field cron expression (minute hour day-of-month month
+// day-of-week) supporting '*', lists, ranges and steps
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	if IsFuzzyCron(expression) {
+		return nil, fmt.Errorf("fuzzy schedule %q must be scattered before fire times can be computed", expression)
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: must have exactly 5 fields (minute hour day-of-month month day-of-week)", expression)
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+		}
+		bits[i] = value
+	}
+	// Fold day-of-week 7 into 0 (Sunday)
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		Expression:     expression,
+		minute:         bits[0],
+		hour:           bits[1],
+		dayOfMonth:     bits[2],
+		month:          bits[3],
+		dayOfWeek:      bits[4],
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bit set of allowed values
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, spec.name)
+			}
+			step = n
+		}
+
+		low, high := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowStr, highStr, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowStr, spec); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highStr, spec); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, spec.name)
+			}
+		default:
+			value, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a single numeric cron value and checks its range
+func parseCronValue(s string, spec cronField) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, spec.name)
+	}
+	if value < spec.min || value > spec.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d in %s field", value, spec.min, spec.max, spec.name)
+	}
+	return value, nil
+}
+
+// matchesDay reports whether the schedule fires on the day of t
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<t.Day()) != 0
+	dowMatch := s.dayOfWeek&(1<<int(t.Weekday())) != 0
+	if !s.dayOfMonthStar && !s.dayOfWeekStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next returns the first fire time strictly after the given time, in UTC. It returns the
+// zero time when the expression does not fire within the search window.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	scheduleCronNextLog.Printf("No fire time within %d years for %s", maxCronSearchYears, s.Expression)
+	return time.Time{}
+}
+
+// NextCronTimes returns the next count fire times of a cron expression after the given time
+func NextCronTimes(expression string, after time.Time, count int) ([]time.Time, error) {
+	schedule, err := ParseCronSchedule(expression)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, errors.New("count must be at least 1")
+	}
+
+	times := make([]time.Time, 0, count)
+	for len(times) < count {
+		next := schedule.Next(after)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+		after = next
+	}
+	return times, nil
+}
@@ -0,0 +1,66 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextCronTimes(t *testing.T) {
+	// Wednesday 2026-01-14 10:30 UTC
+	from := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cron     string
+		count    int
+		expected []string
+	}{
+		{"daily", "17 9 * * *", 2, []string{"2026-01-15T09:17:00Z", "2026-01-16T09:17:00Z"}},
+		{"same day later", "45 10 * * *", 1, []string{"2026-01-14T10:45:00Z"}},
+		{"every six hours", "12 */6 * * *", 3, []string{"2026-01-14T12:12:00Z", "2026-01-14T18:12:00Z", "2026-01-15T00:12:00Z"}},
+		{"weekdays", "0 9 * * 1-5", 3, []string{"2026-01-15T09:00:00Z", "2026-01-16T09:00:00Z", "2026-01-19T09:00:00Z"}},
+		{"sunday as 7", "0 0 * * 7", 1, []string{"2026-01-18T00:00:00Z"}},
+		{"list and step start", "5/20 11 * * *", 3, []string{"2026-01-14T11:05:00Z", "2026-01-14T11:25:00Z", "2026-01-14T11:45:00Z"}},
+		{"month rollover", "0 0 1 * *", 2, []string{"2026-02-01T00:00:00Z", "2026-03-01T00:00:00Z"}},
+		{"day of month or day of week", "0 0 20 * 5", 3, []string{"2026-01-16T00:00:00Z", "2026-01-20T00:00:00Z", "2026-01-23T00:00:00Z"}},
+		{"never fires", "0 0 31 2 *", 1, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, err := NextCronTimes(tt.cron, from, tt.count)
+			require.NoError(t, err, "cron should parse")
+			actual := make([]string, 0, len(times))
+			for _, fireTime := range times {
+				actual = append(actual, fireTime.Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.expected, actual, "fire times should match")
+		})
+	}
+}
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	tests := []struct {
+		cron string
+		want string
+	}{
+		{"0 0 * *", "exactly 5 fields"},
+		{"60 0 * * *", "out of range"},
+		{"0 0 0 * *", "out of range"},
+		{"0 5-2 * * *", "invalid range"},
+		{"*/0 * * * *", "invalid step"},
+		{"0 0 * JAN *", "invalid value"},
+		{"FUZZY:DAILY * * *", "must be scattered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cron, func(t *testing.T) {
+			_, err := ParseCronSchedule(tt.cron)
+			require.Error(t, err, "invalid cron should be rejected")
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
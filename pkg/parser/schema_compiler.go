@@ -88,8 +88,11 @@ var safeOutputMetaFields = map[string]bool{
 	"env":             true,
 	"github-token":    true,
 	"app":             true,
+	"approval":        true,
+	"targets":         true,
 	"max-patch-size":  true,
 	"jobs":            true,
+	"types":           true,
 	"runs-on":         true,
 	"messages":        true,
 }
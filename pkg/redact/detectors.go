@@ -0,0 +1,212 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Names of the built-in detectors. They are the values accepted by secret-masking.detectors
+// and must stay in sync with the detectors of actions/setup/js/redact_secrets.cjs, which
+// TestDetectorsMatchJavaScript checks.
+const (
+	DetectorGitHubTokens = "github-tokens"
+	DetectorCloudKeys    = "cloud-keys"
+	DetectorAPIKeys      = "api-keys"
+	DetectorJWT          = "jwt"
+	DetectorPrivateKeys  = "private-keys"
+	DetectorEntropy      = "entropy"
+)
+
+// Detector finds secrets in text
+type Detector interface {
+	// Name identifies the detector in configuration and in matches
+	Name() string
+	// FindAll returns the secrets found in text
+	FindAll(text string) []Match
+}
+
+// Pattern is a named regular expression matching one token format
+type Pattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// patternDetector reports every match of a list of patterns
+type patternDetector struct {
+	name     string
+	patterns []Pattern
+}
+
+// NewPatternDetector creates a detector that reports every match of the given patterns
+func NewPatternDetector(name string, patterns ...Pattern) Detector {
+	return &patternDetector{name: name, patterns: patterns}
+}
+
+func (d *patternDetector) Name() string { return d.name }
+
+func (d *patternDetector) FindAll(text string) []Match {
+	var matches []Match
+	for _, p := range d.patterns {
+		for _, loc := range p.Regexp.FindAllStringIndex(text, -1) {
+			matches = append(matches, Match{Detector: d.name, Rule: p.Name, Start: loc[0], End: loc[1]})
+		}
+	}
+	return matches
+}
+
+// entropyCandidatePattern matches runs of base64 and base64url characters
+var entropyCandidatePattern = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+
+// entropyDetector reports long random-looking strings that no token format matches
+type entropyDetector struct {
+	minLength int
+	threshold float64
+}
+
+// NewEntropyDetector creates a detector that reports strings of at least minLength base64
+// characters mixing upper case letters, lower case letters and digits whose Shannon entropy
+// is at least threshold bits per character.
+func NewEntropyDetector(minLength int, threshold float64) Detector {
+	return &entropyDetector{minLength: minLength, threshold: threshold}
+}
+
+func (d *entropyDetector) Name() string { return DetectorEntropy }
+
+func (d *entropyDetector) FindAll(text string) []Match {
+	var matches []Match
+	for _, loc := range entropyCandidatePattern.FindAllStringIndex(text, -1) {
+		candidate := text[loc[0]:loc[1]]
+		if len(candidate) < d.minLength || !hasMixedCharacters(candidate) || ShannonEntropy(candidate) < d.threshold {
+			continue
+		}
+		matches = append(matches, Match{Detector: DetectorEntropy, Rule: "High Entropy String", Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+// hasMixedCharacters reports whether s contains upper case and lower case letters and at
+// least three digits, which rules out identifiers, words and hexadecimal hashes.
+func hasMixedCharacters(s string) bool {
+	var upper, lower bool
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digits++
+		}
+	}
+	return upper && lower && digits >= 3
+}
+
+// ShannonEntropy returns the Shannon entropy of s in bits per byte
+func ShannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	entropy := 0.0
+	length := float64(len(s))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Defaults of the entropy detector, tuned so that random tokens of 32 characters or more are
+// reported while identifiers, paths, hashes and UUIDs are not
+const (
+	defaultEntropyMinLength = 32
+	defaultEntropyThreshold = 4.0
+)
+
+// builtinDetectors lists the built-in detectors in the order they are documented
+var builtinDetectors = []Detector{
+	NewPatternDetector(DetectorGitHubTokens,
+		Pattern{"GitHub Personal Access Token (classic)", regexp.MustCompile(`ghp_[0-9a-zA-Z]{36}`)},
+		Pattern{"GitHub Server-to-Server Token", regexp.MustCompile(`ghs_[0-9a-zA-Z]{36}`)},
+		Pattern{"GitHub OAuth Access Token", regexp.MustCompile(`gho_[0-9a-zA-Z]{36}`)},
+		Pattern{"GitHub User Access Token", regexp.MustCompile(`ghu_[0-9a-zA-Z]{36}`)},
+		Pattern{"GitHub Fine-grained PAT", regexp.MustCompile(`github_pat_[0-9a-zA-Z_]{82}`)},
+		Pattern{"GitHub Refresh Token", regexp.MustCompile(`ghr_[0-9a-zA-Z]{36}`)},
+	),
+	NewPatternDetector(DetectorCloudKeys,
+		Pattern{"Azure Storage Account Key", regexp.MustCompile(`[a-zA-Z0-9+/]{88}==`)},
+		Pattern{"Azure SAS Token", regexp.MustCompile(`\?sv=[0-9-]{1,20}&s[rts]=[\w\-]{1,20}&sig=[A-Za-z0-9%+/=]{1,200}`)},
+		Pattern{"Google API Key", regexp.MustCompile(`AIzaSy[0-9A-Za-z_-]{33}`)},
+		Pattern{"Google OAuth Access Token", regexp.MustCompile(`ya29\.[0-9A-Za-z_-]{1,800}`)},
+		Pattern{"AWS Access Key ID", regexp.MustCompile(`AKIA[0-9A-Z]{16}`)},
+		Pattern{"AWS Temporary Access Key ID", regexp.MustCompile(`ASIA[0-9A-Z]{16}`)},
+	),
+	NewPatternDetector(DetectorAPIKeys,
+		Pattern{"OpenAI API Key", regexp.MustCompile(`sk-[a-zA-Z0-9]{48}`)},
+		Pattern{"OpenAI Project API Key", regexp.MustCompile(`sk-proj-[a-zA-Z0-9]{48,64}`)},
+		Pattern{"Anthropic API Key", regexp.MustCompile(`sk-ant-api03-[a-zA-Z0-9_-]{95}`)},
+		Pattern{"Slack Token", regexp.MustCompile(`xox[abposr]-[0-9A-Za-z-]{10,}`)},
+		Pattern{"Stripe Live Secret Key", regexp.MustCompile(`sk_live_[0-9a-zA-Z]{24,}`)},
+		Pattern{"npm Access Token", regexp.MustCompile(`npm_[0-9a-zA-Z]{36}`)},
+	),
+	NewPatternDetector(DetectorJWT,
+		Pattern{"JSON Web Token", regexp.MustCompile(`eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	),
+	NewPatternDetector(DetectorPrivateKeys,
+		Pattern{"Private Key", regexp.MustCompile(`-----BEGIN [A-Z ]{0,20}PRIVATE KEY(?: BLOCK)?-----[\s\S]*?-----END [A-Z ]{0,20}PRIVATE KEY(?: BLOCK)?-----`)},
+	),
+	NewEntropyDetector(defaultEntropyMinLength, defaultEntropyThreshold),
+}
+
+// BuiltinDetectorNames returns the names of all built-in detectors
+func BuiltinDetectorNames() []string {
+	names := make([]string, 0, len(builtinDetectors))
+	for _, d := range builtinDetectors {
+		names = append(names, d.Name())
+	}
+	return names
+}
+
+// DefaultDetectorNames returns the names of the detectors used when none are configured.
+// The entropy detector is opt-in because it can match random-looking identifiers.
+func DefaultDetectorNames() []string {
+	names := make([]string, 0, len(builtinDetectors))
+	for _, d := range builtinDetectors {
+		if d.Name() != DetectorEntropy {
+			names = append(names, d.Name())
+		}
+	}
+	return names
+}
+
+// LookupDetector returns the built-in detector with the given name
+func LookupDetector(name string) (Detector, bool) {
+	for _, d := range builtinDetectors {
+		if d.Name() == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// DetectorsByName returns the built-in detectors with the given names
+func DetectorsByName(names []string) ([]Detector, error) {
+	detectors := make([]Detector, 0, len(names))
+	for _, name := range names {
+		d, ok := LookupDetector(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown secret detector '%s', valid detectors: %s", name, strings.Join(BuiltinDetectorNames(), ", "))
+		}
+		detectors = append(detectors, d)
+	}
+	return detectors, nil
+}
@@ -0,0 +1,76 @@
+//go:build !integration
+
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redactSecretsScript is the JavaScript implementation of the built-in detectors
+const redactSecretsScript = "../../actions/setup/js/redact_secrets.cjs"
+
+var (
+	jsDetectorKeyPattern     = regexp.MustCompile(`^\s*"?([a-z-]+)"?: \[`)
+	jsDetectorPatternPattern = regexp.MustCompile(`\{ name: "([^"]+)", pattern: /(.+?)/g \}`)
+	jsEntropyConstPattern    = regexp.MustCompile(`(?m)^const (ENTROPY_MIN_LENGTH|ENTROPY_THRESHOLD) = ([0-9.]+);`)
+)
+
+// parseJSDetectors extracts the DETECTORS table of redact_secrets.cjs as detector name to
+// "rule name: pattern source" entries
+func parseJSDetectors(t *testing.T, script string) map[string][]string {
+	t.Helper()
+	start := strings.Index(script, "const DETECTORS = {")
+	require.GreaterOrEqual(t, start, 0, "redact_secrets.cjs should declare DETECTORS")
+	end := strings.Index(script[start:], "\n};")
+	require.Positive(t, end, "DETECTORS should be closed")
+
+	detectors := make(map[string][]string)
+	current := ""
+	for line := range strings.SplitSeq(script[start:start+end], "\n") {
+		if m := jsDetectorKeyPattern.FindStringSubmatch(line); m != nil {
+			current = m[1]
+		}
+		for _, m := range jsDetectorPatternPattern.FindAllStringSubmatch(line, -1) {
+			require.NotEmpty(t, current, "pattern %q should belong to a detector", m[1])
+			detectors[current] = append(detectors[current], m[1]+": "+m[2])
+		}
+	}
+	return detectors
+}
+
+// TestDetectorsMatchJavaScript verifies that the detectors of redact_secrets.cjs, which
+// redacts secrets inside workflow runs, match the Go detectors used by the CLI
+func TestDetectorsMatchJavaScript(t *testing.T) {
+	content, err := os.ReadFile(filepath.FromSlash(redactSecretsScript))
+	require.NoError(t, err)
+	script := string(content)
+
+	goDetectors := make(map[string][]string)
+	for _, d := range builtinDetectors {
+		pd, ok := d.(*patternDetector)
+		if !ok {
+			continue
+		}
+		for _, p := range pd.patterns {
+			goDetectors[pd.name] = append(goDetectors[pd.name], p.Name+": "+p.Regexp.String())
+		}
+	}
+	assert.Equal(t, goDetectors, parseJSDetectors(t, script), "DETECTORS in redact_secrets.cjs should match builtinDetectors")
+
+	constants := make(map[string]string)
+	for _, m := range jsEntropyConstPattern.FindAllStringSubmatch(script, -1) {
+		constants[m[1]] = m[2]
+	}
+	assert.Equal(t, strconv.Itoa(defaultEntropyMinLength), constants["ENTROPY_MIN_LENGTH"], "entropy minimum length should match")
+	threshold, err := strconv.ParseFloat(constants["ENTROPY_THRESHOLD"], 64)
+	require.NoError(t, err, "ENTROPY_THRESHOLD should be declared")
+	assert.InDelta(t, defaultEntropyThreshold, threshold, 0, "entropy threshold should match")
+}
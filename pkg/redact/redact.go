@@ -0,0 +1,134 @@
+// Package redact detects secrets in text and replaces them with a placeholder.
+//
+// A Redactor combines detectors for well-known token formats (GitHub tokens, cloud keys,
+// API keys, JWTs, private keys), an optional entropy heuristic for random-looking strings
+// and the exact values of known secrets. The built-in detectors mirror the ones applied by
+// the "Redact secrets in logs" step of compiled workflows, so artifacts downloaded locally
+// can be redacted with the same rules.
+package redact
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var log = logger.New("redact:redact")
+
+// Placeholder replaces every redacted secret
+const Placeholder = "***REDACTED***"
+
+// DetectorSecretValues is the detector name of matches of known secret values
+const DetectorSecretValues = "secret-values"
+
+// minSecretLength is the length under which known secret values are ignored, since short
+// values are more likely to match unrelated text than to be actual secrets
+const minSecretLength = 6
+
+// Match is a secret found in text
+type Match struct {
+	Detector string `json:"detector"`       // Name of the detector that found the secret
+	Rule     string `json:"rule,omitempty"` // Token format or heuristic that matched
+	Start    int    `json:"start"`          // Byte offset of the secret in the text
+	End      int    `json:"end"`            // Byte offset just after the secret
+}
+
+// Redactor finds and masks secrets
+type Redactor struct {
+	detectors []Detector
+	secrets   []string
+}
+
+// New creates a redactor using the given detectors
+func New(detectors ...Detector) *Redactor {
+	return &Redactor{detectors: detectors}
+}
+
+// NewDefault creates a redactor using the default built-in detectors
+func NewDefault() *Redactor {
+	detectors, _ := DetectorsByName(DefaultDetectorNames())
+	return New(detectors...)
+}
+
+// AddSecrets registers known secret values to redact wherever they appear. Values shorter
+// than six characters after trimming are ignored.
+func (r *Redactor) AddSecrets(values ...string) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength {
+			continue
+		}
+		r.secrets = append(r.secrets, value)
+	}
+}
+
+// Find returns the secrets found in text, sorted by position. Overlapping matches are
+// collapsed into the one that starts first, preferring the longest.
+func (r *Redactor) Find(text string) []Match {
+	var matches []Match
+	for _, d := range r.detectors {
+		matches = append(matches, d.FindAll(text)...)
+	}
+	for _, secret := range r.secrets {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], secret)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			matches = append(matches, Match{Detector: DetectorSecretValues, Start: start, End: start + len(secret)})
+			offset = start + len(secret)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	merged := matches[:1]
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.Start < last.End {
+			last.End = max(last.End, m.End)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	log.Printf("Found %d secret(s) in %d bytes", len(merged), len(text))
+	return merged
+}
+
+// Redact replaces the secrets found in text with Placeholder and returns the redacted text
+// together with the matches, whose offsets refer to the original text
+func (r *Redactor) Redact(text string) (string, []Match) {
+	matches := r.Find(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	previous := 0
+	for _, m := range matches {
+		b.WriteString(text[previous:m.Start])
+		b.WriteString(Placeholder)
+		previous = m.End
+	}
+	b.WriteString(text[previous:])
+	return b.String(), matches
+}
+
+// CountByDetector returns the number of matches of each detector
+func CountByDetector(matches []Match) map[string]int {
+	counts := make(map[string]int)
+	for _, m := range matches {
+		counts[m.Detector]++
+	}
+	return counts
+}
@@ -0,0 +1,240 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var batchLog = logger.New("workflow:batch")
+
+// This file handles on.batch, which accumulates high-frequency events and runs the agent
+// once per window with the list of events as context:
+//
+//	on:
+//	  issues:
+//	    types: [opened, edited]
+//	  issue_comment:
+//	    types: [created]
+//	  batch:
+//	    window: 1h
+//	    max-events: 50
+//
+// The compiler generates:
+//   - a flush schedule ("every <window>") and workflow_dispatch for manual flushes
+//   - a workflow-level concurrency group per event run, shared only by the flush runs
+//   - a batch_accumulate job that records every other event as a file on the
+//     memory/batch-<workflow-id> branch, after the pre-activation checks passed
+//   - a pre-activation check that only activates flush runs with pending events
+//   - an activation step that adds the pending events to the prompt; the activation job only
+//     reads the branch
+//   - a batch_flush job that removes the collected events from the branch once the agent job
+//     succeeded, so that events of a failed run are processed again by the next flush
+//
+// Only metadata (event, action, number, URL, actor, ref, SHA) is recorded; the agent reads
+// the referenced issues, comments and commits with its GitHub tools.
+
+// batchableEvents lists the triggers that can be batched
+var batchableEvents = []string{"push", "issues", "issue_comment"}
+
+// unbatchableEvents lists the GitHub Actions triggers that cannot be combined with on.batch
+var unbatchableEvents = []string{
+	"pull_request", "pull_request_target", "pull_request_review", "pull_request_review_comment",
+	"discussion", "discussion_comment", "repository_dispatch", "workflow_run", "workflow_call",
+	"release", "create", "delete", "deployment", "deployment_status", "fork", "gollum", "label",
+	"milestone", "merge_group", "check_run", "check_suite", "page_build", "public",
+	"registry_package", "status", "watch", "slash_command",
+}
+
+// BatchConfig is the parsed on.batch configuration
+type BatchConfig struct {
+	Window    string // Flush interval, e.g. "1h"
+	MaxEvents int    // Maximum number of events passed to one flush run
+}
+
+// extractBatchConfig extracts on.batch, validates the triggers it is combined with and replaces
+// it with the flush schedule. It returns nil when on.batch is not set.
+func extractBatchConfig(onMap map[string]any) (*BatchConfig, error) {
+	batchValue, hasBatch := onMap["batch"]
+	if !hasBatch {
+		return nil, nil
+	}
+
+	config := &BatchConfig{Window: constants.DefaultBatchWindow, MaxEvents: constants.DefaultBatchMaxEvents}
+	switch v := batchValue.(type) {
+	case nil:
+	case map[string]any:
+		for key := range v {
+			if key != "window" && key != "max-events" {
+				return nil, fmt.Errorf("unknown on.batch field '%s', valid fields: window, max-events", key)
+			}
+		}
+		if window, exists := v["window"]; exists {
+			windowStr, ok := window.(string)
+			if !ok || windowStr == "" {
+				return nil, fmt.Errorf("on.batch.window must be a duration such as 30m, 1h or 1d, got %v", window)
+			}
+			config.Window = windowStr
+		}
+		if maxEvents, exists := v["max-events"]; exists {
+			n, ok := parseIntValue(maxEvents)
+			if !ok || n < 1 {
+				return nil, fmt.Errorf("on.batch.max-events must be a positive integer, got %v", maxEvents)
+			}
+			config.MaxEvents = n
+		}
+	default:
+		return nil, fmt.Errorf("on.batch must be an object with window and max-events, got %T", batchValue)
+	}
+
+	if _, hasSchedule := onMap["schedule"]; hasSchedule {
+		return nil, errors.New("on.batch cannot be combined with on.schedule, the flush schedule is derived from on.batch.window")
+	}
+	hasBatchableEvent := false
+	for trigger := range onMap {
+		if slices.Contains(unbatchableEvents, trigger) {
+			return nil, fmt.Errorf("on.batch does not support the '%s' trigger, batched triggers: %s", trigger, strings.Join(batchableEvents, ", "))
+		}
+		hasBatchableEvent = hasBatchableEvent || slices.Contains(batchableEvents, trigger)
+	}
+	if !hasBatchableEvent {
+		return nil, fmt.Errorf("on.batch requires at least one batched trigger: %s", strings.Join(batchableEvents, ", "))
+	}
+
+	// Replace on.batch with the flush schedule, which is converted to cron with the other
+	// schedule shorthands
+	delete(onMap, "batch")
+	onMap["schedule"] = "every " + config.Window
+	if _, hasDispatch := onMap["workflow_dispatch"]; !hasDispatch {
+		onMap["workflow_dispatch"] = nil
+	}
+
+	batchLog.Printf("Extracted batch config: window=%s, maxEvents=%d", config.Window, config.MaxEvents)
+	return config, nil
+}
+
+// batchBranchName returns the branch on which the events of a workflow are accumulated
+func batchBranchName(workflowID string) string {
+	return "memory/batch-" + workflowID
+}
+
+// generateBatchCheck generates the pre-activation step that activates flush runs with
+// pending events
+func (c *Compiler) generateBatchCheck(data *WorkflowData, steps []string) []string {
+	steps = append(steps, "      - name: Check batched events\n")
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CheckBatchEventsStepID))
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_BRANCH: %q\n", batchBranchName(data.WorkflowID)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("check_batch_events.cjs"))
+	return steps
+}
+
+// generateBatchCollectStep generates the activation step that adds the pending events to the
+// prompt and outputs their paths for the batch_flush job
+func (c *Compiler) generateBatchCollectStep(data *WorkflowData) []string {
+	var steps []string
+	steps = append(steps, "      - name: Collect batched events\n")
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CollectBatchEventsStepID))
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_BRANCH: %q\n", batchBranchName(data.WorkflowID)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_MAX_EVENTS: \"%d\"\n", data.Batch.MaxEvents))
+	steps = append(steps, "          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("collect_batch_events.cjs"))
+	return steps
+}
+
+// buildBatchAccumulateJob creates the job recording the events of a batched workflow. It runs
+// after the pre-activation checks so that only events that would have activated the agent
+// are recorded.
+func (c *Compiler) buildBatchAccumulateJob(data *WorkflowData) (*Job, error) {
+	batchLog.Printf("Building batch accumulate job for workflow %s", data.WorkflowID)
+
+	setupActionRef := c.resolveActionReference("./actions/setup", data)
+	if setupActionRef == "" {
+		return nil, errors.New("setup action reference is required but could not be resolved")
+	}
+
+	var steps []string
+	steps = append(steps, c.generateCheckoutActionsFolder(data)...)
+	steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+	steps = append(steps, "      - name: Record event\n")
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_BRANCH: %q\n", batchBranchName(data.WorkflowID)))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("record_batch_event.cjs"))
+
+	recordCondition := BuildComparison(
+		BuildPropertyAccess(fmt.Sprintf("needs.%s.outputs.%s", constants.PreActivationJobName, constants.BatchRecordOutput)),
+		"==",
+		BuildStringLiteral("true"),
+	)
+
+	return &Job{
+		Name:        string(constants.BatchAccumulateJobName),
+		If:          recordCondition.Render(),
+		RunsOn:      c.formatSafeOutputsRunsOn(data.SafeOutputs),
+		Permissions: NewPermissionsContentsWrite().RenderToYAML(),
+		Steps:       steps,
+		Needs:       []string{string(constants.PreActivationJobName)},
+	}, nil
+}
+
+// generateBatchConcurrencyConfig generates the workflow-level concurrency configuration of a
+// batched workflow. A group shared by every run keeps at most one pending run and cancels the
+// others before batch_accumulate records their events, so each event run gets a group of its
+// own. Flush runs (schedule and workflow_dispatch) share one group so that one flush runs at a time.
+func generateBatchConcurrencyConfig() string {
+	return "concurrency:\n  group: \"gh-aw-${{ github.workflow }}-${{ (github.event_name == 'schedule' || github.event_name == 'workflow_dispatch') && 'flush' || github.run_id }}\""
+}
+
+// buildBatchFlushJob creates the job removing the events collected by the activation job from
+// the batch branch. It only runs when the agent job succeeded.
+func (c *Compiler) buildBatchFlushJob(data *WorkflowData) (*Job, error) {
+	batchLog.Printf("Building batch flush job for workflow %s", data.WorkflowID)
+
+	setupActionRef := c.resolveActionReference("./actions/setup", data)
+	if setupActionRef == "" {
+		return nil, errors.New("setup action reference is required but could not be resolved")
+	}
+
+	eventsOutput := fmt.Sprintf("needs.%s.outputs.%s", constants.ActivationJobName, constants.BatchEventsOutput)
+
+	var steps []string
+	steps = append(steps, c.generateCheckoutActionsFolder(data)...)
+	steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+	steps = append(steps, "      - name: Remove processed events\n")
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_BRANCH: %q\n", batchBranchName(data.WorkflowID)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_BATCH_EVENTS: ${{ %s }}\n", eventsOutput))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("flush_batch_events.cjs"))
+
+	flushCondition := BuildAnd(
+		BuildFunctionCall("success"),
+		BuildComparison(BuildPropertyAccess(eventsOutput), "!=", BuildStringLiteral("")),
+	)
+
+	return &Job{
+		Name:        string(constants.BatchFlushJobName),
+		If:          flushCondition.Render(),
+		RunsOn:      c.formatSafeOutputsRunsOn(data.SafeOutputs),
+		Permissions: NewPermissionsContentsWrite().RenderToYAML(),
+		Steps:       steps,
+		Needs:       []string{string(constants.ActivationJobName), string(constants.AgentJobName)},
+	}, nil
+}
@@ -0,0 +1,172 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractBatchConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		on   map[string]any
+		want *BatchConfig
+	}{
+		{
+			name: "no batch",
+			on:   map[string]any{"issues": nil},
+			want: nil,
+		},
+		{
+			name: "defaults",
+			on:   map[string]any{"issues": nil, "batch": nil},
+			want: &BatchConfig{Window: "1h", MaxEvents: 100},
+		},
+		{
+			name: "window and max events",
+			on:   map[string]any{"push": nil, "batch": map[string]any{"window": "30m", "max-events": uint64(20)}},
+			want: &BatchConfig{Window: "30m", MaxEvents: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractBatchConfig(tt.on)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NotContains(t, tt.on, "batch", "on.batch should not be emitted")
+			if tt.want != nil {
+				assert.Equal(t, "every "+tt.want.Window, tt.on["schedule"], "flush schedule should be derived from the window")
+				assert.Contains(t, tt.on, "workflow_dispatch", "manual flushes should be possible")
+			}
+		})
+	}
+}
+
+func TestExtractBatchConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		on   map[string]any
+		want string
+	}{
+		{
+			name: "unknown field",
+			on:   map[string]any{"issues": nil, "batch": map[string]any{"size": 5}},
+			want: "unknown on.batch field 'size'",
+		},
+		{
+			name: "invalid max events",
+			on:   map[string]any{"issues": nil, "batch": map[string]any{"max-events": 0}},
+			want: "on.batch.max-events must be a positive integer",
+		},
+		{
+			name: "invalid window",
+			on:   map[string]any{"issues": nil, "batch": map[string]any{"window": 60}},
+			want: "on.batch.window must be a duration",
+		},
+		{
+			name: "combined with schedule",
+			on:   map[string]any{"issues": nil, "schedule": "daily", "batch": nil},
+			want: "on.batch cannot be combined with on.schedule",
+		},
+		{
+			name: "unbatchable trigger",
+			on:   map[string]any{"issues": nil, "pull_request": nil, "batch": nil},
+			want: "on.batch does not support the 'pull_request' trigger",
+		},
+		{
+			name: "no batched trigger",
+			on:   map[string]any{"workflow_dispatch": nil, "batch": nil},
+			want: "on.batch requires at least one batched trigger",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractBatchConfig(tt.on)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
+
+func TestBatchCompilation(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "batch-compile")
+	workflowContent := `---
+on:
+  issues:
+    types: [opened, edited]
+  issue_comment:
+    types: [created]
+  batch:
+    window: 30m
+    max-events: 20
+engine: copilot
+---
+
+# Triage
+
+Triage the batched issues.
+`
+	workflowFile := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(workflowContent), 0644))
+
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("triage.md")
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "batched workflow should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	onSection, _, _ := strings.Cut(lock, "\npermissions:")
+	assert.Contains(t, onSection, `cron: "*/30 * * * *"`, "flush schedule should be generated from the window")
+	assert.Contains(t, onSection, "workflow_dispatch:", "manual flushes should be possible")
+	assert.NotContains(t, lock, "max-events:", "on.batch should not be emitted")
+
+	concurrency := workflowLevelConcurrency(lock)
+	assert.Contains(t, concurrency, "github.run_id", "event runs should not share a group that cancels pending runs")
+	assert.Contains(t, concurrency, "(github.event_name == 'schedule' || github.event_name == 'workflow_dispatch') && 'flush'", "flush runs should share one group")
+	assert.NotContains(t, concurrency, "github.event.issue.number", "events of the same issue should all be recorded")
+	assert.NotContains(t, concurrency, "cancel-in-progress", "batched runs should never be cancelled")
+
+	assert.Contains(t, lock, "id: check_batch_events", "pre-activation job should check pending events")
+	assert.Contains(t, lock, "steps.check_batch_events.outputs.batch_ok == 'true'", "activated output should include the batch check")
+	assert.Contains(t, lock, "batch_record: ${{", "pre-activation job should decide whether to record the event")
+
+	_, accumulateJob, found := strings.Cut(lock, "\n  batch_accumulate:\n")
+	require.True(t, found, "batch_accumulate job should be generated")
+	accumulateJob, _, _ = strings.Cut(accumulateJob, "\n\n")
+	assert.Contains(t, accumulateJob, "needs: pre_activation")
+	assert.Contains(t, accumulateJob, "if: needs.pre_activation.outputs.batch_record == 'true'")
+	assert.Contains(t, accumulateJob, "contents: write", "recording events pushes to the batch branch")
+	assert.Contains(t, accumulateJob, `GH_AW_BATCH_BRANCH: "memory/batch-triage"`)
+	assert.Contains(t, accumulateJob, "record_batch_event.cjs")
+
+	assert.Contains(t, lock, "name: Collect batched events", "activation job should add the events to the prompt")
+	assert.Contains(t, lock, `GH_AW_BATCH_MAX_EVENTS: "20"`)
+	assert.Less(t, strings.Index(lock, "name: Collect batched events"), strings.Index(lock, "name: Upload prompt artifact"), "events should be collected before the prompt is uploaded")
+	assert.Contains(t, lock, "batch_events: ${{ steps.collect_batch_events.outputs.paths }}", "activation job should output the collected events")
+
+	_, activationJob, found := strings.Cut(lock, "\n  activation:\n")
+	require.True(t, found, "activation job should be generated")
+	activationJob, _, _ = strings.Cut(activationJob, "\n\n")
+	assert.NotContains(t, activationJob, "contents: write", "activation job should only read the batch branch")
+
+	_, flushJob, found := strings.Cut(lock, "\n  batch_flush:\n")
+	require.True(t, found, "batch_flush job should be generated")
+	flushJob, _, _ = strings.Cut(flushJob, "\n\n")
+	assert.Contains(t, flushJob, "- activation\n")
+	assert.Contains(t, flushJob, "- agent\n")
+	assert.Contains(t, flushJob, "if: (success()) && (needs.activation.outputs.batch_events != '')", "events should only be removed after a successful agent run")
+	assert.Contains(t, flushJob, "contents: write", "removing events pushes to the batch branch")
+	assert.Contains(t, flushJob, "flush_batch_events.cjs")
+}
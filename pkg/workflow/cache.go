@@ -39,6 +39,23 @@ func generateDefaultCacheKey(cacheID string) string {
 	return fmt.Sprintf("memory-%s-${{ env.GH_AW_WORKFLOW_ID_SANITIZED }}-${{ github.run_id }}", cacheID)
 }
 
+// ResolveCacheMemoryKeyPrefix resolves the cache key of a cache-memory outside of GitHub Actions
+// and returns the prefix shared by the cache entries of all runs of the workflow, i.e. the key
+// without its run ID. Expressions other than the workflow ID cannot be resolved locally, so the
+// prefix stops at the first of them and exact is false.
+func ResolveCacheMemoryKeyPrefix(cache CacheMemoryEntry, workflowID string) (prefix string, exact bool) {
+	key := cache.Key
+	if key == "" {
+		key = generateDefaultCacheKey(cache.ID)
+	}
+	key = strings.ReplaceAll(key, "${{ env.GH_AW_WORKFLOW_ID_SANITIZED }}", SanitizeWorkflowIDForCacheKey(workflowID))
+	prefix = strings.TrimSuffix(key, "${{ github.run_id }}")
+	if idx := strings.Index(prefix, "${{"); idx >= 0 {
+		return prefix[:idx], false
+	}
+	return prefix, true
+}
+
 // parseCacheMemoryEntry parses a single cache-memory entry from a map
 func parseCacheMemoryEntry(cacheMap map[string]any, defaultID string) (CacheMemoryEntry, error) {
 	entry := CacheMemoryEntry{
@@ -0,0 +1,56 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCacheMemoryKeyPrefix(t *testing.T) {
+	tests := []struct {
+		name           string
+		cache          CacheMemoryEntry
+		expectedPrefix string
+		expectedExact  bool
+	}{
+		{
+			name:           "default cache",
+			cache:          CacheMemoryEntry{ID: "default", Key: generateDefaultCacheKey("default")},
+			expectedPrefix: "memory-dailyreport-",
+			expectedExact:  true,
+		},
+		{
+			name:           "named cache",
+			cache:          CacheMemoryEntry{ID: "notes", Key: generateDefaultCacheKey("notes")},
+			expectedPrefix: "memory-notes-dailyreport-",
+			expectedExact:  true,
+		},
+		{
+			name:           "missing key falls back to the default key",
+			cache:          CacheMemoryEntry{ID: "notes"},
+			expectedPrefix: "memory-notes-dailyreport-",
+			expectedExact:  true,
+		},
+		{
+			name:           "custom key",
+			cache:          CacheMemoryEntry{ID: "default", Key: "agent-state-${{ github.run_id }}"},
+			expectedPrefix: "agent-state-",
+			expectedExact:  true,
+		},
+		{
+			name:           "custom key with other expressions",
+			cache:          CacheMemoryEntry{ID: "default", Key: "state-${{ github.ref_name }}-${{ github.run_id }}"},
+			expectedPrefix: "state-",
+			expectedExact:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, exact := ResolveCacheMemoryKeyPrefix(tt.cache, "daily-report")
+			assert.Equal(t, tt.expectedPrefix, prefix, "key prefix should match the compiled workflow")
+			assert.Equal(t, tt.expectedExact, exact)
+		})
+	}
+}
@@ -150,6 +150,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate safe-outputs targets configuration
+	log.Printf("Validating safe-outputs targets")
+	if err := validateSafeOutputTargets(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate safe-outputs allowed-domains configuration
 	log.Printf("Validating safe-outputs allowed-domains")
 	if err := c.validateSafeOutputsAllowedDomains(workflowData.SafeOutputs); err != nil {
@@ -229,6 +235,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		c.IncrementWarningCount()
 	}
 
+	// Emit experimental warning for concurrency.policy feature
+	if workflowData.ConcurrencyPolicy != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: concurrency.policy"))
+		c.IncrementWarningCount()
+	}
+
 	// Validate workflow_run triggers have branch restrictions
 	log.Printf("Validating workflow_run triggers for branch restrictions")
 	if err := c.validateWorkflowRunBranches(workflowData, markdownPath); err != nil {
@@ -508,8 +520,10 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	// Reset the step order tracker for this compilation
 	c.stepOrderTracker = NewStepOrderTracker()
 
-	// Reset schedule friendly formats for this compilation
+	// Reset schedule friendly formats, calendar and batch configuration for this compilation
 	c.scheduleFriendlyFormats = nil
+	c.scheduleCalendar = nil
+	c.batchConfig = nil
 
 	// Reset the artifact manager for this compilation
 	if c.artifactManager == nil {
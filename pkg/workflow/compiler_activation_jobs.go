@@ -69,6 +69,22 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		perms.Set(PermissionActions, PermissionRead)
 	}
 
+	// Add contents: read permission if events are batched (needed to read the batch branch)
+	if data.Batch != nil {
+		if perms == nil {
+			perms = NewPermissions()
+		}
+		perms.Set(PermissionContents, PermissionRead)
+	}
+
+	// Add actions: read permission if runs are queued (needed to query in-flight workflow runs)
+	if data.ConcurrencyPolicy != nil && data.ConcurrencyPolicy.Policy == ConcurrencyPolicyQueue {
+		if perms == nil {
+			perms = NewPermissions()
+		}
+		perms.Set(PermissionActions, PermissionRead)
+	}
+
 	// Set permissions if any were configured
 	if perms != nil {
 		permissions = perms.RenderToYAML()
@@ -192,6 +208,24 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		steps = append(steps, generateGitHubScriptWithRequire("check_skip_bots.cjs"))
 	}
 
+	// Add schedule calendar check if the schedule has exclusion dates or blackout windows
+	if data.ScheduleCalendar != nil {
+		calendarJSON, err := json.Marshal(data.ScheduleCalendar)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schedule calendar: %w", err)
+		}
+
+		steps = append(steps, "      - name: Check schedule calendar\n")
+		steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CheckScheduleCalendarStepID))
+		steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+		steps = append(steps, "        env:\n")
+		steps = append(steps, fmt.Sprintf("          GH_AW_SCHEDULE_CALENDAR: %q\n", string(calendarJSON)))
+		steps = append(steps, fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name))
+		steps = append(steps, "        with:\n")
+		steps = append(steps, "          script: |\n")
+		steps = append(steps, generateGitHubScriptWithRequire("check_schedule_calendar.cjs"))
+	}
+
 	// Add command position check if this is a command workflow
 	if len(data.Command) > 0 {
 		steps = append(steps, "      - name: Check command position\n")
@@ -206,10 +240,9 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		steps = append(steps, generateGitHubScriptWithRequire("check_command_position.cjs"))
 	}
 
-	// Append custom steps from jobs.pre-activation if present
-	if len(customSteps) > 0 {
-		compilerActivationJobsLog.Printf("Adding %d custom steps to pre-activation job", len(customSteps))
-		steps = append(steps, customSteps...)
+	// Add batch check if events are batched
+	if data.Batch != nil {
+		steps = c.generateBatchCheck(data, steps)
 	}
 
 	// Generate the activated output expression using expression builders
@@ -278,6 +311,16 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		conditions = append(conditions, skipBotsCheckOk)
 	}
 
+	if data.ScheduleCalendar != nil {
+		// Add schedule calendar check condition
+		scheduleCalendarCheck := BuildComparison(
+			BuildPropertyAccess(fmt.Sprintf("steps.%s.outputs.%s", constants.CheckScheduleCalendarStepID, constants.ScheduleCalendarOkOutput)),
+			"==",
+			BuildStringLiteral("true"),
+		)
+		conditions = append(conditions, scheduleCalendarCheck)
+	}
+
 	if data.RateLimit != nil {
 		// Add rate limit check condition
 		rateLimitCheck := BuildComparison(
@@ -288,6 +331,30 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		conditions = append(conditions, rateLimitCheck)
 	}
 
+	// Events of a batched workflow are recorded when every other check passed
+	recordConditions := slices.Clone(conditions)
+
+	if data.Batch != nil {
+		// Add batch check condition
+		batchCheck := BuildComparison(
+			BuildPropertyAccess(fmt.Sprintf("steps.%s.outputs.%s", constants.CheckBatchEventsStepID, constants.BatchOkOutput)),
+			"==",
+			BuildStringLiteral("true"),
+		)
+		conditions = append(conditions, batchCheck)
+	}
+
+	var concurrencyPolicyCheck ConditionNode
+	if data.ConcurrencyPolicy.NeedsPreActivationCheck() {
+		// Add concurrency policy check condition
+		concurrencyPolicyCheck = BuildComparison(
+			BuildPropertyAccess(fmt.Sprintf("steps.%s.outputs.%s", constants.CheckConcurrencyPolicyStepID, constants.ConcurrencyPolicyOkOutput)),
+			"==",
+			BuildStringLiteral("true"),
+		)
+		conditions = append(conditions, concurrencyPolicyCheck)
+	}
+
 	if len(data.Command) > 0 {
 		// Add command position check condition
 		commandPositionCheck := BuildComparison(
@@ -298,6 +365,28 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		conditions = append(conditions, commandPositionCheck)
 	}
 
+	// Add concurrency policy check last, gated on the other checks so that rejected runs do not wait
+	if concurrencyPolicyCheck != nil {
+		var gateNode ConditionNode
+		for _, condition := range conditions {
+			if condition == concurrencyPolicyCheck {
+				continue
+			}
+			if gateNode == nil {
+				gateNode = condition
+			} else {
+				gateNode = BuildAnd(gateNode, condition)
+			}
+		}
+		steps = c.generateConcurrencyPolicyCheck(data, steps, gateNode)
+	}
+
+	// Append custom steps from jobs.pre-activation if present
+	if len(customSteps) > 0 {
+		compilerActivationJobsLog.Printf("Adding %d custom steps to pre-activation job", len(customSteps))
+		steps = append(steps, customSteps...)
+	}
+
 	// Build the final expression
 	if len(conditions) == 0 {
 		// This should never happen - it means pre-activation job was created without any checks
@@ -330,6 +419,18 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		outputs[constants.MatchedCommandOutput] = "''"
 	}
 
+	// Tell the batch_accumulate job whether to record the event
+	if data.Batch != nil {
+		recordNode := BuildAnd(
+			BuildNotEquals(BuildPropertyAccess("github.event_name"), BuildStringLiteral("schedule")),
+			BuildNotEquals(BuildPropertyAccess("github.event_name"), BuildStringLiteral("workflow_dispatch")),
+		)
+		for _, condition := range slices.Backward(recordConditions) {
+			recordNode = BuildAnd(condition, recordNode)
+		}
+		outputs[constants.BatchRecordOutput] = fmt.Sprintf("${{ %s }}", recordNode.Render())
+	}
+
 	// Merge custom outputs from jobs.pre-activation if present
 	if len(customOutputs) > 0 {
 		compilerActivationJobsLog.Printf("Adding %d custom outputs to pre-activation job", len(customOutputs))
@@ -695,6 +796,12 @@ func (c *Compiler) buildActivationJob(data *WorkflowData, preActivationJobCreate
 		}
 	}
 
+	// A debounced run only activates once the debounce job waited out the quiet period; the
+	// job is cancelled by newer events, which skips activation
+	if data.ConcurrencyPolicy.NeedsDebounceJob() {
+		activationNeeds = append(activationNeeds, string(constants.DebounceJobName))
+	}
+
 	// Apply workflow_run repository safety check exclusively to activation job
 	// This check is combined with any existing activation condition
 	if workflowRunRepoSafety != "" {
@@ -705,6 +812,12 @@ func (c *Compiler) buildActivationJob(data *WorkflowData, preActivationJobCreate
 	compilerActivationJobsLog.Print("Generating prompt in activation job")
 	c.generatePromptInActivationJob(&steps, data, preActivationJobCreated, customJobsBeforeActivation)
 
+	// Add the batched events to the prompt; the batch_flush job removes them once the agent succeeded
+	if data.Batch != nil {
+		steps = append(steps, c.generateBatchCollectStep(data)...)
+		outputs[constants.BatchEventsOutput] = fmt.Sprintf("${{ steps.%s.outputs.paths }}", constants.CollectBatchEventsStepID)
+	}
+
 	// Upload prompt.txt as an artifact for the agent job to download
 	compilerActivationJobsLog.Print("Adding prompt artifact upload step")
 	steps = append(steps, "      - name: Upload prompt artifact\n")
@@ -209,6 +209,17 @@ func (c *Compiler) buildJobs(data *WorkflowData, markdownPath string) error {
 		return err
 	}
 
+	// Build the job removing the processed events of a batched workflow
+	if data.Batch != nil && activationJobCreated {
+		flushJob, err := c.buildBatchFlushJob(data)
+		if err != nil {
+			return fmt.Errorf("failed to build %s job: %w", constants.BatchFlushJobName, err)
+		}
+		if err := c.jobManager.AddJob(flushJob); err != nil {
+			return fmt.Errorf("failed to add %s job: %w", constants.BatchFlushJobName, err)
+		}
+	}
+
 	// Build safe outputs jobs if configured
 	if err := c.buildSafeOutputsJobs(data, string(constants.AgentJobName), markdownPath); err != nil {
 		return fmt.Errorf("failed to build safe outputs jobs: %w", err)
@@ -241,12 +252,15 @@ func (c *Compiler) buildPreActivationAndActivationJobs(data *WorkflowData, front
 	hasSkipIfNoMatch := data.SkipIfNoMatch != nil
 	hasSkipRoles := len(data.SkipRoles) > 0
 	hasSkipBots := len(data.SkipBots) > 0
+	hasScheduleCalendar := data.ScheduleCalendar != nil
 	hasCommandTrigger := len(data.Command) > 0
 	hasRateLimit := data.RateLimit != nil
-	compilerJobsLog.Printf("Job configuration: needsPermissionCheck=%v, hasStopTime=%v, hasSkipIfMatch=%v, hasSkipIfNoMatch=%v, hasSkipRoles=%v, hasSkipBots=%v, hasCommand=%v, hasRateLimit=%v", needsPermissionCheck, hasStopTime, hasSkipIfMatch, hasSkipIfNoMatch, hasSkipRoles, hasSkipBots, hasCommandTrigger, hasRateLimit)
+	hasConcurrencyPolicy := data.ConcurrencyPolicy.NeedsPreActivationCheck()
+	hasBatch := data.Batch != nil
+	compilerJobsLog.Printf("Job configuration: needsPermissionCheck=%v, hasStopTime=%v, hasSkipIfMatch=%v, hasSkipIfNoMatch=%v, hasSkipRoles=%v, hasSkipBots=%v, hasScheduleCalendar=%v, hasCommand=%v, hasRateLimit=%v, hasConcurrencyPolicy=%v, hasBatch=%v", needsPermissionCheck, hasStopTime, hasSkipIfMatch, hasSkipIfNoMatch, hasSkipRoles, hasSkipBots, hasScheduleCalendar, hasCommandTrigger, hasRateLimit, hasConcurrencyPolicy, hasBatch)
 
-	// Build pre-activation job if needed (combines membership checks, stop-time validation, skip-if-match check, skip-if-no-match check, skip-roles check, skip-bots check, rate limit check, and command position check)
-	if needsPermissionCheck || hasStopTime || hasSkipIfMatch || hasSkipIfNoMatch || hasSkipRoles || hasSkipBots || hasCommandTrigger || hasRateLimit {
+	// Build pre-activation job if needed (combines membership checks, stop-time validation, skip-if-match check, skip-if-no-match check, skip-roles check, skip-bots check, schedule calendar check, rate limit check, batch check, concurrency policy check, and command position check)
+	if needsPermissionCheck || hasStopTime || hasSkipIfMatch || hasSkipIfNoMatch || hasSkipRoles || hasSkipBots || hasScheduleCalendar || hasCommandTrigger || hasRateLimit || hasConcurrencyPolicy || hasBatch {
 		compilerJobsLog.Print("Building pre-activation job")
 		preActivationJob, err := c.buildPreActivationJob(data, needsPermissionCheck)
 		if err != nil {
@@ -259,6 +273,28 @@ func (c *Compiler) buildPreActivationAndActivationJobs(data *WorkflowData, front
 		preActivationJobCreated = true
 	}
 
+	// Build the job recording the events of a batched workflow
+	if hasBatch {
+		batchJob, err := c.buildBatchAccumulateJob(data)
+		if err != nil {
+			return preActivationJobCreated, false, fmt.Errorf("failed to build %s job: %w", constants.BatchAccumulateJobName, err)
+		}
+		if err := c.jobManager.AddJob(batchJob); err != nil {
+			return preActivationJobCreated, false, fmt.Errorf("failed to add %s job: %w", constants.BatchAccumulateJobName, err)
+		}
+	}
+
+	// Build the job waiting out the quiet period of a debounced workflow
+	if data.ConcurrencyPolicy.NeedsDebounceJob() {
+		debounceJob, err := c.buildDebounceJob(data, preActivationJobCreated)
+		if err != nil {
+			return preActivationJobCreated, false, fmt.Errorf("failed to build %s job: %w", constants.DebounceJobName, err)
+		}
+		if err := c.jobManager.AddJob(debounceJob); err != nil {
+			return preActivationJobCreated, false, fmt.Errorf("failed to add %s job: %w", constants.DebounceJobName, err)
+		}
+	}
+
 	// Determine if we need to add workflow_run repository safety check
 	var workflowRunRepoSafety string
 	if c.hasWorkflowRunTrigger(frontmatter) {
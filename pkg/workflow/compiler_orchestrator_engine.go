@@ -211,6 +211,14 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		}
 	}
 
+	// Override the engine model with the command line model setting if provided
+	if c.modelOverride != "" {
+		if engineConfig == nil {
+			engineConfig = &EngineConfig{ID: engineSetting}
+		}
+		engineConfig.Model = c.modelOverride
+	}
+
 	// Validate the engine setting
 	orchestratorEngineLog.Printf("Validating engine setting: %s", engineSetting)
 	if err := c.validateEngine(engineSetting); err != nil {
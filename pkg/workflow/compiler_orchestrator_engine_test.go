@@ -111,6 +111,37 @@ engine: copilot
 	assert.Equal(t, "claude", result.engineSetting)
 }
 
+// TestSetupEngineAndImports_ModelOverride tests command-line model override
+func TestSetupEngineAndImports_ModelOverride(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "model-override")
+
+	testContent := `---
+on: push
+engine: copilot
+---
+
+# Test Workflow
+`
+
+	testFile := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(testContent), 0644))
+
+	compiler := NewCompiler(WithModelOverride("gpt-5"))
+	content := []byte(testContent)
+
+	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
+	require.NoError(t, err)
+
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.NotNil(t, result.engineConfig)
+
+	// Model should be overridden while the engine is kept
+	assert.Equal(t, "copilot", result.engineSetting)
+	assert.Equal(t, "gpt-5", result.engineConfig.Model)
+}
+
 // TestSetupEngineAndImports_InvalidEngine tests error handling for invalid engine
 func TestSetupEngineAndImports_InvalidEngine(t *testing.T) {
 	tmpDir := testutil.TempDir(t, "engine-invalid")
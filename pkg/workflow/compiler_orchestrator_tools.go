@@ -66,6 +66,9 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 			return nil, fmt.Errorf("failed to merge secret-masking: %w", err)
 		}
 	}
+	if err := validateSecretMaskingDetectors(secretMasking); err != nil {
+		return nil, err
+	}
 
 	var tools map[string]any
 
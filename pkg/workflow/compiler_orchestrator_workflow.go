@@ -52,6 +52,11 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 	// Store a stable workflow identifier derived from the file name.
 	workflowData.WorkflowID = GetWorkflowIDFromPath(cleanPath)
 
+	// Verify the workflow source against aw.lock.json, if the repository has one
+	if err := c.validateWorkflowSourceLock(cleanPath, workflowData.Source); err != nil {
+		return nil, err
+	}
+
 	// Validate that inlined-imports is not used with agent file imports.
 	// Agent files require runtime access and cannot be resolved without sources.
 	if workflowData.InlinedImports && engineSetup.importsResult.AgentFile != "" {
@@ -212,7 +217,7 @@ func (c *Compiler) extractYAMLSections(frontmatter map[string]any, workflowData
 	workflowData.On = c.extractTopLevelYAMLSection(frontmatter, "on")
 	workflowData.Permissions = c.extractPermissions(frontmatter)
 	workflowData.Network = c.extractTopLevelYAMLSection(frontmatter, "network")
-	workflowData.Concurrency = c.extractTopLevelYAMLSection(frontmatter, "concurrency")
+	workflowData.Concurrency = c.extractConcurrencySection(frontmatter)
 	workflowData.RunName = c.extractTopLevelYAMLSection(frontmatter, "run-name")
 	workflowData.Env = c.extractTopLevelYAMLSection(frontmatter, "env")
 	workflowData.Features = c.extractFeatures(frontmatter)
@@ -479,6 +484,10 @@ func (c *Compiler) extractAdditionalConfigurations(
 	workflowData.Roles = c.extractRoles(frontmatter)
 	workflowData.Bots = c.extractBots(frontmatter)
 	workflowData.RateLimit = c.extractRateLimitConfig(frontmatter)
+	workflowData.ConcurrencyPolicy, err = c.extractConcurrencyPolicy(frontmatter)
+	if err != nil {
+		return err
+	}
 	workflowData.SkipRoles = c.mergeSkipRoles(c.extractSkipRoles(frontmatter), importsResult.MergedSkipRoles)
 	workflowData.SkipBots = c.mergeSkipBots(c.extractSkipBots(frontmatter), importsResult.MergedSkipBots)
 
@@ -492,6 +501,9 @@ func (c *Compiler) extractAdditionalConfigurations(
 	if len(importsResult.MergedSafeInputs) > 0 {
 		workflowData.SafeInputs = c.mergeSafeInputs(workflowData.SafeInputs, importsResult.MergedSafeInputs)
 	}
+	if err := validateSafeInputsConfig(workflowData.SafeInputs); err != nil {
+		return err
+	}
 
 	// Extract safe-jobs from safe-outputs.jobs location
 	topSafeJobs := extractSafeJobsFromFrontmatter(frontmatter)
@@ -544,10 +556,18 @@ func (c *Compiler) extractAdditionalConfigurations(
 	}
 	workflowData.SafeOutputs = mergedSafeOutputs
 
+	if err := validateCustomSafeOutputTypes(workflowData.SafeOutputs); err != nil {
+		return err
+	}
+
 	// Auto-inject create-issues if safe-outputs is configured but has no non-builtin outputs.
 	// This ensures every workflow with safe-outputs has at least one meaningful action handler.
 	applyDefaultCreateIssue(workflowData)
 
+	if err := validateSafeOutputApproval(workflowData.SafeOutputs); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -564,6 +584,12 @@ func (c *Compiler) processOnSectionAndFilters(
 		return err
 	}
 
+	// Exclusion calendar from the object form of on.schedule (parsed during schedule preprocessing)
+	workflowData.ScheduleCalendar = c.scheduleCalendar
+
+	// Event batching from on.batch (parsed during schedule preprocessing)
+	workflowData.Batch = c.batchConfig
+
 	// Process skip-if-match configuration from the on: section
 	if err := c.processSkipIfMatchConfiguration(frontmatter, workflowData); err != nil {
 		return err
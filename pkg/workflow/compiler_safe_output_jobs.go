@@ -28,6 +28,19 @@ func (c *Compiler) buildSafeOutputsJobs(data *WorkflowData, jobName, markdownPat
 	// Track safe output job names to establish dependencies for conclusion job
 	var safeOutputJobNames []string
 
+	// Build the review job posting the approval checklist if approval is configured
+	// The consolidated job waits on it and executes only the approved items
+	reviewJob, err := c.buildSafeOutputsReviewJob(data, jobName)
+	if err != nil {
+		return fmt.Errorf("failed to build safe outputs review job: %w", err)
+	}
+	if reviewJob != nil {
+		if err := c.jobManager.AddJob(reviewJob); err != nil {
+			return fmt.Errorf("failed to add safe outputs review job: %w", err)
+		}
+		compilerSafeOutputJobsLog.Print("Added safe outputs review job")
+	}
+
 	// Build consolidated safe outputs job containing all safe output operations as steps
 	consolidatedJob, consolidatedStepNames, err := c.buildConsolidatedSafeOutputsJob(data, jobName, markdownPath)
 	if err != nil {
@@ -52,6 +65,19 @@ func (c *Compiler) buildSafeOutputsJobs(data *WorkflowData, jobName, markdownPat
 	safeOutputJobNames = append(safeOutputJobNames, safeJobNames...)
 	compilerSafeOutputJobsLog.Printf("Added %d custom safe-job names to conclusion dependencies", len(safeJobNames))
 
+	// Build one job per custom safe-output type, each with only the permissions the type declares
+	for _, typeName := range sortedCustomSafeOutputTypeNames(data.SafeOutputs.Types) {
+		typeJob, err := c.buildCustomSafeOutputTypeJob(data, typeName, jobName, markdownPath)
+		if err != nil {
+			return fmt.Errorf("failed to build job for safe-outputs type '%s': %w", typeName, err)
+		}
+		if err := c.jobManager.AddJob(typeJob); err != nil {
+			return fmt.Errorf("failed to add job for safe-outputs type '%s': %w", typeName, err)
+		}
+		safeOutputJobNames = append(safeOutputJobNames, typeJob.Name)
+	}
+	compilerSafeOutputJobsLog.Printf("Added %d custom safe-output type jobs", len(data.SafeOutputs.Types))
+
 	// Build upload_assets job as a separate job if configured
 	// This needs to be separate from the consolidated safe_outputs job because it requires:
 	// 1. Git configuration for pushing to orphaned branches
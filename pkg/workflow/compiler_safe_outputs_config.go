@@ -456,7 +456,10 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddDefault("max_patch_size", maxPatchSize).
 			AddTemplatableBool("footer", getEffectiveFooterForTemplatable(c.Footer, cfg.Footer)).
 			AddBoolPtr("fallback_as_issue", c.FallbackAsIssue).
-			AddIfNotEmpty("base_branch", c.BaseBranch)
+			AddIfNotEmpty("base_branch", c.BaseBranch).
+			AddStringSlice("allowed_paths", c.AllowedPaths).
+			AddStringSlice("protected_paths", c.ProtectedPaths).
+			AddIfNotEmpty("if_path_violation", c.IfPathViolation)
 		return builder.Build()
 	},
 	"push_to_pull_request_branch": func(cfg *SafeOutputsConfig) map[string]any {
@@ -476,6 +479,9 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddIfNotEmpty("if_no_changes", c.IfNoChanges).
 			AddIfNotEmpty("commit_title_suffix", c.CommitTitleSuffix).
 			AddDefault("max_patch_size", maxPatchSize).
+			AddStringSlice("allowed_paths", c.AllowedPaths).
+			AddStringSlice("protected_paths", c.ProtectedPaths).
+			AddIfNotEmpty("if_path_violation", c.IfPathViolation).
 			Build()
 	},
 	"update_pull_request": func(cfg *SafeOutputsConfig) map[string]any {
@@ -38,20 +38,30 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 	// Note: GitHub App token minting step is added later (after setup/downloads)
 	// to ensure proper step ordering. See insertion logic below.
 
+	// Enable safe-output-projects flag if project-related safe outputs are configured.
+	// Targets with their own credentials need the same @actions/github package to create
+	// per-target clients.
+	enableProjectSupport := c.hasProjectRelatedSafeOutputs(data.SafeOutputs) || hasSafeOutputTargetCredentials(data.SafeOutputs)
+
 	// Add setup action to copy JavaScript files
 	setupActionRef := c.resolveActionReference("./actions/setup", data)
 	if setupActionRef != "" || c.actionMode.IsScript() {
 		// For dev mode (local action path), checkout the actions folder first
 		steps = append(steps, c.generateCheckoutActionsFolder(data)...)
 
-		// Enable safe-output-projects flag if project-related safe outputs are configured
-		enableProjectSupport := c.hasProjectRelatedSafeOutputs(data.SafeOutputs)
 		steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, enableProjectSupport)...)
 	}
 
 	// Add artifact download steps after setup
 	steps = append(steps, buildAgentOutputDownloadSteps()...)
 
+	// Drop unapproved items from the agent output before any safe output is processed
+	approvalEnabled := c.isSafeOutputApprovalEnabled(data)
+	if approvalEnabled {
+		steps = append(steps, buildApprovalFilterStep(data.SafeOutputs.Approval)...)
+		permissions.Merge(NewPermissionsFromMap(map[PermissionScope]PermissionLevel{PermissionIssues: PermissionRead}))
+	}
+
 	// Add patch artifact download if create-pull-request or push-to-pull-request-branch is enabled
 	// Both of these safe outputs require the patch file to apply changes
 	// Download from unified agent-artifacts artifact
@@ -254,15 +264,23 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 	// Note: Link Sub Issue step - now handled by handler manager
 	// Note: Hide Comment step - now handled by handler manager
 
+	// Note: Custom safe-output types - now handled as separate jobs (see buildCustomSafeOutputTypeJob)
+	// so that the permissions each type declares are only granted to its own handler
+
 	// If no steps were added, return nil
 	if len(safeOutputStepNames) == 0 {
 		consolidatedSafeOutputsJobLog.Print("No safe output steps were added")
 		return nil, nil, nil
 	}
 
-	// Add GitHub App token minting step at the beginning if app is configured
-	if data.SafeOutputs.App != nil {
-		appTokenSteps := c.buildGitHubAppTokenMintStep(data.SafeOutputs.App, permissions)
+	// Add GitHub App token minting steps at the beginning if app or app targets are configured
+	targetTokenSteps := c.buildSafeOutputTargetTokenSteps(data.SafeOutputs, permissions)
+	if data.SafeOutputs.App != nil || len(targetTokenSteps) > 0 {
+		var appTokenSteps []string
+		if data.SafeOutputs.App != nil {
+			appTokenSteps = c.buildGitHubAppTokenMintStep(data.SafeOutputs.App, permissions)
+		}
+		appTokenSteps = append(appTokenSteps, targetTokenSteps...)
 		// Calculate insertion index: after setup action (if present) and artifact downloads, but before checkout and safe output steps
 		insertIndex := 0
 
@@ -272,11 +290,14 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 			if len(c.generateCheckoutActionsFolder(data)) > 0 {
 				insertIndex += 6 // Checkout step (6 lines: name, uses, with, sparse-checkout header, actions, persist-credentials)
 			}
-			insertIndex += 4 // Setup step (4 lines: name, uses, with, destination)
+			insertIndex += len(c.generateSetupStep(setupActionRef, SetupActionDestination, enableProjectSupport)) // Setup step (4 lines, 5 with safe-output-projects)
 		}
 
 		// Add artifact download steps count
 		insertIndex += len(buildAgentOutputDownloadSteps())
+		if approvalEnabled {
+			insertIndex += len(buildApprovalFilterStep(data.SafeOutputs.Approval))
+		}
 
 		// Add patch download steps if present
 		// Download from unified agent-artifacts artifact
@@ -305,6 +326,7 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 	if data.SafeOutputs.App != nil {
 		steps = append(steps, c.buildGitHubAppTokenInvalidationStep()...)
 	}
+	steps = append(steps, c.buildSafeOutputTargetTokenInvalidationSteps(data.SafeOutputs)...)
 
 	// Upload the safe output items manifest as an artifact (non-staged mode only).
 	// This step runs even if previous steps fail, ensuring the audit trail
@@ -317,18 +339,7 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 
 	// Build the job condition
 	// The job should run if agent job completed (not skipped) AND detection passed (if enabled)
-	agentNotSkipped := BuildAnd(
-		&NotNode{Child: BuildFunctionCall("cancelled")},
-		BuildNotEquals(
-			BuildPropertyAccess(fmt.Sprintf("needs.%s.result", constants.AgentJobName)),
-			BuildStringLiteral("skipped"),
-		),
-	)
-
-	jobCondition := agentNotSkipped
-	if threatDetectionEnabled {
-		jobCondition = BuildAnd(agentNotSkipped, buildDetectionSuccessCondition())
-	}
+	jobCondition := buildSafeOutputsJobCondition(threatDetectionEnabled)
 
 	// Build dependencies — detection is now inline in the agent job, no separate dependency needed
 	needs := []string{mainJobName}
@@ -342,6 +353,12 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		needs = append(needs, "unlock")
 		consolidatedSafeOutputsJobLog.Print("Added unlock job dependency to safe_outputs job")
 	}
+	// Wait for the approval checklist and, when it lists actions, for a reviewer to approve the environment
+	var environment string
+	if approvalEnabled {
+		needs = append(needs, SafeOutputsReviewJobName)
+		environment = buildSafeOutputApprovalEnvironment(data.SafeOutputs.Approval)
+	}
 
 	// Extract workflow ID from markdown path for GH_AW_WORKFLOW_ID
 	workflowID := GetWorkflowIDFromPath(markdownPath)
@@ -354,6 +371,7 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		If:             jobCondition.Render(),
 		RunsOn:         c.formatSafeOutputsRunsOn(data.SafeOutputs),
 		Permissions:    permissions.RenderToYAML(),
+		Environment:    environment,
 		TimeoutMinutes: 15, // Slightly longer timeout for consolidated job with multiple steps
 		Env:            jobEnv,
 		Steps:          steps,
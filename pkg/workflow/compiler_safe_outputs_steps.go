@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
 )
 
 var consolidatedSafeOutputsStepsLog = logger.New("workflow:compiler_safe_outputs_steps")
@@ -116,6 +117,18 @@ func (c *Compiler) buildSharedPRCheckoutSteps(data *WorkflowData) []string {
 		gitRemoteToken = effectiveToken
 	}
 
+	// A declared target with its own credentials takes precedence for the create-pull-request
+	// target repository
+	if data.SafeOutputs.CreatePullRequests != nil && data.SafeOutputs.CreatePullRequests.TargetRepoSlug != "" {
+		if targetRepo, ok := matchSafeOutputTarget(data.SafeOutputs.Targets, data.SafeOutputs.CreatePullRequests.TargetRepoSlug); ok {
+			if targetToken := safeOutputTargetToken(data.SafeOutputs, targetRepo); targetToken != "" {
+				consolidatedSafeOutputsStepsLog.Printf("Using token of safe-outputs target %s for checkout", targetRepo)
+				checkoutToken = targetToken
+				gitRemoteToken = targetToken
+			}
+		}
+	}
+
 	// Build combined condition: execute if either create_pull_request or push_to_pull_request_branch will run
 	var condition ConditionNode
 	if data.SafeOutputs.CreatePullRequests != nil && data.SafeOutputs.PushToPullRequestBranch != nil {
@@ -272,6 +285,18 @@ func (c *Compiler) buildHandlerManagerStep(data *WorkflowData) []string {
 	// Add all safe output configuration env vars (still needed by individual handlers)
 	c.addAllSafeOutputConfigEnvVars(&steps, data)
 
+	// Add the declared targets and their tokens for per-message client routing
+	c.addSafeOutputTargetsEnvVars(&steps, data.SafeOutputs)
+
+	// Custom types are processed by their own jobs, so the handler manager skips them
+	if data.SafeOutputs != nil && len(data.SafeOutputs.Types) > 0 {
+		customTypes := make([]string, 0, len(data.SafeOutputs.Types))
+		for _, typeName := range sortedCustomSafeOutputTypeNames(data.SafeOutputs.Types) {
+			customTypes = append(customTypes, stringutil.NormalizeSafeOutputIdentifier(typeName))
+		}
+		steps = append(steps, fmt.Sprintf("          GH_AW_CUSTOM_SAFE_OUTPUT_TYPES: %q\n", strings.Join(customTypes, ",")))
+	}
+
 	// Add extra empty commit token if create-pull-request or push-to-pull-request-branch is configured.
 	// This token is used to push an empty commit after code changes to trigger CI events,
 	// working around the GITHUB_TOKEN limitation where events don't trigger other workflows.
@@ -25,6 +25,7 @@ func (c *Compiler) CompileToYAML(workflowData *WorkflowData, markdownPath string
 
 	c.stepOrderTracker = NewStepOrderTracker()
 	c.scheduleFriendlyFormats = nil
+	c.scheduleCalendar = nil
 
 	if c.artifactManager == nil {
 		c.artifactManager = NewArtifactManager()
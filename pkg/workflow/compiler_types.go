@@ -22,6 +22,11 @@ func WithEngineOverride(engine string) CompilerOption {
 	return func(c *Compiler) { c.engineOverride = engine }
 }
 
+// WithModelOverride sets the AI model override
+func WithModelOverride(model string) CompilerOption {
+	return func(c *Compiler) { c.modelOverride = model }
+}
+
 // WithCustomOutput sets a custom output path for the compiled workflow
 func WithCustomOutput(path string) CompilerOption {
 	return func(c *Compiler) { c.customOutput = path }
@@ -109,38 +114,43 @@ type Compiler struct {
 	verbose                 bool
 	quiet                   bool // If true, suppress success messages (for interactive mode)
 	engineOverride          string
-	customOutput            string              // If set, output will be written to this path instead of default location
-	version                 string              // Version of the extension
-	skipValidation          bool                // If true, skip schema validation
-	noEmit                  bool                // If true, validate without generating lock files
-	strictMode              bool                // If true, enforce strict validation requirements
-	trialMode               bool                // If true, suppress safe outputs for trial mode execution
-	trialLogicalRepoSlug    string              // If set in trial mode, the logical repository to checkout
-	refreshStopTime         bool                // If true, regenerate stop-after times instead of preserving existing ones
-	forceRefreshActionPins  bool                // If true, clear action cache and resolve all actions from GitHub API
-	failFast                bool                // If true, stop at first validation error instead of collecting all errors
-	actionCacheCleared      bool                // Tracks if action cache has already been cleared (for forceRefreshActionPins)
-	markdownPath            string              // Path to the markdown file being compiled (for context in dynamic tool generation)
-	actionMode              ActionMode          // Mode for generating JavaScript steps (inline vs custom actions)
-	actionTag               string              // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
-	jobManager              *JobManager         // Manages jobs and dependencies
-	engineRegistry          *EngineRegistry     // Registry of available agentic engines
-	fileTracker             FileTracker         // Optional file tracker for tracking created files
-	warningCount            int                 // Number of warnings encountered during compilation
-	stepOrderTracker        *StepOrderTracker   // Tracks step ordering for validation
-	actionCache             *ActionCache        // Shared cache for action pin resolutions across all workflows
-	actionResolver          *ActionResolver     // Shared resolver for action pins across all workflows
-	actionPinWarnings       map[string]bool     // Shared cache of already-warned action pin failures (key: "repo@version")
-	importCache             *parser.ImportCache // Shared cache for imported workflow files
-	workflowIdentifier      string              // Identifier for the current workflow being compiled (for schedule scattering)
-	scheduleWarnings        []string            // Accumulated schedule warnings for this compiler instance
-	repositorySlug          string              // Repository slug (owner/repo) used as seed for scattering
-	artifactManager         *ArtifactManager    // Tracks artifact uploads/downloads for validation
-	scheduleFriendlyFormats map[int]string      // Maps schedule item index to friendly format string for current workflow
-	gitRoot                 string              // Git repository root directory (if set, used for action cache path)
-	contentOverride         string              // If set, use this content instead of reading from disk (for Wasm/in-memory compilation)
-	skipHeader              bool                // If true, skip ASCII art header in generated YAML (for Wasm/editor mode)
-	inlinePrompt            bool                // If true, inline markdown content in YAML instead of using runtime-import macros (for Wasm builds)
+	modelOverride           string
+	customOutput            string                  // If set, output will be written to this path instead of default location
+	version                 string                  // Version of the extension
+	skipValidation          bool                    // If true, skip schema validation
+	noEmit                  bool                    // If true, validate without generating lock files
+	strictMode              bool                    // If true, enforce strict validation requirements
+	trialMode               bool                    // If true, suppress safe outputs for trial mode execution
+	trialLogicalRepoSlug    string                  // If set in trial mode, the logical repository to checkout
+	refreshStopTime         bool                    // If true, regenerate stop-after times instead of preserving existing ones
+	refreshImportLock       bool                    // If true, re-resolve remote imports pinned in aw.lock.json and re-pin them
+	recordImportLock        bool                    // If true, pin remote imports missing from aw.lock.json (saved by SaveImportLock)
+	forceRefreshActionPins  bool                    // If true, clear action cache and resolve all actions from GitHub API
+	failFast                bool                    // If true, stop at first validation error instead of collecting all errors
+	actionCacheCleared      bool                    // Tracks if action cache has already been cleared (for forceRefreshActionPins)
+	markdownPath            string                  // Path to the markdown file being compiled (for context in dynamic tool generation)
+	actionMode              ActionMode              // Mode for generating JavaScript steps (inline vs custom actions)
+	actionTag               string                  // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
+	jobManager              *JobManager             // Manages jobs and dependencies
+	engineRegistry          *EngineRegistry         // Registry of available agentic engines
+	fileTracker             FileTracker             // Optional file tracker for tracking created files
+	warningCount            int                     // Number of warnings encountered during compilation
+	stepOrderTracker        *StepOrderTracker       // Tracks step ordering for validation
+	actionCache             *ActionCache            // Shared cache for action pin resolutions across all workflows
+	actionResolver          *ActionResolver         // Shared resolver for action pins across all workflows
+	actionPinWarnings       map[string]bool         // Shared cache of already-warned action pin failures (key: "repo@version")
+	importCache             *parser.ImportCache     // Shared cache for imported workflow files
+	workflowIdentifier      string                  // Identifier for the current workflow being compiled (for schedule scattering)
+	scheduleWarnings        []string                // Accumulated schedule warnings for this compiler instance
+	repositorySlug          string                  // Repository slug (owner/repo) used as seed for scattering
+	artifactManager         *ArtifactManager        // Tracks artifact uploads/downloads for validation
+	scheduleFriendlyFormats map[int]string          // Maps schedule item index to friendly format string for current workflow
+	scheduleCalendar        *ScheduleCalendarConfig // Exclusion calendar from the object form of on.schedule for current workflow
+	batchConfig             *BatchConfig            // on.batch configuration for current workflow
+	gitRoot                 string                  // Git repository root directory (if set, used for action cache path)
+	contentOverride         string                  // If set, use this content instead of reading from disk (for Wasm/in-memory compilation)
+	skipHeader              bool                    // If true, skip ASCII art header in generated YAML (for Wasm/editor mode)
+	inlinePrompt            bool                    // If true, inline markdown content in YAML instead of using runtime-import macros (for Wasm builds)
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -230,6 +240,32 @@ func (c *Compiler) SetRefreshStopTime(refresh bool) {
 	c.refreshStopTime = refresh
 }
 
+// SetRefreshImportLock configures whether remote imports pinned in aw.lock.json
+// are re-resolved from their ref and re-pinned
+func (c *Compiler) SetRefreshImportLock(refresh bool) {
+	c.refreshImportLock = refresh
+	if c.importCache != nil {
+		c.importCache.SetRefreshLock(refresh)
+	}
+}
+
+// SetRecordImportLock configures whether remote imports missing from aw.lock.json are
+// pinned instead of rejected. Pins are only written by SaveImportLock.
+func (c *Compiler) SetRecordImportLock(record bool) {
+	c.recordImportLock = record
+	if c.importCache != nil {
+		c.importCache.SetRecordLock(record)
+	}
+}
+
+// SaveImportLock writes the remote import pins recorded during compilation to aw.lock.json
+func (c *Compiler) SaveImportLock() error {
+	if c.importCache == nil {
+		return nil
+	}
+	return c.importCache.SaveLock()
+}
+
 // SetForceRefreshActionPins configures whether to force refresh of action pins
 func (c *Compiler) SetForceRefreshActionPins(force bool) {
 	c.forceRefreshActionPins = force
@@ -353,6 +389,8 @@ func (c *Compiler) getSharedImportCache() *parser.ImportCache {
 			cwd = "."
 		}
 		c.importCache = parser.NewImportCache(cwd)
+		c.importCache.SetRefreshLock(c.refreshImportLock)
+		c.importCache.SetRecordLock(c.recordImportLock)
 		logTypes.Print("Initialized shared import cache for compiler")
 	}
 	return c.importCache
@@ -426,45 +464,48 @@ type WorkflowData struct {
 	AgentImportSpec       string        // Original import specification for agent file (e.g., "owner/repo/path@ref")
 	RepositoryImports     []string      // Repository-only imports (format: "owner/repo@ref") for .github folder merging
 	StopTime              string
-	SkipIfMatch           *SkipIfMatchConfig   // skip-if-match configuration with query and max threshold
-	SkipIfNoMatch         *SkipIfNoMatchConfig // skip-if-no-match configuration with query and min threshold
-	SkipRoles             []string             // roles to skip workflow for (e.g., [admin, maintainer, write])
-	SkipBots              []string             // users to skip workflow for (e.g., [user1, user2])
-	ManualApproval        string               // environment name for manual approval from on: section
-	Command               []string             // for /command trigger support - multiple command names
-	CommandEvents         []string             // events where command should be active (nil = all events)
-	CommandOtherEvents    map[string]any       // for merging command with other events
-	AIReaction            string               // AI reaction type like "eyes", "heart", etc.
-	StatusComment         *bool                // whether to post status comments (default: true when ai-reaction is set, false otherwise)
-	LockForAgent          bool                 // whether to lock the issue during agent workflow execution
-	Jobs                  map[string]any       // custom job configurations with dependencies
-	Cache                 string               // cache configuration
-	NeedsTextOutput       bool                 // whether the workflow uses ${{ needs.task.outputs.text }}
-	NetworkPermissions    *NetworkPermissions  // parsed network permissions
-	SandboxConfig         *SandboxConfig       // parsed sandbox configuration (AWF or SRT)
-	SafeOutputs           *SafeOutputsConfig   // output configuration for automatic output routes
-	SafeInputs            *SafeInputsConfig    // safe-inputs configuration for custom MCP tools
-	Roles                 []string             // permission levels required to trigger workflow
-	Bots                  []string             // allow list of bot identifiers that can trigger workflow
-	RateLimit             *RateLimitConfig     // rate limiting configuration for workflow triggers
-	CacheMemoryConfig     *CacheMemoryConfig   // parsed cache-memory configuration
-	RepoMemoryConfig      *RepoMemoryConfig    // parsed repo-memory configuration
-	Runtimes              map[string]any       // runtime version overrides from frontmatter
-	PluginInfo            *PluginInfo          // Consolidated plugin information (plugins, custom token, MCP configs)
-	ToolsTimeout          int                  // timeout in seconds for tool/MCP operations (0 = use engine default)
-	ToolsStartupTimeout   int                  // timeout in seconds for MCP server startup (0 = use engine default)
-	Features              map[string]any       // feature flags and configuration options from frontmatter (supports bool and string values)
-	ActionCache           *ActionCache         // cache for action pin resolutions
-	ActionResolver        *ActionResolver      // resolver for action pins
-	StrictMode            bool                 // strict mode for action pinning
-	SecretMasking         *SecretMaskingConfig // secret masking configuration
-	ParsedFrontmatter     *FrontmatterConfig   // cached parsed frontmatter configuration (for performance optimization)
-	RawFrontmatter        map[string]any       // raw parsed frontmatter map (for passing to hash functions without re-parsing)
-	ActionPinWarnings     map[string]bool      // cache of already-warned action pin failures (key: "repo@version")
-	ActionMode            ActionMode           // action mode for workflow compilation (dev, release, script)
-	HasExplicitGitHubTool bool                 // true if tools.github was explicitly configured in frontmatter
-	InlinedImports        bool                 // if true, inline all imports at compile time (from inlined-imports frontmatter field)
-	CheckoutConfigs       []*CheckoutConfig    // user-configured checkout settings from frontmatter
+	SkipIfMatch           *SkipIfMatchConfig       // skip-if-match configuration with query and max threshold
+	SkipIfNoMatch         *SkipIfNoMatchConfig     // skip-if-no-match configuration with query and min threshold
+	SkipRoles             []string                 // roles to skip workflow for (e.g., [admin, maintainer, write])
+	SkipBots              []string                 // users to skip workflow for (e.g., [user1, user2])
+	ScheduleCalendar      *ScheduleCalendarConfig  // dates and windows in which scheduled runs are skipped
+	ManualApproval        string                   // environment name for manual approval from on: section
+	Command               []string                 // for /command trigger support - multiple command names
+	CommandEvents         []string                 // events where command should be active (nil = all events)
+	CommandOtherEvents    map[string]any           // for merging command with other events
+	AIReaction            string                   // AI reaction type like "eyes", "heart", etc.
+	StatusComment         *bool                    // whether to post status comments (default: true when ai-reaction is set, false otherwise)
+	LockForAgent          bool                     // whether to lock the issue during agent workflow execution
+	Jobs                  map[string]any           // custom job configurations with dependencies
+	Cache                 string                   // cache configuration
+	NeedsTextOutput       bool                     // whether the workflow uses ${{ needs.task.outputs.text }}
+	NetworkPermissions    *NetworkPermissions      // parsed network permissions
+	SandboxConfig         *SandboxConfig           // parsed sandbox configuration (AWF or SRT)
+	SafeOutputs           *SafeOutputsConfig       // output configuration for automatic output routes
+	SafeInputs            *SafeInputsConfig        // safe-inputs configuration for custom MCP tools
+	Roles                 []string                 // permission levels required to trigger workflow
+	Bots                  []string                 // allow list of bot identifiers that can trigger workflow
+	RateLimit             *RateLimitConfig         // rate limiting configuration for workflow triggers
+	ConcurrencyPolicy     *ConcurrencyPolicyConfig // queueing policy from concurrency.policy
+	Batch                 *BatchConfig             // event batching from on.batch
+	CacheMemoryConfig     *CacheMemoryConfig       // parsed cache-memory configuration
+	RepoMemoryConfig      *RepoMemoryConfig        // parsed repo-memory configuration
+	Runtimes              map[string]any           // runtime version overrides from frontmatter
+	PluginInfo            *PluginInfo              // Consolidated plugin information (plugins, custom token, MCP configs)
+	ToolsTimeout          int                      // timeout in seconds for tool/MCP operations (0 = use engine default)
+	ToolsStartupTimeout   int                      // timeout in seconds for MCP server startup (0 = use engine default)
+	Features              map[string]any           // feature flags and configuration options from frontmatter (supports bool and string values)
+	ActionCache           *ActionCache             // cache for action pin resolutions
+	ActionResolver        *ActionResolver          // resolver for action pins
+	StrictMode            bool                     // strict mode for action pinning
+	SecretMasking         *SecretMaskingConfig     // secret masking configuration
+	ParsedFrontmatter     *FrontmatterConfig       // cached parsed frontmatter configuration (for performance optimization)
+	RawFrontmatter        map[string]any           // raw parsed frontmatter map (for passing to hash functions without re-parsing)
+	ActionPinWarnings     map[string]bool          // cache of already-warned action pin failures (key: "repo@version")
+	ActionMode            ActionMode               // action mode for workflow compilation (dev, release, script)
+	HasExplicitGitHubTool bool                     // true if tools.github was explicitly configured in frontmatter
+	InlinedImports        bool                     // if true, inline all imports at compile time (from inlined-imports frontmatter field)
+	CheckoutConfigs       []*CheckoutConfig        // user-configured checkout settings from frontmatter
 }
 
 // BaseSafeOutputConfig holds common configuration fields for all safe output types
@@ -515,7 +556,10 @@ type SafeOutputsConfig struct {
 	NoOp                            *NoOpConfig                            `yaml:"noop,omitempty"`                         // No-op output for logging only (always available as fallback)
 	ThreatDetection                 *ThreatDetectionConfig                 `yaml:"threat-detection,omitempty"`             // Threat detection configuration
 	Jobs                            map[string]*SafeJobConfig              `yaml:"jobs,omitempty"`                         // Safe-jobs configuration (moved from top-level)
+	Types                           map[string]*CustomSafeOutputTypeConfig `yaml:"types,omitempty"`                        // Custom safe-output types declared in frontmatter
+	Approval                        *SafeOutputApprovalConfig              `yaml:"approval,omitempty"`                     // Per-item human approval before safe outputs execute
 	App                             *GitHubAppConfig                       `yaml:"app,omitempty"`                          // GitHub App credentials for token minting
+	Targets                         map[string]*SafeOutputRepoTarget       `yaml:"targets,omitempty"`                      // Repositories safe outputs may target, with per-target credentials
 	AllowedDomains                  []string                               `yaml:"allowed-domains,omitempty"`
 	AllowGitHubReferences           []string                               `yaml:"allowed-github-references,omitempty"` // Allowed repositories for GitHub references (e.g., ["repo", "org/repo2"])
 	Staged                          bool                                   `yaml:"staged,omitempty"`                    // If true, emit step summary messages instead of making GitHub API calls
@@ -576,5 +620,6 @@ type MentionsConfig struct {
 
 // SecretMaskingConfig holds configuration for secret redaction behavior
 type SecretMaskingConfig struct {
-	Steps []map[string]any `yaml:"steps,omitempty"` // Additional secret redaction steps to inject after built-in redaction
+	Steps     []map[string]any `yaml:"steps,omitempty"`     // Additional secret redaction steps to inject after built-in redaction
+	Detectors []string         `yaml:"detectors,omitempty"` // Built-in detectors applied by the redaction step (nil for the defaults, empty for none)
 }
@@ -159,7 +159,10 @@ func (c *Compiler) generateWorkflowBody(yaml *strings.Builder, data *WorkflowDat
 	// Agent permissions are applied only to the agent job
 	yaml.WriteString("permissions: {}\n\n")
 
-	yaml.WriteString(data.Concurrency + "\n\n")
+	// Queue and debounce policies have no workflow-level concurrency group
+	if data.Concurrency != "" {
+		yaml.WriteString(data.Concurrency + "\n\n")
+	}
 	yaml.WriteString(data.RunName + "\n\n")
 
 	// Add env section if present
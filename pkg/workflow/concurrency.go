@@ -20,6 +20,18 @@ func GenerateConcurrencyConfig(workflowData *WorkflowData, isCommandTrigger bool
 		return workflowData.Concurrency
 	}
 
+	// An explicit policy decides the concurrency group
+	if workflowData.ConcurrencyPolicy != nil {
+		concurrencyLog.Printf("Using concurrency policy: %s", workflowData.ConcurrencyPolicy.Policy)
+		return generateConcurrencyPolicyConfig(workflowData.ConcurrencyPolicy)
+	}
+
+	// Batched workflows must not drop the runs recording events
+	if workflowData.Batch != nil {
+		concurrencyLog.Print("Using batch concurrency group")
+		return generateBatchConcurrencyConfig()
+	}
+
 	// Build concurrency group keys using the original workflow-specific logic
 	keys := buildConcurrencyGroupKeys(workflowData, isCommandTrigger)
 	groupValue := strings.Join(keys, "-")
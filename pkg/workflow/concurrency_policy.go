@@ -0,0 +1,238 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var concurrencyPolicyLog = logger.New("workflow:concurrency_policy")
+
+// This file handles concurrency.policy, which replaces the generated concurrency group with
+// an explicit queueing strategy:
+//
+//	concurrency:
+//	  policy: queue       # queue, debounce or coalesce
+//	  max-depth: 3        # queue: runs allowed to wait behind the running one
+//	  max-wait-minutes: 60 # queue: minutes a run waits for the runs ahead before failing
+//	  quiet-minutes: 5    # debounce: minutes without a newer event before the run proceeds
+//
+// GitHub Actions concurrency groups keep at most one pending run and silently cancel the
+// others, so the policies are compiled as follows:
+//   - queue: no workflow-level group; a pre-activation check counts the in-flight runs of the
+//     workflow through the API, skips the run when the queue is full and otherwise waits
+//     until the runs ahead of it have completed, failing after max-wait-minutes
+//   - debounce: a debounce job joins a per-entity group with cancel-in-progress and waits
+//     quiet-minutes, so every newer event for the same issue or pull request cancels the
+//     waiting run and only the latest one of a burst proceeds. The job only runs once the
+//     pre-activation checks passed, so events that are rejected anyway (e.g. from a commenter
+//     without the required role) never join the group and cannot cancel an accepted run
+//   - coalesce: a per-entity workflow-level group without cancel-in-progress, so there is at
+//     most one running and one pending run per issue or pull request
+
+// Concurrency policies
+const (
+	ConcurrencyPolicyQueue    = "queue"
+	ConcurrencyPolicyDebounce = "debounce"
+	ConcurrencyPolicyCoalesce = "coalesce"
+)
+
+// validConcurrencyPolicies lists the values accepted by concurrency.policy
+var validConcurrencyPolicies = []string{ConcurrencyPolicyQueue, ConcurrencyPolicyDebounce, ConcurrencyPolicyCoalesce}
+
+// concurrencyPolicyFields are the gh-aw extensions of the concurrency section. They are not
+// GitHub Actions fields and are never rendered into the lock file.
+var concurrencyPolicyFields = []string{"policy", "max-depth", "max-wait-minutes", "quiet-minutes"}
+
+// concurrencyEntityKey identifies the issue, pull request or discussion of the triggering
+// event, falling back to the ref for events without one
+const concurrencyEntityKey = "${{ github.event.issue.number || github.event.pull_request.number || github.event.discussion.number || github.ref }}"
+
+// ConcurrencyPolicyConfig is the parsed concurrency.policy configuration
+type ConcurrencyPolicyConfig struct {
+	Policy         string
+	MaxDepth       int // queue only
+	MaxWaitMinutes int // queue only
+	QuietMinutes   int // debounce only
+}
+
+// NeedsPreActivationCheck reports whether the policy is enforced by a pre-activation step
+func (p *ConcurrencyPolicyConfig) NeedsPreActivationCheck() bool {
+	return p != nil && p.Policy == ConcurrencyPolicyQueue
+}
+
+// NeedsDebounceJob reports whether the policy is enforced by the debounce job
+func (p *ConcurrencyPolicyConfig) NeedsDebounceJob() bool {
+	return p != nil && p.Policy == ConcurrencyPolicyDebounce
+}
+
+// extractConcurrencyPolicy extracts concurrency.policy from frontmatter. It returns nil when
+// the concurrency section has no policy.
+func (c *Compiler) extractConcurrencyPolicy(frontmatter map[string]any) (*ConcurrencyPolicyConfig, error) {
+	concurrency, ok := frontmatter["concurrency"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	policyValue, hasPolicy := concurrency["policy"]
+	if !hasPolicy {
+		for _, field := range concurrencyPolicyFields[1:] {
+			if _, exists := concurrency[field]; exists {
+				return nil, fmt.Errorf("concurrency.%s requires concurrency.policy", field)
+			}
+		}
+		return nil, nil
+	}
+
+	policy, ok := policyValue.(string)
+	if !ok || !slices.Contains(validConcurrencyPolicies, policy) {
+		return nil, fmt.Errorf("invalid concurrency.policy '%v', valid policies: %s", policyValue, strings.Join(validConcurrencyPolicies, ", "))
+	}
+	for _, field := range []string{"group", "cancel-in-progress"} {
+		if _, exists := concurrency[field]; exists {
+			return nil, fmt.Errorf("concurrency.%s cannot be combined with concurrency.policy, the policy defines the concurrency group", field)
+		}
+	}
+
+	config := &ConcurrencyPolicyConfig{Policy: policy}
+	if value, exists := concurrency["max-depth"]; exists {
+		if policy != ConcurrencyPolicyQueue {
+			return nil, fmt.Errorf("concurrency.max-depth is only supported with policy: %s", ConcurrencyPolicyQueue)
+		}
+		depth, ok := parseIntValue(value)
+		if !ok || depth < 1 {
+			return nil, fmt.Errorf("concurrency.max-depth must be a positive integer, got %v", value)
+		}
+		config.MaxDepth = depth
+	}
+	if value, exists := concurrency["max-wait-minutes"]; exists {
+		if policy != ConcurrencyPolicyQueue {
+			return nil, fmt.Errorf("concurrency.max-wait-minutes is only supported with policy: %s", ConcurrencyPolicyQueue)
+		}
+		minutes, ok := parseIntValue(value)
+		if !ok || minutes < 1 {
+			return nil, fmt.Errorf("concurrency.max-wait-minutes must be a positive integer, got %v", value)
+		}
+		config.MaxWaitMinutes = minutes
+	}
+	if value, exists := concurrency["quiet-minutes"]; exists {
+		if policy != ConcurrencyPolicyDebounce {
+			return nil, fmt.Errorf("concurrency.quiet-minutes is only supported with policy: %s", ConcurrencyPolicyDebounce)
+		}
+		minutes, ok := parseIntValue(value)
+		if !ok || minutes < 1 {
+			return nil, fmt.Errorf("concurrency.quiet-minutes must be a positive integer, got %v", value)
+		}
+		config.QuietMinutes = minutes
+	}
+
+	// Apply defaults
+	if policy == ConcurrencyPolicyQueue && config.MaxDepth == 0 {
+		config.MaxDepth = constants.DefaultConcurrencyQueueDepth
+	}
+	if policy == ConcurrencyPolicyQueue && config.MaxWaitMinutes == 0 {
+		config.MaxWaitMinutes = constants.DefaultConcurrencyMaxWaitMinutes
+	}
+	if policy == ConcurrencyPolicyDebounce && config.QuietMinutes == 0 {
+		config.QuietMinutes = constants.DefaultConcurrencyQuietMinutes
+	}
+
+	concurrencyPolicyLog.Printf("Extracted concurrency policy: policy=%s, maxDepth=%d, maxWaitMinutes=%d, quietMinutes=%d", config.Policy, config.MaxDepth, config.MaxWaitMinutes, config.QuietMinutes)
+	return config, nil
+}
+
+// extractConcurrencySection extracts the concurrency section as YAML. A section with a policy
+// yields no YAML because the policy decides the concurrency group.
+func (c *Compiler) extractConcurrencySection(frontmatter map[string]any) string {
+	if concurrency, ok := frontmatter["concurrency"].(map[string]any); ok {
+		if _, hasPolicy := concurrency["policy"]; hasPolicy {
+			return ""
+		}
+	}
+	return c.extractTopLevelYAMLSection(frontmatter, "concurrency")
+}
+
+// generateConcurrencyPolicyConfig generates the workflow-level concurrency configuration of a
+// policy. Queue and debounce have none because a workflow-level group would cancel the
+// pending runs they wait for.
+func generateConcurrencyPolicyConfig(policy *ConcurrencyPolicyConfig) string {
+	if policy.Policy != ConcurrencyPolicyCoalesce {
+		return ""
+	}
+	return fmt.Sprintf("concurrency:\n  group: \"gh-aw-${{ github.workflow }}-%s\"", concurrencyEntityKey)
+}
+
+// generateDebounceJobConcurrency generates the concurrency configuration of the debounce job.
+// Every newer event for the same entity cancels the waiting job.
+func generateDebounceJobConcurrency() string {
+	return fmt.Sprintf("concurrency:\n  group: \"gh-aw-debounce-${{ github.workflow }}-%s\"\n  cancel-in-progress: true", concurrencyEntityKey)
+}
+
+// buildDebounceJob creates the job waiting for the quiet period of a debounced workflow. It runs
+// after the pre-activation checks passed, and the activation job depends on it.
+func (c *Compiler) buildDebounceJob(data *WorkflowData, preActivationJobCreated bool) (*Job, error) {
+	concurrencyPolicyLog.Printf("Building debounce job: quietMinutes=%d", data.ConcurrencyPolicy.QuietMinutes)
+
+	setupActionRef := c.resolveActionReference("./actions/setup", data)
+	if setupActionRef == "" {
+		return nil, errors.New("setup action reference is required but could not be resolved")
+	}
+
+	var steps []string
+	checkoutSteps := c.generateCheckoutActionsFolder(data)
+	steps = append(steps, checkoutSteps...)
+	steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+	steps = c.generateConcurrencyPolicyCheck(data, steps, nil)
+
+	// The debounce check only sleeps; it needs contents: read for the dev mode checkout only
+	permissions := NewPermissionsEmpty()
+	if len(checkoutSteps) > 0 {
+		permissions = NewPermissionsContentsRead()
+	}
+
+	job := &Job{
+		Name:        string(constants.DebounceJobName),
+		RunsOn:      c.formatSafeOutputsRunsOn(data.SafeOutputs),
+		Permissions: permissions.RenderToYAML(),
+		Concurrency: c.indentYAMLLines(generateDebounceJobConcurrency(), "    "),
+		Steps:       steps,
+	}
+	if preActivationJobCreated {
+		job.Needs = []string{string(constants.PreActivationJobName)}
+		job.If = BuildEquals(
+			BuildPropertyAccess(fmt.Sprintf("needs.%s.outputs.%s", constants.PreActivationJobName, constants.ActivatedOutput)),
+			BuildStringLiteral("true"),
+		).Render()
+	}
+	return job, nil
+}
+
+// generateConcurrencyPolicyCheck generates the step enforcing a queue or debounce policy. A
+// non-nil condition gates the step on the checks that ran before it, so rejected events do
+// not wait.
+func (c *Compiler) generateConcurrencyPolicyCheck(data *WorkflowData, steps []string, condition ConditionNode) []string {
+	policy := data.ConcurrencyPolicy
+	steps = append(steps, "      - name: Check concurrency policy\n")
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CheckConcurrencyPolicyStepID))
+	if condition != nil {
+		steps = append(steps, fmt.Sprintf("        if: %s\n", condition.Render()))
+	}
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_CONCURRENCY_POLICY: %q\n", policy.Policy))
+	if policy.Policy == ConcurrencyPolicyQueue {
+		steps = append(steps, fmt.Sprintf("          GH_AW_CONCURRENCY_MAX_DEPTH: \"%d\"\n", policy.MaxDepth))
+		steps = append(steps, fmt.Sprintf("          GH_AW_CONCURRENCY_MAX_WAIT_MINUTES: \"%d\"\n", policy.MaxWaitMinutes))
+	} else {
+		steps = append(steps, fmt.Sprintf("          GH_AW_CONCURRENCY_QUIET_MINUTES: \"%d\"\n", policy.QuietMinutes))
+	}
+	steps = append(steps, fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          github-token: ${{ secrets.GITHUB_TOKEN }}\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("check_concurrency_policy.cjs"))
+	return steps
+}
@@ -0,0 +1,222 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractConcurrencyPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency any
+		want        *ConcurrencyPolicyConfig
+	}{
+		{
+			name:        "no concurrency",
+			concurrency: nil,
+			want:        nil,
+		},
+		{
+			name:        "group only",
+			concurrency: map[string]any{"group": "my-group"},
+			want:        nil,
+		},
+		{
+			name:        "queue with default depth",
+			concurrency: map[string]any{"policy": "queue"},
+			want:        &ConcurrencyPolicyConfig{Policy: ConcurrencyPolicyQueue, MaxDepth: 5, MaxWaitMinutes: 60},
+		},
+		{
+			name:        "queue with depth",
+			concurrency: map[string]any{"policy": "queue", "max-depth": uint64(2), "max-wait-minutes": 30},
+			want:        &ConcurrencyPolicyConfig{Policy: ConcurrencyPolicyQueue, MaxDepth: 2, MaxWaitMinutes: 30},
+		},
+		{
+			name:        "debounce",
+			concurrency: map[string]any{"policy": "debounce", "quiet-minutes": 10},
+			want:        &ConcurrencyPolicyConfig{Policy: ConcurrencyPolicyDebounce, QuietMinutes: 10},
+		},
+		{
+			name:        "coalesce",
+			concurrency: map[string]any{"policy": "coalesce"},
+			want:        &ConcurrencyPolicyConfig{Policy: ConcurrencyPolicyCoalesce},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter := map[string]any{}
+			if tt.concurrency != nil {
+				frontmatter["concurrency"] = tt.concurrency
+			}
+			got, err := NewCompiler().extractConcurrencyPolicy(frontmatter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractConcurrencyPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency map[string]any
+		want        string
+	}{
+		{
+			name:        "unknown policy",
+			concurrency: map[string]any{"policy": "fifo"},
+			want:        "invalid concurrency.policy 'fifo'",
+		},
+		{
+			name:        "policy with group",
+			concurrency: map[string]any{"policy": "queue", "group": "my-group"},
+			want:        "concurrency.group cannot be combined with concurrency.policy",
+		},
+		{
+			name:        "depth without policy",
+			concurrency: map[string]any{"group": "my-group", "max-depth": 2},
+			want:        "concurrency.max-depth requires concurrency.policy",
+		},
+		{
+			name:        "quiet minutes with queue",
+			concurrency: map[string]any{"policy": "queue", "quiet-minutes": 5},
+			want:        "concurrency.quiet-minutes is only supported with policy: debounce",
+		},
+		{
+			name:        "max wait with debounce",
+			concurrency: map[string]any{"policy": "debounce", "max-wait-minutes": 30},
+			want:        "concurrency.max-wait-minutes is only supported with policy: queue",
+		},
+		{
+			name:        "invalid max wait",
+			concurrency: map[string]any{"policy": "queue", "max-wait-minutes": 0},
+			want:        "concurrency.max-wait-minutes must be a positive integer",
+		},
+		{
+			name:        "invalid depth",
+			concurrency: map[string]any{"policy": "queue", "max-depth": 0},
+			want:        "concurrency.max-depth must be a positive integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCompiler().extractConcurrencyPolicy(map[string]any{"concurrency": tt.concurrency})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
+
+// compileConcurrencyPolicyWorkflow compiles an issue triage workflow with the given concurrency
+// section and returns the lock file
+func compileConcurrencyPolicyWorkflow(t *testing.T, concurrency string) string {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "concurrency-policy-compile")
+	workflowContent := `---
+on:
+  issues:
+    types: [opened, edited]
+engine: copilot
+` + concurrency + `
+---
+
+# Triage
+
+Triage the issue.
+`
+	workflowFile := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(workflowContent), 0644))
+
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("triage.md")
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow with a concurrency policy should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	return string(lockContent)
+}
+
+// workflowLevelConcurrency returns the top-level concurrency section of a lock file
+func workflowLevelConcurrency(lock string) string {
+	_, after, found := strings.Cut(lock, "\nconcurrency:\n")
+	if !found {
+		return ""
+	}
+	section, _, _ := strings.Cut(after, "\n\n")
+	return section
+}
+
+func TestConcurrencyPolicyCompilation(t *testing.T) {
+	t.Run("queue", func(t *testing.T) {
+		lock := compileConcurrencyPolicyWorkflow(t, "concurrency:\n  policy: queue\n  max-depth: 2")
+
+		assert.Empty(t, workflowLevelConcurrency(lock), "queue should not use a workflow-level group that drops pending runs")
+		assert.NotContains(t, lock, "policy: queue", "policy fields should not be emitted")
+		assert.Contains(t, lock, "id: check_concurrency_policy", "pre-activation job should check the queue")
+		assert.Contains(t, lock, `GH_AW_CONCURRENCY_POLICY: "queue"`)
+		assert.Contains(t, lock, `GH_AW_CONCURRENCY_MAX_DEPTH: "2"`)
+		assert.Contains(t, lock, `GH_AW_CONCURRENCY_MAX_WAIT_MINUTES: "60"`, "queued runs should stop waiting after the default max wait")
+		assert.Contains(t, lock, "steps.check_concurrency_policy.outputs.concurrency_policy_ok", "activated output should include the policy check")
+		assert.Contains(t, lock, "actions: read", "queue check needs to list workflow runs")
+		assert.Contains(t, lock, "        id: check_concurrency_policy\n        if: steps.check_membership.outputs.is_team_member == 'true'\n",
+			"rejected events should not wait in the queue")
+	})
+
+	t.Run("debounce", func(t *testing.T) {
+		lock := compileConcurrencyPolicyWorkflow(t, "concurrency:\n  policy: debounce\n  quiet-minutes: 3")
+
+		assert.Empty(t, workflowLevelConcurrency(lock), "debounce should not use a workflow-level group")
+		assert.Contains(t, lock, `GH_AW_CONCURRENCY_QUIET_MINUTES: "3"`)
+		assert.Contains(t, lock, "cancel-in-progress: true", "newer events should cancel the waiting job")
+
+		var workflow struct {
+			Jobs map[string]struct {
+				If          string `yaml:"if"`
+				Needs       any    `yaml:"needs"`
+				Concurrency struct {
+					Group            string `yaml:"group"`
+					CancelInProgress bool   `yaml:"cancel-in-progress"`
+				} `yaml:"concurrency"`
+				Steps []struct {
+					ID string `yaml:"id"`
+				} `yaml:"steps"`
+			} `yaml:"jobs"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(lock), &workflow), "lock file should be valid YAML")
+
+		preActivation := workflow.Jobs["pre_activation"]
+		assert.Empty(t, preActivation.Concurrency.Group, "events rejected by the pre-activation checks should not join the debounce group")
+		for _, step := range preActivation.Steps {
+			assert.NotEqual(t, "check_concurrency_policy", step.ID, "pre-activation job should not wait for the quiet period")
+		}
+
+		debounce, ok := workflow.Jobs["debounce"]
+		require.True(t, ok, "debounce job should be generated")
+		assert.Equal(t, "gh-aw-debounce-${{ github.workflow }}-${{ github.event.issue.number || github.event.pull_request.number || github.event.discussion.number || github.ref }}", debounce.Concurrency.Group,
+			"debounce job should join the per-entity debounce group")
+		assert.True(t, debounce.Concurrency.CancelInProgress)
+		assert.Equal(t, "pre_activation", debounce.Needs)
+		assert.Equal(t, "needs.pre_activation.outputs.activated == 'true'", debounce.If, "only accepted events should be debounced")
+		assert.Contains(t, workflow.Jobs["activation"].Needs, "debounce", "activation should wait for the quiet period")
+	})
+
+	t.Run("coalesce", func(t *testing.T) {
+		lock := compileConcurrencyPolicyWorkflow(t, "concurrency:\n  policy: coalesce")
+
+		section := workflowLevelConcurrency(lock)
+		assert.Contains(t, section, "github.event.issue.number || github.event.pull_request.number", "coalesce should group runs per entity")
+		assert.NotContains(t, section, "cancel-in-progress", "coalesce should not cancel running runs")
+		assert.NotContains(t, lock, "check_concurrency_policy", "coalesce is enforced by the concurrency group alone")
+	})
+}
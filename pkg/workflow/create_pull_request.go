@@ -18,6 +18,12 @@ func getFallbackAsIssue(config *CreatePullRequestsConfig) bool {
 	return *config.FallbackAsIssue
 }
 
+// createPullRequestNeedsIssuesWrite reports whether create-pull-request may create issues, either
+// as a fallback when the push fails or when the patch violates the path policy.
+func createPullRequestNeedsIssuesWrite(config *CreatePullRequestsConfig) bool {
+	return getFallbackAsIssue(config) || (config != nil && config.IfPathViolation == "issue")
+}
+
 // CreatePullRequestsConfig holds configuration for creating GitHub pull requests from agent output
 type CreatePullRequestsConfig struct {
 	BaseSafeOutputConfig           `yaml:",inline"`
@@ -36,6 +42,9 @@ type CreatePullRequestsConfig struct {
 	Footer                         *string  `yaml:"footer,omitempty"`                              // Controls whether AI-generated footer is added. When false, visible footer is omitted but XML markers are kept.
 	FallbackAsIssue                *bool    `yaml:"fallback-as-issue,omitempty"`                   // When true (default), creates an issue if PR creation fails. When false, no fallback occurs and issues: write permission is not requested.
 	GithubTokenForExtraEmptyCommit string   `yaml:"github-token-for-extra-empty-commit,omitempty"` // Token used to push an empty commit to trigger CI events. Use a PAT or "app" for GitHub App auth.
+	AllowedPaths                   []string `yaml:"allowed-paths,omitempty"`                       // Globs of the files the patch may change. If omitted, any file may be changed.
+	ProtectedPaths                 []string `yaml:"protected-paths,omitempty"`                     // Globs of the files the patch must not change (take precedence over allowed-paths)
+	IfPathViolation                string   `yaml:"if-path-violation,omitempty"`                   // Behavior when the patch changes files outside the path policy: "reject" (default), "drop", or "issue"
 }
 
 // buildCreateOutputPullRequestJob creates the create_pull_request job
@@ -211,13 +220,12 @@ func (c *Compiler) buildCreateOutputPullRequestJob(data *WorkflowData, mainJobNa
 		"error_message":       "${{ steps.create_pull_request.outputs.error_message }}",
 	}
 
-	// Choose permissions based on fallback-as-issue setting
-	fallbackAsIssue := getFallbackAsIssue(data.SafeOutputs.CreatePullRequests)
+	// Choose permissions based on fallback-as-issue and if-path-violation settings
 	var permissions *Permissions
-	if fallbackAsIssue {
+	if createPullRequestNeedsIssuesWrite(data.SafeOutputs.CreatePullRequests) {
 		// Default: include issues: write for fallback behavior
 		permissions = NewPermissionsContentsWriteIssuesWritePRWrite()
-		createPRLog.Print("Using permissions with issues:write (fallback-as-issue enabled)")
+		createPRLog.Print("Using permissions with issues:write (fallback issues enabled)")
 	} else {
 		// Fallback disabled: only need contents: write and pull-requests: write
 		permissions = NewPermissionsContentsWritePRWrite()
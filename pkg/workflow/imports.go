@@ -356,6 +356,7 @@ func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedS
 
 	// Track types defined in imported configs for conflict detection
 	importedDefinedTypes := make(map[string]bool)
+	importedCustomTypes := make(map[string]bool)
 
 	// Collect all imported configs. This includes configs with only meta fields (like allowed-domains,
 	// staged, env, github-token, max-patch-size, runs-on) as well as those defining safe output types.
@@ -389,6 +390,21 @@ func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedS
 			}
 		}
 
+		// Custom types (safe-outputs.types) follow the same rules, keyed by type name
+		if customTypes, ok := config["types"].(map[string]any); ok {
+			for typeName := range customTypes {
+				if topSafeOutputs != nil && topSafeOutputs.Types[typeName] != nil {
+					importsLog.Printf("Main workflow overrides imported custom safe-output type: %s", typeName)
+					delete(customTypes, typeName)
+					continue
+				}
+				if importedCustomTypes[typeName] {
+					return nil, fmt.Errorf("safe-outputs conflict: type '%s' is defined in multiple imported workflows. Each safe-output type can only be defined once", typeName)
+				}
+				importedCustomTypes[typeName] = true
+			}
+		}
+
 		importedConfigs = append(importedConfigs, config)
 	}
 
@@ -639,6 +655,9 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	}
 
 	// Merge meta-configuration fields (only set if empty/zero in result)
+	if result.Approval == nil && importedConfig.Approval != nil {
+		result.Approval = importedConfig.Approval
+	}
 	if len(result.AllowedDomains) == 0 && len(importedConfig.AllowedDomains) > 0 {
 		result.AllowedDomains = importedConfig.AllowedDomains
 	}
@@ -691,6 +710,16 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 		result.Steps = append(result.Steps, importedConfig.Steps...)
 	}
 
+	// Merge custom types: conflicts were resolved in MergeSafeOutputs, so imported types are added as-is
+	for typeName, typeConfig := range importedConfig.Types {
+		if result.Types == nil {
+			result.Types = make(map[string]*CustomSafeOutputTypeConfig)
+		}
+		if _, exists := result.Types[typeName]; !exists {
+			result.Types[typeName] = typeConfig
+		}
+	}
+
 	// NOTE: Jobs are NOT merged here. They are handled separately in compiler_orchestrator.go
 	// via mergeSafeJobsFromIncludedConfigs and extractSafeJobsFromFrontmatter.
 	// The Jobs field is managed independently from other safe-output types to support
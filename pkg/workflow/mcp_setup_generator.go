@@ -233,7 +233,7 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 				}
 			}
 		}
-		validationConfigJSON, err := GetValidationConfigJSON(enabledTypes)
+		validationConfigJSON, err := getValidationConfigJSONWithCustomTypes(enabledTypes, customSafeOutputTypesValidationConfig(workflowData.SafeOutputs))
 		if err != nil {
 			// Log error prominently - validation config is critical for safe output processing
 			// The error will be caught at compile time if this ever fails
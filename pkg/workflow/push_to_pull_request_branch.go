@@ -18,6 +18,9 @@ type PushToPullRequestBranchConfig struct {
 	IfNoChanges                    string   `yaml:"if-no-changes,omitempty"`                       // Behavior when no changes to push: "warn", "error", or "ignore" (default: "warn")
 	CommitTitleSuffix              string   `yaml:"commit-title-suffix,omitempty"`                 // Optional suffix to append to generated commit titles
 	GithubTokenForExtraEmptyCommit string   `yaml:"github-token-for-extra-empty-commit,omitempty"` // Token used to push an empty commit to trigger CI events. Use a PAT or "app" for GitHub App auth.
+	AllowedPaths                   []string `yaml:"allowed-paths,omitempty"`                       // Globs of the files the patch may change. If omitted, any file may be changed.
+	ProtectedPaths                 []string `yaml:"protected-paths,omitempty"`                     // Globs of the files the patch must not change (take precedence over allowed-paths)
+	IfPathViolation                string   `yaml:"if-path-violation,omitempty"`                   // Behavior when the patch changes files outside the path policy: "reject" (default) or "drop"
 }
 
 // buildCheckoutRepository generates a checkout step with optional target repository and custom token
@@ -118,6 +121,13 @@ func (c *Compiler) parsePushToPullRequestBranchConfig(outputMap map[string]any)
 				}
 			}
 
+			// Parse allowed-paths and protected-paths (optional glob lists)
+			pushToBranchConfig.AllowedPaths = ParseStringArrayFromConfig(configMap, "allowed-paths", pushToPullRequestBranchLog)
+			pushToBranchConfig.ProtectedPaths = ParseStringArrayFromConfig(configMap, "protected-paths", pushToPullRequestBranchLog)
+
+			// Parse if-path-violation (optional, defaults to "reject" at runtime)
+			pushToBranchConfig.IfPathViolation = extractStringFromMap(configMap, "if-path-violation", pushToPullRequestBranchLog)
+
 			// Parse github-token-for-extra-empty-commit (optional) - token for pushing empty commit to trigger CI
 			if emptyCommitToken, exists := configMap["github-token-for-extra-empty-commit"]; exists {
 				if emptyCommitTokenStr, ok := emptyCommitToken.(string); ok {
@@ -60,9 +60,12 @@ func (c *Compiler) generateSecretRedactionStep(yaml *strings.Builder, yamlConten
 	// This is important for validation to ensure the step ordering is correct
 	c.stepOrderTracker.RecordSecretRedaction("Redact secrets in logs")
 
-	// If no secrets found, we still generate the step but it will be a no-op at runtime
-	// This ensures consistent step ordering and validation
-	if len(secretReferences) == 0 {
+	// Detectors selected through secret-masking.detectors (empty when the defaults apply)
+	detectors := secretDetectorsEnvValue(data.SecretMasking)
+
+	// If no secrets found and no detectors are configured, we still generate the step but it will be
+	// a no-op at runtime. This ensures consistent step ordering and validation
+	if len(secretReferences) == 0 && detectors == "" {
 		secretMaskingLog.Print("No secrets found, generating no-op redaction step")
 		// Generate a minimal no-op redaction step for validation purposes
 		yaml.WriteString("      - name: Redact secrets in logs\n")
@@ -93,6 +96,9 @@ func (c *Compiler) generateSecretRedactionStep(yaml *strings.Builder, yamlConten
 			escapedRefs[i] = escapeSingleQuote(ref)
 		}
 		fmt.Fprintf(yaml, "          GH_AW_SECRET_NAMES: '%s'\n", strings.Join(escapedRefs, ","))
+		if detectors != "" {
+			fmt.Fprintf(yaml, "          GH_AW_SECRET_DETECTORS: '%s'\n", escapeSingleQuote(detectors))
+		}
 
 		// Pass the actual secret values as environment variables so they can be redacted
 		// Each secret will be available as an environment variable
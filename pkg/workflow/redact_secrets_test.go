@@ -155,6 +155,29 @@ Test workflow for secret redaction.
 	}
 }
 
+func TestSecretRedactionStepDetectors(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.stepOrderTracker = NewStepOrderTracker()
+
+	var yaml strings.Builder
+	data := &WorkflowData{SecretMasking: &SecretMaskingConfig{Detectors: []string{"github-tokens", "entropy"}}}
+	compiler.generateSecretRedactionStep(&yaml, "", data)
+
+	step := yaml.String()
+	if strings.Contains(step, "No secrets to redact") {
+		t.Error("Expected a redaction step running the configured detectors even without secret references")
+	}
+	if !strings.Contains(step, "GH_AW_SECRET_DETECTORS: 'github-tokens,entropy'") {
+		t.Errorf("Expected GH_AW_SECRET_DETECTORS in redaction step, got:\n%s", step)
+	}
+
+	yaml.Reset()
+	compiler.generateSecretRedactionStep(&yaml, "${{ secrets.API_KEY }}", &WorkflowData{})
+	if strings.Contains(yaml.String(), "GH_AW_SECRET_DETECTORS") {
+		t.Error("Expected no GH_AW_SECRET_DETECTORS when the default detectors apply")
+	}
+}
+
 func TestValidateSecretReferences(t *testing.T) {
 	tests := []struct {
 		name    string
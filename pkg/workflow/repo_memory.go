@@ -37,6 +37,16 @@ const (
 	maxRepoMemoryPatchSize = 102400
 )
 
+// Repo-memory storage modes
+const (
+	// repoMemoryModeFiles stores arbitrary files; the agent's copy of a file replaces the branch copy.
+	repoMemoryModeFiles = "files"
+	// repoMemoryModeJSONL stores append-only logs; new lines are appended to the branch copy of each .jsonl file.
+	repoMemoryModeJSONL = "jsonl"
+	// repoMemoryModeKV stores key/value maps; the keys of each .json object are merged into the branch copy.
+	repoMemoryModeKV = "kv"
+)
+
 // Pre-compiled regexes for performance (avoid recompilation in hot paths)
 var (
 	// branchPrefixValidPattern matches valid branch prefix characters (alphanumeric, hyphens, underscores)
@@ -61,6 +71,10 @@ type RepoMemoryEntry struct {
 	Description       string   `yaml:"description,omitempty"`        // optional description for this memory
 	CreateOrphan      bool     `yaml:"create-orphan,omitempty"`      // create orphaned branch if missing (default: true)
 	AllowedExtensions []string `yaml:"allowed-extensions,omitempty"` // allowed file extensions (default: [".json", ".jsonl", ".txt", ".md", ".csv"])
+	Mode              string   `yaml:"mode,omitempty"`               // storage mode: "files" (default), "jsonl" or "kv"
+	MaxCommits        int      `yaml:"max-commits,omitempty"`        // squash the branch history once it exceeds this many commits (0 keeps all history)
+	MaxTotalSize      int      `yaml:"max-total-size,omitempty"`     // maximum total size of the memory branch in bytes (0 means unlimited)
+	RetentionDays     int      `yaml:"retention-days,omitempty"`     // drop memory not updated within this many days (0 keeps everything)
 }
 
 // RepoMemoryToolConfig represents the configuration for repo-memory in tools
@@ -320,6 +334,10 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig, workflowID
 						}
 					}
 				}
+				// Parse storage mode, compaction and retention settings
+				if err := parseRepoMemoryGovernance(memoryMap, &entry); err != nil {
+					return nil, err
+				}
 				// Default to standard allowed extensions if not specified
 				if len(entry.AllowedExtensions) == 0 {
 					entry.AllowedExtensions = constants.DefaultAllowedMemoryExtensions
@@ -461,6 +479,10 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig, workflowID
 				}
 			}
 		}
+		// Parse storage mode, compaction and retention settings
+		if err := parseRepoMemoryGovernance(configMap, &entry); err != nil {
+			return nil, err
+		}
 		// Default to standard allowed extensions if not specified
 		if len(entry.AllowedExtensions) == 0 {
 			entry.AllowedExtensions = constants.DefaultAllowedMemoryExtensions
@@ -473,6 +495,54 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig, workflowID
 	return nil, nil
 }
 
+// parseRepoMemoryGovernance parses the storage mode and the history and size limits of a memory.
+// Structured modes restrict the allowed extensions to their file format unless extensions are set explicitly.
+func parseRepoMemoryGovernance(memoryMap map[string]any, entry *RepoMemoryEntry) error {
+	if mode, exists := memoryMap["mode"]; exists {
+		modeStr, _ := mode.(string)
+		switch modeStr {
+		case repoMemoryModeFiles, repoMemoryModeJSONL, repoMemoryModeKV:
+			entry.Mode = modeStr
+		default:
+			return fmt.Errorf("invalid repo-memory mode '%v' for memory '%s': must be one of %s, %s or %s", mode, entry.ID, repoMemoryModeFiles, repoMemoryModeJSONL, repoMemoryModeKV)
+		}
+	}
+
+	limits := []struct {
+		key      string
+		min, max int
+		value    *int
+	}{
+		{"max-commits", 1, 10000, &entry.MaxCommits},
+		{"max-total-size", 1, 1073741824, &entry.MaxTotalSize},
+		{"retention-days", 1, 3650, &entry.RetentionDays},
+	}
+	for _, limit := range limits {
+		raw, exists := memoryMap[limit.key]
+		if !exists {
+			continue
+		}
+		value, ok := parseIntValue(raw)
+		if !ok {
+			return fmt.Errorf("%s must be an integer, got %v", limit.key, raw)
+		}
+		if err := validateIntRange(value, limit.min, limit.max, limit.key); err != nil {
+			return err
+		}
+		*limit.value = value
+	}
+
+	if len(entry.AllowedExtensions) == 0 {
+		switch entry.Mode {
+		case repoMemoryModeJSONL:
+			entry.AllowedExtensions = []string{".jsonl"}
+		case repoMemoryModeKV:
+			entry.AllowedExtensions = []string{".json"}
+		}
+	}
+	return nil
+}
+
 // validateNoDuplicateMemoryIDs checks for duplicate memory IDs and returns an error if found
 func validateNoDuplicateMemoryIDs(memories []RepoMemoryEntry) error {
 	seen := make(map[string]bool)
@@ -730,6 +800,19 @@ func (c *Compiler) buildPushRepoMemoryJob(data *WorkflowData, threatDetectionEna
 			// Quote the value to prevent YAML alias interpretation of patterns like *.md
 			fmt.Fprintf(&step, "          FILE_GLOB_FILTER: \"%s\"\n", fileGlobFilter)
 		}
+		// Storage mode and governance limits are enforced by the script before pushing
+		if memory.Mode != "" && memory.Mode != repoMemoryModeFiles {
+			fmt.Fprintf(&step, "          MEMORY_MODE: %s\n", memory.Mode)
+		}
+		if memory.MaxCommits > 0 {
+			fmt.Fprintf(&step, "          MAX_COMMITS: %d\n", memory.MaxCommits)
+		}
+		if memory.MaxTotalSize > 0 {
+			fmt.Fprintf(&step, "          MAX_TOTAL_SIZE: %d\n", memory.MaxTotalSize)
+		}
+		if memory.RetentionDays > 0 {
+			fmt.Fprintf(&step, "          RETENTION_DAYS: %d\n", memory.RetentionDays)
+		}
 		step.WriteString("        with:\n")
 		step.WriteString("          script: |\n")
 
@@ -0,0 +1,134 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoMemoryGovernanceConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	t.Run("object notation", func(t *testing.T) {
+		toolsConfig, err := ParseToolsConfig(map[string]any{
+			"repo-memory": map[string]any{
+				"mode":           "jsonl",
+				"max-commits":    50,
+				"max-total-size": 1048576,
+				"retention-days": 30,
+			},
+		})
+		require.NoError(t, err)
+
+		config, err := compiler.extractRepoMemoryConfig(toolsConfig, "my-workflow")
+		require.NoError(t, err)
+		require.Len(t, config.Memories, 1)
+
+		memory := config.Memories[0]
+		assert.Equal(t, "jsonl", memory.Mode)
+		assert.Equal(t, 50, memory.MaxCommits)
+		assert.Equal(t, 1048576, memory.MaxTotalSize)
+		assert.Equal(t, 30, memory.RetentionDays)
+		assert.Equal(t, []string{".jsonl"}, memory.AllowedExtensions, "jsonl mode should only allow .jsonl files by default")
+	})
+
+	t.Run("array notation", func(t *testing.T) {
+		toolsConfig, err := ParseToolsConfig(map[string]any{
+			"repo-memory": []any{
+				map[string]any{"id": "state", "mode": "kv"},
+				map[string]any{"id": "notes", "mode": "kv", "allowed-extensions": []any{".json", ".md"}},
+				map[string]any{"id": "files"},
+			},
+		})
+		require.NoError(t, err)
+
+		config, err := compiler.extractRepoMemoryConfig(toolsConfig, "my-workflow")
+		require.NoError(t, err)
+		require.Len(t, config.Memories, 3)
+
+		assert.Equal(t, []string{".json"}, config.Memories[0].AllowedExtensions, "kv mode should only allow .json files by default")
+		assert.Equal(t, []string{".json", ".md"}, config.Memories[1].AllowedExtensions, "explicit extensions should be kept")
+		assert.Empty(t, config.Memories[2].Mode)
+		assert.Zero(t, config.Memories[2].MaxCommits)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			memory   map[string]any
+			errorMsg string
+		}{
+			{"unknown mode", map[string]any{"mode": "sqlite"}, "invalid repo-memory mode"},
+			{"zero max-commits", map[string]any{"max-commits": 0}, "max-commits must be between 1 and 10000"},
+			{"negative max-total-size", map[string]any{"max-total-size": -1}, "max-total-size must be between"},
+			{"retention-days too large", map[string]any{"retention-days": 5000}, "retention-days must be between 1 and 3650"},
+			{"non-integer retention-days", map[string]any{"retention-days": "30"}, "retention-days must be an integer"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				toolsConfig, err := ParseToolsConfig(map[string]any{"repo-memory": tt.memory})
+				require.NoError(t, err)
+
+				_, err = compiler.extractRepoMemoryConfig(toolsConfig, "my-workflow")
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			})
+		}
+	})
+}
+
+func TestRepoMemoryGovernancePushJob(t *testing.T) {
+	compiler := NewCompiler()
+	data := &WorkflowData{
+		RepoMemoryConfig: &RepoMemoryConfig{
+			Memories: []RepoMemoryEntry{
+				{ID: "log", BranchName: "memory/log", Mode: "jsonl", MaxCommits: 20, MaxTotalSize: 65536, RetentionDays: 14},
+				{ID: "plain", BranchName: "memory/plain", Mode: "files"},
+			},
+		},
+	}
+
+	job, err := compiler.buildPushRepoMemoryJob(data, false)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	steps := strings.Join(job.Steps, "")
+	logStep := steps[strings.Index(steps, "Push repo-memory changes (log)"):strings.Index(steps, "Push repo-memory changes (plain)")]
+	assert.Contains(t, logStep, "MEMORY_MODE: jsonl")
+	assert.Contains(t, logStep, "MAX_COMMITS: 20")
+	assert.Contains(t, logStep, "MAX_TOTAL_SIZE: 65536")
+	assert.Contains(t, logStep, "RETENTION_DAYS: 14")
+
+	plainStep := steps[strings.Index(steps, "Push repo-memory changes (plain)"):]
+	assert.NotContains(t, plainStep, "MEMORY_MODE", "files mode is the default and should not be emitted")
+	assert.NotContains(t, plainStep, "MAX_COMMITS")
+	assert.NotContains(t, plainStep, "RETENTION_DAYS")
+}
+
+func TestRepoMemoryGovernancePrompt(t *testing.T) {
+	section := buildRepoMemoryPromptSection(&RepoMemoryConfig{
+		Memories: []RepoMemoryEntry{
+			{ID: "default", BranchName: "memory/default", Mode: "jsonl", RetentionDays: 7, MaxTotalSize: 4096},
+		},
+	})
+	require.NotNil(t, section)
+
+	constraints := section.EnvVars["GH_AW_MEMORY_CONSTRAINTS"]
+	assert.Contains(t, constraints, "**Storage Mode**: Append-only log")
+	assert.Contains(t, constraints, "Entries with a `timestamp` older than 7 days are removed")
+	assert.Contains(t, constraints, "4096 bytes (4 KB) for the whole memory; the oldest entries are dropped first")
+
+	section = buildRepoMemoryPromptSection(&RepoMemoryConfig{
+		Memories: []RepoMemoryEntry{
+			{ID: "state", BranchName: "memory/state", Mode: "kv"},
+			{ID: "notes", BranchName: "memory/notes"},
+		},
+	})
+	require.NotNil(t, section)
+	assert.Contains(t, section.EnvVars["GH_AW_MEMORY_LIST"], "(branch: `memory/state`, mode: `kv`)")
+	assert.Contains(t, section.EnvVars["GH_AW_MEMORY_LIST"], "(branch: `memory/notes`)")
+}
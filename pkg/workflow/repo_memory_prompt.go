@@ -40,7 +40,7 @@ func buildRepoMemoryPromptSection(config *RepoMemoryConfig) *PromptSection {
 		// The value is either "\n" (blank line only) or "\n\n**Constraints:**\n...\n"
 		// so that the template line __GH_AW_MEMORY_CONSTRAINTS__\nExamples... renders correctly.
 		constraintsText := "\n"
-		if len(memory.FileGlob) > 0 || memory.MaxFileSize > 0 || memory.MaxFileCount > 0 || memory.MaxPatchSize > 0 {
+		if len(memory.FileGlob) > 0 || memory.MaxFileSize > 0 || memory.MaxFileCount > 0 || memory.MaxPatchSize > 0 || memory.MaxTotalSize > 0 || memory.RetentionDays > 0 {
 			var constraints strings.Builder
 			constraints.WriteString("\n\n**Constraints:**\n")
 			if len(memory.FileGlob) > 0 {
@@ -55,6 +55,7 @@ func buildRepoMemoryPromptSection(config *RepoMemoryConfig) *PromptSection {
 			if memory.MaxPatchSize > 0 {
 				fmt.Fprintf(&constraints, "- **Max Patch Size**: %d bytes (%d KB) total per push (max: %d KB)\n", memory.MaxPatchSize, memory.MaxPatchSize/1024, maxRepoMemoryPatchSize/1024)
 			}
+			writeRepoMemoryGovernanceConstraints(&constraints, memory)
 			constraintsText = constraints.String()
 		}
 
@@ -86,6 +87,9 @@ func buildRepoMemoryPromptSection(config *RepoMemoryConfig) *PromptSection {
 		if memory.TargetRepo != "" {
 			fmt.Fprintf(&memoryList, " in `%s`", memory.TargetRepo)
 		}
+		if memory.Mode != "" && memory.Mode != repoMemoryModeFiles {
+			fmt.Fprintf(&memoryList, ", mode: `%s`", memory.Mode)
+		}
 		memoryList.WriteString(")\n")
 	}
 
@@ -134,3 +138,28 @@ func buildRepoMemoryPromptSection(config *RepoMemoryConfig) *PromptSection {
 		},
 	}
 }
+
+// writeRepoMemoryGovernanceConstraints describes the storage mode and the retention and size
+// limits of a memory, which change what is kept when the memory is pushed
+func writeRepoMemoryGovernanceConstraints(constraints *strings.Builder, memory RepoMemoryEntry) {
+	switch memory.Mode {
+	case repoMemoryModeJSONL:
+		constraints.WriteString("- **Storage Mode**: Append-only log. Add one JSON object per line to `.jsonl` files; existing lines cannot be changed or removed\n")
+	case repoMemoryModeKV:
+		constraints.WriteString("- **Storage Mode**: Key/value. Each `.json` file holds a JSON object whose keys are merged into the stored copy; set a key to `null` to delete it\n")
+	}
+	if memory.RetentionDays > 0 {
+		if memory.Mode == repoMemoryModeJSONL {
+			fmt.Fprintf(constraints, "- **Retention**: Entries with a `timestamp` older than %d days are removed\n", memory.RetentionDays)
+		} else {
+			fmt.Fprintf(constraints, "- **Retention**: Files not updated within %d days are removed\n", memory.RetentionDays)
+		}
+	}
+	if memory.MaxTotalSize > 0 {
+		fmt.Fprintf(constraints, "- **Max Total Size**: %d bytes (%d KB) for the whole memory", memory.MaxTotalSize, memory.MaxTotalSize/1024)
+		if memory.Mode == repoMemoryModeJSONL {
+			constraints.WriteString("; the oldest entries are dropped first")
+		}
+		constraints.WriteString("\n")
+	}
+}
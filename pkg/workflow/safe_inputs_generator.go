@@ -70,14 +70,7 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 
 		for _, paramName := range inputNames {
 			param := toolConfig.Inputs[paramName]
-			propDef := map[string]any{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				propDef["default"] = param.Default
-			}
-			props[paramName] = propDef
+			props[paramName] = param.JSONSchema()
 			if param.Required {
 				required = append(required, paramName)
 			}
@@ -40,10 +40,20 @@ type SafeInputToolConfig struct {
 
 // SafeInputParam holds the configuration for a tool input parameter
 type SafeInputParam struct {
-	Type        string // JSON schema type (string, number, boolean, array, object)
-	Description string // Description of the parameter
-	Required    bool   // Whether the parameter is required
-	Default     any    // Default value
+	Type        string                     // JSON schema type (string, number, integer, boolean, array, object)
+	Description string                     // Description of the parameter
+	Required    bool                       // Whether the parameter is required
+	Default     any                        // Default value
+	Enum        []any                      // Allowed values
+	Pattern     string                     // Regular expression that string values must match
+	Minimum     *float64                   // Minimum numeric value (inclusive)
+	Maximum     *float64                   // Maximum numeric value (inclusive)
+	MinLength   *int                       // Minimum string length
+	MaxLength   *int                       // Maximum string length
+	MinItems    *int                       // Minimum number of array items
+	MaxItems    *int                       // Maximum number of array items
+	Items       *SafeInputParam            // Schema of array items
+	Properties  map[string]*SafeInputParam // Schema of nested object properties
 }
 
 // SafeInputsMode constants define the available transport modes
@@ -72,6 +82,88 @@ func IsSafeInputsEnabled(safeInputs *SafeInputsConfig, workflowData *WorkflowDat
 	return HasSafeInputs(safeInputs)
 }
 
+// parseSafeInputParams parses a map of input parameter definitions, skipping malformed entries
+func parseSafeInputParams(inputsMap map[string]any) map[string]*SafeInputParam {
+	params := make(map[string]*SafeInputParam, len(inputsMap))
+	for paramName, paramValue := range inputsMap {
+		if paramMap, ok := paramValue.(map[string]any); ok {
+			params[paramName] = parseSafeInputParam(paramMap)
+		}
+	}
+	return params
+}
+
+// parseSafeInputParam parses a single input parameter definition.
+// Array items and object properties are parsed recursively.
+func parseSafeInputParam(paramMap map[string]any) *SafeInputParam {
+	param := &SafeInputParam{
+		Type: "string", // default type
+	}
+
+	if t, ok := paramMap["type"].(string); ok {
+		param.Type = t
+	}
+	if desc, ok := paramMap["description"].(string); ok {
+		param.Description = desc
+	}
+	if req, ok := paramMap["required"].(bool); ok {
+		param.Required = req
+	}
+	if def, exists := paramMap["default"]; exists {
+		param.Default = def
+	}
+	if enum, ok := paramMap["enum"].([]any); ok {
+		param.Enum = enum
+	}
+	if pattern, ok := paramMap["pattern"].(string); ok {
+		param.Pattern = pattern
+	}
+
+	param.Minimum = parseSafeInputNumber(paramMap["minimum"])
+	param.Maximum = parseSafeInputNumber(paramMap["maximum"])
+	param.MinLength = parseSafeInputCount(paramMap["min-length"])
+	param.MaxLength = parseSafeInputCount(paramMap["max-length"])
+	param.MinItems = parseSafeInputCount(paramMap["min-items"])
+	param.MaxItems = parseSafeInputCount(paramMap["max-items"])
+
+	if items, ok := paramMap["items"].(map[string]any); ok {
+		param.Items = parseSafeInputParam(items)
+	}
+	if props, ok := paramMap["properties"].(map[string]any); ok {
+		param.Properties = parseSafeInputParams(props)
+	}
+
+	return param
+}
+
+// parseSafeInputNumber converts a YAML or JSON number into a float64 pointer
+func parseSafeInputNumber(value any) *float64 {
+	var f float64
+	switch v := value.(type) {
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case uint64:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return nil
+	}
+	return &f
+}
+
+// parseSafeInputCount converts a YAML or JSON number into a non-negative int pointer
+func parseSafeInputCount(value any) *int {
+	f := parseSafeInputNumber(value)
+	if f == nil || *f < 0 || *f > math.MaxInt32 {
+		return nil
+	}
+	n := int(*f)
+	return &n
+}
+
 // parseSafeInputsMap parses safe-inputs configuration from a map.
 // This is the shared implementation used by both ParseSafeInputs and extractSafeInputsConfig.
 // Returns the config and a boolean indicating whether any tools were found.
@@ -112,37 +204,7 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 		// Parse inputs (optional)
 		if inputs, exists := toolMap["inputs"]; exists {
 			if inputsMap, ok := inputs.(map[string]any); ok {
-				for paramName, paramValue := range inputsMap {
-					if paramMap, ok := paramValue.(map[string]any); ok {
-						param := &SafeInputParam{
-							Type: "string", // default type
-						}
-
-						if t, exists := paramMap["type"]; exists {
-							if tStr, ok := t.(string); ok {
-								param.Type = tStr
-							}
-						}
-
-						if desc, exists := paramMap["description"]; exists {
-							if descStr, ok := desc.(string); ok {
-								param.Description = descStr
-							}
-						}
-
-						if req, exists := paramMap["required"]; exists {
-							if reqBool, ok := req.(bool); ok {
-								param.Required = reqBool
-							}
-						}
-
-						if def, exists := paramMap["default"]; exists {
-							param.Default = def
-						}
-
-						toolConfig.Inputs[paramName] = param
-					}
-				}
+				toolConfig.Inputs = parseSafeInputParams(inputsMap)
 			}
 		}
 
@@ -308,32 +370,7 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 			// Parse inputs
 			if inputs, exists := toolMap["inputs"]; exists {
 				if inputsMap, ok := inputs.(map[string]any); ok {
-					for paramName, paramValue := range inputsMap {
-						if paramMap, ok := paramValue.(map[string]any); ok {
-							param := &SafeInputParam{
-								Type: "string",
-							}
-							if t, exists := paramMap["type"]; exists {
-								if tStr, ok := t.(string); ok {
-									param.Type = tStr
-								}
-							}
-							if desc, exists := paramMap["description"]; exists {
-								if descStr, ok := desc.(string); ok {
-									param.Description = descStr
-								}
-							}
-							if req, exists := paramMap["required"]; exists {
-								if reqBool, ok := req.(bool); ok {
-									param.Required = reqBool
-								}
-							}
-							if def, exists := paramMap["default"]; exists {
-								param.Default = def
-							}
-							toolConfig.Inputs[paramName] = param
-						}
-					}
+					toolConfig.Inputs = parseSafeInputParams(inputsMap)
 				}
 			}
 
@@ -0,0 +1,329 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeInputsValidationLog = logger.New("workflow:safe_inputs_validation")
+
+// safeInputParamTypes lists the JSON schema types accepted for safe-input parameters
+var safeInputParamTypes = []string{"string", "number", "integer", "boolean", "array", "object"}
+
+// JSONSchema renders the parameter as a JSON schema property definition.
+// Constraints are emitted with their JSON schema names so that MCP clients see the full contract.
+func (p *SafeInputParam) JSONSchema() map[string]any {
+	schema := map[string]any{
+		"type":        p.Type,
+		"description": p.Description,
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.MinItems != nil {
+		schema["minItems"] = *p.MinItems
+	}
+	if p.MaxItems != nil {
+		schema["maxItems"] = *p.MaxItems
+	}
+	if p.Items != nil {
+		schema["items"] = p.Items.JSONSchema()
+	}
+	if len(p.Properties) > 0 {
+		props := make(map[string]any, len(p.Properties))
+		var required []string
+		for name, prop := range p.Properties {
+			props[name] = prop.JSONSchema()
+			if prop.Required {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = props
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	return schema
+}
+
+// validateSafeInputsConfig checks tool parameter definitions at compile time so that
+// invalid constraints are reported before the workflow runs
+func validateSafeInputsConfig(config *SafeInputsConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	toolNames := make([]string, 0, len(config.Tools))
+	for name := range config.Tools {
+		toolNames = append(toolNames, name)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		tool := config.Tools[toolName]
+		for _, paramName := range sortedSafeInputParamNames(tool.Inputs) {
+			if err := validateSafeInputParamDefinition(paramName, tool.Inputs[paramName]); err != nil {
+				return fmt.Errorf("safe-inputs tool '%s': %w", toolName, err)
+			}
+		}
+	}
+
+	safeInputsValidationLog.Printf("Validated parameter definitions for %d safe-input tools", len(toolNames))
+	return nil
+}
+
+// validateSafeInputParamDefinition checks a single parameter definition and its nested schemas
+func validateSafeInputParamDefinition(path string, param *SafeInputParam) error {
+	if !slices.Contains(safeInputParamTypes, param.Type) {
+		return fmt.Errorf("input '%s' has unsupported type '%s'. Valid types: %s", path, param.Type, strings.Join(safeInputParamTypes, ", "))
+	}
+
+	if param.Pattern != "" {
+		if param.Type != "string" {
+			return fmt.Errorf("input '%s': pattern is only supported for string inputs", path)
+		}
+		if _, err := regexp.Compile(param.Pattern); err != nil {
+			return fmt.Errorf("input '%s' has invalid pattern: %w", path, err)
+		}
+		if err := checkPortableSafeInputPattern(param.Pattern); err != nil {
+			return fmt.Errorf("input '%s' has unsupported pattern: %w", path, err)
+		}
+	}
+	if (param.Minimum != nil || param.Maximum != nil) && param.Type != "number" && param.Type != "integer" {
+		return fmt.Errorf("input '%s': minimum and maximum are only supported for number and integer inputs", path)
+	}
+	if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+		return fmt.Errorf("input '%s': minimum (%v) is greater than maximum (%v)", path, *param.Minimum, *param.Maximum)
+	}
+	if (param.MinLength != nil || param.MaxLength != nil) && param.Type != "string" {
+		return fmt.Errorf("input '%s': min-length and max-length are only supported for string inputs", path)
+	}
+	if param.MinLength != nil && param.MaxLength != nil && *param.MinLength > *param.MaxLength {
+		return fmt.Errorf("input '%s': min-length (%d) is greater than max-length (%d)", path, *param.MinLength, *param.MaxLength)
+	}
+	if (param.MinItems != nil || param.MaxItems != nil || param.Items != nil) && param.Type != "array" {
+		return fmt.Errorf("input '%s': items, min-items and max-items are only supported for array inputs", path)
+	}
+	if param.MinItems != nil && param.MaxItems != nil && *param.MinItems > *param.MaxItems {
+		return fmt.Errorf("input '%s': min-items (%d) is greater than max-items (%d)", path, *param.MinItems, *param.MaxItems)
+	}
+	if len(param.Properties) > 0 && param.Type != "object" {
+		return fmt.Errorf("input '%s': properties are only supported for object inputs", path)
+	}
+
+	if param.Items != nil {
+		if err := validateSafeInputParamDefinition(path+"[]", param.Items); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedSafeInputParamNames(param.Properties) {
+		if err := validateSafeInputParamDefinition(path+"."+name, param.Properties[name]); err != nil {
+			return err
+		}
+	}
+
+	// Enum values and defaults must themselves satisfy the declared schema
+	for _, value := range param.Enum {
+		if errs := validateSafeInputValue(path, param, value, false); len(errs) > 0 {
+			return fmt.Errorf("input '%s' has invalid enum value %v: %s", path, value, errs[0])
+		}
+	}
+	if param.Default != nil {
+		if errs := validateSafeInputValue(path, param, param.Default, true); len(errs) > 0 {
+			return fmt.Errorf("input '%s' has invalid default: %s", path, errs[0])
+		}
+	}
+
+	return nil
+}
+
+// checkPortableSafeInputPattern rejects regular expression syntax that Go accepts but
+// JavaScript does not. Patterns are enforced both by the Go validation and by the
+// JavaScript MCP server, so they must mean the same thing in both engines.
+func checkPortableSafeInputPattern(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			if strings.IndexByte("AzQEpPC", pattern[i]) >= 0 {
+				return fmt.Errorf("escape \\%c is not supported in JavaScript", pattern[i])
+			}
+		case strings.HasPrefix(pattern[i:], "(?P<"):
+			return errors.New("(?P<name>...) groups are not supported in JavaScript, use (?<name>...)")
+		case strings.HasPrefix(pattern[i:], "(?") && !strings.HasPrefix(pattern[i:], "(?:") && !strings.HasPrefix(pattern[i:], "(?<"):
+			return errors.New("inline flags such as (?i) are not supported in JavaScript")
+		case strings.HasPrefix(pattern[i:], "[[:"):
+			return errors.New("POSIX character classes such as [[:alpha:]] are not supported in JavaScript")
+		}
+	}
+	return nil
+}
+
+// ValidateSafeInputArguments validates tool call arguments against the tool's parameter
+// definitions. All violations are reported together so callers can fix them in one pass.
+func ValidateSafeInputArguments(tool *SafeInputToolConfig, args map[string]any) error {
+	var errs []string
+	for _, name := range sortedSafeInputParamNames(tool.Inputs) {
+		param := tool.Inputs[name]
+		value, exists := args[name]
+		if !exists || value == nil {
+			if param.Required {
+				errs = append(errs, fmt.Sprintf("missing required input '%s'", name))
+			}
+			continue
+		}
+		errs = append(errs, validateSafeInputValue(name, param, value, true)...)
+	}
+
+	if len(errs) > 0 {
+		safeInputsValidationLog.Printf("Arguments for tool %s failed validation: %d errors", tool.Name, len(errs))
+		return fmt.Errorf("invalid arguments for tool '%s':\n  - %s", tool.Name, strings.Join(errs, "\n  - "))
+	}
+	return nil
+}
+
+// validateSafeInputValue checks a value against a parameter schema and returns all violations.
+// checkEnum is false when validating enum entries themselves.
+func validateSafeInputValue(path string, param *SafeInputParam, value any, checkEnum bool) []string {
+	if err := checkSafeInputType(param.Type, value); err != nil {
+		return []string{fmt.Sprintf("input '%s' %s", path, err)}
+	}
+
+	var errs []string
+	if checkEnum && len(param.Enum) > 0 {
+		if !slices.ContainsFunc(param.Enum, func(allowed any) bool { return safeInputValuesEqual(allowed, value) }) {
+			errs = append(errs, fmt.Sprintf("input '%s' must be one of %v, got %v", path, param.Enum, value))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if param.MinLength != nil && length < *param.MinLength {
+			errs = append(errs, fmt.Sprintf("input '%s' must be at least %d characters", path, *param.MinLength))
+		}
+		if param.MaxLength != nil && length > *param.MaxLength {
+			errs = append(errs, fmt.Sprintf("input '%s' must be at most %d characters", path, *param.MaxLength))
+		}
+		if param.Pattern != "" {
+			// Invalid patterns are rejected at compile time
+			if re, err := regexp.Compile(param.Pattern); err == nil && !re.MatchString(v) {
+				errs = append(errs, fmt.Sprintf("input '%s' must match pattern %s", path, param.Pattern))
+			}
+		}
+	case []any:
+		if param.MinItems != nil && len(v) < *param.MinItems {
+			errs = append(errs, fmt.Sprintf("input '%s' must have at least %d items", path, *param.MinItems))
+		}
+		if param.MaxItems != nil && len(v) > *param.MaxItems {
+			errs = append(errs, fmt.Sprintf("input '%s' must have at most %d items", path, *param.MaxItems))
+		}
+		if param.Items != nil {
+			for i, item := range v {
+				errs = append(errs, validateSafeInputValue(fmt.Sprintf("%s[%d]", path, i), param.Items, item, true)...)
+			}
+		}
+	case map[string]any:
+		for _, name := range sortedSafeInputParamNames(param.Properties) {
+			prop := param.Properties[name]
+			propValue, exists := v[name]
+			if !exists || propValue == nil {
+				if prop.Required {
+					errs = append(errs, fmt.Sprintf("missing required input '%s.%s'", path, name))
+				}
+				continue
+			}
+			errs = append(errs, validateSafeInputValue(path+"."+name, prop, propValue, true)...)
+		}
+	default:
+		if n, ok := safeInputNumber(value); ok {
+			if param.Minimum != nil && n < *param.Minimum {
+				errs = append(errs, fmt.Sprintf("input '%s' must be >= %v", path, *param.Minimum))
+			}
+			if param.Maximum != nil && n > *param.Maximum {
+				errs = append(errs, fmt.Sprintf("input '%s' must be <= %v", path, *param.Maximum))
+			}
+		}
+	}
+	return errs
+}
+
+// checkSafeInputType verifies that a decoded JSON or YAML value has the declared type
+func checkSafeInputType(paramType string, value any) error {
+	ok := false
+	switch paramType {
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = safeInputNumber(value)
+	case "integer":
+		n, isNumber := safeInputNumber(value)
+		ok = isNumber && n == math.Trunc(n)
+	case "array":
+		_, ok = value.([]any)
+	case "object":
+		_, ok = value.(map[string]any)
+	default:
+		return errors.New("has an unsupported type " + paramType)
+	}
+	if !ok {
+		return fmt.Errorf("must be of type %s, got %T", paramType, value)
+	}
+	return nil
+}
+
+// safeInputNumber converts numeric values decoded from JSON or YAML to float64
+func safeInputNumber(value any) (float64, bool) {
+	if n := parseSafeInputNumber(value); n != nil {
+		return *n, true
+	}
+	return 0, false
+}
+
+// safeInputValuesEqual compares values, treating numbers of different Go types as equal
+func safeInputValuesEqual(a, b any) bool {
+	if x, ok := safeInputNumber(a); ok {
+		y, ok := safeInputNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func sortedSafeInputParamNames(params map[string]*SafeInputParam) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,255 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typedSafeInputTool(t *testing.T) *SafeInputToolConfig {
+	t.Helper()
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"deploy": map[string]any{
+				"description": "Deploy a service",
+				"script":      "return inputs;",
+				"inputs": map[string]any{
+					"env": map[string]any{
+						"type":     "string",
+						"enum":     []any{"staging", "production"},
+						"required": true,
+					},
+					"ref": map[string]any{
+						"type":       "string",
+						"pattern":    `^v\d+\.\d+$`,
+						"max-length": uint64(8),
+					},
+					"replicas": map[string]any{
+						"type":    "integer",
+						"minimum": uint64(1),
+						"maximum": uint64(5),
+						"default": uint64(2),
+					},
+					"regions": map[string]any{
+						"type":      "array",
+						"min-items": uint64(1),
+						"items":     map[string]any{"type": "string", "min-length": uint64(2)},
+					},
+					"owner": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"team":  map[string]any{"type": "string", "required": true},
+							"pager": map[string]any{"type": "boolean"},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NotNil(t, config, "config should parse")
+	require.Contains(t, config.Tools, "deploy")
+	return config.Tools["deploy"]
+}
+
+func TestParseSafeInputParamConstraints(t *testing.T) {
+	tool := typedSafeInputTool(t)
+
+	env := tool.Inputs["env"]
+	assert.Equal(t, []any{"staging", "production"}, env.Enum)
+	assert.True(t, env.Required)
+
+	ref := tool.Inputs["ref"]
+	assert.Equal(t, `^v\d+\.\d+$`, ref.Pattern)
+	require.NotNil(t, ref.MaxLength)
+	assert.Equal(t, 8, *ref.MaxLength)
+
+	replicas := tool.Inputs["replicas"]
+	require.NotNil(t, replicas.Minimum)
+	require.NotNil(t, replicas.Maximum)
+	assert.InDelta(t, 1.0, *replicas.Minimum, 0)
+	assert.InDelta(t, 5.0, *replicas.Maximum, 0)
+
+	regions := tool.Inputs["regions"]
+	require.NotNil(t, regions.Items, "array items should be parsed")
+	assert.Equal(t, "string", regions.Items.Type)
+	require.NotNil(t, regions.Items.MinLength)
+
+	owner := tool.Inputs["owner"]
+	require.Contains(t, owner.Properties, "team")
+	assert.True(t, owner.Properties["team"].Required)
+}
+
+func TestSafeInputParamJSONSchema(t *testing.T) {
+	tool := typedSafeInputTool(t)
+
+	assert.Equal(t, []any{"staging", "production"}, tool.Inputs["env"].JSONSchema()["enum"])
+	assert.Equal(t, 8, tool.Inputs["ref"].JSONSchema()["maxLength"])
+
+	regions := tool.Inputs["regions"].JSONSchema()
+	assert.Equal(t, 1, regions["minItems"])
+	items, ok := regions["items"].(map[string]any)
+	require.True(t, ok, "items should be a nested schema")
+	assert.Equal(t, 2, items["minLength"])
+
+	owner := tool.Inputs["owner"].JSONSchema()
+	assert.Equal(t, []string{"team"}, owner["required"])
+	assert.Contains(t, owner["properties"], "pager")
+}
+
+func TestValidateSafeInputArguments(t *testing.T) {
+	tool := typedSafeInputTool(t)
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		wantErrs []string
+	}{
+		{
+			name: "valid arguments",
+			args: map[string]any{
+				"env":      "staging",
+				"ref":      "v1.2",
+				"replicas": float64(3),
+				"regions":  []any{"eu", "us"},
+				"owner":    map[string]any{"team": "platform", "pager": true},
+			},
+		},
+		{
+			name:     "missing required input",
+			args:     map[string]any{},
+			wantErrs: []string{"missing required input 'env'"},
+		},
+		{
+			name:     "enum violation",
+			args:     map[string]any{"env": "qa"},
+			wantErrs: []string{"input 'env' must be one of"},
+		},
+		{
+			name:     "pattern and length violations",
+			args:     map[string]any{"env": "staging", "ref": "main-branch"},
+			wantErrs: []string{"must be at most 8 characters", "must match pattern"},
+		},
+		{
+			name:     "range and integer violations",
+			args:     map[string]any{"env": "staging", "replicas": float64(9)},
+			wantErrs: []string{"input 'replicas' must be <= 5"},
+		},
+		{
+			name:     "non-integer number",
+			args:     map[string]any{"env": "staging", "replicas": 1.5},
+			wantErrs: []string{"input 'replicas' must be of type integer"},
+		},
+		{
+			name:     "array item violations",
+			args:     map[string]any{"env": "staging", "regions": []any{"e"}},
+			wantErrs: []string{"input 'regions[0]' must be at least 2 characters"},
+		},
+		{
+			name:     "nested object violations",
+			args:     map[string]any{"env": "staging", "owner": map[string]any{"pager": "yes"}},
+			wantErrs: []string{"input 'owner.pager' must be of type boolean", "missing required input 'owner.team'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSafeInputArguments(tool, tt.args)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err, "arguments should be valid")
+				return
+			}
+			require.Error(t, err, "arguments should be rejected")
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidateSafeInputsConfig(t *testing.T) {
+	ptr := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name    string
+		param   *SafeInputParam
+		wantErr string
+	}{
+		{
+			name:  "valid constraints",
+			param: &SafeInputParam{Type: "integer", Minimum: ptr(1), Maximum: ptr(3), Default: 2},
+		},
+		{
+			name:    "unsupported type",
+			param:   &SafeInputParam{Type: "date"},
+			wantErr: "unsupported type 'date'",
+		},
+		{
+			name:    "invalid pattern",
+			param:   &SafeInputParam{Type: "string", Pattern: "("},
+			wantErr: "invalid pattern",
+		},
+		{
+			name:    "inline flags",
+			param:   &SafeInputParam{Type: "string", Pattern: "(?i)^main$"},
+			wantErr: "inline flags such as (?i) are not supported in JavaScript",
+		},
+		{
+			name:    "Go-only escape",
+			param:   &SafeInputParam{Type: "string", Pattern: `\A\pL+\z`},
+			wantErr: "escape \\A is not supported in JavaScript",
+		},
+		{
+			name:    "POSIX class",
+			param:   &SafeInputParam{Type: "string", Pattern: "^[[:alpha:]]+$"},
+			wantErr: "POSIX character classes",
+		},
+		{
+			name:  "portable pattern",
+			param: &SafeInputParam{Type: "string", Pattern: `^(?:v|release-)(?<major>\d+)\.[a-z\-]*\\$`},
+		},
+		{
+			name:    "pattern on number",
+			param:   &SafeInputParam{Type: "number", Pattern: "^1$"},
+			wantErr: "pattern is only supported for string inputs",
+		},
+		{
+			name:    "minimum greater than maximum",
+			param:   &SafeInputParam{Type: "number", Minimum: ptr(5), Maximum: ptr(1)},
+			wantErr: "minimum (5) is greater than maximum (1)",
+		},
+		{
+			name:    "enum value with wrong type",
+			param:   &SafeInputParam{Type: "string", Enum: []any{"a", 1}},
+			wantErr: "invalid enum value 1",
+		},
+		{
+			name:    "default outside enum",
+			param:   &SafeInputParam{Type: "string", Enum: []any{"a", "b"}, Default: "c"},
+			wantErr: "invalid default",
+		},
+		{
+			name:    "invalid nested item",
+			param:   &SafeInputParam{Type: "array", Items: &SafeInputParam{Type: "string", Minimum: ptr(1)}},
+			wantErr: "input 'x[]': minimum and maximum are only supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &SafeInputsConfig{Tools: map[string]*SafeInputToolConfig{
+				"tool": {Name: "tool", Inputs: map[string]*SafeInputParam{"x": tt.param}},
+			}}
+			err := validateSafeInputsConfig(config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "definition should be valid")
+				return
+			}
+			require.Error(t, err, "definition should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Contains(t, err.Error(), "safe-inputs tool 'tool'")
+		})
+	}
+}
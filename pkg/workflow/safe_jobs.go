@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"strings"
@@ -179,6 +180,14 @@ func (c *Compiler) buildSafeJobs(data *WorkflowData, threatDetectionEnabled bool
 		// Add any additional dependencies from the config
 		job.Needs = append(job.Needs, jobConfig.Needs...)
 
+		// Wait for the approval checklist and, when it lists actions, for a reviewer to approve
+		// the environment, like the safe_outputs job
+		approvalEnabled := c.isSafeOutputApprovalEnabled(data)
+		if approvalEnabled {
+			job.Needs = append(job.Needs, SafeOutputsReviewJobName)
+			job.Environment = buildSafeOutputApprovalEnvironment(data.SafeOutputs.Approval)
+		}
+
 		// Set runs-on
 		if jobConfig.RunsOn != nil {
 			if runsOnStr, ok := jobConfig.RunsOn.(string); ok {
@@ -217,6 +226,16 @@ func (c *Compiler) buildSafeJobs(data *WorkflowData, threatDetectionEnabled bool
 		// Build job steps
 		var steps []string
 
+		// The approval filter runs from the setup action scripts
+		if approvalEnabled {
+			setupActionRef := c.resolveActionReference("./actions/setup", data)
+			if setupActionRef == "" && !c.actionMode.IsScript() {
+				return nil, errors.New("setup action reference is required but could not be resolved")
+			}
+			steps = append(steps, c.generateCheckoutActionsFolder(data)...)
+			steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+		}
+
 		// Add step to download agent output artifact using shared helper
 		downloadSteps := buildArtifactDownloadSteps(ArtifactDownloadConfig{
 			ArtifactName: constants.AgentOutputArtifactName,
@@ -243,6 +262,11 @@ func (c *Compiler) buildSafeJobs(data *WorkflowData, threatDetectionEnabled bool
 			}
 		}
 
+		// Drop unapproved items so that the job only sees the actions a reviewer checked
+		if approvalEnabled {
+			steps = append(steps, buildApprovalFilterStep(data.SafeOutputs.Approval)...)
+		}
+
 		// Add custom steps from the job configuration
 		if len(jobConfig.Steps) > 0 {
 			for _, step := range jobConfig.Steps {
@@ -275,6 +299,13 @@ func (c *Compiler) buildSafeJobs(data *WorkflowData, threatDetectionEnabled bool
 			for perm, level := range jobConfig.Permissions {
 				perms.Set(PermissionScope(perm), PermissionLevel(level))
 			}
+			// The approval filter reads the checklist comment, and dev mode checks out the actions folder
+			if approvalEnabled {
+				perms.Merge(NewPermissionsFromMap(map[PermissionScope]PermissionLevel{PermissionIssues: PermissionRead}))
+				if len(c.generateCheckoutActionsFolder(data)) > 0 {
+					perms.Merge(NewPermissionsContentsRead())
+				}
+			}
 			job.Permissions = perms.RenderToYAML()
 		}
 
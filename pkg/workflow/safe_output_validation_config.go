@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"encoding/json"
+	"maps"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -373,6 +374,12 @@ var ValidationConfig = map[string]TypeValidationConfig{
 // If enabledTypes is empty or nil, returns all validation configs
 // If enabledTypes is provided, returns only configs for the specified types
 func GetValidationConfigJSON(enabledTypes []string) (string, error) {
+	return getValidationConfigJSONWithCustomTypes(enabledTypes, nil)
+}
+
+// getValidationConfigJSONWithCustomTypes is GetValidationConfigJSON extended with the rules of
+// custom safe-output types, which are derived from the workflow instead of ValidationConfig
+func getValidationConfigJSONWithCustomTypes(enabledTypes []string, customTypes map[string]TypeValidationConfig) (string, error) {
 	safeOutputValidationLog.Printf("Getting validation config JSON for %d types", len(enabledTypes))
 
 	configToMarshal := ValidationConfig
@@ -387,6 +394,10 @@ func GetValidationConfigJSON(enabledTypes []string) (string, error) {
 	} else {
 		safeOutputValidationLog.Print("Returning all validation configs")
 	}
+	if len(customTypes) > 0 {
+		configToMarshal = maps.Clone(configToMarshal)
+		maps.Copy(configToMarshal, customTypes)
+	}
 
 	data, err := json.MarshalIndent(configToMarshal, "", "  ")
 	if err != nil {
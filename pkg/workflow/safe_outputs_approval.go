@@ -0,0 +1,206 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var safeOutputsApprovalLog = logger.New("workflow:safe_outputs_approval")
+
+// SafeOutputsReviewJobName is the name of the job that posts the approval checklist
+const SafeOutputsReviewJobName = "safe_outputs_review"
+
+// SafeOutputApprovalConfig configures per-item human approval of safe outputs.
+// The review job posts a checklist of the proposed actions, and the jobs processing agent
+// output wait on the environment before executing only the items checked by a reviewer.
+type SafeOutputApprovalConfig struct {
+	Environment string   `yaml:"environment"`     // Environment with required reviewers gating execution
+	Types       []string `yaml:"types,omitempty"` // Safe output types requiring approval (empty = all types)
+
+	unsupportedFields []string // Fields other than environment and types, rejected by validation
+}
+
+// parseSafeOutputApprovalConfig parses safe-outputs.approval, which is either the
+// environment name or an object with environment and types
+func parseSafeOutputApprovalConfig(value any) *SafeOutputApprovalConfig {
+	switch v := value.(type) {
+	case string:
+		return &SafeOutputApprovalConfig{Environment: v}
+	case map[string]any:
+		config := &SafeOutputApprovalConfig{}
+		if env, ok := v["environment"].(string); ok {
+			config.Environment = env
+		}
+		if types, ok := v["types"].([]any); ok {
+			for _, t := range types {
+				if s, ok := t.(string); ok {
+					config.Types = append(config.Types, s)
+				}
+			}
+		}
+		for key := range v {
+			if key != "environment" && key != "types" {
+				config.unsupportedFields = append(config.unsupportedFields, key)
+			}
+		}
+		sort.Strings(config.unsupportedFields)
+		return config
+	}
+	return nil
+}
+
+// validateSafeOutputApproval checks that the approval environment is set and that
+// every gated type is configured in safe-outputs
+func validateSafeOutputApproval(safeOutputs *SafeOutputsConfig) error {
+	if safeOutputs == nil || safeOutputs.Approval == nil {
+		return nil
+	}
+	if len(safeOutputs.Approval.unsupportedFields) > 0 {
+		return fmt.Errorf("safe-outputs.approval.%s is not supported: actions are approved by checking them in the review comment and approving the environment; reaction and label approval are not available", safeOutputs.Approval.unsupportedFields[0])
+	}
+	if strings.TrimSpace(safeOutputs.Approval.Environment) == "" {
+		return errors.New("safe-outputs.approval requires an 'environment' with required reviewers")
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range GetEnabledSafeOutputToolNames(safeOutputs) {
+		enabled[stringutil.NormalizeSafeOutputIdentifier(name)] = true
+	}
+	for _, t := range safeOutputs.Approval.Types {
+		if !enabled[stringutil.NormalizeSafeOutputIdentifier(t)] {
+			return fmt.Errorf("safe-outputs.approval.types: '%s' is not a configured safe output", t)
+		}
+	}
+	return nil
+}
+
+// isSafeOutputApprovalEnabled returns true when safe outputs must be approved before execution.
+// Staged runs never execute actions, so they skip the approval queue.
+func (c *Compiler) isSafeOutputApprovalEnabled(data *WorkflowData) bool {
+	if data.SafeOutputs == nil || data.SafeOutputs.Approval == nil {
+		return false
+	}
+	return !c.trialMode && !data.SafeOutputs.Staged
+}
+
+// approvalTypesEnvValue returns the comma-separated list of gated types
+func approvalTypesEnvValue(config *SafeOutputApprovalConfig) string {
+	types := make([]string, 0, len(config.Types))
+	for _, t := range config.Types {
+		types = append(types, stringutil.NormalizeSafeOutputIdentifier(t))
+	}
+	return strings.Join(types, ",")
+}
+
+// buildSafeOutputsReviewJob creates the job that posts the approval checklist for the
+// proposed safe outputs. The safe_outputs job depends on it and reads the checklist back.
+func (c *Compiler) buildSafeOutputsReviewJob(data *WorkflowData, mainJobName string) (*Job, error) {
+	if !c.isSafeOutputApprovalEnabled(data) {
+		return nil, nil
+	}
+	safeOutputsApprovalLog.Printf("Building safe outputs review job for environment %s", data.SafeOutputs.Approval.Environment)
+
+	var steps []string
+
+	setupActionRef := c.resolveActionReference("./actions/setup", data)
+	if setupActionRef == "" && !c.actionMode.IsScript() {
+		return nil, errors.New("setup action reference is required but could not be resolved")
+	}
+
+	// For dev mode (local action path), checkout the actions folder first
+	steps = append(steps, c.generateCheckoutActionsFolder(data)...)
+	steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+	steps = append(steps, buildAgentOutputDownloadSteps()...)
+
+	// Need contents: read for dev mode checkout, issues and pull-requests: write for the checklist comment
+	var permissions *Permissions
+	if (c.actionMode.IsDev() || c.actionMode.IsScript()) && len(c.generateCheckoutActionsFolder(data)) > 0 {
+		permissions = NewPermissionsContentsRead()
+	} else {
+		permissions = NewPermissions()
+	}
+	permissions.Set(PermissionIssues, PermissionWrite)
+	permissions.Set(PermissionPullRequests, PermissionWrite)
+
+	if data.SafeOutputs.App != nil {
+		steps = append(steps, c.buildGitHubAppTokenMintStep(data.SafeOutputs.App, permissions)...)
+	}
+
+	steps = append(steps, "      - name: Request approval for safe outputs\n")
+	steps = append(steps, "        id: request_approval\n")
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_APPROVAL_TYPES: %q\n", approvalTypesEnvValue(data.SafeOutputs.Approval)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_APPROVAL_ENVIRONMENT: %q\n", data.SafeOutputs.Approval.Environment))
+	steps = append(steps, fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name))
+	steps = append(steps, "        with:\n")
+	c.addSafeOutputGitHubTokenForConfig(&steps, data, "")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("safe_output_approval_request.cjs"))
+
+	if data.SafeOutputs.App != nil {
+		steps = append(steps, c.buildGitHubAppTokenInvalidationStep()...)
+	}
+
+	job := &Job{
+		Name:           SafeOutputsReviewJobName,
+		If:             buildSafeOutputsJobCondition(data.SafeOutputs.ThreatDetection != nil).Render(),
+		RunsOn:         c.formatSafeOutputsRunsOn(data.SafeOutputs),
+		Permissions:    permissions.RenderToYAML(),
+		TimeoutMinutes: 5,
+		Steps:          steps,
+		Needs:          []string{mainJobName},
+		Outputs: map[string]string{
+			"comment_id":    "${{ steps.request_approval.outputs.comment_id }}",
+			"comment_url":   "${{ steps.request_approval.outputs.comment_url }}",
+			"pending_count": "${{ steps.request_approval.outputs.pending_count }}",
+		},
+	}
+	return job, nil
+}
+
+// buildSafeOutputApprovalEnvironment returns the environment of the safe_outputs job. The
+// environment only applies when the review job listed actions requiring approval; an empty
+// environment name runs the job without waiting for a reviewer.
+func buildSafeOutputApprovalEnvironment(config *SafeOutputApprovalConfig) string {
+	environment := "'" + strings.ReplaceAll(config.Environment, "'", "''") + "'"
+	return fmt.Sprintf("environment: ${{ needs.%s.outputs.pending_count != '0' && %s || '' }}", SafeOutputsReviewJobName, environment)
+}
+
+// buildApprovalFilterStep builds the step that removes unapproved items from the agent
+// output before any safe output is processed. It reads the checklist with the job token.
+func buildApprovalFilterStep(config *SafeOutputApprovalConfig) []string {
+	var steps []string
+	steps = append(steps, "      - name: Filter approved safe outputs\n")
+	steps = append(steps, "        id: approval_filter\n")
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_APPROVAL_COMMENT_ID: ${{ needs.%s.outputs.comment_id }}\n", SafeOutputsReviewJobName))
+	steps = append(steps, fmt.Sprintf("          GH_AW_APPROVAL_TYPES: %q\n", approvalTypesEnvValue(config)))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("safe_output_approval_filter.cjs"))
+	return steps
+}
+
+// buildSafeOutputsJobCondition builds the condition shared by the jobs processing agent output:
+// the agent job completed (not skipped) and detection passed (if enabled)
+func buildSafeOutputsJobCondition(threatDetectionEnabled bool) ConditionNode {
+	agentNotSkipped := BuildAnd(
+		&NotNode{Child: BuildFunctionCall("cancelled")},
+		BuildNotEquals(
+			BuildPropertyAccess(fmt.Sprintf("needs.%s.result", constants.AgentJobName)),
+			BuildStringLiteral("skipped"),
+		),
+	)
+	if threatDetectionEnabled {
+		return BuildAnd(agentNotSkipped, buildDetectionSuccessCondition())
+	}
+	return agentNotSkipped
+}
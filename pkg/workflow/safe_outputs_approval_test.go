@@ -0,0 +1,229 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeOutputApprovalConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected *SafeOutputApprovalConfig
+	}{
+		{
+			name:     "environment shorthand",
+			value:    "agent-review",
+			expected: &SafeOutputApprovalConfig{Environment: "agent-review"},
+		},
+		{
+			name: "object with types",
+			value: map[string]any{
+				"environment": "agent-review",
+				"types":       []any{"create-pull-request", "create-issue"},
+			},
+			expected: &SafeOutputApprovalConfig{Environment: "agent-review", Types: []string{"create-pull-request", "create-issue"}},
+		},
+		{
+			name: "unsupported approval trigger",
+			value: map[string]any{
+				"environment": "agent-review",
+				"reaction":    "+1",
+			},
+			expected: &SafeOutputApprovalConfig{Environment: "agent-review", unsupportedFields: []string{"reaction"}},
+		},
+		{
+			name:     "invalid value",
+			value:    true,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseSafeOutputApprovalConfig(tt.value))
+		})
+	}
+}
+
+func TestValidateSafeOutputApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "no approval",
+			config: &SafeOutputsConfig{},
+		},
+		{
+			name: "all types",
+			config: &SafeOutputsConfig{
+				CreateIssues: &CreateIssuesConfig{},
+				Approval:     &SafeOutputApprovalConfig{Environment: "agent-review"},
+			},
+		},
+		{
+			name: "configured type",
+			config: &SafeOutputsConfig{
+				CreateIssues: &CreateIssuesConfig{},
+				Approval:     &SafeOutputApprovalConfig{Environment: "agent-review", Types: []string{"create-issue"}},
+			},
+		},
+		{
+			name: "missing environment",
+			config: &SafeOutputsConfig{
+				CreateIssues: &CreateIssuesConfig{},
+				Approval:     &SafeOutputApprovalConfig{},
+			},
+			wantErr: "requires an 'environment'",
+		},
+		{
+			name: "label approval",
+			config: &SafeOutputsConfig{
+				CreateIssues: &CreateIssuesConfig{},
+				Approval:     &SafeOutputApprovalConfig{Environment: "agent-review", unsupportedFields: []string{"label"}},
+			},
+			wantErr: "safe-outputs.approval.label is not supported",
+		},
+		{
+			name: "type not configured",
+			config: &SafeOutputsConfig{
+				CreateIssues: &CreateIssuesConfig{},
+				Approval:     &SafeOutputApprovalConfig{Environment: "agent-review", Types: []string{"create-pull-request"}},
+			},
+			wantErr: "'create-pull-request' is not a configured safe output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeOutputApproval(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "approval config should be valid")
+				return
+			}
+			require.Error(t, err, "approval config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func compileApprovalTestWorkflow(t *testing.T, safeOutputs string) string {
+	t.Helper()
+	content := `---
+on: issues
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+` + safeOutputs + `
+---
+
+# Test Workflow
+
+Report results.`
+
+	tmpDir := testutil.TempDir(t, "safe-output-approval-test")
+	testFile := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "test.lock.yml"))
+	require.NoError(t, err)
+	return string(lockContent)
+}
+
+func TestCompileWorkflowWithSafeOutputApproval(t *testing.T) {
+	lock := compileApprovalTestWorkflow(t, `  create-issue:
+  add-comment:
+  approval:
+    environment: agent-review
+    types: [create-issue]`)
+
+	assert.Contains(t, lock, "  safe_outputs_review:\n", "review job should be generated")
+	assert.Contains(t, lock, "id: request_approval")
+	assert.Contains(t, lock, `GH_AW_APPROVAL_ENVIRONMENT: "agent-review"`)
+	assert.Contains(t, lock, "comment_id: ${{ steps.request_approval.outputs.comment_id }}")
+	assert.Contains(t, lock, "      - safe_outputs_review\n", "safe_outputs should depend on the review job")
+	assert.Contains(t, lock, "    environment: ${{ needs.safe_outputs_review.outputs.pending_count != '0' && 'agent-review' || '' }}\n", "safe_outputs should only wait on the environment when actions require approval")
+	assert.Contains(t, lock, "id: approval_filter")
+	assert.Contains(t, lock, "GH_AW_APPROVAL_COMMENT_ID: ${{ needs.safe_outputs_review.outputs.comment_id }}")
+	assert.Contains(t, lock, `GH_AW_APPROVAL_TYPES: "create_issue"`)
+}
+
+func TestCompileWorkflowWithSafeOutputApprovalGatesCustomJobs(t *testing.T) {
+	lockContent := compileApprovalTestWorkflow(t, `  approval: agent-review
+  jobs:
+    deploy:
+      permissions:
+        deployments: write
+      inputs:
+        environment:
+          type: string
+      steps:
+        - name: Deploy
+          run: jq '.items[] | select(.type == "deploy")' "$GH_AW_AGENT_OUTPUT"
+  types:
+    notify:
+      inputs:
+        message:
+          type: string
+      script: |
+        core.info(item.message);`)
+
+	var lock struct {
+		Jobs map[string]struct {
+			Needs       any               `yaml:"needs"`
+			Environment string            `yaml:"environment"`
+			Permissions map[string]string `yaml:"permissions"`
+			Steps       []struct {
+				ID   string `yaml:"id"`
+				Name string `yaml:"name"`
+			} `yaml:"steps"`
+		} `yaml:"jobs"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(lockContent), &lock), "lock file should be valid YAML")
+
+	for _, name := range []string{"deploy", "custom_notify"} {
+		job, ok := lock.Jobs[name]
+		require.True(t, ok, "job %s should be generated", name)
+		assert.Contains(t, job.Needs, SafeOutputsReviewJobName, "job %s should wait for the approval checklist", name)
+		assert.Equal(t, "${{ needs.safe_outputs_review.outputs.pending_count != '0' && 'agent-review' || '' }}", job.Environment, "job %s should wait on the environment", name)
+		assert.Equal(t, "read", job.Permissions["issues"], "job %s should be able to read the checklist", name)
+	}
+
+	// Rejected items are dropped from the agent output before any step of the custom job reads it
+	filterIndex, deployIndex := -1, -1
+	for i, step := range lock.Jobs["deploy"].Steps {
+		switch {
+		case step.ID == "approval_filter":
+			filterIndex = i
+		case step.Name == "Deploy":
+			deployIndex = i
+		}
+	}
+	require.NotEqual(t, -1, filterIndex, "safe job should filter unapproved items")
+	require.NotEqual(t, -1, deployIndex, "safe job should run the configured steps")
+	assert.Less(t, filterIndex, deployIndex, "unapproved items should be dropped before the configured steps run")
+	assert.Equal(t, "write", lock.Jobs["deploy"].Permissions["deployments"], "declared permissions should be kept")
+}
+
+func TestCompileWorkflowWithSafeOutputApprovalStaged(t *testing.T) {
+	lock := compileApprovalTestWorkflow(t, `  staged: true
+  create-issue:
+  approval: agent-review`)
+
+	assert.NotContains(t, lock, "safe_outputs_review", "staged runs should skip the approval queue")
+	assert.NotContains(t, lock, "environment: agent-review")
+}
@@ -477,12 +477,29 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				}
 			}
 
+			// Handle custom safe-output types
+			if types, exists := outputMap["types"]; exists {
+				if typesMap, ok := types.(map[string]any); ok {
+					config.Types = parseCustomSafeOutputTypes(typesMap)
+				}
+			}
+
+			// Handle per-item approval queue
+			if approval, exists := outputMap["approval"]; exists {
+				config.Approval = parseSafeOutputApprovalConfig(approval)
+			}
+
 			// Handle app configuration for GitHub App token minting
 			if app, exists := outputMap["app"]; exists {
 				if appMap, ok := app.(map[string]any); ok {
 					config.App = parseAppConfig(appMap)
 				}
 			}
+
+			// Handle per-repository targets and their credentials
+			if targets, exists := outputMap["targets"]; exists {
+				config.Targets = parseSafeOutputTargets(targets)
+			}
 		}
 	}
 
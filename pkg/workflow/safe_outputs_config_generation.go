@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/github/gh-aw/pkg/stringutil"
 )
 
 // ========================================
@@ -476,6 +478,13 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 		}
 	}
 
+	// Add custom safe-output types; their field rules are emitted separately in validation.json
+	for typeName, typeConfig := range data.SafeOutputs.Types {
+		safeOutputsConfig[stringutil.NormalizeSafeOutputIdentifier(typeName)] = map[string]any{
+			"max": typeConfig.Max,
+		}
+	}
+
 	// Add safe-jobs configuration from SafeOutputs.Jobs
 	if len(data.SafeOutputs.Jobs) > 0 {
 		safeOutputsConfigLog.Printf("Processing %d safe job configurations", len(data.SafeOutputs.Jobs))
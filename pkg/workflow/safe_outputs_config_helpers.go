@@ -7,6 +7,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
 )
 
 // ========================================
@@ -67,6 +68,12 @@ func hasAnySafeOutputEnabled(safeOutputs *SafeOutputsConfig) bool {
 		return true
 	}
 
+	// Check custom types separately as it's a map
+	if len(safeOutputs.Types) > 0 {
+		safeOutputReflectionLog.Printf("Found %d custom types enabled", len(safeOutputs.Types))
+		return true
+	}
+
 	// Use reflection to check all pointer fields
 	val := reflect.ValueOf(safeOutputs).Elem()
 	for fieldName := range safeOutputFieldMapping {
@@ -105,6 +112,11 @@ func getEnabledSafeOutputToolNamesReflection(safeOutputs *SafeOutputsConfig) []s
 		safeOutputReflectionLog.Printf("Added custom job tool: %s", jobName)
 	}
 
+	// Add custom type tools
+	for typeName := range safeOutputs.Types {
+		tools = append(tools, stringutil.NormalizeSafeOutputIdentifier(typeName))
+	}
+
 	// Sort tools to ensure deterministic compilation
 	sort.Strings(tools)
 
@@ -159,8 +171,8 @@ func hasNonBuiltinSafeOutputsEnabled(safeOutputs *SafeOutputsConfig) bool {
 		return false
 	}
 
-	// Custom safe-jobs are always non-builtin
-	if len(safeOutputs.Jobs) > 0 {
+	// Custom safe-jobs and custom types are always non-builtin
+	if len(safeOutputs.Jobs) > 0 || len(safeOutputs.Types) > 0 {
 		return true
 	}
 
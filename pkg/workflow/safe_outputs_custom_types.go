@@ -0,0 +1,475 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var customSafeOutputTypesLog = logger.New("workflow:safe_outputs_custom_types")
+
+// customSafeOutputTypeEnvKeyPattern matches the environment variable names accepted in env
+var customSafeOutputTypeEnvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// customSafeOutputTypeDefaultMax is the default number of items an agent may emit per custom type
+const customSafeOutputTypeDefaultMax = 1
+
+// CustomSafeOutputTypeConfig defines a safe-output type declared in frontmatter under safe-outputs.types.
+// The agent sees a typed MCP tool built from Inputs; the items it emits are validated, sanitized and
+// handed to either an inline script or a list of steps running in a job of their own.
+type CustomSafeOutputTypeConfig struct {
+	Description  string                     `yaml:"description,omitempty"`
+	Inputs       map[string]*SafeInputParam `yaml:"inputs,omitempty"`
+	Max          int                        `yaml:"max,omitempty"`
+	AllowedRepos []string                   `yaml:"allowed-repos,omitempty"`
+	Permissions  map[string]string          `yaml:"permissions,omitempty"`
+	Script       string                     `yaml:"script,omitempty"`
+	Steps        []any                      `yaml:"steps,omitempty"`
+	Env          map[string]string          `yaml:"env,omitempty"`
+	GitHubToken  string                     `yaml:"github-token,omitempty"`
+}
+
+// parseCustomSafeOutputTypes parses the safe-outputs.types map
+func parseCustomSafeOutputTypes(typesMap map[string]any) map[string]*CustomSafeOutputTypeConfig {
+	if len(typesMap) == 0 {
+		return nil
+	}
+
+	customSafeOutputTypesLog.Printf("Parsing %d custom safe-output types", len(typesMap))
+	result := make(map[string]*CustomSafeOutputTypeConfig, len(typesMap))
+
+	for typeName, typeValue := range typesMap {
+		typeMap, ok := typeValue.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		config := &CustomSafeOutputTypeConfig{
+			Max: customSafeOutputTypeDefaultMax,
+		}
+		if description, ok := typeMap["description"].(string); ok {
+			config.Description = description
+		}
+		if inputs, ok := typeMap["inputs"].(map[string]any); ok {
+			config.Inputs = parseSafeInputParams(inputs)
+		}
+		if maxValue, exists := typeMap["max"]; exists {
+			if n, ok := parseIntValue(maxValue); ok {
+				config.Max = n
+			}
+		}
+		config.AllowedRepos = ParseStringArrayFromConfig(typeMap, "allowed-repos", customSafeOutputTypesLog)
+		if permissions, ok := typeMap["permissions"].(map[string]any); ok {
+			config.Permissions = make(map[string]string, len(permissions))
+			for scope, level := range permissions {
+				if levelStr, ok := level.(string); ok {
+					config.Permissions[scope] = levelStr
+				}
+			}
+		}
+		if script, ok := typeMap["script"].(string); ok {
+			config.Script = script
+		}
+		if steps, ok := typeMap["steps"].([]any); ok {
+			config.Steps = steps
+		}
+		if env, ok := typeMap["env"].(map[string]any); ok {
+			config.Env = make(map[string]string, len(env))
+			for key, value := range env {
+				if valueStr, ok := value.(string); ok {
+					config.Env[key] = valueStr
+				}
+			}
+		}
+		if token, ok := typeMap["github-token"].(string); ok {
+			config.GitHubToken = token
+		}
+
+		result[typeName] = config
+	}
+
+	return result
+}
+
+// validateCustomSafeOutputTypes checks custom type definitions at compile time
+func validateCustomSafeOutputTypes(safeOutputs *SafeOutputsConfig) error {
+	if safeOutputs == nil || len(safeOutputs.Types) == 0 {
+		return nil
+	}
+
+	builtinToolNames := make(map[string]bool, len(safeOutputFieldMapping))
+	for _, toolName := range safeOutputFieldMapping {
+		builtinToolNames[toolName] = true
+	}
+	jobToolNames := make(map[string]bool, len(safeOutputs.Jobs))
+	for jobName := range safeOutputs.Jobs {
+		jobToolNames[stringutil.NormalizeSafeOutputIdentifier(jobName)] = true
+	}
+
+	seen := make(map[string]string, len(safeOutputs.Types))
+	for _, typeName := range sortedCustomSafeOutputTypeNames(safeOutputs.Types) {
+		config := safeOutputs.Types[typeName]
+		toolName := stringutil.NormalizeSafeOutputIdentifier(typeName)
+
+		if builtinToolNames[toolName] {
+			return fmt.Errorf("safe-outputs type '%s' conflicts with the built-in safe output '%s'", typeName, toolName)
+		}
+		if jobToolNames[toolName] {
+			return fmt.Errorf("safe-outputs type '%s' conflicts with the safe-outputs job of the same name", typeName)
+		}
+		if other, ok := seen[toolName]; ok {
+			return fmt.Errorf("safe-outputs types '%s' and '%s' resolve to the same tool name '%s'", other, typeName, toolName)
+		}
+		seen[toolName] = typeName
+
+		if err := validateCustomSafeOutputType(config); err != nil {
+			return fmt.Errorf("safe-outputs type '%s': %w", typeName, err)
+		}
+	}
+
+	customSafeOutputTypesLog.Printf("Validated %d custom safe-output types", len(safeOutputs.Types))
+	return nil
+}
+
+func validateCustomSafeOutputType(config *CustomSafeOutputTypeConfig) error {
+	hasScript := strings.TrimSpace(config.Script) != ""
+	if hasScript == (len(config.Steps) > 0) {
+		return errors.New("exactly one of 'script' or 'steps' must be specified")
+	}
+	if config.Max < 1 {
+		return fmt.Errorf("max must be at least 1, got %d", config.Max)
+	}
+
+	if _, exists := config.Inputs["type"]; exists {
+		return errors.New("input 'type' is reserved")
+	}
+	if _, exists := config.Inputs["repo"]; exists && len(config.AllowedRepos) > 0 {
+		return errors.New("input 'repo' is reserved when allowed-repos is set")
+	}
+	for _, name := range sortedSafeInputParamNames(config.Inputs) {
+		if err := validateSafeInputParamDefinition(name, config.Inputs[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(config.Env)) {
+		if !customSafeOutputTypeEnvKeyPattern.MatchString(key) {
+			return fmt.Errorf("env key '%s' is not a valid environment variable name", key)
+		}
+	}
+
+	for _, scope := range slices.Sorted(maps.Keys(config.Permissions)) {
+		if convertStringToPermissionScope(scope) == "" {
+			return fmt.Errorf("unknown permission scope '%s'", scope)
+		}
+		switch PermissionLevel(config.Permissions[scope]) {
+		case PermissionRead, PermissionWrite, PermissionNone:
+		default:
+			return fmt.Errorf("permission '%s' has invalid level '%s' (expected read, write or none)", scope, config.Permissions[scope])
+		}
+	}
+	return nil
+}
+
+// inputSchema returns the JSON schema of the tool exposed to the agent
+func (t *CustomSafeOutputTypeConfig) inputSchema() map[string]any {
+	properties := make(map[string]any, len(t.Inputs)+1)
+	var required []string
+	for name, param := range t.Inputs {
+		properties[name] = param.JSONSchema()
+		if param.Required {
+			required = append(required, name)
+		}
+	}
+	if len(t.AllowedRepos) > 0 {
+		properties["repo"] = map[string]any{
+			"type":        "string",
+			"description": "Target repository in 'owner/repo' format. Defaults to the current repository. Allowed: " + strings.Join(t.AllowedRepos, ", "),
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// generateCustomSafeOutputTypeToolDefinition builds the safe_outputs_tools.json entry for a custom type
+func generateCustomSafeOutputTypeToolDefinition(toolName string, config *CustomSafeOutputTypeConfig) map[string]any {
+	description := config.Description
+	if description == "" {
+		description = fmt.Sprintf("Emit a %s safe output", toolName)
+	}
+	if config.Max > 1 {
+		description += fmt.Sprintf(" CONSTRAINTS: Maximum %d item(s) can be emitted.", config.Max)
+	}
+	return map[string]any{
+		"name":        toolName,
+		"description": description,
+		"inputSchema": config.inputSchema(),
+	}
+}
+
+// validationConfig derives the ingestion validation rules for a custom type from its inputs.
+// String values are always sanitized, like the body fields of built-in safe outputs.
+func (t *CustomSafeOutputTypeConfig) validationConfig() TypeValidationConfig {
+	fields := make(map[string]FieldValidation, len(t.Inputs)+1)
+	for name, param := range t.Inputs {
+		field := FieldValidation{Required: param.Required}
+		switch param.Type {
+		case "string":
+			field.Type = "string"
+			field.Sanitize = true
+			field.MaxLength = MaxBodyLength
+			if param.MaxLength != nil {
+				field.MaxLength = *param.MaxLength
+			}
+			field.Pattern = param.Pattern
+			for _, value := range param.Enum {
+				if s, ok := value.(string); ok {
+					field.Enum = append(field.Enum, s)
+				}
+			}
+		case "number", "integer":
+			field.Type = "number"
+		case "boolean":
+			field.Type = "boolean"
+		case "array":
+			field.Type = "array"
+			if param.Items != nil && param.Items.Type == "string" {
+				field.ItemType = "string"
+				field.ItemSanitize = true
+				field.ItemMaxLength = MaxBodyLength
+				if param.Items.MaxLength != nil {
+					field.ItemMaxLength = *param.Items.MaxLength
+				}
+			}
+		}
+		fields[name] = field
+	}
+	if len(t.AllowedRepos) > 0 {
+		fields["repo"] = FieldValidation{Type: "string", MaxLength: 256}
+	}
+	return TypeValidationConfig{DefaultMax: t.Max, Fields: fields}
+}
+
+// customSafeOutputTypesValidationConfig returns the validation rules of all custom types keyed by tool name
+func customSafeOutputTypesValidationConfig(safeOutputs *SafeOutputsConfig) map[string]TypeValidationConfig {
+	if safeOutputs == nil || len(safeOutputs.Types) == 0 {
+		return nil
+	}
+	configs := make(map[string]TypeValidationConfig, len(safeOutputs.Types))
+	for typeName, config := range safeOutputs.Types {
+		configs[stringutil.NormalizeSafeOutputIdentifier(typeName)] = config.validationConfig()
+	}
+	return configs
+}
+
+// permissions returns the permissions declared by a custom type. Types that declare none
+// render as "permissions: {}" so that their job never falls back to the default token scopes.
+func (t *CustomSafeOutputTypeConfig) permissions() *Permissions {
+	permissions := NewPermissionsEmpty()
+	for scope, level := range t.Permissions {
+		if permissionScope := convertStringToPermissionScope(scope); permissionScope != "" {
+			permissions.Set(permissionScope, PermissionLevel(level))
+		}
+	}
+	return permissions
+}
+
+// customSafeOutputTypeJobName returns the name of the job processing a custom type
+func customSafeOutputTypeJobName(typeName string) string {
+	return "custom_" + stringutil.NormalizeSafeOutputIdentifier(typeName)
+}
+
+// buildCustomSafeOutputTypeJob builds the job that processes one custom type. Each type runs in
+// its own job so that the permissions it declares are granted to its handler only, instead of to
+// every safe output processed by the consolidated safe_outputs job.
+func (c *Compiler) buildCustomSafeOutputTypeJob(data *WorkflowData, typeName string, mainJobName, markdownPath string) (*Job, error) {
+	config := data.SafeOutputs.Types[typeName]
+	toolName := stringutil.NormalizeSafeOutputIdentifier(typeName)
+	customSafeOutputTypesLog.Printf("Building job for custom safe-output type %s", typeName)
+
+	setupActionRef := c.resolveActionReference("./actions/setup", data)
+	if setupActionRef == "" && !c.actionMode.IsScript() {
+		return nil, errors.New("setup action reference is required but could not be resolved")
+	}
+
+	permissions := config.permissions()
+	var steps []string
+
+	// For dev mode (local action path), checkout the actions folder first
+	checkoutSteps := c.generateCheckoutActionsFolder(data)
+	if len(checkoutSteps) > 0 {
+		permissions.Merge(NewPermissionsContentsRead())
+	}
+	steps = append(steps, checkoutSteps...)
+	steps = append(steps, c.generateSetupStep(setupActionRef, SetupActionDestination, false)...)
+	steps = append(steps, buildAgentOutputDownloadSteps()...)
+
+	// Drop unapproved items like the consolidated job does
+	needs := []string{mainJobName}
+	var environment string
+	if c.isSafeOutputApprovalEnabled(data) {
+		steps = append(steps, buildApprovalFilterStep(data.SafeOutputs.Approval)...)
+		permissions.Merge(NewPermissionsFromMap(map[PermissionScope]PermissionLevel{PermissionIssues: PermissionRead}))
+		needs = append(needs, SafeOutputsReviewJobName)
+		environment = buildSafeOutputApprovalEnvironment(data.SafeOutputs.Approval)
+	}
+	if data.LockForAgent {
+		needs = append(needs, "unlock")
+	}
+
+	// The app token is minted with the permissions of this type only
+	if data.SafeOutputs.App != nil {
+		steps = append(steps, c.buildGitHubAppTokenMintStep(data.SafeOutputs.App, permissions)...)
+	}
+
+	typeSteps, _, err := c.buildCustomSafeOutputTypeSteps(data, typeName, config)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, typeSteps...)
+
+	if data.SafeOutputs.App != nil {
+		steps = append(steps, c.buildGitHubAppTokenInvalidationStep()...)
+	}
+
+	// Run only when the agent emitted the type and detection passed (if enabled)
+	var jobCondition ConditionNode = BuildSafeOutputType(toolName)
+	if data.SafeOutputs.ThreatDetection != nil {
+		jobCondition = BuildAnd(jobCondition, buildDetectionSuccessCondition())
+	}
+
+	return &Job{
+		Name:           customSafeOutputTypeJobName(typeName),
+		If:             jobCondition.Render(),
+		RunsOn:         c.formatSafeOutputsRunsOn(data.SafeOutputs),
+		Permissions:    permissions.RenderToYAML(),
+		Environment:    environment,
+		TimeoutMinutes: 10,
+		Env:            c.buildJobLevelSafeOutputEnvVars(data, GetWorkflowIDFromPath(markdownPath)),
+		Steps:          steps,
+		Needs:          needs,
+	}, nil
+}
+
+// buildCustomSafeOutputTypeSteps builds the steps that process one custom type.
+// Script handlers run in a single github-script step; step handlers run after a preparation step
+// that validates the items and writes them to a file, and are skipped when there are no items.
+func (c *Compiler) buildCustomSafeOutputTypeSteps(data *WorkflowData, typeName string, config *CustomSafeOutputTypeConfig) ([]string, string, error) {
+	toolName := stringutil.NormalizeSafeOutputIdentifier(typeName)
+	stepID := "custom_" + toolName
+	customSafeOutputTypesLog.Printf("Building steps for custom safe-output type %s", typeName)
+
+	schemaJSON, err := json.Marshal(config.inputSchema())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal input schema for safe-outputs type '%s': %w", typeName, err)
+	}
+
+	envVars := []string{
+		fmt.Sprintf("          GH_AW_CUSTOM_OUTPUT_TYPE: %q\n", toolName),
+		fmt.Sprintf("          GH_AW_CUSTOM_OUTPUT_SCHEMA: %q\n", string(schemaJSON)),
+	}
+	if len(config.AllowedRepos) > 0 {
+		envVars = append(envVars, fmt.Sprintf("          GH_AW_CUSTOM_OUTPUT_ALLOWED_REPOS: %q\n", strings.Join(config.AllowedRepos, ",")))
+	}
+
+	stepConfig := SafeOutputStepConfig{
+		StepName:      "Process " + typeName,
+		StepID:        stepID,
+		CustomEnvVars: envVars,
+		Token:         config.GitHubToken,
+	}
+	if config.Script != "" {
+		// The user script becomes the body of the per-item handler
+		stepConfig.CustomEnvVars = append(stepConfig.CustomEnvVars, formatCustomSafeOutputTypeEnv(config.Env)...)
+		stepConfig.Script = fmt.Sprintf("const { main } = require('%s/custom_safe_output.cjs');\nawait main(async item => {\n%s\n});\n",
+			SetupActionDestination, strings.TrimRight(config.Script, "\n"))
+		return c.buildConsolidatedSafeOutputStep(data, stepConfig), stepID, nil
+	}
+
+	stepConfig.StepName = "Prepare " + typeName + " items"
+	stepConfig.ScriptName = "custom_safe_output"
+	steps := c.buildConsolidatedSafeOutputStep(data, stepConfig)
+
+	condition := fmt.Sprintf("steps.%s.outputs.count != '0'", stepID)
+	for i, step := range config.Steps {
+		stepMap, ok := step.(map[string]any)
+		if !ok {
+			return nil, "", fmt.Errorf("safe-outputs type '%s': step at index %d is not a valid step object", typeName, i)
+		}
+		stepMap = maps.Clone(stepMap)
+
+		if userIf, ok := stepMap["if"].(string); ok && userIf != "" {
+			stepMap["if"] = fmt.Sprintf("(%s) && (%s)", condition, c.extractExpressionFromIfString(userIf))
+		} else {
+			stepMap["if"] = condition
+		}
+
+		env := map[string]any{}
+		for key, value := range config.Env {
+			env[key] = value
+		}
+		if stepEnv, ok := stepMap["env"].(map[string]any); ok {
+			maps.Copy(env, stepEnv)
+		}
+		env["GH_AW_CUSTOM_OUTPUT_ITEMS"] = fmt.Sprintf("${{ steps.%s.outputs.items_file }}", stepID)
+		if _, ok := env["GH_TOKEN"]; !ok {
+			env["GH_TOKEN"] = c.customSafeOutputTypeToken(data, config)
+		}
+		stepMap["env"] = env
+
+		typedStep, err := MapToStep(stepMap)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert safe-outputs type '%s' step at index %d to typed step: %w", typeName, i, err)
+		}
+		pinnedStep := ApplyActionPinToTypedStep(typedStep, data)
+		stepYAML, err := c.convertStepToYAML(pinnedStep.ToMap())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert safe-outputs type '%s' step at index %d to YAML: %w", typeName, i, err)
+		}
+		steps = append(steps, stepYAML)
+	}
+	return steps, stepID, nil
+}
+
+// customSafeOutputTypeToken returns the token expression exposed to step handlers as GH_TOKEN
+func (c *Compiler) customSafeOutputTypeToken(data *WorkflowData, config *CustomSafeOutputTypeConfig) string {
+	if data.SafeOutputs != nil && data.SafeOutputs.App != nil {
+		return "${{ steps.safe-outputs-app-token.outputs.token }}"
+	}
+	token := config.GitHubToken
+	if token == "" && data.SafeOutputs != nil {
+		token = data.SafeOutputs.GitHubToken
+	}
+	return getEffectiveSafeOutputGitHubToken(token)
+}
+
+// formatCustomSafeOutputTypeEnv renders the env of a custom type. Values are quoted so that
+// they cannot break out of the env mapping; keys are validated by validateCustomSafeOutputType.
+func formatCustomSafeOutputTypeEnv(env map[string]string) []string {
+	lines := make([]string, 0, len(env))
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		lines = append(lines, fmt.Sprintf("          %s: %q\n", key, env[key]))
+	}
+	return lines
+}
+
+func sortedCustomSafeOutputTypeNames(types map[string]*CustomSafeOutputTypeConfig) []string {
+	return slices.Sorted(maps.Keys(types))
+}
@@ -0,0 +1,314 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomSafeOutputTypes(t *testing.T) {
+	types := parseCustomSafeOutputTypes(map[string]any{
+		"deploy-preview": map[string]any{
+			"description":   "Request a preview deployment",
+			"max":           uint64(3),
+			"allowed-repos": []any{"octo/site"},
+			"permissions":   map[string]any{"deployments": "write"},
+			"inputs": map[string]any{
+				"environment": map[string]any{"type": "string", "enum": []any{"staging", "preview"}, "required": true},
+			},
+			"script": "core.info(item.environment);",
+			"env":    map[string]any{"REGION": "eu"},
+		},
+		"notify": map[string]any{
+			"steps": []any{map[string]any{"run": "echo hi"}},
+		},
+	})
+
+	require.Len(t, types, 2)
+	deploy := types["deploy-preview"]
+	assert.Equal(t, "Request a preview deployment", deploy.Description)
+	assert.Equal(t, 3, deploy.Max)
+	assert.Equal(t, []string{"octo/site"}, deploy.AllowedRepos)
+	assert.Equal(t, map[string]string{"deployments": "write"}, deploy.Permissions)
+	assert.Equal(t, map[string]string{"REGION": "eu"}, deploy.Env)
+	require.Contains(t, deploy.Inputs, "environment")
+	assert.True(t, deploy.Inputs["environment"].Required)
+
+	assert.Equal(t, 1, types["notify"].Max, "max should default to 1")
+	assert.Len(t, types["notify"].Steps, 1)
+}
+
+func TestValidateCustomSafeOutputTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name: "valid script type",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Permissions: map[string]string{"deployments": "write"}},
+			}},
+		},
+		{
+			name: "missing handler",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1},
+			}},
+			wantErr: "exactly one of 'script' or 'steps'",
+		},
+		{
+			name: "both handlers",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Steps: []any{map[string]any{"run": "true"}}},
+			}},
+			wantErr: "exactly one of 'script' or 'steps'",
+		},
+		{
+			name: "conflicts with built-in",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"create-issue": {Max: 1, Script: "return;"},
+			}},
+			wantErr: "conflicts with the built-in safe output 'create_issue'",
+		},
+		{
+			name: "conflicts with safe job",
+			config: &SafeOutputsConfig{
+				Jobs:  map[string]*SafeJobConfig{"notify": {}},
+				Types: map[string]*CustomSafeOutputTypeConfig{"notify": {Max: 1, Script: "return;"}},
+			},
+			wantErr: "conflicts with the safe-outputs job",
+		},
+		{
+			name: "reserved input",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Inputs: map[string]*SafeInputParam{"type": {Type: "string"}}},
+			}},
+			wantErr: "input 'type' is reserved",
+		},
+		{
+			name: "invalid input definition",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Inputs: map[string]*SafeInputParam{"env": {Type: "date"}}},
+			}},
+			wantErr: "input 'env' has unsupported type 'date'",
+		},
+		{
+			name: "invalid env key",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Env: map[string]string{"BAD KEY": "value"}},
+			}},
+			wantErr: "env key 'BAD KEY' is not a valid environment variable name",
+		},
+		{
+			name: "unknown permission scope",
+			config: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+				"deploy": {Max: 1, Script: "return;", Permissions: map[string]string{"wiki": "write"}},
+			}},
+			wantErr: "unknown permission scope 'wiki'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCustomSafeOutputTypes(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "definition should be valid")
+				return
+			}
+			require.Error(t, err, "definition should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCustomSafeOutputTypeToolAndValidation(t *testing.T) {
+	maxLength := 200
+	config := &CustomSafeOutputTypeConfig{
+		Description:  "Request a deployment",
+		Max:          2,
+		AllowedRepos: []string{"octo/site"},
+		Inputs: map[string]*SafeInputParam{
+			"environment": {Type: "string", Enum: []any{"staging", "preview"}, Required: true},
+			"note":        {Type: "string", MaxLength: &maxLength},
+			"replicas":    {Type: "integer"},
+			"regions":     {Type: "array", Items: &SafeInputParam{Type: "string"}},
+		},
+		Script: "return;",
+	}
+
+	tool := generateCustomSafeOutputTypeToolDefinition("deploy", config)
+	assert.Equal(t, "deploy", tool["name"])
+	assert.Contains(t, tool["description"], "Maximum 2 item(s)")
+	schema, ok := tool["inputSchema"].(map[string]any)
+	require.True(t, ok, "tool should have an input schema")
+	assert.Equal(t, []string{"environment"}, schema["required"])
+	assert.Contains(t, schema["properties"], "repo", "allowed-repos should add a repo input")
+
+	validation := config.validationConfig()
+	assert.Equal(t, 2, validation.DefaultMax)
+	assert.Equal(t, FieldValidation{Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength, Enum: []string{"staging", "preview"}}, validation.Fields["environment"])
+	assert.Equal(t, 200, validation.Fields["note"].MaxLength)
+	assert.Equal(t, "number", validation.Fields["replicas"].Type)
+	assert.True(t, validation.Fields["regions"].ItemSanitize, "string array items should be sanitized")
+	assert.Contains(t, validation.Fields, "repo")
+}
+
+func TestCompileWorkflowWithCustomSafeOutputTypes(t *testing.T) {
+	content := `---
+on: issues
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  types:
+    deploy-preview:
+      description: Request a preview deployment
+      permissions:
+        deployments: write
+      inputs:
+        environment:
+          type: string
+          required: true
+      script: |
+        core.info(item.environment);
+    notify:
+      inputs:
+        message:
+          type: string
+      steps:
+        - name: Send notification
+          run: cat "$GH_AW_CUSTOM_OUTPUT_ITEMS"
+---
+
+# Test Workflow
+
+Report results.`
+
+	tmpDir := testutil.TempDir(t, "custom-safe-output-types-test")
+	testFile := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "test.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `"name": "deploy_preview"`, "tool should be generated into safe_outputs_tools.json")
+	assert.Contains(t, lock, `"deploy_preview":{"max":1}`, "type should be enabled in config.json")
+	assert.Contains(t, lock, "id: custom_deploy_preview")
+	assert.Contains(t, lock, "await main(async item => {")
+	assert.Contains(t, lock, "id: custom_notify")
+	assert.Contains(t, lock, "if: steps.custom_notify.outputs.count != '0'")
+	assert.Contains(t, lock, "GH_AW_CUSTOM_OUTPUT_ITEMS: ${{ steps.custom_notify.outputs.items_file }}")
+	assert.False(t, strings.Contains(lock, "create_issue"), "custom types should count as non-builtin outputs")
+
+	var workflow struct {
+		Jobs map[string]struct {
+			Permissions map[string]string `yaml:"permissions"`
+			Needs       any               `yaml:"needs"`
+			If          string            `yaml:"if"`
+		} `yaml:"jobs"`
+	}
+	require.NoError(t, yaml.Unmarshal(lockContent, &workflow), "lock file should be valid YAML")
+	require.Contains(t, workflow.Jobs, "custom_deploy_preview", "each type should run in its own job")
+	require.Contains(t, workflow.Jobs, "custom_notify", "each type should run in its own job")
+	assert.Equal(t, "write", workflow.Jobs["custom_deploy_preview"].Permissions["deployments"], "type permissions should be granted to its own job")
+	assert.NotContains(t, workflow.Jobs["custom_notify"].Permissions, "deployments", "type permissions should not leak into other types")
+	assert.NotContains(t, workflow.Jobs["safe_outputs"].Permissions, "deployments", "type permissions should not leak into the safe-outputs job")
+	assert.Contains(t, workflow.Jobs["custom_deploy_preview"].If, "contains(needs.agent.outputs.output_types, 'deploy_preview')")
+	assert.Contains(t, workflow.Jobs["conclusion"].Needs, "custom_deploy_preview", "conclusion should wait for the type jobs")
+}
+
+func TestCustomSafeOutputTypeJobWithoutPermissions(t *testing.T) {
+	data := &WorkflowData{
+		Name: "test",
+		SafeOutputs: &SafeOutputsConfig{Types: map[string]*CustomSafeOutputTypeConfig{
+			"notify": {Max: 1, Script: "core.info(item.message);"},
+		}},
+	}
+
+	compiler := NewCompilerWithVersion("1.0.0")
+	compiler.SetActionMode(ActionModeRelease)
+	job, err := compiler.buildCustomSafeOutputTypeJob(data, "notify", "agent", "test.md")
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom_notify", job.Name)
+	assert.Equal(t, "permissions: {}", job.Permissions, "a type without permissions should not inherit the default token scopes")
+	assert.Equal(t, []string{"agent"}, job.Needs)
+}
+
+func TestGetValidationConfigJSONWithCustomTypes(t *testing.T) {
+	custom := map[string]TypeValidationConfig{
+		"deploy": {DefaultMax: 2, Fields: map[string]FieldValidation{"environment": {Type: "string"}}},
+	}
+	data, err := getValidationConfigJSONWithCustomTypes([]string{"add_comment", "deploy"}, custom)
+	require.NoError(t, err)
+
+	var parsed map[string]TypeValidationConfig
+	require.NoError(t, json.Unmarshal([]byte(data), &parsed))
+	assert.Contains(t, parsed, "add_comment")
+	assert.Equal(t, 2, parsed["deploy"].DefaultMax)
+	assert.NotContains(t, ValidationConfig, "deploy", "custom types must not leak into the global config")
+}
+
+func TestCustomSafeOutputTypeEnvIsQuoted(t *testing.T) {
+	content := `---
+on: issues
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  types:
+    deploy:
+      env:
+        DEPLOY_NOTE: "target: prod\ninjected: true"
+      script: |
+        core.info(process.env.DEPLOY_NOTE);
+---
+
+# Test Workflow
+
+Deploy.`
+
+	tmpDir := testutil.TempDir(t, "custom-safe-output-env-test")
+	testFile := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "test.lock.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(lockContent), `DEPLOY_NOTE: "target: prod\ninjected: true"`, "env value should be quoted")
+
+	var lock struct {
+		Jobs map[string]struct {
+			Steps []struct {
+				ID  string            `yaml:"id"`
+				Env map[string]string `yaml:"env"`
+			} `yaml:"steps"`
+		} `yaml:"jobs"`
+	}
+	require.NoError(t, yaml.Unmarshal(lockContent, &lock), "lock file should be valid YAML")
+	var env map[string]string
+	for _, step := range lock.Jobs["custom_deploy"].Steps {
+		if step.ID == "custom_deploy" {
+			env = step.Env
+		}
+	}
+	require.NotNil(t, env, "custom type step should be generated")
+	assert.Equal(t, "target: prod\ninjected: true", env["DEPLOY_NOTE"], "env value should round-trip")
+	assert.NotContains(t, env, "injected", "env value should not inject keys")
+}
@@ -0,0 +1,97 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestPathPolicyConfig(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "path-policy-test")
+	testContent := `---
+on: push
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  create-pull-request:
+    allowed-paths:
+      - "src/**"
+      - "docs/**"
+    protected-paths:
+      - "**/CODEOWNERS"
+    if-path-violation: issue
+    fallback-as-issue: false
+  push-to-pull-request-branch:
+    protected-paths:
+      - ".github/workflows/**"
+    if-path-violation: drop
+---
+
+# Path Policy
+
+Update the code.
+`
+	testFile := filepath.Join(tmpDir, "path-policy.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(testContent), 0644))
+
+	compiler := NewCompiler()
+	workflowData, err := compiler.ParseWorkflowFile(testFile)
+	require.NoError(t, err, "workflow with a path policy should parse")
+	require.NotNil(t, workflowData.SafeOutputs)
+
+	createPR := workflowData.SafeOutputs.CreatePullRequests
+	require.NotNil(t, createPR)
+	assert.Equal(t, []string{"src/**", "docs/**"}, createPR.AllowedPaths)
+	assert.Equal(t, []string{"**/CODEOWNERS"}, createPR.ProtectedPaths)
+	assert.Equal(t, "issue", createPR.IfPathViolation)
+	assert.True(t, createPullRequestNeedsIssuesWrite(createPR), "issue fallback for path violations should need issues: write")
+
+	push := workflowData.SafeOutputs.PushToPullRequestBranch
+	require.NotNil(t, push)
+	assert.Empty(t, push.AllowedPaths)
+	assert.Equal(t, []string{".github/workflows/**"}, push.ProtectedPaths)
+	assert.Equal(t, "drop", push.IfPathViolation)
+
+	createPRHandler := handlerRegistry["create_pull_request"](workflowData.SafeOutputs)
+	assert.Equal(t, []string{"src/**", "docs/**"}, createPRHandler["allowed_paths"])
+	assert.Equal(t, []string{"**/CODEOWNERS"}, createPRHandler["protected_paths"])
+	assert.Equal(t, "issue", createPRHandler["if_path_violation"])
+
+	pushHandler := handlerRegistry["push_to_pull_request_branch"](workflowData.SafeOutputs)
+	assert.NotContains(t, pushHandler, "allowed_paths", "empty allowed-paths should not be emitted")
+	assert.Equal(t, []string{".github/workflows/**"}, pushHandler["protected_paths"])
+	assert.Equal(t, "drop", pushHandler["if_path_violation"])
+}
+
+func TestPullRequestPathPolicyRejectsUnknownAction(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "path-policy-invalid-test")
+	testContent := `---
+on: push
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  push-to-pull-request-branch:
+    protected-paths:
+      - "CODEOWNERS"
+    if-path-violation: issue
+---
+
+# Path Policy
+
+Update the code.
+`
+	testFile := filepath.Join(tmpDir, "path-policy-invalid.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(testContent), 0644))
+
+	err := NewCompiler().CompileWorkflow(testFile)
+	require.Error(t, err, "push-to-pull-request-branch cannot fall back to an issue")
+	assert.Contains(t, err.Error(), "if-path-violation", "error should name the invalid field")
+}
@@ -71,7 +71,7 @@ func ComputePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 	}
 	if safeOutputs.CreatePullRequests != nil {
 		// Check fallback-as-issue setting to determine permissions
-		if getFallbackAsIssue(safeOutputs.CreatePullRequests) {
+		if createPullRequestNeedsIssuesWrite(safeOutputs.CreatePullRequests) {
 			safeOutputsPermissionsLog.Print("Adding permissions for create-pull-request with fallback-as-issue")
 			permissions.Merge(NewPermissionsContentsWriteIssuesWritePRWrite())
 		} else {
@@ -244,6 +244,21 @@ func TestComputePermissionsForSafeOutputs(t *testing.T) {
 				PermissionPullRequests: PermissionWrite,
 			},
 		},
+		{
+			name: "create-pull-request with if-path-violation issue - issues permission without fallback-as-issue",
+			safeOutputs: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("1")},
+					FallbackAsIssue:      boolPtr(false),
+					IfPathViolation:      "issue",
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents:     PermissionWrite,
+				PermissionIssues:       PermissionWrite,
+				PermissionPullRequests: PermissionWrite,
+			},
+		},
 		{
 			name: "push-to-pull-request-branch - no issues permission",
 			safeOutputs: &SafeOutputsConfig{
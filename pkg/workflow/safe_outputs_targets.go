@@ -0,0 +1,252 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeOutputsTargetsLog = logger.New("workflow:safe_outputs_targets")
+
+// This file handles safe-outputs.targets, which declares the repositories safe outputs may
+// target and the credentials used for each of them:
+//
+//	safe-outputs:
+//	  targets:
+//	    acme/tracker:
+//	      app:
+//	        app-id: ${{ vars.TRACKER_APP_ID }}
+//	        private-key: ${{ secrets.TRACKER_APP_KEY }}
+//	    acme/docs:
+//	      github-token: ${{ secrets.DOCS_TOKEN }}
+//	  create-issue:
+//	    target-repo: acme/tracker
+//	  create-pull-request:
+//	    target-repo: acme/docs
+//
+// App targets get their own token minting step. The handler manager receives the targets and
+// their tokens and switches the GitHub client per message based on the repository the message
+// targets; messages for repositories that are not declared are rejected.
+
+// safeOutputTargetPattern matches "owner/repo" and "owner/*" target keys
+var safeOutputTargetPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/([A-Za-z0-9_.-]+|\*)$`)
+
+// SafeOutputRepoTarget holds the credentials used for safe outputs targeting a repository.
+// When neither is set, the default safe outputs token is used.
+type SafeOutputRepoTarget struct {
+	GitHubToken string           `yaml:"github-token,omitempty"` // Token for this target
+	App         *GitHubAppConfig `yaml:"app,omitempty"`          // GitHub App installation for this target
+}
+
+// parseSafeOutputTargets parses safe-outputs.targets
+func parseSafeOutputTargets(value any) map[string]*SafeOutputRepoTarget {
+	targetsMap, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	targets := make(map[string]*SafeOutputRepoTarget, len(targetsMap))
+	for repo, targetValue := range targetsMap {
+		target := &SafeOutputRepoTarget{}
+		if targetMap, ok := targetValue.(map[string]any); ok {
+			if token, ok := targetMap["github-token"].(string); ok {
+				target.GitHubToken = token
+			}
+			if appMap, ok := targetMap["app"].(map[string]any); ok {
+				target.App = parseAppConfig(appMap)
+			}
+		}
+		targets[repo] = target
+	}
+	safeOutputsTargetsLog.Printf("Parsed %d safe-outputs targets", len(targets))
+	return targets
+}
+
+// validateSafeOutputTargets checks the target keys and credentials, and that every static
+// target-repo and allowed-repos entry of the configured safe outputs is a declared target
+func validateSafeOutputTargets(config *SafeOutputsConfig) error {
+	if config == nil || len(config.Targets) == 0 {
+		return nil
+	}
+
+	for _, repo := range sortedSafeOutputTargetRepos(config.Targets) {
+		target := config.Targets[repo]
+		if !safeOutputTargetPattern.MatchString(repo) {
+			return fmt.Errorf("invalid safe-outputs.targets key %q, expected 'owner/repo' or 'owner/*'", repo)
+		}
+		if target.GitHubToken != "" && target.App != nil {
+			return fmt.Errorf("safe-outputs.targets.%s: github-token and app cannot be combined", repo)
+		}
+		if target.App != nil && (target.App.AppID == "" || target.App.PrivateKey == "") {
+			return fmt.Errorf("safe-outputs.targets.%s.app requires app-id and private-key", repo)
+		}
+	}
+
+	handlerNames := make([]string, 0, len(handlerRegistry))
+	for name := range handlerRegistry {
+		handlerNames = append(handlerNames, name)
+	}
+	sort.Strings(handlerNames)
+
+	for _, name := range handlerNames {
+		handlerConfig := handlerRegistry[name](config)
+		if handlerConfig == nil {
+			continue
+		}
+		var repos []string
+		if targetRepo, ok := handlerConfig["target-repo"].(string); ok {
+			repos = append(repos, targetRepo)
+		}
+		if allowedRepos, ok := handlerConfig["allowed_repos"].([]string); ok {
+			repos = append(repos, allowedRepos...)
+		}
+		for _, repo := range repos {
+			// Expressions and bare repository names are resolved at runtime
+			if isGitHubExpression(repo) || !strings.Contains(repo, "/") {
+				continue
+			}
+			if _, ok := matchSafeOutputTarget(config.Targets, repo); !ok {
+				return fmt.Errorf("%s targets repository '%s', which is not declared in safe-outputs.targets. Declared targets: %s",
+					strings.ReplaceAll(name, "_", "-"), repo, strings.Join(sortedSafeOutputTargetRepos(config.Targets), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// matchSafeOutputTarget returns the target declared for a repository, preferring an exact
+// match over an organization wildcard
+func matchSafeOutputTarget(targets map[string]*SafeOutputRepoTarget, repo string) (string, bool) {
+	if _, ok := targets[repo]; ok {
+		return repo, true
+	}
+	owner, _, found := strings.Cut(repo, "/")
+	if !found {
+		return "", false
+	}
+	if _, ok := targets[owner+"/*"]; ok {
+		return owner + "/*", true
+	}
+	return "", false
+}
+
+// sortedSafeOutputTargetRepos returns the target keys in a deterministic order
+func sortedSafeOutputTargetRepos(targets map[string]*SafeOutputRepoTarget) []string {
+	repos := make([]string, 0, len(targets))
+	for repo := range targets {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// safeOutputTargetTokenStepID returns the ID of the token minting step of an app target
+func safeOutputTargetTokenStepID(index int) string {
+	return fmt.Sprintf("safe-outputs-target-token-%d", index)
+}
+
+// safeOutputTargetToken returns the token expression of a target, or "" when the target uses
+// the default safe outputs token
+func safeOutputTargetToken(config *SafeOutputsConfig, repo string) string {
+	for i, targetRepo := range sortedSafeOutputTargetRepos(config.Targets) {
+		if targetRepo != repo {
+			continue
+		}
+		target := config.Targets[targetRepo]
+		if target.App != nil {
+			return fmt.Sprintf("${{ steps.%s.outputs.token }}", safeOutputTargetTokenStepID(i))
+		}
+		return target.GitHubToken
+	}
+	return ""
+}
+
+// hasSafeOutputTargetCredentials returns true when at least one target has its own credentials
+func hasSafeOutputTargetCredentials(config *SafeOutputsConfig) bool {
+	if config == nil {
+		return false
+	}
+	for _, target := range config.Targets {
+		if target.GitHubToken != "" || target.App != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSafeOutputTargetTokenSteps generates a token minting step for every app target. The
+// installation defaults to the owner of the target and to the target repository, or the whole
+// organization for "owner/*".
+func (c *Compiler) buildSafeOutputTargetTokenSteps(config *SafeOutputsConfig, permissions *Permissions) []string {
+	var steps []string
+	for i, repo := range sortedSafeOutputTargetRepos(config.Targets) {
+		target := config.Targets[repo]
+		if target.App == nil {
+			continue
+		}
+		app := *target.App
+		owner, name, _ := strings.Cut(repo, "/")
+		if app.Owner == "" {
+			app.Owner = owner
+		}
+		if len(app.Repositories) == 0 {
+			app.Repositories = []string{name}
+		}
+		safeOutputsTargetsLog.Printf("Building token minting step for target %s", repo)
+		for _, step := range c.buildGitHubAppTokenMintStep(&app, permissions) {
+			step = strings.ReplaceAll(step, "name: Generate GitHub App token", "name: Generate GitHub App token for "+repo)
+			steps = append(steps, strings.ReplaceAll(step, "id: safe-outputs-app-token", "id: "+safeOutputTargetTokenStepID(i)))
+		}
+	}
+	return steps
+}
+
+// buildSafeOutputTargetTokenInvalidationSteps generates the invalidation step of every app target
+func (c *Compiler) buildSafeOutputTargetTokenInvalidationSteps(config *SafeOutputsConfig) []string {
+	var steps []string
+	for i, repo := range sortedSafeOutputTargetRepos(config.Targets) {
+		if config.Targets[repo].App == nil {
+			continue
+		}
+		for _, step := range c.buildGitHubAppTokenInvalidationStep() {
+			step = strings.ReplaceAll(step, "name: Invalidate GitHub App token", "name: Invalidate GitHub App token for "+repo)
+			steps = append(steps, strings.ReplaceAll(step, "steps.safe-outputs-app-token.outputs.token", fmt.Sprintf("steps.%s.outputs.token", safeOutputTargetTokenStepID(i))))
+		}
+	}
+	return steps
+}
+
+// addSafeOutputTargetsEnvVars passes the targets to the handler manager. Each target lists the
+// environment variable holding its token so that tokens are never part of the JSON value.
+func (c *Compiler) addSafeOutputTargetsEnvVars(steps *[]string, config *SafeOutputsConfig) {
+	if config == nil || len(config.Targets) == 0 {
+		return
+	}
+
+	type targetEntry struct {
+		Repo     string `json:"repo"`
+		TokenEnv string `json:"token_env,omitempty"`
+	}
+	var entries []targetEntry
+	var tokenEnvVars []string
+	for i, repo := range sortedSafeOutputTargetRepos(config.Targets) {
+		entry := targetEntry{Repo: repo}
+		if token := safeOutputTargetToken(config, repo); token != "" {
+			entry.TokenEnv = fmt.Sprintf("GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_%d", i)
+			tokenEnvVars = append(tokenEnvVars, fmt.Sprintf("          %s: %s\n", entry.TokenEnv, token))
+		}
+		entries = append(entries, entry)
+	}
+
+	targetsJSON, err := json.Marshal(entries)
+	if err != nil {
+		safeOutputsTargetsLog.Printf("Failed to marshal safe-outputs targets: %v", err)
+		return
+	}
+	*steps = append(*steps, fmt.Sprintf("          GH_AW_SAFE_OUTPUTS_TARGETS: %q\n", string(targetsJSON)))
+	*steps = append(*steps, tokenEnvVars...)
+}
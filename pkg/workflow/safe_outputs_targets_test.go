@@ -0,0 +1,174 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeOutputTargets(t *testing.T) {
+	targets := parseSafeOutputTargets(map[string]any{
+		"acme/tracker": map[string]any{
+			"app": map[string]any{"app-id": "${{ vars.APP_ID }}", "private-key": "${{ secrets.APP_KEY }}"},
+		},
+		"acme/docs": map[string]any{"github-token": "${{ secrets.DOCS_TOKEN }}"},
+		"acme/*":    nil,
+	})
+
+	require.Len(t, targets, 3)
+	assert.Equal(t, "${{ vars.APP_ID }}", targets["acme/tracker"].App.AppID)
+	assert.Equal(t, "${{ secrets.DOCS_TOKEN }}", targets["acme/docs"].GitHubToken)
+	assert.Equal(t, &SafeOutputRepoTarget{}, targets["acme/*"], "a target without credentials uses the default token")
+	assert.Equal(t, []string{"acme/*", "acme/docs", "acme/tracker"}, sortedSafeOutputTargetRepos(targets))
+}
+
+func TestMatchSafeOutputTarget(t *testing.T) {
+	targets := map[string]*SafeOutputRepoTarget{
+		"acme/docs": {GitHubToken: "${{ secrets.DOCS_TOKEN }}"},
+		"acme/*":    {},
+	}
+
+	repo, ok := matchSafeOutputTarget(targets, "acme/docs")
+	assert.True(t, ok)
+	assert.Equal(t, "acme/docs", repo, "exact match should win over the organization wildcard")
+
+	repo, ok = matchSafeOutputTarget(targets, "acme/tracker")
+	assert.True(t, ok)
+	assert.Equal(t, "acme/*", repo)
+
+	_, ok = matchSafeOutputTarget(targets, "other/tracker")
+	assert.False(t, ok)
+}
+
+func TestValidateSafeOutputTargets(t *testing.T) {
+	app := &GitHubAppConfig{AppID: "${{ vars.APP_ID }}", PrivateKey: "${{ secrets.APP_KEY }}"}
+
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "no targets",
+			config: &SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{TargetRepoSlug: "acme/tracker"}},
+		},
+		{
+			name: "declared target repo",
+			config: &SafeOutputsConfig{
+				Targets:      map[string]*SafeOutputRepoTarget{"acme/tracker": {App: app}},
+				CreateIssues: &CreateIssuesConfig{TargetRepoSlug: "acme/tracker"},
+			},
+		},
+		{
+			name: "allowed repos covered by an organization wildcard",
+			config: &SafeOutputsConfig{
+				Targets:      map[string]*SafeOutputRepoTarget{"acme/*": {}},
+				CreateIssues: &CreateIssuesConfig{AllowedRepos: []string{"acme/tracker", "acme/docs"}},
+			},
+		},
+		{
+			name: "expression target repo",
+			config: &SafeOutputsConfig{
+				Targets:      map[string]*SafeOutputRepoTarget{"acme/tracker": {}},
+				CreateIssues: &CreateIssuesConfig{TargetRepoSlug: "${{ vars.TRACKER }}"},
+			},
+		},
+		{
+			name: "undeclared target repo",
+			config: &SafeOutputsConfig{
+				Targets:      map[string]*SafeOutputRepoTarget{"acme/tracker": {}},
+				CreateIssues: &CreateIssuesConfig{TargetRepoSlug: "acme/docs"},
+			},
+			wantErr: "create-issue targets repository 'acme/docs', which is not declared in safe-outputs.targets",
+		},
+		{
+			name:    "invalid key",
+			config:  &SafeOutputsConfig{Targets: map[string]*SafeOutputRepoTarget{"acme": {}}},
+			wantErr: "invalid safe-outputs.targets key \"acme\"",
+		},
+		{
+			name:    "token and app",
+			config:  &SafeOutputsConfig{Targets: map[string]*SafeOutputRepoTarget{"acme/docs": {GitHubToken: "${{ secrets.DOCS_TOKEN }}", App: app}}},
+			wantErr: "github-token and app cannot be combined",
+		},
+		{
+			name:    "incomplete app",
+			config:  &SafeOutputsConfig{Targets: map[string]*SafeOutputRepoTarget{"acme/docs": {App: &GitHubAppConfig{AppID: "${{ vars.APP_ID }}"}}}},
+			wantErr: "safe-outputs.targets.acme/docs.app requires app-id and private-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeOutputTargets(tt.config)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestSafeOutputTargetsCompilation(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "safe-outputs-targets-compile")
+	workflowContent := `---
+on:
+  issues:
+    types: [opened]
+engine: copilot
+safe-outputs:
+  targets:
+    acme/tracker:
+      app:
+        app-id: ${{ vars.TRACKER_APP_ID }}
+        private-key: ${{ secrets.TRACKER_APP_KEY }}
+    acme/docs:
+      github-token: ${{ secrets.DOCS_TOKEN }}
+  create-issue:
+    target-repo: acme/tracker
+  create-pull-request:
+    target-repo: acme/docs
+---
+
+# Route
+
+File issues in the tracker and documentation changes in the docs repository.
+`
+	workflowFile := filepath.Join(tmpDir, "route.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(workflowContent), 0644))
+
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("route.md")
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow with safe-outputs targets should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	_, mintStep, found := strings.Cut(lock, "- name: Generate GitHub App token for acme/tracker\n")
+	require.True(t, found, "app target should get its own token minting step")
+	mintStep, _, _ = strings.Cut(mintStep, "      - name:")
+	assert.Contains(t, mintStep, "id: safe-outputs-target-token-1")
+	assert.Contains(t, mintStep, "owner: acme")
+	assert.Contains(t, mintStep, "repositories: tracker", "token should be scoped to the target repository")
+	assert.Contains(t, lock, "name: Invalidate GitHub App token for acme/tracker")
+
+	assert.Contains(t, lock, `GH_AW_SAFE_OUTPUTS_TARGETS: "[{\"repo\":\"acme/docs\",\"token_env\":\"GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_0\"},{\"repo\":\"acme/tracker\",\"token_env\":\"GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_1\"}]"`)
+	assert.Contains(t, lock, "GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_0: ${{ secrets.DOCS_TOKEN }}")
+	assert.Contains(t, lock, "GH_AW_SAFE_OUTPUTS_TARGET_TOKEN_1: ${{ steps.safe-outputs-target-token-1.outputs.token }}")
+	assert.Contains(t, lock, "safe-output-projects: 'true'", "per-target clients need @actions/github")
+
+	assert.Contains(t, lock, "GIT_TOKEN: ${{ secrets.DOCS_TOKEN }}", "pull requests should be pushed with the token of the docs target")
+	assert.Contains(t, lock, "path: /tmp/gh-aw/\n      - name: Generate GitHub App token for acme/tracker", "token should be minted after the patch download")
+	assert.Less(t, strings.Index(lock, "name: Generate GitHub App token for acme/tracker"), strings.Index(lock, "repository: acme/docs"), "token should be minted before the checkout")
+}
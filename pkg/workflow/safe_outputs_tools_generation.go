@@ -231,6 +231,15 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 		}
 	}
 
+	// Add custom type tools from SafeOutputs.Types
+	if len(data.SafeOutputs.Types) > 0 {
+		safeOutputsConfigLog.Printf("Adding %d custom type tools", len(data.SafeOutputs.Types))
+		for _, typeName := range sortedCustomSafeOutputTypeNames(data.SafeOutputs.Types) {
+			toolName := stringutil.NormalizeSafeOutputIdentifier(typeName)
+			filteredTools = append(filteredTools, generateCustomSafeOutputTypeToolDefinition(toolName, data.SafeOutputs.Types[typeName]))
+		}
+	}
+
 	if safeOutputsConfigLog.Enabled() {
 		safeOutputsConfigLog.Printf("Filtered %d tools from %d total tools (including %d custom jobs)", len(filteredTools), len(allTools), len(data.SafeOutputs.Jobs))
 	}
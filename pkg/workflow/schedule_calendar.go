@@ -0,0 +1,343 @@
+package workflow
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var scheduleCalendarLog = logger.New("workflow:schedule_calendar")
+
+// This file handles the object form of on.schedule, which adds exclusion calendars to the
+// cron schedules:
+//
+//	on:
+//	  schedule:
+//	    cron: daily around 9am on weekdays
+//	    timezone: Europe/Berlin
+//	    skip-dates:
+//	      - 2026-12-25
+//	      - date: 2026-12-26
+//	        reason: Boxing Day
+//	    skip-calendar: ../aw/holidays.ics
+//	    blackouts:
+//	      - start: 2026-12-15
+//	        end: 2027-01-05
+//	        reason: Release freeze
+//
+// The crons are compiled into the regular schedule trigger. The exclusions are compiled
+// into a pre-activation check that skips scheduled runs falling on an excluded date or
+// inside a blackout window; manually dispatched runs are not affected.
+
+// scheduleDateLayout is the layout of calendar dates in exclusion calendars
+const scheduleDateLayout = "2006-01-02"
+
+// maxICSEventDays bounds the number of days a single calendar event can exclude
+const maxICSEventDays = 366
+
+// ScheduleCalendarConfig holds the dates and windows in which scheduled runs are skipped
+type ScheduleCalendarConfig struct {
+	Timezone  string             `json:"timezone"` // IANA timezone in which dates are evaluated
+	SkipDates []ScheduleSkipDate `json:"skip_dates,omitempty"`
+	Blackouts []ScheduleBlackout `json:"blackouts,omitempty"`
+}
+
+// ScheduleSkipDate is a date (YYYY-MM-DD) on which scheduled runs are skipped
+type ScheduleSkipDate struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ScheduleBlackout is a window in which scheduled runs are skipped. Start and end are both
+// dates (inclusive) or both RFC 3339 timestamps.
+type ScheduleBlackout struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// extractScheduleCalendar converts the object form of on.schedule to the array of cron items
+// and returns the exclusion calendar. Calendar files are resolved relative to the workflow
+// file and must be inside the .github folder.
+func extractScheduleCalendar(schedule map[string]any, markdownPath string) ([]any, *ScheduleCalendarConfig, error) {
+	var items []any
+	switch cron := schedule["cron"].(type) {
+	case string:
+		items = append(items, map[string]any{"cron": cron})
+	case []any:
+		for i, value := range cron {
+			str, ok := value.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("schedule cron item %d must be a string", i)
+			}
+			items = append(items, map[string]any{"cron": str})
+		}
+	default:
+		return nil, nil, errors.New("schedule object requires a 'cron' field with a schedule string or a list of schedule strings")
+	}
+	if len(items) == 0 {
+		return nil, nil, errors.New("schedule object requires at least one cron schedule")
+	}
+
+	calendar := &ScheduleCalendarConfig{Timezone: "UTC"}
+	if tz, ok := schedule["timezone"].(string); ok && tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return nil, nil, fmt.Errorf("invalid schedule timezone %q: %w", tz, err)
+		}
+		calendar.Timezone = tz
+	}
+
+	skipDates, err := parseScheduleSkipDates(schedule["skip-dates"])
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar.SkipDates = skipDates
+
+	for _, path := range extractStringSliceField(schedule["skip-calendar"], "skip-calendar") {
+		dates, err := loadScheduleCalendarFile(path, filepath.Dir(markdownPath))
+		if err != nil {
+			return nil, nil, err
+		}
+		calendar.SkipDates = append(calendar.SkipDates, dates...)
+	}
+	calendar.SkipDates = dedupeScheduleSkipDates(calendar.SkipDates)
+
+	blackouts, err := parseScheduleBlackouts(schedule["blackouts"])
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar.Blackouts = blackouts
+
+	if len(calendar.SkipDates) == 0 && len(calendar.Blackouts) == 0 {
+		scheduleCalendarLog.Print("Schedule object has no exclusions")
+		return items, nil, nil
+	}
+	scheduleCalendarLog.Printf("Schedule calendar: timezone=%s, skipDates=%d, blackouts=%d", calendar.Timezone, len(calendar.SkipDates), len(calendar.Blackouts))
+	return items, calendar, nil
+}
+
+// parseScheduleSkipDates parses skip-dates entries: a date, or an object with date and reason
+func parseScheduleSkipDates(value any) ([]ScheduleSkipDate, error) {
+	if value == nil {
+		return nil, nil
+	}
+	entries, ok := value.([]any)
+	if !ok {
+		return nil, errors.New("schedule skip-dates must be a list of dates")
+	}
+
+	var dates []ScheduleSkipDate
+	for i, entry := range entries {
+		var skipDate ScheduleSkipDate
+		switch v := entry.(type) {
+		case map[string]any:
+			skipDate.Date = scheduleCalendarString(v["date"])
+			skipDate.Reason, _ = v["reason"].(string)
+		default:
+			skipDate.Date = scheduleCalendarString(v)
+		}
+		if _, err := time.Parse(scheduleDateLayout, skipDate.Date); err != nil {
+			return nil, fmt.Errorf("schedule skip-dates item %d: invalid date %q, expected YYYY-MM-DD", i, skipDate.Date)
+		}
+		dates = append(dates, skipDate)
+	}
+	return dates, nil
+}
+
+// parseScheduleBlackouts parses blackout windows and checks that each ends after it starts
+func parseScheduleBlackouts(value any) ([]ScheduleBlackout, error) {
+	if value == nil {
+		return nil, nil
+	}
+	entries, ok := value.([]any)
+	if !ok {
+		return nil, errors.New("schedule blackouts must be a list of objects with start and end")
+	}
+
+	var blackouts []ScheduleBlackout
+	for i, entry := range entries {
+		entryMap, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("schedule blackouts item %d must be an object with start and end", i)
+		}
+		blackout := ScheduleBlackout{
+			Start: scheduleCalendarString(entryMap["start"]),
+			End:   scheduleCalendarString(entryMap["end"]),
+		}
+		blackout.Reason, _ = entryMap["reason"].(string)
+
+		start, startIsDate, err := parseScheduleBoundary(blackout.Start)
+		if err != nil {
+			return nil, fmt.Errorf("schedule blackouts item %d: invalid start: %w", i, err)
+		}
+		end, endIsDate, err := parseScheduleBoundary(blackout.End)
+		if err != nil {
+			return nil, fmt.Errorf("schedule blackouts item %d: invalid end: %w", i, err)
+		}
+		if startIsDate != endIsDate {
+			return nil, fmt.Errorf("schedule blackouts item %d: start and end must both be dates or both be timestamps", i)
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("schedule blackouts item %d: end %s is before start %s", i, blackout.End, blackout.Start)
+		}
+		blackouts = append(blackouts, blackout)
+	}
+	return blackouts, nil
+}
+
+// parseScheduleBoundary parses a blackout boundary as a date or an RFC 3339 timestamp
+func parseScheduleBoundary(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(scheduleDateLayout, value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a date (YYYY-MM-DD) or an RFC 3339 timestamp", value)
+}
+
+// scheduleCalendarString returns a date value as a string. YAML parsers may decode unquoted
+// dates as timestamps, which are formatted back to YYYY-MM-DD.
+func scheduleCalendarString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format(scheduleDateLayout)
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// loadScheduleCalendarFile reads the all-day dates of an iCalendar (.ics) file
+func loadScheduleCalendarFile(path, baseDir string) ([]ScheduleSkipDate, error) {
+	fullPath, err := parser.ResolveIncludePath(path, baseDir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("schedule skip-calendar %s: %w", path, err)
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule skip-calendar %s: %w", path, err)
+	}
+	dates, err := parseICSDates(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule skip-calendar %s: %w", path, err)
+	}
+	scheduleCalendarLog.Printf("Loaded %d dates from calendar %s", len(dates), fullPath)
+	return dates, nil
+}
+
+// parseICSDates returns the dates covered by the events of an iCalendar document. Each event
+// excludes the dates from DTSTART up to, but not including, DTEND; recurrence rules are not
+// expanded, so recurring events only exclude their first occurrence.
+func parseICSDates(content string) ([]ScheduleSkipDate, error) {
+	var dates []ScheduleSkipDate
+	var start, end, summary string
+	inEvent := false
+
+	for _, line := range unfoldICSLines(content) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop property parameters such as DTSTART;VALUE=DATE
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			start, end, summary = "", "", ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+			eventDates, err := icsEventDates(start, end, summary)
+			if err != nil {
+				return nil, err
+			}
+			dates = append(dates, eventDates...)
+		case !inEvent:
+		case name == "DTSTART":
+			start = value
+		case name == "DTEND":
+			end = value
+		case name == "SUMMARY":
+			summary = unescapeICSText(value)
+		}
+	}
+	if len(dates) == 0 {
+		return nil, errors.New("no events found")
+	}
+	return dates, nil
+}
+
+// icsEventDates expands an event into the dates it covers
+func icsEventDates(start, end, summary string) ([]ScheduleSkipDate, error) {
+	startDate, err := parseICSDate(start)
+	if err != nil {
+		return nil, fmt.Errorf("event %q: invalid DTSTART: %w", summary, err)
+	}
+	endDate := startDate.AddDate(0, 0, 1)
+	if end != "" {
+		if endDate, err = parseICSDate(end); err != nil {
+			return nil, fmt.Errorf("event %q: invalid DTEND: %w", summary, err)
+		}
+		// DTEND is exclusive for all-day events; timed events end on the day they end
+		if len(end) > len("20060102") {
+			endDate = endDate.AddDate(0, 0, 1)
+		}
+	}
+
+	var dates []ScheduleSkipDate
+	for day := startDate; day.Before(endDate) || day.Equal(startDate); day = day.AddDate(0, 0, 1) {
+		if len(dates) == maxICSEventDays {
+			return nil, fmt.Errorf("event %q covers more than %d days", summary, maxICSEventDays)
+		}
+		dates = append(dates, ScheduleSkipDate{Date: day.Format(scheduleDateLayout), Reason: summary})
+	}
+	return dates, nil
+}
+
+// parseICSDate parses the date part of an iCalendar DATE or DATE-TIME value
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < len("20060102") {
+		return time.Time{}, fmt.Errorf("%q is not a date", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// unfoldICSLines splits an iCalendar document into logical lines, joining folded lines
+// (continuation lines start with a space or tab)
+func unfoldICSLines(content string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// unescapeICSText unescapes an iCalendar TEXT value
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// dedupeScheduleSkipDates sorts the dates and keeps the first reason given for each date
+func dedupeScheduleSkipDates(dates []ScheduleSkipDate) []ScheduleSkipDate {
+	slices.SortStableFunc(dates, func(a, b ScheduleSkipDate) int { return strings.Compare(a.Date, b.Date) })
+	return slices.CompactFunc(dates, func(a, b ScheduleSkipDate) bool { return a.Date == b.Date })
+}
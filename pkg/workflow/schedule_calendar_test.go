@@ -0,0 +1,154 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHolidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261225\r\n" +
+	"DTEND;VALUE=DATE:20261227\r\n" +
+	"SUMMARY:Christmas\\, Boxing \r\n" +
+	" Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20270101T090000Z\r\n" +
+	"DTEND:20270101T170000Z\r\n" +
+	"SUMMARY:New Year\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICSDates(t *testing.T) {
+	dates, err := parseICSDates(testHolidaysICS)
+	require.NoError(t, err, "calendar should parse")
+	assert.Equal(t, []ScheduleSkipDate{
+		{Date: "2026-12-25", Reason: "Christmas, Boxing Day"},
+		{Date: "2026-12-26", Reason: "Christmas, Boxing Day"},
+		{Date: "2027-01-01", Reason: "New Year"},
+	}, dates, "all-day events should exclude up to DTEND and timed events their day")
+
+	_, err = parseICSDates("BEGIN:VCALENDAR\nEND:VCALENDAR\n")
+	require.Error(t, err, "calendar without events should be rejected")
+
+	_, err = parseICSDates("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT\n")
+	require.Error(t, err, "invalid dates should be rejected")
+}
+
+func TestExtractScheduleCalendar(t *testing.T) {
+	dir := testutil.TempDir(t, "schedule-calendar")
+	workflowsDir := filepath.Join(dir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github", "aw"), 0755))
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "aw", "holidays.ics"), []byte(testHolidaysICS), 0644))
+
+	items, calendar, err := extractScheduleCalendar(map[string]any{
+		"cron":          []any{"daily around 9am", "0 18 * * 5"},
+		"timezone":      "Europe/Berlin",
+		"skip-dates":    []any{"2026-12-26", map[string]any{"date": "2026-12-31", "reason": "New Year's Eve"}},
+		"skip-calendar": "../aw/holidays.ics",
+		"blackouts":     []any{map[string]any{"start": "2026-12-14", "end": "2026-12-18", "reason": "Release freeze"}},
+	}, filepath.Join(workflowsDir, "report.md"))
+	require.NoError(t, err, "schedule object should be extracted")
+
+	assert.Equal(t, []any{map[string]any{"cron": "daily around 9am"}, map[string]any{"cron": "0 18 * * 5"}}, items, "crons should be converted to schedule items")
+	require.NotNil(t, calendar)
+	assert.Equal(t, "Europe/Berlin", calendar.Timezone)
+	assert.Equal(t, []ScheduleSkipDate{
+		{Date: "2026-12-25", Reason: "Christmas, Boxing Day"},
+		{Date: "2026-12-26"},
+		{Date: "2026-12-31", Reason: "New Year's Eve"},
+		{Date: "2027-01-01", Reason: "New Year"},
+	}, calendar.SkipDates, "dates should be merged, sorted and deduplicated with explicit dates first")
+	assert.Equal(t, []ScheduleBlackout{{Start: "2026-12-14", End: "2026-12-18", Reason: "Release freeze"}}, calendar.Blackouts)
+
+	items, calendar, err = extractScheduleCalendar(map[string]any{"cron": "weekly"}, filepath.Join(workflowsDir, "report.md"))
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Nil(t, calendar, "schedule without exclusions should not need a calendar check")
+}
+
+func TestExtractScheduleCalendarErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule map[string]any
+		want     string
+	}{
+		{"missing cron", map[string]any{"skip-dates": []any{"2026-12-25"}}, "requires a 'cron' field"},
+		{"invalid timezone", map[string]any{"cron": "daily", "timezone": "Mars/Olympus"}, "invalid schedule timezone"},
+		{"invalid date", map[string]any{"cron": "daily", "skip-dates": []any{"12/25/2026"}}, "expected YYYY-MM-DD"},
+		{"reversed blackout", map[string]any{"cron": "daily", "blackouts": []any{map[string]any{"start": "2026-12-20", "end": "2026-12-10"}}}, "is before start"},
+		{"mixed blackout", map[string]any{"cron": "daily", "blackouts": []any{map[string]any{"start": "2026-12-20", "end": "2026-12-21T10:00:00Z"}}}, "both be dates or both be timestamps"},
+		{"calendar outside .github", map[string]any{"cron": "daily", "skip-calendar": "../../../holidays.ics"}, "must be within .github folder"},
+	}
+	markdownPath := filepath.Join(t.TempDir(), ".github", "workflows", "report.md")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := extractScheduleCalendar(tt.schedule, markdownPath)
+			require.Error(t, err, "invalid schedule object should be rejected")
+			assert.Contains(t, err.Error(), tt.want, "error should explain the problem")
+		})
+	}
+}
+
+func TestScheduleCalendarPreActivationCheck(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "schedule-calendar-compile")
+	workflowContent := `---
+on:
+  schedule:
+    cron: daily around 9am
+    timezone: Europe/Berlin
+    skip-dates:
+      - "2026-12-25"
+      - date: "2026-12-26"
+        reason: Boxing Day
+    blackouts:
+      - start: "2026-12-14"
+        end: "2026-12-18"
+        reason: Release freeze
+engine: copilot
+---
+
+# Calendar Workflow
+
+Report on the repository.
+`
+	workflowFile := filepath.Join(tmpDir, "calendar-workflow.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(workflowContent), 0644))
+
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("calendar-workflow.md")
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow with a schedule object should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "- cron: ", "crons should be compiled into the schedule trigger")
+	assert.NotContains(t, lock, "skip-dates:", "exclusions should not be emitted in the trigger")
+	assert.Contains(t, lock, "id: check_schedule_calendar", "pre-activation job should check the calendar")
+	assert.Contains(t, lock, "steps.check_schedule_calendar.outputs.schedule_calendar_ok", "activated output should include the calendar check")
+
+	var calendarJSON string
+	for line := range strings.SplitSeq(lock, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "GH_AW_SCHEDULE_CALENDAR: "); ok {
+			require.NoError(t, json.Unmarshal([]byte(value), &calendarJSON), "calendar should be a quoted string")
+		}
+	}
+	var calendar ScheduleCalendarConfig
+	require.NoError(t, json.Unmarshal([]byte(calendarJSON), &calendar), "calendar should be JSON")
+	assert.Equal(t, "Europe/Berlin", calendar.Timezone)
+	assert.Equal(t, []ScheduleSkipDate{{Date: "2026-12-25"}, {Date: "2026-12-26", Reason: "Boxing Day"}}, calendar.SkipDates)
+	assert.Equal(t, "Release freeze", calendar.Blackouts[0].Reason)
+}
@@ -208,6 +208,13 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 		return nil
 	}
 
+	// Replace on.batch with its flush schedule before the schedule is converted to cron
+	batchConfig, err := extractBatchConfig(onMap)
+	if err != nil {
+		return err
+	}
+	c.batchConfig = batchConfig
+
 	// Check if schedule field exists in the "on" map
 	scheduleValue, hasSchedule := onMap["schedule"]
 	if !hasSchedule {
@@ -248,10 +255,23 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 		return nil
 	}
 
+	// Handle object format with exclusion calendars: schedule: {cron: ..., skip-dates: ...}
+	// The crons are converted to the array format below and the exclusions are compiled
+	// into a pre-activation check
+	if scheduleObject, ok := scheduleValue.(map[string]any); ok {
+		items, calendar, err := extractScheduleCalendar(scheduleObject, markdownPath)
+		if err != nil {
+			return err
+		}
+		c.scheduleCalendar = calendar
+		onMap["schedule"] = items
+		scheduleValue = items
+	}
+
 	// Schedule should be an array of schedule items
 	scheduleArray, ok := scheduleValue.([]any)
 	if !ok {
-		return errors.New("schedule field must be a string or an array")
+		return errors.New("schedule field must be a string, an array or an object with a 'cron' field")
 	}
 
 	// Initialize friendly formats map for this compilation
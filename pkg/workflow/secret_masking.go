@@ -2,7 +2,11 @@ package workflow
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/github/gh-aw/pkg/redact"
 )
 
 // extractSecretMaskingConfig extracts secret-masking configuration from frontmatter
@@ -27,8 +31,21 @@ func (c *Compiler) extractSecretMaskingConfig(frontmatter map[string]any) *Secre
 				}
 			}
 
-			// Return nil if no steps were found
-			if len(config.Steps) == 0 {
+			// Extract detectors array
+			if detectors, exists := secretMaskingMap["detectors"]; exists {
+				if detectorsArray, ok := detectors.([]any); ok {
+					config.Detectors = make([]string, 0, len(detectorsArray))
+					for _, detector := range detectorsArray {
+						if name, ok := detector.(string); ok {
+							config.Detectors = append(config.Detectors, name)
+						}
+					}
+					secretMaskingLog.Printf("Extracted %d secret detectors from frontmatter", len(config.Detectors))
+				}
+			}
+
+			// Return nil if neither steps nor detectors were found
+			if len(config.Steps) == 0 && config.Detectors == nil {
 				secretMaskingLog.Print("No secret-masking steps found in frontmatter")
 				return nil
 			}
@@ -55,8 +72,11 @@ func (c *Compiler) MergeSecretMasking(topConfig *SecretMaskingConfig, importedSe
 	if topConfig != nil {
 		result.Steps = make([]map[string]any, len(topConfig.Steps))
 		copy(result.Steps, topConfig.Steps)
+		result.Detectors = topConfig.Detectors
 		secretMaskingLog.Printf("Starting with %d top-level steps", len(topConfig.Steps))
 	}
+	// Detectors of the main workflow take precedence; otherwise the detectors of all imports are combined
+	topHasDetectors := result.Detectors != nil
 
 	// Split by newlines to handle multiple JSON objects from different imports
 	lines := strings.Split(importedSecretMaskingJSON, "\n")
@@ -80,9 +100,22 @@ func (c *Compiler) MergeSecretMasking(topConfig *SecretMaskingConfig, importedSe
 			result.Steps = append(result.Steps, importedConfig.Steps...)
 			secretMaskingLog.Printf("Merged %d steps from import", len(importedConfig.Steps))
 		}
+
+		// Combine detectors from imported config unless the main workflow selects its own
+		if importedConfig.Detectors != nil && !topHasDetectors {
+			if result.Detectors == nil {
+				result.Detectors = []string{}
+			}
+			for _, detector := range importedConfig.Detectors {
+				if !slices.Contains(result.Detectors, detector) {
+					result.Detectors = append(result.Detectors, detector)
+				}
+			}
+			secretMaskingLog.Printf("Merged %d detectors from import", len(importedConfig.Detectors))
+		}
 	}
 
-	if len(result.Steps) == 0 {
+	if len(result.Steps) == 0 && result.Detectors == nil {
 		secretMaskingLog.Print("No secret-masking steps after merging")
 		return nil, nil
 	}
@@ -90,3 +123,26 @@ func (c *Compiler) MergeSecretMasking(topConfig *SecretMaskingConfig, importedSe
 	secretMaskingLog.Printf("Successfully merged secret-masking with %d total steps", len(result.Steps))
 	return result, nil
 }
+
+// validateSecretMaskingDetectors checks that the configured secret detectors are built-in detectors
+func validateSecretMaskingDetectors(config *SecretMaskingConfig) error {
+	if config == nil || len(config.Detectors) == 0 {
+		return nil
+	}
+	if _, err := redact.DetectorsByName(config.Detectors); err != nil {
+		return fmt.Errorf("invalid secret-masking.detectors: %w", err)
+	}
+	return nil
+}
+
+// secretDetectorsEnvValue returns the value of GH_AW_SECRET_DETECTORS for the redaction step, or an
+// empty string when the built-in defaults apply
+func secretDetectorsEnvValue(config *SecretMaskingConfig) string {
+	if config == nil || config.Detectors == nil {
+		return ""
+	}
+	if len(config.Detectors) == 0 {
+		return "none"
+	}
+	return strings.Join(config.Detectors, ",")
+}
@@ -279,3 +279,53 @@ func TestSecretMaskingIntegration(t *testing.T) {
 		t.Error("Expected step name but got nil")
 	}
 }
+
+func TestSecretMaskingDetectors(t *testing.T) {
+	c := NewCompiler()
+
+	config := c.extractSecretMaskingConfig(map[string]any{
+		"secret-masking": map[string]any{"detectors": []any{"jwt", "entropy"}},
+	})
+	if config == nil {
+		t.Fatal("Expected secret masking config with detectors only")
+	}
+	if got := secretDetectorsEnvValue(config); got != "jwt,entropy" {
+		t.Errorf("Expected detectors 'jwt,entropy', got %q", got)
+	}
+
+	disabled := c.extractSecretMaskingConfig(map[string]any{
+		"secret-masking": map[string]any{"detectors": []any{}},
+	})
+	if got := secretDetectorsEnvValue(disabled); got != "none" {
+		t.Errorf("Expected an empty detectors list to disable detectors, got %q", got)
+	}
+
+	if got := secretDetectorsEnvValue(nil); got != "" {
+		t.Errorf("Expected defaults when secret-masking is not configured, got %q", got)
+	}
+
+	if err := validateSecretMaskingDetectors(&SecretMaskingConfig{Detectors: []string{"jwt", "passwords"}}); err == nil || !strings.Contains(err.Error(), "unknown secret detector 'passwords'") {
+		t.Errorf("Expected unknown detector error, got %v", err)
+	}
+}
+
+func TestMergeSecretMaskingDetectors(t *testing.T) {
+	c := NewCompiler()
+	imported := `{"detectors":["jwt","private-keys"]}` + "\n" + `{"detectors":["jwt","entropy"]}`
+
+	merged, err := c.MergeSecretMasking(nil, imported)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(merged.Detectors, ","); got != "jwt,private-keys,entropy" {
+		t.Errorf("Expected detectors of imports to be combined, got %q", got)
+	}
+
+	merged, err = c.MergeSecretMasking(&SecretMaskingConfig{Detectors: []string{"github-tokens"}}, imported)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(merged.Detectors, ","); got != "github-tokens" {
+		t.Errorf("Expected detectors of the main workflow to take precedence, got %q", got)
+	}
+}
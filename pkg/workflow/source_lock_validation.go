@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var sourceLockValidationLog = logger.New("workflow:source_lock_validation")
+
+// validateWorkflowSourceLock checks the workflow's source field against aw.lock.json.
+// Remote imports are verified while they are resolved (see parser.ImportCache); this
+// check catches a source field edited without running 'gh aw update'.
+func (c *Compiler) validateWorkflowSourceLock(markdownPath, source string) error {
+	lock, err := c.getSharedImportCache().Lock()
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return nil
+	}
+
+	relPath := markdownPath
+	if cwd, err := os.Getwd(); err == nil {
+		if absPath, err := filepath.Abs(markdownPath); err == nil {
+			if rel, err := filepath.Rel(cwd, absPath); err == nil {
+				relPath = rel
+			}
+		}
+	}
+
+	entry := lock.Workflow(relPath)
+	if entry == nil {
+		if source != "" {
+			sourceLockValidationLog.Printf("Workflow %s is not pinned in the lockfile", relPath)
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s: source %s is not pinned in %s. Run 'gh aw update' to pin it.", relPath, source, parser.AWLockFile)))
+			c.IncrementWarningCount()
+		}
+		return nil
+	}
+
+	if entry.Source != source {
+		return fmt.Errorf("%s: source %q does not match %q pinned in %s. Run 'gh aw update' to re-pin the workflow", relPath, source, entry.Source, parser.AWLockFile)
+	}
+	sourceLockValidationLog.Printf("Workflow %s matches lockfile pin %s", relPath, entry.SHA)
+	return nil
+}
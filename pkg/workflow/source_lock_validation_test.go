@@ -0,0 +1,96 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkflowSourceLock(t *testing.T) {
+	const source = "octo/aw/workflows/triage.md@v1.0.0"
+
+	tests := []struct {
+		name       string
+		lockSource string // empty means no lockfile
+		source     string
+		wantErr    string
+	}{
+		{
+			name:   "no lockfile",
+			source: source,
+		},
+		{
+			name:       "matching pin",
+			lockSource: source,
+			source:     source,
+		},
+		{
+			name:       "source edited without update",
+			lockSource: source,
+			source:     "octo/aw/workflows/triage.md@v2.0.0",
+			wantErr:    "does not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "source-lock-*")
+			t.Chdir(tmpDir)
+
+			workflowPath := filepath.Join(".github", "workflows", "triage.md")
+			require.NoError(t, os.MkdirAll(filepath.Dir(workflowPath), 0755))
+			if tt.lockSource != "" {
+				lock := parser.NewAWLock()
+				lock.SetWorkflow(workflowPath, &parser.AWLockEntry{Source: tt.lockSource, SHA: "0123456789abcdef0123456789abcdef01234567", Hash: parser.ComputeContentHash([]byte("x"))})
+				require.NoError(t, lock.Save(tmpDir))
+			}
+
+			compiler := NewCompiler()
+			err := compiler.validateWorkflowSourceLock(filepath.Join(tmpDir, workflowPath), tt.source)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "source should match the lockfile")
+				return
+			}
+			require.Error(t, err, "source mismatch should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCompileLeavesAWLockUnchanged(t *testing.T) {
+	const spec = "octo/aw/shared/tools.md@v1.0.0"
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	sharedContent := []byte("---\ntools:\n  github:\n    toolsets: [issues]\n---\n\nUse the GitHub tools.\n")
+
+	tmpDir := testutil.TempDir(t, "aw-lock-compile-*")
+	t.Chdir(tmpDir)
+
+	lock := parser.NewAWLock()
+	lock.SetImport(spec, &parser.AWLockEntry{Source: spec, SHA: sha, Hash: parser.ComputeContentHash(sharedContent)})
+	require.NoError(t, lock.Save(tmpDir))
+	_, err := parser.NewImportCache(tmpDir).Set("octo", "aw", "shared/tools.md", sha, sharedContent)
+	require.NoError(t, err)
+
+	workflowPath := filepath.Join(tmpDir, ".github", "workflows", "triage.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(workflowPath), 0755))
+	workflow := "---\non: issues\npermissions:\n  contents: read\n  issues: read\nimports:\n  - " + spec + "\n---\n\n# Triage\n\nTriage the issue.\n"
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	lockPath := filepath.Join(tmpDir, parser.AWLockFile)
+	before, err := os.ReadFile(lockPath)
+	require.NoError(t, err)
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "pinned import should resolve from the cache")
+
+	after, err := os.ReadFile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "compile must not rewrite aw.lock.json")
+}
@@ -11,11 +11,12 @@ var threatLog = logger.New("workflow:threat_detection")
 
 // ThreatDetectionConfig holds configuration for threat detection in agent output
 type ThreatDetectionConfig struct {
-	Prompt         string        `yaml:"prompt,omitempty"`        // Additional custom prompt instructions to append
-	Steps          []any         `yaml:"steps,omitempty"`         // Array of extra job steps
-	EngineConfig   *EngineConfig `yaml:"engine-config,omitempty"` // Extended engine configuration for threat detection
-	EngineDisabled bool          `yaml:"-"`                       // Internal flag: true when engine is explicitly set to false
-	RunsOn         string        `yaml:"runs-on,omitempty"`       // Runner override for the detection job
+	Prompt         string                         `yaml:"prompt,omitempty"`        // Additional custom prompt instructions to append
+	Steps          []any                          `yaml:"steps,omitempty"`         // Array of extra job steps
+	EngineConfig   *EngineConfig                  `yaml:"engine-config,omitempty"` // Extended engine configuration for threat detection
+	EngineDisabled bool                           `yaml:"-"`                       // Internal flag: true when engine is explicitly set to false
+	RunsOn         string                         `yaml:"runs-on,omitempty"`       // Runner override for the detection job
+	Scanners       *ThreatDetectionScannersConfig `yaml:"scanners,omitempty"`      // Deterministic scanners (nil uses the defaults)
 }
 
 // parseThreatDetectionConfig handles threat-detection configuration
@@ -64,6 +65,11 @@ func (c *Compiler) parseThreatDetectionConfig(outputMap map[string]any) *ThreatD
 				}
 			}
 
+			// Parse scanners field
+			if scanners, exists := configMap["scanners"]; exists {
+				threatConfig.Scanners = parseThreatDetectionScanners(scanners)
+			}
+
 			// Parse runs-on field
 			if runOn, exists := configMap["runs-on"]; exists {
 				if runOnStr, ok := runOn.(string); ok {
@@ -130,24 +136,27 @@ func (c *Compiler) buildInlineDetectionSteps(data *WorkflowData) []string {
 	// Step 3: Prepare files - copies agent output files to expected paths
 	steps = append(steps, c.buildPrepareDetectionFilesStep()...)
 
-	// Step 4: Setup threat detection (github-script)
+	// Step 4: Deterministic scanners (secrets, workflow files, binaries, large files, dependencies)
+	steps = append(steps, c.buildThreatDetectionScannersStep(data)...)
+
+	// Step 5: Setup threat detection (github-script)
 	steps = append(steps, c.buildThreatDetectionAnalysisStep(data)...)
 
-	// Step 5: Engine execution (AWF, no network)
+	// Step 6: Engine execution (AWF, no network)
 	steps = append(steps, c.buildDetectionEngineExecutionStep(data)...)
 
-	// Step 6: Custom steps if configured
+	// Step 7: Custom steps if configured
 	if len(data.SafeOutputs.ThreatDetection.Steps) > 0 {
 		steps = append(steps, c.buildCustomThreatDetectionSteps(data.SafeOutputs.ThreatDetection.Steps)...)
 	}
 
-	// Step 7: Parse threat detection results
+	// Step 8: Parse threat detection results (merges the scanner findings)
 	steps = append(steps, c.buildParsingStep()...)
 
-	// Step 8: Upload detection log artifact
+	// Step 9: Upload detection log artifact
 	steps = append(steps, c.buildUploadDetectionLogStep()...)
 
-	// Step 9: Detection conclusion - sets final detection_success and detection_conclusion outputs
+	// Step 10: Detection conclusion - sets final detection_success and detection_conclusion outputs
 	steps = append(steps, c.buildDetectionConclusionStep()...)
 
 	threatLog.Printf("Generated %d inline detection step lines", len(steps))
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var threatScannersLog = logger.New("workflow:threat_detection_scanners")
+
+// defaultThreatScannerMaxFileSize is the default size in KB above which an added file is reported
+const defaultThreatScannerMaxFileSize = 512
+
+// ThreatDetectionScannersConfig configures the deterministic scanners that run before the
+// AI threat detection. Each scanner produces structured findings that are merged into the
+// detection verdict: credential findings as a secret leak, workflow file and dependency
+// findings as warnings, and all others as a malicious patch.
+type ThreatDetectionScannersConfig struct {
+	Secrets       bool `yaml:"secrets" json:"secrets"`               // Credential patterns in patches and output text
+	WorkflowFiles bool `yaml:"workflow-files" json:"workflow_files"` // Modifications of .github/workflows/** in patches
+	BinaryFiles   bool `yaml:"binary-files" json:"binary_files"`     // Binary files in patches
+	MaxFileSize   int  `yaml:"max-file-size" json:"max_file_size"`   // Added content per file in KB above which it is reported (0 disables)
+	Dependencies  bool `yaml:"dependencies" json:"dependencies"`     // Suspicious dependency additions in package manifests
+}
+
+// defaultThreatDetectionScanners returns the scanner configuration used when none is specified.
+// Only the secrets scanner is enabled; the others are opt-in.
+func defaultThreatDetectionScanners() *ThreatDetectionScannersConfig {
+	return &ThreatDetectionScannersConfig{Secrets: true}
+}
+
+// allThreatDetectionScanners returns the configuration enabling every scanner
+func allThreatDetectionScanners() *ThreatDetectionScannersConfig {
+	return &ThreatDetectionScannersConfig{
+		Secrets:       true,
+		WorkflowFiles: true,
+		BinaryFiles:   true,
+		MaxFileSize:   defaultThreatScannerMaxFileSize,
+		Dependencies:  true,
+	}
+}
+
+// parseThreatDetectionScanners parses the scanners field of the threat-detection configuration.
+// true enables all scanners, false disables them, and an object overrides individual scanners
+// of the default configuration.
+func parseThreatDetectionScanners(value any) *ThreatDetectionScannersConfig {
+	scanners := defaultThreatDetectionScanners()
+	switch v := value.(type) {
+	case bool:
+		if !v {
+			threatScannersLog.Print("Threat detection scanners disabled")
+			return &ThreatDetectionScannersConfig{}
+		}
+		return allThreatDetectionScanners()
+	case map[string]any:
+		for key, field := range map[string]*bool{
+			"secrets":        &scanners.Secrets,
+			"workflow-files": &scanners.WorkflowFiles,
+			"binary-files":   &scanners.BinaryFiles,
+			"dependencies":   &scanners.Dependencies,
+		} {
+			if enabled, ok := v[key].(bool); ok {
+				*field = enabled
+			}
+		}
+		if size, ok := parseIntValue(v["max-file-size"]); ok && size >= 0 {
+			scanners.MaxFileSize = size
+		}
+	}
+	return scanners
+}
+
+// resolveThreatDetectionScanners returns the scanners to run for a workflow, or nil when all are disabled
+func resolveThreatDetectionScanners(config *ThreatDetectionConfig) *ThreatDetectionScannersConfig {
+	if config == nil {
+		return nil
+	}
+	scanners := config.Scanners
+	if scanners == nil {
+		scanners = defaultThreatDetectionScanners()
+	}
+	if *scanners == (ThreatDetectionScannersConfig{}) {
+		return nil
+	}
+	return scanners
+}
+
+// buildThreatDetectionScannersStep creates the step that runs the deterministic scanners over
+// the agent output and patches. Findings are written next to the detection files and merged
+// into the verdict by the parsing step, so the step itself never fails.
+func (c *Compiler) buildThreatDetectionScannersStep(data *WorkflowData) []string {
+	scanners := resolveThreatDetectionScanners(data.SafeOutputs.ThreatDetection)
+	if scanners == nil {
+		return nil
+	}
+	scannersJSON, err := json.Marshal(scanners)
+	if err != nil {
+		threatScannersLog.Printf("Failed to marshal scanner configuration: %v", err)
+		return nil
+	}
+	threatScannersLog.Printf("Adding threat detection scanners step: %s", scannersJSON)
+
+	steps := []string{
+		"      - name: Run threat detection scanners\n",
+		"        id: detection_scanners\n",
+		fmt.Sprintf("        if: %s\n", detectionStepCondition),
+		fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")),
+		"        env:\n",
+		fmt.Sprintf("          GH_AW_DETECTION_SCANNERS: %q\n", string(scannersJSON)),
+		"        with:\n",
+		"          script: |\n",
+	}
+	script := `const { setupGlobals } = require('` + SetupActionDestination + `/setup_globals.cjs');
+setupGlobals(core, github, context, exec, io);
+const { main } = require('` + SetupActionDestination + `/threat_detection_scanners.cjs');
+await main();`
+	return append(steps, FormatJavaScriptForYAML(script)...)
+}
@@ -0,0 +1,114 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThreatDetectionScanners(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected *ThreatDetectionScannersConfig
+	}{
+		{
+			name:  "true enables all scanners",
+			value: true,
+			expected: &ThreatDetectionScannersConfig{
+				Secrets:       true,
+				WorkflowFiles: true,
+				BinaryFiles:   true,
+				MaxFileSize:   512,
+				Dependencies:  true,
+			},
+		},
+		{
+			name:     "false disables all scanners",
+			value:    false,
+			expected: &ThreatDetectionScannersConfig{},
+		},
+		{
+			name:  "object enables individual scanners",
+			value: map[string]any{"binary-files": true, "dependencies": true, "max-file-size": 2048},
+			expected: &ThreatDetectionScannersConfig{
+				Secrets:      true,
+				BinaryFiles:  true,
+				MaxFileSize:  2048,
+				Dependencies: true,
+			},
+		},
+		{
+			name:     "object disables the secrets scanner",
+			value:    map[string]any{"secrets": false},
+			expected: &ThreatDetectionScannersConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseThreatDetectionScanners(tt.value), "scanner configuration should match")
+		})
+	}
+}
+
+func TestParseThreatDetectionConfigScanners(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parseThreatDetectionConfig(map[string]any{
+		"threat-detection": map[string]any{"scanners": map[string]any{"workflow-files": true}},
+	})
+	require.NotNil(t, config)
+	require.NotNil(t, config.Scanners)
+	assert.True(t, config.Scanners.WorkflowFiles, "workflow-files scanner should be enabled")
+	assert.True(t, config.Scanners.Secrets, "secrets scanner should keep its default")
+	assert.False(t, config.Scanners.Dependencies, "other scanners should stay opt-in")
+
+	config = compiler.parseThreatDetectionConfig(map[string]any{"threat-detection": true})
+	require.NotNil(t, config)
+	assert.Nil(t, config.Scanners, "scanners should use the defaults when not configured")
+}
+
+func TestResolveThreatDetectionScanners(t *testing.T) {
+	assert.Nil(t, resolveThreatDetectionScanners(nil), "no threat detection means no scanners")
+	assert.Equal(t, defaultThreatDetectionScanners(), resolveThreatDetectionScanners(&ThreatDetectionConfig{}), "scanners should default to the secrets scanner")
+	assert.Nil(t, resolveThreatDetectionScanners(&ThreatDetectionConfig{Scanners: &ThreatDetectionScannersConfig{}}), "disabled scanners should not run")
+}
+
+func TestThreatDetectionScannersStep(t *testing.T) {
+	compiler := NewCompiler()
+
+	t.Run("scanners run before the AI analysis", func(t *testing.T) {
+		data := &WorkflowData{SafeOutputs: &SafeOutputsConfig{ThreatDetection: &ThreatDetectionConfig{}}}
+		stepsString := strings.Join(compiler.buildInlineDetectionSteps(data), "")
+
+		scannersPos := strings.Index(stepsString, "Run threat detection scanners")
+		setupPos := strings.Index(stepsString, "Setup threat detection")
+		parsePos := strings.Index(stepsString, "Parse threat detection results")
+		require.NotEqual(t, -1, scannersPos, "scanners step should be generated")
+		assert.Less(t, scannersPos, setupPos, "scanners should run before the AI analysis")
+		assert.Less(t, setupPos, parsePos, "AI analysis should run before the results are parsed")
+
+		assert.Contains(t, stepsString, "id: detection_scanners")
+		assert.Contains(t, stepsString, `GH_AW_DETECTION_SCANNERS: "{\"secrets\":true,\"workflow_files\":false,\"binary_files\":false,\"max_file_size\":0,\"dependencies\":false}"`)
+		assert.Contains(t, stepsString, "threat_detection_scanners.cjs")
+	})
+
+	t.Run("scanners run when the AI engine is disabled", func(t *testing.T) {
+		data := &WorkflowData{SafeOutputs: &SafeOutputsConfig{ThreatDetection: &ThreatDetectionConfig{EngineDisabled: true}}}
+		stepsString := strings.Join(compiler.buildInlineDetectionSteps(data), "")
+
+		assert.Contains(t, stepsString, "Run threat detection scanners", "scanners should provide deterministic detection without an engine")
+		assert.Contains(t, stepsString, "Parse threat detection results")
+	})
+
+	t.Run("disabled scanners are omitted", func(t *testing.T) {
+		data := &WorkflowData{SafeOutputs: &SafeOutputsConfig{ThreatDetection: &ThreatDetectionConfig{Scanners: &ThreatDetectionScannersConfig{}}}}
+		stepsString := strings.Join(compiler.buildInlineDetectionSteps(data), "")
+
+		assert.NotContains(t, stepsString, "Run threat detection scanners", "no scanner step should be generated")
+	})
+}